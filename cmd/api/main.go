@@ -1,20 +1,30 @@
 package main
 
 import (
+	"expvar"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/csrf"
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
 	httpSwagger "github.com/swaggo/http-swagger"
 
+	"github.com/gregoryAlvim/gobank/internal/accountnumber"
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/calendar"
 	"github.com/gregoryAlvim/gobank/internal/database"
+	"github.com/gregoryAlvim/gobank/internal/events"
+	"github.com/gregoryAlvim/gobank/internal/fx"
 	"github.com/gregoryAlvim/gobank/internal/handlers"
+	"github.com/gregoryAlvim/gobank/internal/money"
 	"github.com/gregoryAlvim/gobank/internal/repositories"
 	"github.com/gregoryAlvim/gobank/internal/services"
+	"github.com/gregoryAlvim/gobank/internal/webhook"
 )
 
 // @title Bank API
@@ -42,22 +52,247 @@ func main() {
 
 	// Initialize repository and service
 	accountRepo := repositories.NewPsqlAccountRepository()
+	if branch := os.Getenv("BANK_BRANCH"); branch != "" {
+		if !accountnumber.ValidBranch(branch) {
+			log.Fatalf("Invalid BANK_BRANCH: %q must have %d digits", branch, accountnumber.BranchDigits)
+		}
+		accountRepo.Branch = branch
+	}
 	accountService := services.NewAccountService(accountRepo)
+	// Transfers above TRANSFER_APPROVAL_THRESHOLD, in BRL, wait for an
+	// operator's approval. Unset means no transfer needs one
+	fxRepo := repositories.NewPsqlFXRepository()
+	accountService.Rates = fxRepo
+	if threshold := os.Getenv("TRANSFER_APPROVAL_THRESHOLD"); threshold != "" {
+		amount, err := money.Parse(threshold)
+		if err != nil || !amount.IsPositive() {
+			log.Fatalf("Invalid TRANSFER_APPROVAL_THRESHOLD: %q", threshold)
+		}
+		accountService.ApprovalThreshold = amount
+	}
+	accountService.ApprovalTTL = durationEnv("TRANSFER_APPROVAL_TTL", services.DefaultApprovalTTL)
 	accountHandler := handlers.NewAccountHandler(accountService)
+	go chargeOverdraftInterest(accountService, time.Hour)
+	go chargeMaintenanceFees(accountService, time.Hour)
+	// Accounts with no customer activity for this long become dormant
+	go markDormantAccounts(accountService, durationEnv("ACCOUNT_DORMANCY_PERIOD", 365*24*time.Hour), time.Hour)
+	// Daily balance snapshots keep balance queries in the past fast
+	go snapshotBalances(accountService, time.Hour)
+
+	// Interest on savings, accrued daily and credited monthly
+	interestService := services.NewInterestService(repositories.NewPsqlInterestRepository())
+	interestHandler := handlers.NewInterestHandler(interestService)
+	go runInterest(interestService, time.Hour)
+
+	// Scheduled and standing transfers run on business days. HOLIDAYS_FILE
+	// lists the holidays; unset means only weekends are skipped
+	holidays := calendar.New()
+	if path := os.Getenv("HOLIDAYS_FILE"); path != "" {
+		loaded, err := calendar.Load(path)
+		if err != nil {
+			log.Fatalf("Invalid HOLIDAYS_FILE: %v", err)
+		}
+		holidays = loaded
+	}
+	scheduleService := services.NewScheduledTransferService(repositories.NewPsqlScheduledTransferRepository(), accountService, holidays)
+	scheduleService.Retry = services.ScheduleRetryPolicy{
+		MaxAttempts: intEnv("SCHEDULED_TRANSFER_MAX_ATTEMPTS", services.DefaultScheduleRetryPolicy.MaxAttempts),
+		Delay:       durationEnv("SCHEDULED_TRANSFER_RETRY_DELAY", services.DefaultScheduleRetryPolicy.Delay),
+	}
+	scheduleHandler := handlers.NewScheduledTransferHandler(scheduleService)
+	go runScheduledTransfers(scheduleService, time.Minute)
+
+	// Exchange rates for cross-currency transfers. FX_RATES_FILE, when set,
+	// is loaded into the rates at startup; quotes lock a rate for
+	// FX_QUOTE_TTL
+	fxService := services.NewFXService(fxRepo, accountRepo)
+	fxService.QuoteTTL = durationEnv("FX_QUOTE_TTL", services.DefaultQuoteTTL)
+	if path := os.Getenv("FX_RATES_FILE"); path != "" {
+		entries, err := fx.Load(path)
+		if err != nil {
+			log.Fatalf("Invalid FX_RATES_FILE: %v", err)
+		}
+		if err := fxService.LoadRates(entries); err != nil {
+			log.Fatalf("Failed to load FX_RATES_FILE: %v", err)
+		}
+	}
+	fxHandler := handlers.NewFXHandler(fxService)
+
+	// Domain events are written to the outbox with each change and relayed
+	// to the subscribers of the in-process bus. EVENTS_FILE, when set,
+	// appends them to a file as NDJSON
+	bus := events.NewInProcess()
+	if path := os.Getenv("EVENTS_FILE"); path != "" {
+		file, err := events.OpenNDJSON(path)
+		if err != nil {
+			log.Fatalf("Invalid EVENTS_FILE: %v", err)
+		}
+		bus.Subscribe(file.Publish)
+	}
+
+	// Webhooks are queued from the relayed events and posted, signed, to
+	// each subscription. Failed deliveries are retried with exponential
+	// backoff up to WEBHOOK_MAX_ATTEMPTS times
+	webhookService := services.NewWebhookService(repositories.NewPsqlWebhookRepository(),
+		webhook.NewClient(durationEnv("WEBHOOK_TIMEOUT", 10*time.Second)))
+	webhookService.Retry = services.WebhookRetryPolicy{
+		MaxAttempts: intEnv("WEBHOOK_MAX_ATTEMPTS", services.DefaultWebhookRetryPolicy.MaxAttempts),
+		BaseDelay:   durationEnv("WEBHOOK_RETRY_BASE_DELAY", services.DefaultWebhookRetryPolicy.BaseDelay),
+		MaxDelay:    durationEnv("WEBHOOK_RETRY_MAX_DELAY", services.DefaultWebhookRetryPolicy.MaxDelay),
+	}
+	bus.Subscribe(webhookService.Enqueue)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	go deliverWebhooks(webhookService, durationEnv("WEBHOOK_DELIVERY_INTERVAL", 5*time.Second))
+
+	relay := services.NewOutboxRelay(repositories.NewPsqlOutboxRepository(), bus)
+	go relayEvents(relay, durationEnv("OUTBOX_RELAY_INTERVAL", time.Second))
+
+	// Each account change writes a pending audit log record; they are
+	// chained in the background so money transactions do not wait on the
+	// log's lock
+	auditLog := services.NewAuditLogService(repositories.NewPsqlAuditLogRepository())
+	go chainAuditLog(auditLog, durationEnv("AUDIT_LOG_CHAIN_INTERVAL", time.Second))
+
+	// Authentication. JWT_KEYS lists the signing keys as kid:secret pairs;
+	// the first one signs, all of them verify.
+	signingKeys, err := auth.ParseKeySet(os.Getenv("JWT_KEYS"))
+	if err != nil {
+		log.Fatalf("Invalid JWT_KEYS: %v", err)
+	}
+	tokens := auth.NewTokenIssuer(signingKeys, durationEnv("JWT_ACCESS_TTL", 15*time.Minute))
+	authRepo := repositories.NewPsqlAuthRepository()
+	authService := services.NewAuthService(authRepo, tokens, durationEnv("JWT_REFRESH_TTL", 30*24*time.Hour))
+	authHandler := handlers.NewAuthHandler(authService)
+	authenticate := handlers.Authenticate(tokens)
+
+	// Roles and the audit trail of operator actions
+	auditService := services.NewAuditService(repositories.NewPsqlAuditRepository())
+	auditHandler := handlers.NewAuditHandler(auditService)
+	authz := handlers.NewAuthorizer(auditService)
+
+	ledgerRepo := repositories.NewPsqlLedgerRepository()
+	ledgerService := services.NewLedgerService(ledgerRepo)
+	ledgerHandler := handlers.NewLedgerHandler(ledgerService)
+
+	// Idempotency keys for money-moving endpoints
+	idempotencyTTL := durationEnv("IDEMPOTENCY_KEY_TTL", 24*time.Hour)
+	idempotencyLease := durationEnv("IDEMPOTENCY_KEY_LEASE", time.Minute)
+	idempotencyRepo := repositories.NewPsqlIdempotencyRepository()
+	idempotency := handlers.NewIdempotencyMiddleware(idempotencyRepo, idempotencyTTL, idempotencyLease)
+	go purgeExpiredIdempotencyKeys(idempotencyRepo, time.Hour)
 
 	// Router
 	r := mux.NewRouter()
+	// Every request gets an ID, recorded with the changes it makes
+	r.Use(handlers.RequestID)
 
 	// Swagger
 	r.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
 
-	// Handlers
+	// Public handlers
 	r.HandleFunc("/account", accountHandler.CreateAccount).Methods("POST")
-	r.HandleFunc("/account/{id}/balance", accountHandler.GetBalance).Methods("GET")
-	r.HandleFunc("/account/{id}/deposit", accountHandler.Deposit).Methods("POST")
-	r.HandleFunc("/account/{id}/withdraw", accountHandler.Withdraw).Methods("POST")
-	r.HandleFunc("/account/transfer", accountHandler.Transfer).Methods("POST")
-	r.HandleFunc("/account/{id}", accountHandler.CloseAccount).Methods("DELETE")
+	r.HandleFunc("/auth/login", authHandler.Login).Methods("POST")
+	r.HandleFunc("/auth/refresh", authHandler.Refresh).Methods("POST")
+	r.HandleFunc("/auth/logout", authHandler.Logout).Methods("POST")
+	r.HandleFunc("/auth/operators/login", authHandler.OperatorLogin).Methods("POST")
+
+	// {id} is either the internal account ID or the account number. Each
+	// route requires a permission; customers must also own the account
+	accounts := r.PathPrefix("/account").Subrouter()
+	accounts.Use(authenticate, handlers.ResolveAccountNumbers(accountService), handlers.RequireAccountOwner(accountService))
+	accounts.HandleFunc("/{id}/balance", authz.Require(auth.PermViewAccount, accountHandler.GetBalance)).Methods("GET")
+	accounts.HandleFunc("/{id}/transactions", authz.Require(auth.PermViewAccount, accountHandler.GetTransactions)).Methods("GET")
+	accounts.HandleFunc("/{id}/deposit", authz.Require(auth.PermDeposit, idempotency.Wrap(accountHandler.Deposit))).Methods("POST")
+	accounts.HandleFunc("/{id}/withdraw", authz.Require(auth.PermWithdraw, idempotency.Wrap(accountHandler.Withdraw))).Methods("POST")
+	accounts.HandleFunc("/transfer", authz.Require(auth.PermTransfer, idempotency.Wrap(accountHandler.Transfer))).Methods("POST")
+	accounts.HandleFunc("/{id}", authz.Require(auth.PermCloseAccount, accountHandler.CloseAccount)).Methods("DELETE")
+	accounts.HandleFunc("/{id}/close", authz.Require(auth.PermCloseAccount, accountHandler.CloseAccount)).Methods("POST")
+	accounts.HandleFunc("/{id}/ledger", authz.Require(auth.PermViewAccount, ledgerHandler.GetLedger)).Methods("GET")
+	accounts.HandleFunc("/{id}/holds", authz.Require(auth.PermHoldFunds, idempotency.Wrap(accountHandler.PlaceHold))).Methods("POST")
+	accounts.HandleFunc("/{id}/holds", authz.Require(auth.PermViewAccount, accountHandler.ListHolds)).Methods("GET")
+	accounts.HandleFunc("/{id}/holds/{hold_id}/capture", authz.Require(auth.PermHoldFunds, idempotency.Wrap(accountHandler.CaptureHold))).Methods("POST")
+	accounts.HandleFunc("/{id}/holds/{hold_id}/void", authz.Require(auth.PermHoldFunds, accountHandler.VoidHold)).Methods("POST")
+	accounts.HandleFunc("/{id}/freeze", authz.Require(auth.PermFreezeAccount, accountHandler.FreezeAccount)).Methods("POST")
+	accounts.HandleFunc("/{id}/unfreeze", authz.Require(auth.PermUnfreezeAccount, accountHandler.UnfreezeAccount)).Methods("POST")
+	accounts.HandleFunc("/{id}/activate", authz.Require(auth.PermActivateAccount, accountHandler.ActivateAccount)).Methods("POST")
+	accounts.HandleFunc("/{id}/status-history", authz.Require(auth.PermViewAccount, accountHandler.ListStatusChanges)).Methods("GET")
+	accounts.HandleFunc("/{id}/balance-corrections", authz.Require(auth.PermCorrectBalance, accountHandler.CorrectBalance)).Methods("POST")
+	accounts.HandleFunc("/{id}/overdraft", authz.Require(auth.PermViewAccount, accountHandler.GetOverdraft)).Methods("GET")
+	accounts.HandleFunc("/{id}/overdraft", authz.Require(auth.PermManageOverdraft, accountHandler.SetOverdraftLimit)).Methods("PUT")
+	accounts.HandleFunc("/{id}/fee-waivers", authz.Require(auth.PermViewAccount, accountHandler.ListFeeWaivers)).Methods("GET")
+	accounts.HandleFunc("/{id}/fee-waivers/{fee_type}", authz.Require(auth.PermManageFees, accountHandler.WaiveFee)).Methods("PUT")
+	accounts.HandleFunc("/{id}/fee-waivers/{fee_type}", authz.Require(auth.PermManageFees, accountHandler.RemoveFeeWaiver)).Methods("DELETE")
+	accounts.HandleFunc("/{id}/scheduled-transfers", authz.Require(auth.PermTransfer, idempotency.Wrap(scheduleHandler.Create))).Methods("POST")
+	accounts.HandleFunc("/{id}/scheduled-transfers", authz.Require(auth.PermViewAccount, scheduleHandler.List)).Methods("GET")
+	accounts.HandleFunc("/{id}/scheduled-transfers/{schedule_id}", authz.Require(auth.PermViewAccount, scheduleHandler.Get)).Methods("GET")
+	accounts.HandleFunc("/{id}/scheduled-transfers/{schedule_id}", authz.Require(auth.PermTransfer, scheduleHandler.Update)).Methods("PUT")
+	accounts.HandleFunc("/{id}/scheduled-transfers/{schedule_id}", authz.Require(auth.PermTransfer, scheduleHandler.Cancel)).Methods("DELETE")
+	accounts.HandleFunc("/{id}/scheduled-transfers/{schedule_id}/runs", authz.Require(auth.PermViewAccount, scheduleHandler.ListRuns)).Methods("GET")
+	accounts.HandleFunc("/{id}/fx-quotes", authz.Require(auth.PermTransfer, fxHandler.CreateQuote)).Methods("POST")
+
+	customers := r.PathPrefix("/customers/{customer_id}").Subrouter()
+	customers.Use(authenticate, handlers.RequireCustomer)
+	customers.HandleFunc("/accounts", authz.Require(auth.PermOpenAccount, accountHandler.OpenAccount)).Methods("POST")
+	customers.HandleFunc("/accounts", authz.Require(auth.PermViewAccount, accountHandler.GetCustomerAccounts)).Methods("GET")
+	customers.HandleFunc("/webhooks", authz.Require(auth.PermManageWebhooks, webhookHandler.CreateSubscription)).Methods("POST")
+	customers.HandleFunc("/webhooks", authz.Require(auth.PermManageWebhooks, webhookHandler.ListSubscriptions)).Methods("GET")
+	customers.HandleFunc("/webhooks/{webhook_id}", authz.Require(auth.PermManageWebhooks, webhookHandler.DeleteSubscription)).Methods("DELETE")
+	customers.HandleFunc("/webhooks/{webhook_id}/deliveries", authz.Require(auth.PermManageWebhooks, webhookHandler.ListDeliveries)).Methods("GET")
+	customers.HandleFunc("/webhooks/{webhook_id}/deliveries/{delivery_id}/resend", authz.Require(auth.PermManageWebhooks, webhookHandler.Resend)).Methods("POST")
+
+	// Transfers waiting for approval. Operators cannot review their own
+	approvals := r.PathPrefix("/transfer-approvals").Subrouter()
+	approvals.Use(authenticate)
+	approvals.HandleFunc("", authz.Require(auth.PermReviewTransfer, accountHandler.ListTransferApprovals)).Methods("GET")
+	approvals.HandleFunc("/{approval_id}/approve", authz.Require(auth.PermReviewTransfer, accountHandler.ApproveTransfer)).Methods("POST")
+	approvals.HandleFunc("/{approval_id}/reject", authz.Require(auth.PermReviewTransfer, accountHandler.RejectTransfer)).Methods("POST")
+
+	// Overdraft limits and interest rates of each account category
+	overdraftPolicies := r.PathPrefix("/overdraft-policies").Subrouter()
+	overdraftPolicies.Use(authenticate)
+	overdraftPolicies.HandleFunc("", authz.Require(auth.PermViewAccount, accountHandler.ListOverdraftPolicies)).Methods("GET")
+	overdraftPolicies.HandleFunc("/{category}", authz.Require(auth.PermManageOverdraft, accountHandler.SetOverdraftPolicy)).Methods("PUT")
+
+	// Withdrawal, transfer and maintenance fees
+	fees := r.PathPrefix("/fees").Subrouter()
+	fees.Use(authenticate)
+	fees.HandleFunc("", authz.Require(auth.PermViewAccount, accountHandler.ListFees)).Methods("GET")
+	fees.HandleFunc("/{type:withdrawal}", authz.Require(auth.PermManageFees, accountHandler.SetFee)).Methods("PUT")
+	fees.HandleFunc("/{type:transfer}/{from_type}/{to_type}", authz.Require(auth.PermManageFees, accountHandler.SetFee)).Methods("PUT")
+	fees.HandleFunc("/{type:maintenance}/{category}", authz.Require(auth.PermManageFees, accountHandler.SetFee)).Methods("PUT")
+
+	// Interest rates and manual interest runs
+	interest := r.PathPrefix("/interest").Subrouter()
+	interest.Use(authenticate)
+	interest.HandleFunc("/rates", authz.Require(auth.PermViewAccount, interestHandler.ListRates)).Methods("GET")
+	interest.HandleFunc("/rates/{category}", authz.Require(auth.PermManageInterest, interestHandler.SetRate)).Methods("PUT")
+	interest.HandleFunc("/cdi/{date}", authz.Require(auth.PermManageInterest, interestHandler.SetCDIRate)).Methods("PUT")
+	interest.HandleFunc("/runs", authz.Require(auth.PermManageInterest, interestHandler.Run)).Methods("POST")
+
+	// Exchange rates and spreads of each currency pair
+	fxRates := r.PathPrefix("/fx").Subrouter()
+	fxRates.Use(authenticate)
+	fxRates.HandleFunc("/rates", authz.Require(auth.PermViewAccount, fxHandler.ListRates)).Methods("GET")
+	fxRates.HandleFunc("/rates/{base}/{quote}", authz.Require(auth.PermManageFX, fxHandler.SetRate)).Methods("PUT")
+
+	// Partner webhooks, which get the events of every account
+	partnerWebhooks := r.PathPrefix("/webhooks").Subrouter()
+	partnerWebhooks.Use(authenticate)
+	partnerWebhooks.HandleFunc("", authz.Require(auth.PermManagePartnerWebhooks, webhookHandler.CreateSubscription)).Methods("POST")
+	partnerWebhooks.HandleFunc("", authz.Require(auth.PermManagePartnerWebhooks, webhookHandler.ListSubscriptions)).Methods("GET")
+	partnerWebhooks.HandleFunc("/{webhook_id}", authz.Require(auth.PermManagePartnerWebhooks, webhookHandler.DeleteSubscription)).Methods("DELETE")
+	partnerWebhooks.HandleFunc("/{webhook_id}/deliveries", authz.Require(auth.PermManagePartnerWebhooks, webhookHandler.ListDeliveries)).Methods("GET")
+	partnerWebhooks.HandleFunc("/{webhook_id}/deliveries/{delivery_id}/resend", authz.Require(auth.PermManagePartnerWebhooks, webhookHandler.Resend)).Methods("POST")
+
+	audit := r.PathPrefix("/audit").Subrouter()
+	audit.Use(authenticate)
+	audit.HandleFunc("/events", authz.Require(auth.PermViewAudit, auditHandler.ListEvents)).Methods("GET")
+
+	// Runtime metrics, including transaction retry counters. They show the
+	// process's command line and memory stats, so only operators see them
+	debug := r.PathPrefix("/debug").Subrouter()
+	debug.Use(authenticate)
+	debug.HandleFunc("/vars", authz.Require(auth.PermViewMetrics, expvar.Handler().ServeHTTP)).Methods("GET")
 
 	// CSRF protection
 	csrfMiddleware := csrf.Protect([]byte("32-byte-long-auth-key"))
@@ -66,3 +301,193 @@ func main() {
 	fmt.Println("Server started at :8080")
 	log.Fatal(http.ListenAndServe(":8080", csrfMiddleware(r)))
 }
+
+// durationEnv reads a duration such as "15m" from the environment, or
+// returns def when the variable is unset.
+func durationEnv(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Fatalf("Invalid %s: %q", name, v)
+	}
+	return d
+}
+
+// intEnv reads a positive integer from the environment, or returns def
+// when the variable is unset.
+func intEnv(name string, def int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		log.Fatalf("Invalid %s: %q", name, v)
+	}
+	return n
+}
+
+// purgeExpiredIdempotencyKeys periodically deletes idempotency keys that
+// are past their expiry.
+func purgeExpiredIdempotencyKeys(repo repositories.IdempotencyRepository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if _, err := repo.DeleteExpired(); err != nil {
+			log.Printf("Failed to purge expired idempotency keys: %v", err)
+		}
+	}
+}
+
+// chargeOverdraftInterest charges a day of interest on negative balances
+// at startup and then every interval. Each account is charged once per
+// calendar day (UTC), whatever the number of runs.
+func chargeOverdraftInterest(service *services.AccountService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		if n, err := service.ChargeOverdraftInterest(today); err != nil {
+			log.Printf("Failed to charge overdraft interest: %v", err)
+		} else if n > 0 {
+			log.Printf("Charged overdraft interest on %d accounts", n)
+		}
+		<-ticker.C
+	}
+}
+
+// chargeMaintenanceFees charges the monthly maintenance fee at startup and
+// then every interval. Each account is charged once per calendar month
+// (UTC), whatever the number of runs.
+func chargeMaintenanceFees(service *services.AccountService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := service.ChargeMaintenanceFees(time.Now().UTC()); err != nil {
+			log.Printf("Failed to charge maintenance fees: %v", err)
+		} else if n > 0 {
+			log.Printf("Charged maintenance fees on %d accounts", n)
+		}
+		<-ticker.C
+	}
+}
+
+// markDormantAccounts marks dormant, at startup and then every interval,
+// the active accounts with no customer activity in the last period.
+func markDormantAccounts(service *services.AccountService, period, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := service.MarkDormantAccounts(time.Now(), period); err != nil {
+			log.Printf("Failed to mark dormant accounts: %v", err)
+		} else if n > 0 {
+			log.Printf("Marked %d accounts dormant", n)
+		}
+		<-ticker.C
+	}
+}
+
+// snapshotBalances takes the daily balance snapshots that are due at
+// startup and then every interval. Each day is snapshotted once, whatever
+// the number of runs.
+func snapshotBalances(service *services.AccountService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := service.SnapshotBalances(time.Now()); err != nil {
+			log.Printf("Failed to snapshot balances: %v", err)
+		} else if n > 0 {
+			log.Printf("Took %d balance snapshots", n)
+		}
+		<-ticker.C
+	}
+}
+
+// runInterest accrues and credits interest on savings at startup and then
+// every interval. Runs after the first one of the day find nothing to do.
+func runInterest(service *services.InterestService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := service.Run(time.Now(), false)
+		if err != nil {
+			log.Printf("Failed to run interest: %v", err)
+		} else {
+			for _, account := range report.Accounts {
+				if account.Error != "" {
+					log.Printf("Failed to run interest on account %d: %s", account.AccountID, account.Error)
+				}
+			}
+		}
+		<-ticker.C
+	}
+}
+
+// runScheduledTransfers runs the scheduled transfers that are due at
+// startup and then every interval.
+func runScheduledTransfers(service *services.ScheduledTransferService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := service.RunDue(time.Now()); err != nil {
+			log.Printf("Failed to run scheduled transfers: %v", err)
+		} else if n > 0 {
+			log.Printf("Ran %d scheduled transfers", n)
+		}
+		<-ticker.C
+	}
+}
+
+// relayEvents publishes the events in the outbox at startup and then
+// every interval.
+func relayEvents(relay *services.OutboxRelay, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if report, err := relay.Relay(); err != nil {
+			log.Printf("Failed to relay events: %v", err)
+		} else if report.Failed > 0 {
+			log.Printf("Failed to publish %d events; they will be retried", report.Failed)
+		}
+		<-ticker.C
+	}
+}
+
+// chainAuditLog chains the pending audit log records at startup and then
+// every interval.
+func chainAuditLog(service *services.AuditLogService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := service.Chain(); err != nil {
+			log.Printf("Failed to chain the audit log: %v", err)
+		}
+		<-ticker.C
+	}
+}
+
+// deliverWebhooks sends the webhook deliveries that are due at startup and
+// then every interval.
+func deliverWebhooks(service *services.WebhookService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := service.DeliverDue(time.Now()); err != nil {
+			log.Printf("Failed to deliver webhooks: %v", err)
+		}
+		<-ticker.C
+	}
+}
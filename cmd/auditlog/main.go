@@ -0,0 +1,74 @@
+// Command auditlog verifies the hash chain of the audit log:
+//
+//	go run ./cmd/auditlog
+//	go run ./cmd/auditlog -anchor 1042:5f1c...
+//
+// It prints the head of the log as sequence:hash. Keep it somewhere the
+// database cannot reach and pass it back with -anchor, so that records
+// cut off the end of the log are noticed too. The command exits with
+// status 1 at the first broken link.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+
+	"github.com/gregoryAlvim/gobank/internal/auditlog"
+	"github.com/gregoryAlvim/gobank/internal/database"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	"github.com/gregoryAlvim/gobank/internal/services"
+)
+
+func main() {
+	anchorFlag := flag.String("anchor", "", "sequence:hash of a head printed by an earlier run")
+	flag.Parse()
+
+	var anchor *models.AuditLogHead
+	if *anchorFlag != "" {
+		var err error
+		if anchor, err = parseHead(*anchorFlag); err != nil {
+			log.Fatalf("Invalid -anchor: %v", err)
+		}
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+	database.InitDB(os.Getenv("DATABASE_URL"))
+	service := services.NewAuditLogService(repositories.NewPsqlAuditLogRepository())
+
+	head, err := service.Verify(anchor)
+	var broken *auditlog.BrokenLinkError
+	switch {
+	case errors.As(err, &broken):
+		fmt.Println(broken)
+		fmt.Printf("Last good record: %d:%s\n", head.Sequence, head.Hash)
+		os.Exit(1)
+	case err != nil:
+		log.Fatalf("Failed to verify the audit log: %v", err)
+	}
+	fmt.Printf("Audit log intact, %d records. Head: %d:%s\n", head.Sequence, head.Sequence, head.Hash)
+}
+
+func parseHead(s string) (*models.AuditLogHead, error) {
+	sequence, hash, ok := strings.Cut(s, ":")
+	if !ok {
+		return nil, errors.New("want sequence:hash")
+	}
+	n, err := strconv.ParseInt(sequence, 10, 64)
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("bad sequence %q", sequence)
+	}
+	if len(hash) != len(auditlog.Genesis) {
+		return nil, fmt.Errorf("bad hash %q", hash)
+	}
+	return &models.AuditLogHead{Sequence: n, Hash: hash}, nil
+}
@@ -0,0 +1,52 @@
+// Command operators creates back-office operators:
+//
+//	OPERATOR_PASSWORD=... go run ./cmd/operators -username ana -role teller
+//
+// The password is read from OPERATOR_PASSWORD, or from the first line of
+// standard input when that is unset.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+
+	"github.com/gregoryAlvim/gobank/internal/database"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	"github.com/gregoryAlvim/gobank/internal/services"
+)
+
+func main() {
+	username := flag.String("username", "", "operator username")
+	role := flag.String("role", "", "teller, supervisor or auditor")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	password := os.Getenv("OPERATOR_PASSWORD")
+	if password == "" {
+		fmt.Fprint(os.Stderr, "Password: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			log.Fatalf("Failed to read password: %v", err)
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+
+	database.InitDB(os.Getenv("DATABASE_URL"))
+	// CreateOperator never issues tokens, so it needs no signing keys.
+	authService := services.NewAuthService(repositories.NewPsqlAuthRepository(), nil, 0)
+
+	operator, err := authService.CreateOperator(*username, *role, password)
+	if err != nil {
+		log.Fatalf("Failed to create operator: %v", err)
+	}
+	fmt.Printf("Operator %q created with ID %d and role %s\n", operator.Username, operator.ID, operator.Role)
+}
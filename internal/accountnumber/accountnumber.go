@@ -0,0 +1,120 @@
+// Package accountnumber builds and parses the bank-style identifiers shown
+// to customers: a 4-digit branch (agência), an 8-digit account number and a
+// check digit, written 0001-00001234-5.
+//
+// The check digit is computed modulo 11 over the 12 digits of branch and
+// account number. Starting from the rightmost digit, the digits are
+// multiplied by the weights 2, 3, 4, 5, 6, 7, 8, 9, 2, 3, ... and summed.
+// The check digit is 11 minus the remainder of that sum divided by 11; a
+// result of 10 or 11 becomes 0.
+package accountnumber
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	BranchDigits = 4
+	NumberDigits = 8
+
+	// DefaultBranch is used when no branch is configured.
+	DefaultBranch = "0001"
+)
+
+var (
+	ErrInvalidFormat     = errors.New("invalid account number format")
+	ErrInvalidCheckDigit = errors.New("invalid account number check digit")
+	ErrOutOfRange        = errors.New("account number out of range")
+)
+
+// Number identifies an account to customers.
+type Number struct {
+	Branch     string
+	Account    string
+	CheckDigit string
+}
+
+// ValidBranch reports whether branch has the expected number of digits.
+func ValidBranch(branch string) bool {
+	return len(branch) == BranchDigits && isDigits(branch)
+}
+
+// New returns the account number with sequence seq in branch.
+func New(branch string, seq int64) (Number, error) {
+	if !ValidBranch(branch) {
+		return Number{}, ErrInvalidFormat
+	}
+	account := fmt.Sprintf("%0*d", NumberDigits, seq)
+	if seq <= 0 || len(account) != NumberDigits {
+		return Number{}, ErrOutOfRange
+	}
+	return Number{Branch: branch, Account: account, CheckDigit: CheckDigit(branch, account)}, nil
+}
+
+// Parse reads a number written as branch-account-digit, e.g.
+// 0001-00001234-5, and verifies its check digit.
+func Parse(s string) (Number, error) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) != 3 ||
+		len(parts[0]) != BranchDigits || !isDigits(parts[0]) ||
+		len(parts[1]) != NumberDigits || !isDigits(parts[1]) ||
+		len(parts[2]) != 1 || !isDigits(parts[2]) {
+		return Number{}, ErrInvalidFormat
+	}
+
+	n := Number{Branch: parts[0], Account: parts[1], CheckDigit: parts[2]}
+	if !n.Valid() {
+		return Number{}, ErrInvalidCheckDigit
+	}
+	return n, nil
+}
+
+// CheckDigit returns the check digit for the branch and account number.
+// Both must contain only decimal digits.
+func CheckDigit(branch, account string) string {
+	digits := branch + account
+	sum, weight := 0, 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+
+	digit := 11 - sum%11
+	if digit >= 10 {
+		digit = 0
+	}
+	return fmt.Sprint(digit)
+}
+
+// Valid reports whether the check digit matches the branch and account.
+func (n Number) Valid() bool {
+	return isDigits(n.Branch+n.Account) && n.CheckDigit == CheckDigit(n.Branch, n.Account)
+}
+
+func (n Number) String() string {
+	return n.Branch + "-" + n.Account + "-" + n.CheckDigit
+}
+
+// LooksLike reports whether s is written like an account number rather
+// than an internal account ID, so callers can tell the two apart without
+// touching the database.
+func LooksLike(s string) bool {
+	return strings.Contains(s, "-")
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package accountnumber
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		branch, account string
+		want            string
+	}{
+		{"0001", "00001234", "1"},
+		{"0001", "00000010", "6"},
+		{"1234", "98765432", "5"},
+		{"0001", "00000005", "0"}, // 11 - 1 = 10 becomes 0
+		{"0001", "00000013", "0"}, // 11 - 0 = 11 becomes 0
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.branch+"-"+tt.account, func(t *testing.T) {
+			assert.Equal(t, tt.want, CheckDigit(tt.branch, tt.account))
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	n, err := New("0001", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, Number{Branch: "0001", Account: "00000010", CheckDigit: "6"}, n)
+	assert.Equal(t, "0001-00000010-6", n.String())
+
+	_, err = New("1", 10)
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+
+	_, err = New("0001", 0)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+
+	_, err = New("0001", 100000000)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Number
+		wantErr error
+	}{
+		{"0001-00001234-1", Number{"0001", "00001234", "1"}, nil},
+		{" 1234-98765432-5 ", Number{"1234", "98765432", "5"}, nil},
+		{"0001-00001234-2", Number{}, ErrInvalidCheckDigit},
+		{"0001-1234-1", Number{}, ErrInvalidFormat},
+		{"0001-00001234", Number{}, ErrInvalidFormat},
+		{"000100001234-1", Number{}, ErrInvalidFormat},
+		{"0001-0000123a-1", Number{}, ErrInvalidFormat},
+		{"0001-00001234-X", Number{}, ErrInvalidFormat},
+		{"", Number{}, ErrInvalidFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Package auditlog chains the records of the audit log with SHA-256, so
+// that editing, deleting or reordering a record breaks the chain from it
+// on.
+//
+// A record's hash is the SHA-256, in hex, of its previous hash, sequence,
+// action, actor, request ID, account ID, counterparty ID, before and after
+// states, as written, and creation time in RFC 3339 with nanoseconds, in
+// UTC. Each field is written as its length in bytes, a colon, the field
+// and a newline. The first record's previous hash is Genesis.
+package auditlog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+// Genesis is the previous hash of the first record.
+var Genesis = strings.Repeat("0", sha256.Size*2)
+
+// Hash returns the hash of the record, chained to its PrevHash. The
+// record's own Hash is not part of it.
+func Hash(r *models.AuditRecord) string {
+	h := sha256.New()
+	for _, field := range []string{
+		r.PrevHash,
+		strconv.FormatInt(r.Sequence, 10),
+		r.Action,
+		r.Actor,
+		r.RequestID,
+		strconv.Itoa(r.AccountID),
+		strconv.Itoa(r.CounterpartyID),
+		string(r.Before),
+		string(r.After),
+		r.CreatedAt.UTC().Format(time.RFC3339Nano),
+	} {
+		fmt.Fprintf(h, "%d:%s\n", len(field), field)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// BrokenLinkError reports the first record that does not follow from the
+// records before it.
+type BrokenLinkError struct {
+	Sequence int64
+	Reason   string
+}
+
+func (e *BrokenLinkError) Error() string {
+	return fmt.Sprintf("audit log broken at record %d: %s", e.Sequence, e.Reason)
+}
+
+// Verifier walks the chain. Records must be passed to Check in order of
+// sequence, starting with the first.
+type Verifier struct {
+	next     int64
+	prevHash string
+}
+
+func NewVerifier() *Verifier {
+	return &Verifier{next: 1, prevHash: Genesis}
+}
+
+// Check checks that the record is the next one in the chain and that its
+// hash matches its contents, returning a *BrokenLinkError when it is not.
+// After an error the verifier must not be used again.
+func (v *Verifier) Check(r *models.AuditRecord) error {
+	switch {
+	case r.Sequence < v.next:
+		return &BrokenLinkError{Sequence: r.Sequence, Reason: "is out of order"}
+	case r.Sequence > v.next:
+		return &BrokenLinkError{Sequence: v.next, Reason: "is missing"}
+	case r.PrevHash != v.prevHash:
+		return &BrokenLinkError{Sequence: r.Sequence, Reason: "does not link to the record before it"}
+	case Hash(r) != r.Hash:
+		return &BrokenLinkError{Sequence: r.Sequence, Reason: "does not match its hash"}
+	}
+	v.next, v.prevHash = r.Sequence+1, r.Hash
+	return nil
+}
+
+// Head returns the sequence and hash of the last record checked, or zero
+// and Genesis before the first. Keeping the head somewhere else lets a
+// later check tell whether records were cut off the end of the log.
+func (v *Verifier) Head() (int64, string) {
+	return v.next - 1, v.prevHash
+}
@@ -0,0 +1,127 @@
+package auditlog
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+func record(sequence int64, prevHash string, after string) *models.AuditRecord {
+	r := &models.AuditRecord{
+		Sequence:  sequence,
+		Action:    models.AuditDeposit,
+		Actor:     "customer:4",
+		RequestID: "req-1",
+		AccountID: 10,
+		Before:    json.RawMessage(`[{"account_id":10,"balance":100.00}]`),
+		After:     json.RawMessage(after),
+		CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 123456000, time.UTC),
+		PrevHash:  prevHash,
+	}
+	r.Hash = Hash(r)
+	return r
+}
+
+// chain returns n records, each linked to the one before.
+func chain(n int) []*models.AuditRecord {
+	var records []*models.AuditRecord
+	prev := Genesis
+	for i := 1; i <= n; i++ {
+		r := record(int64(i), prev, `[{"account_id":10,"balance":150.00}]`)
+		records = append(records, r)
+		prev = r.Hash
+	}
+	return records
+}
+
+func TestHash(t *testing.T) {
+	r := record(1, Genesis, `[{"account_id":10,"balance":150.00}]`)
+	// Computed independently from the format in the package comment.
+	assert.Equal(t, "aa00f7a3b0114e05172f6e5c6e0204ab916ef52e3854883d2b802f2d5b59153a", r.Hash)
+
+	// The time zone the record is read back in does not matter.
+	r.CreatedAt = r.CreatedAt.In(time.FixedZone("BRT", -3*60*60))
+	assert.Equal(t, r.Hash, Hash(r))
+}
+
+func TestVerifier(t *testing.T) {
+	v := NewVerifier()
+	sequence, head := v.Head()
+	assert.Equal(t, int64(0), sequence)
+	assert.Equal(t, Genesis, head)
+
+	records := chain(3)
+	for _, r := range records {
+		assert.NoError(t, v.Check(r))
+	}
+	sequence, head = v.Head()
+	assert.Equal(t, int64(3), sequence)
+	assert.Equal(t, records[2].Hash, head)
+}
+
+func TestVerifier_BrokenLinks(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(records []*models.AuditRecord) []*models.AuditRecord
+		want   BrokenLinkError
+	}{
+		{
+			name: "edited",
+			tamper: func(records []*models.AuditRecord) []*models.AuditRecord {
+				records[1].After = json.RawMessage(`[{"account_id":10,"balance":1500.00}]`)
+				return records
+			},
+			want: BrokenLinkError{Sequence: 2, Reason: "does not match its hash"},
+		},
+		{
+			name: "edited and rehashed",
+			tamper: func(records []*models.AuditRecord) []*models.AuditRecord {
+				records[1].Actor = "operator:1"
+				records[1].Hash = Hash(records[1])
+				return records
+			},
+			want: BrokenLinkError{Sequence: 3, Reason: "does not link to the record before it"},
+		},
+		{
+			name: "deleted",
+			tamper: func(records []*models.AuditRecord) []*models.AuditRecord {
+				return append(records[:1], records[2:]...)
+			},
+			want: BrokenLinkError{Sequence: 2, Reason: "is missing"},
+		},
+		{
+			name: "swapped",
+			tamper: func(records []*models.AuditRecord) []*models.AuditRecord {
+				records[1], records[2] = records[2], records[1]
+				return records
+			},
+			want: BrokenLinkError{Sequence: 2, Reason: "is missing"},
+		},
+		{
+			name: "replaced by a new chain",
+			tamper: func(records []*models.AuditRecord) []*models.AuditRecord {
+				records[0] = record(1, Genesis, `[{"account_id":10,"balance":999.00}]`)
+				return records
+			},
+			want: BrokenLinkError{Sequence: 2, Reason: "does not link to the record before it"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier()
+			var err error
+			for _, r := range tt.tamper(chain(4)) {
+				if err = v.Check(r); err != nil {
+					break
+				}
+			}
+			var broken *BrokenLinkError
+			assert.ErrorAs(t, err, &broken)
+			assert.Equal(t, tt.want, *broken)
+		})
+	}
+}
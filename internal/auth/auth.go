@@ -0,0 +1,275 @@
+// Package auth issues and verifies the API's access tokens and hashes
+// customer passwords.
+//
+// Access tokens are short-lived HS256 JWTs whose subject is the customer
+// or operator ID and whose role claim says which of the two it is. Each
+// token names the key that signed it in its kid header, so keys
+// can be rotated without logging everyone out: put the new key first in
+// the KeySet and drop the old one once its tokens have expired.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrUnauthenticated    = errors.New("authentication required")
+	ErrInvalidToken       = errors.New("invalid or expired access token")
+	ErrInvalidCredentials = errors.New("invalid tax ID or password")
+	ErrForbidden          = errors.New("not allowed to access this resource")
+)
+
+// MinSecretLength is the shortest signing secret accepted, in bytes.
+const MinSecretLength = 32
+
+// Issuer is the iss claim of every access token.
+const Issuer = "gobank"
+
+// Key is an HMAC secret used to sign access tokens. ID is written to the
+// kid header of the tokens it signs.
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// KeySet holds the signing keys. The first key signs new tokens; all of
+// them verify.
+type KeySet struct {
+	keys []Key
+}
+
+func NewKeySet(keys ...Key) (*KeySet, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one signing key is required")
+	}
+	seen := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		if k.ID == "" {
+			return nil, errors.New("signing key ID is empty")
+		}
+		if seen[k.ID] {
+			return nil, fmt.Errorf("duplicate signing key ID %q", k.ID)
+		}
+		if len(k.Secret) < MinSecretLength {
+			return nil, fmt.Errorf("signing key %q must be at least %d bytes", k.ID, MinSecretLength)
+		}
+		seen[k.ID] = true
+	}
+	return &KeySet{keys: keys}, nil
+}
+
+// ParseKeySet reads keys written as "kid:secret,kid:secret". The first key
+// signs new tokens.
+func ParseKeySet(s string) (*KeySet, error) {
+	var keys []Key
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("signing key %q must be written as kid:secret", entry)
+		}
+		keys = append(keys, Key{ID: id, Secret: []byte(secret)})
+	}
+	return NewKeySet(keys...)
+}
+
+func (s *KeySet) signing() Key {
+	return s.keys[0]
+}
+
+func (s *KeySet) lookup(id string) ([]byte, bool) {
+	for _, k := range s.keys {
+		if k.ID == id {
+			return k.Secret, true
+		}
+	}
+	return nil, false
+}
+
+// Principal is the authenticated caller: a customer, identified by
+// CustomerID, or an operator, identified by OperatorID. RequestID is the
+// ID of the request the caller made, recorded with the changes it makes.
+type Principal struct {
+	CustomerID int
+	OperatorID int
+	Role       Role
+	RequestID  string
+}
+
+// IsOperator reports whether the caller is a back-office operator.
+func (p *Principal) IsOperator() bool {
+	return p.Role.IsOperator()
+}
+
+// Scope names the caller uniquely across customers and operators, e.g.
+// "42" or "operator:3".
+func (p *Principal) Scope() string {
+	if p.IsOperator() {
+		return "operator:" + strconv.Itoa(p.OperatorID)
+	}
+	return strconv.Itoa(p.CustomerID)
+}
+
+type principalKey struct{}
+
+// NewContext returns a copy of ctx carrying p.
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored by NewContext, if any.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
+
+// TokenIssuer signs and verifies access tokens.
+type TokenIssuer struct {
+	keys      *KeySet
+	accessTTL time.Duration
+	now       func() time.Time
+}
+
+func NewTokenIssuer(keys *KeySet, accessTTL time.Duration) *TokenIssuer {
+	return &TokenIssuer{keys: keys, accessTTL: accessTTL, now: time.Now}
+}
+
+// AccessTTL is how long new access tokens stay valid.
+func (t *TokenIssuer) AccessTTL() time.Duration {
+	return t.accessTTL
+}
+
+// claims are the claims of an access token. Tokens issued before roles
+// existed have no role and belong to customers.
+type claims struct {
+	jwt.RegisteredClaims
+	Role Role `json:"role,omitempty"`
+}
+
+// IssueAccessToken returns a signed access token for the customer and its
+// expiry.
+func (t *TokenIssuer) IssueAccessToken(customerID int) (string, time.Time, error) {
+	return t.issue(customerID, RoleCustomer)
+}
+
+// IssueOperatorToken returns a signed access token for the operator and
+// its expiry.
+func (t *TokenIssuer) IssueOperatorToken(operatorID int, role Role) (string, time.Time, error) {
+	if !role.IsOperator() {
+		return "", time.Time{}, fmt.Errorf("%q is not an operator role", role)
+	}
+	return t.issue(operatorID, role)
+}
+
+func (t *TokenIssuer) issue(subject int, role Role) (string, time.Time, error) {
+	now := t.now()
+	expiresAt := now.Add(t.accessTTL)
+	claims := claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    Issuer,
+			Subject:   strconv.Itoa(subject),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Role: role,
+	}
+
+	key := t.keys.signing()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = key.ID
+	signed, err := token.SignedString(key.Secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// VerifyAccessToken checks the token's signature, issuer and expiry and
+// returns the customer or operator it was issued to. Any failure is
+// ErrInvalidToken.
+func (t *TokenIssuer) VerifyAccessToken(token string) (*Principal, error) {
+	var claims claims
+	_, err := jwt.ParseWithClaims(token, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		secret, ok := t.keys.lookup(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		return secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(Issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(t.now),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	subject, err := strconv.Atoi(claims.Subject)
+	if err != nil || subject <= 0 {
+		return nil, fmt.Errorf("%w: bad subject", ErrInvalidToken)
+	}
+	switch role := claims.Role; {
+	case role == "" || role == RoleCustomer:
+		return &Principal{CustomerID: subject, Role: RoleCustomer}, nil
+	case role.IsOperator():
+		return &Principal{OperatorID: subject, Role: role}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown role %q", ErrInvalidToken, role)
+	}
+}
+
+// NewRefreshToken returns a random opaque refresh token. Only its hash,
+// from HashRefreshToken, is stored.
+func NewRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashRefreshToken returns the hex SHA-256 of a refresh token.
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// HashPassword hashes a password with bcrypt.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// dummyHash is compared against when a login names no known customer, so
+// that unknown and known tax IDs take the same time to reject.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("gobank-dummy-password"), bcrypt.DefaultCost)
+
+// CheckPassword reports whether password matches hash. An empty hash
+// never matches, but still costs a bcrypt comparison.
+func CheckPassword(hash, password string) bool {
+	if hash == "" {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
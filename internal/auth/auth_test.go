@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	oldKey = Key{ID: "2026-04", Secret: []byte("old-secret-old-secret-old-secret")}
+	newKey = Key{ID: "2026-10", Secret: []byte("new-secret-new-secret-new-secret")}
+)
+
+func newIssuer(t *testing.T, now time.Time, keys ...Key) *TokenIssuer {
+	set, err := NewKeySet(keys...)
+	require.NoError(t, err)
+	issuer := NewTokenIssuer(set, 15*time.Minute)
+	issuer.now = func() time.Time { return now }
+	return issuer
+}
+
+func TestTokenIssuer_RoundTrip(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	issuer := newIssuer(t, now, newKey, oldKey)
+
+	token, expiresAt, err := issuer.IssueAccessToken(42)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(15*time.Minute), expiresAt)
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &jwt.RegisteredClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10", parsed.Header["kid"])
+	assert.Equal(t, "HS256", parsed.Header["alg"])
+
+	principal, err := issuer.VerifyAccessToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, &Principal{CustomerID: 42, Role: RoleCustomer}, principal)
+}
+
+func TestTokenIssuer_OperatorTokens(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	issuer := newIssuer(t, now, newKey)
+
+	token, _, err := issuer.IssueOperatorToken(7, RoleSupervisor)
+	require.NoError(t, err)
+	principal, err := issuer.VerifyAccessToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, &Principal{OperatorID: 7, Role: RoleSupervisor}, principal)
+	assert.True(t, principal.IsOperator())
+	assert.Equal(t, "operator:7", principal.Scope())
+
+	_, _, err = issuer.IssueOperatorToken(7, RoleCustomer)
+	assert.Error(t, err)
+
+	// A token with an unknown role is rejected rather than read as a
+	// customer's.
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer: Issuer, Subject: "7", ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+		Role: "root",
+	})
+	forged.Header["kid"] = newKey.ID
+	signed, err := forged.SignedString(newKey.Secret)
+	require.NoError(t, err)
+	_, err = issuer.VerifyAccessToken(signed)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestTokenIssuer_KeyRotation(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	before := newIssuer(t, now, oldKey)
+	token, _, err := before.IssueAccessToken(42)
+	require.NoError(t, err)
+
+	// The new key signs, the old one still verifies.
+	during := newIssuer(t, now, newKey, oldKey)
+	_, err = during.VerifyAccessToken(token)
+	assert.NoError(t, err)
+
+	// Once the old key is dropped its tokens stop working.
+	after := newIssuer(t, now, newKey)
+	_, err = after.VerifyAccessToken(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestTokenIssuer_RejectsBadTokens(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	issuer := newIssuer(t, now, newKey)
+	token, _, err := issuer.IssueAccessToken(42)
+	require.NoError(t, err)
+
+	later := newIssuer(t, now.Add(16*time.Minute), newKey)
+	_, err = later.VerifyAccessToken(token)
+	assert.ErrorIs(t, err, ErrInvalidToken, "expired")
+
+	parts := strings.Split(token, ".")
+	_, err = issuer.VerifyAccessToken(parts[0] + "." + parts[1] + ".AAAA")
+	assert.ErrorIs(t, err, ErrInvalidToken, "bad signature")
+
+	none := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{
+		Issuer: Issuer, Subject: "42", ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+	})
+	none.Header["kid"] = newKey.ID
+	unsigned, err := none.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+	_, err = issuer.VerifyAccessToken(unsigned)
+	assert.ErrorIs(t, err, ErrInvalidToken, "alg none")
+
+	foreign := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer: "someone-else", Subject: "42", ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+	})
+	foreign.Header["kid"] = newKey.ID
+	signed, err := foreign.SignedString(newKey.Secret)
+	require.NoError(t, err)
+	_, err = issuer.VerifyAccessToken(signed)
+	assert.ErrorIs(t, err, ErrInvalidToken, "wrong issuer")
+}
+
+func TestParseKeySet(t *testing.T) {
+	set, err := ParseKeySet(" 2026-10:new-secret-new-secret-new-secret , 2026-04:old-secret-old-secret-old-secret ")
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10", set.signing().ID)
+	_, ok := set.lookup("2026-04")
+	assert.True(t, ok)
+
+	for _, bad := range []string{
+		"",
+		"no-separator",
+		"k1:short",
+		":new-secret-new-secret-new-secret",
+		"k1:new-secret-new-secret-new-secret,k1:old-secret-old-secret-old-secret",
+	} {
+		_, err := ParseKeySet(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestPasswords(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	require.NoError(t, err)
+	assert.NotContains(t, hash, "correct horse")
+
+	assert.True(t, CheckPassword(hash, "correct horse"))
+	assert.False(t, CheckPassword(hash, "battery staple"))
+	assert.False(t, CheckPassword("", ""))
+}
+
+func TestRefreshTokens(t *testing.T) {
+	a, err := NewRefreshToken()
+	require.NoError(t, err)
+	b, err := NewRefreshToken()
+	require.NoError(t, err)
+
+	assert.NotEqual(t, a, b)
+	assert.Len(t, HashRefreshToken(a), 64)
+	assert.Equal(t, HashRefreshToken(a), HashRefreshToken(a))
+}
+
+func TestPrincipalContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	ctx := NewContext(context.Background(), &Principal{CustomerID: 3})
+	p, ok := FromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, 3, p.CustomerID)
+	assert.Equal(t, "3", p.Scope())
+}
@@ -0,0 +1,89 @@
+package auth
+
+// Role is what a principal is allowed to do. Customers act on their own
+// accounts; the other roles are back-office operators who act on any
+// account.
+type Role string
+
+const (
+	RoleCustomer   Role = "customer"
+	RoleTeller     Role = "teller"
+	RoleSupervisor Role = "supervisor"
+	RoleAuditor    Role = "auditor"
+)
+
+// OperatorRoles lists the roles an operator can be given.
+var OperatorRoles = []Role{RoleTeller, RoleSupervisor, RoleAuditor}
+
+// Valid reports whether r is a known role.
+func (r Role) Valid() bool {
+	_, ok := permissions[r]
+	return ok
+}
+
+// IsOperator reports whether r is a back-office role.
+func (r Role) IsOperator() bool {
+	return r.Valid() && r != RoleCustomer
+}
+
+// Can reports whether r grants the permission.
+func (r Role) Can(p Permission) bool {
+	for _, granted := range permissions[r] {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Permission is an action on accounts, on transfers waiting for approval,
+// on overdraft limits, interest rates, fees and exchange rates, on webhook
+// subscriptions, on the audit trail or on the runtime metrics.
+type Permission string
+
+const (
+	PermViewAccount     Permission = "account:view"
+	PermOpenAccount     Permission = "account:open"
+	PermDeposit         Permission = "account:deposit"
+	PermWithdraw        Permission = "account:withdraw"
+	PermTransfer        Permission = "account:transfer"
+	PermCloseAccount    Permission = "account:close"
+	PermHoldFunds       Permission = "account:hold"
+	PermFreezeAccount   Permission = "account:freeze"
+	PermUnfreezeAccount Permission = "account:unfreeze"
+	PermActivateAccount Permission = "account:activate"
+	PermCorrectBalance  Permission = "account:correct_balance"
+	PermReviewTransfer  Permission = "transfer:review"
+	PermManageOverdraft Permission = "overdraft:manage"
+	PermManageInterest  Permission = "interest:manage"
+	PermManageFees      Permission = "fees:manage"
+	PermManageFX        Permission = "fx:manage"
+	// PermManageWebhooks is for a customer's own subscriptions; partner
+	// subscriptions get the events of every account.
+	PermManageWebhooks        Permission = "webhooks:manage"
+	PermManagePartnerWebhooks Permission = "webhooks:manage_partners"
+	PermViewAudit             Permission = "audit:view"
+	PermViewMetrics           Permission = "metrics:view"
+)
+
+// permissions is the permission matrix. Customers are further limited to
+// their own accounts.
+var permissions = map[Role][]Permission{
+	RoleCustomer: {
+		PermViewAccount, PermOpenAccount, PermDeposit, PermWithdraw, PermTransfer, PermCloseAccount, PermHoldFunds,
+		PermManageWebhooks,
+	},
+	RoleTeller: {
+		PermViewAccount, PermOpenAccount, PermDeposit, PermWithdraw, PermHoldFunds, PermFreezeAccount,
+		PermActivateAccount,
+	},
+	RoleSupervisor: {
+		PermViewAccount, PermOpenAccount, PermDeposit, PermWithdraw, PermTransfer, PermCloseAccount, PermHoldFunds,
+		PermFreezeAccount, PermUnfreezeAccount, PermActivateAccount, PermCorrectBalance, PermReviewTransfer, PermManageOverdraft,
+		PermManageInterest, PermManageFees, PermManageFX, PermManageWebhooks, PermManagePartnerWebhooks, PermViewAudit,
+		PermViewMetrics,
+	},
+	RoleAuditor: {
+		PermViewAccount, PermViewAudit, PermViewMetrics,
+	},
+}
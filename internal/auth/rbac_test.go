@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRole_Can(t *testing.T) {
+	tests := []struct {
+		role    Role
+		allowed []Permission
+		denied  []Permission
+	}{
+		{
+			role: RoleCustomer,
+			allowed: []Permission{PermViewAccount, PermDeposit, PermWithdraw, PermTransfer, PermCloseAccount, PermHoldFunds,
+				PermManageWebhooks},
+			denied: []Permission{PermFreezeAccount, PermUnfreezeAccount, PermCorrectBalance, PermReviewTransfer, PermManageOverdraft,
+				PermManageInterest, PermManageFees, PermManageFX, PermManagePartnerWebhooks, PermViewAudit, PermViewMetrics},
+		},
+		{
+			role:    RoleTeller,
+			allowed: []Permission{PermViewAccount, PermDeposit, PermWithdraw, PermHoldFunds, PermFreezeAccount},
+			denied: []Permission{PermTransfer, PermCloseAccount, PermUnfreezeAccount, PermCorrectBalance, PermReviewTransfer,
+				PermManageOverdraft, PermManageInterest, PermManageFees, PermManageFX, PermManageWebhooks, PermManagePartnerWebhooks,
+				PermViewAudit, PermViewMetrics},
+		},
+		{
+			role: RoleSupervisor,
+			allowed: []Permission{PermViewAccount, PermDeposit, PermTransfer, PermCloseAccount,
+				PermFreezeAccount, PermUnfreezeAccount, PermCorrectBalance, PermReviewTransfer, PermManageOverdraft,
+				PermManageInterest, PermManageFees, PermManageFX, PermManageWebhooks, PermManagePartnerWebhooks, PermViewAudit, PermViewMetrics},
+		},
+		{
+			role:    RoleAuditor,
+			allowed: []Permission{PermViewAccount, PermViewAudit, PermViewMetrics},
+			denied: []Permission{PermDeposit, PermWithdraw, PermTransfer, PermHoldFunds, PermFreezeAccount, PermCorrectBalance, PermReviewTransfer,
+				PermManageOverdraft, PermManageInterest, PermManageFees, PermManageFX, PermManageWebhooks, PermManagePartnerWebhooks},
+		},
+		{
+			role:   "root",
+			denied: []Permission{PermViewAccount},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			for _, p := range tt.allowed {
+				assert.True(t, tt.role.Can(p), p)
+			}
+			for _, p := range tt.denied {
+				assert.False(t, tt.role.Can(p), p)
+			}
+		})
+	}
+}
+
+func TestRole_IsOperator(t *testing.T) {
+	assert.False(t, RoleCustomer.IsOperator())
+	assert.False(t, Role("root").IsOperator())
+	for _, r := range OperatorRoles {
+		assert.True(t, r.IsOperator(), r)
+	}
+}
@@ -0,0 +1,94 @@
+// Package calendar tells business days apart from weekends and holidays.
+//
+// Holidays are read from a text file with one date per line in the form
+// 2006-01-02. Blank lines are skipped and anything after a # is a
+// comment. Days are compared by their calendar date in UTC.
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Calendar is a set of holidays. The zero value has none, so only
+// weekends are not business days.
+type Calendar struct {
+	holidays map[string]bool
+}
+
+// New returns a calendar with the given holidays.
+func New(holidays ...time.Time) *Calendar {
+	c := &Calendar{holidays: make(map[string]bool, len(holidays))}
+	for _, day := range holidays {
+		c.holidays[key(day)] = true
+	}
+	return c
+}
+
+// Load reads the holidays in the file at path.
+func Load(path string) (*Calendar, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	c, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Parse reads holidays, one per line. Errors name the offending line.
+func Parse(r io.Reader) (*Calendar, error) {
+	var holidays []time.Time
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		day, err := time.Parse(time.DateOnly, line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q", n, line)
+		}
+		holidays = append(holidays, day)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return New(holidays...), nil
+}
+
+func key(day time.Time) string {
+	return day.UTC().Format(time.DateOnly)
+}
+
+// IsHoliday reports whether day is one of the calendar's holidays.
+func (c *Calendar) IsHoliday(day time.Time) bool {
+	return c.holidays[key(day)]
+}
+
+// IsBusinessDay reports whether day is a weekday and not a holiday.
+func (c *Calendar) IsBusinessDay(day time.Time) bool {
+	switch day.UTC().Weekday() {
+	case time.Saturday, time.Sunday:
+		return false
+	}
+	return !c.IsHoliday(day)
+}
+
+// NextBusinessDay returns day itself when it is a business day, or else
+// the first business day after it.
+func (c *Calendar) NextBusinessDay(day time.Time) time.Time {
+	for !c.IsBusinessDay(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
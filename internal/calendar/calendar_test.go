@@ -0,0 +1,56 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(s string) time.Time {
+	day, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return day
+}
+
+func TestParse(t *testing.T) {
+	c, err := Parse(strings.NewReader("# 2026\n2026-04-03 # Sexta-feira Santa\n\n  2026-04-21\n"))
+	require.NoError(t, err)
+	assert.True(t, c.IsHoliday(date("2026-04-03")))
+	assert.True(t, c.IsHoliday(date("2026-04-21")))
+	assert.False(t, c.IsHoliday(date("2026-04-22")))
+
+	_, err = Parse(strings.NewReader("2026-04-03\n21/04/2026\n"))
+	assert.EqualError(t, err, `line 2: invalid date "21/04/2026"`)
+}
+
+func TestCalendar_NextBusinessDay(t *testing.T) {
+	c := New(date("2026-04-03"), date("2026-12-25"))
+
+	tests := []struct {
+		day, want string
+	}{
+		{"2026-04-01", "2026-04-01"}, // Wednesday
+		{"2026-04-03", "2026-04-06"}, // Good Friday, then the weekend
+		{"2026-04-04", "2026-04-06"}, // Saturday
+		{"2026-12-25", "2026-12-28"}, // Christmas on a Friday
+	}
+	for _, tt := range tests {
+		assert.Equal(t, date(tt.want), c.NextBusinessDay(date(tt.day)), tt.day)
+	}
+
+	// The zero value only skips weekends.
+	var none Calendar
+	assert.Equal(t, date("2026-04-03"), none.NextBusinessDay(date("2026-04-03")))
+}
+
+func TestLoad(t *testing.T) {
+	c, err := Load("../../config/holidays.txt")
+	require.NoError(t, err)
+	assert.True(t, c.IsHoliday(date("2026-11-20")))
+	assert.False(t, c.IsBusinessDay(date("2027-02-09")))
+}
@@ -0,0 +1,40 @@
+// Package currency lists the ISO 4217 currencies accounts can hold.
+//
+// Amounts are kept in cents (see package money), so only currencies with
+// two decimal places are supported.
+package currency
+
+import "sort"
+
+// Default is the currency of accounts opened without one, and of every
+// account that existed before accounts had a currency.
+const Default = "BRL"
+
+var supported = map[string]bool{
+	"ARS": true, // Argentine peso
+	"AUD": true, // Australian dollar
+	"BRL": true, // Brazilian real
+	"CAD": true, // Canadian dollar
+	"CHF": true, // Swiss franc
+	"CNY": true, // Chinese yuan
+	"EUR": true, // Euro
+	"GBP": true, // Pound sterling
+	"MXN": true, // Mexican peso
+	"USD": true, // US dollar
+}
+
+// Valid reports whether code is a supported currency. Codes are upper
+// case, e.g. "USD".
+func Valid(code string) bool {
+	return supported[code]
+}
+
+// Codes returns the supported currencies in alphabetical order.
+func Codes() []string {
+	codes := make([]string, 0, len(supported))
+	for code := range supported {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
@@ -0,0 +1,24 @@
+package currency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValid(t *testing.T) {
+	assert.True(t, Valid("BRL"))
+	assert.True(t, Valid("USD"))
+	assert.False(t, Valid("usd"))
+	assert.False(t, Valid("JPY")) // no minor unit
+	assert.False(t, Valid(""))
+}
+
+func TestCodes(t *testing.T) {
+	codes := Codes()
+	assert.Contains(t, codes, Default)
+	assert.IsIncreasing(t, codes)
+	for _, code := range codes {
+		assert.Len(t, code, 3)
+	}
+}
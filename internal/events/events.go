@@ -0,0 +1,15 @@
+// Package events publishes the domain events relayed from the outbox.
+// Delivery is at least once: an event may be published again, with the
+// same ID, after a failure or a crash, so consumers should drop the IDs
+// they have already seen.
+package events
+
+import (
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+// Publisher delivers events. An error means the event was not delivered
+// and will be published again later.
+type Publisher interface {
+	Publish(event models.Event) error
+}
@@ -0,0 +1,85 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+var deposit = models.Event{
+	ID:        "0b9c3b5e-3f7e-4e43-9d8a-5c1f2a7e6d10",
+	Sequence:  4,
+	Type:      models.EventDeposit,
+	AccountID: 10,
+	Data:      json.RawMessage(`{"amount":100.00}`),
+	CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+}
+
+func TestInProcess(t *testing.T) {
+	p := NewInProcess()
+	assert.NoError(t, p.Publish(deposit), "no handlers")
+
+	var first, second []string
+	p.Subscribe(func(e models.Event) error {
+		first = append(first, e.ID)
+		return errors.New("consumer down")
+	})
+	p.Subscribe(func(e models.Event) error {
+		second = append(second, e.ID)
+		return nil
+	})
+
+	// A failing handler does not keep the event from the others.
+	err := p.Publish(deposit)
+	assert.EqualError(t, err, "consumer down")
+	assert.Equal(t, []string{deposit.ID}, first)
+	assert.Equal(t, []string{deposit.ID}, second)
+}
+
+func TestNDJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	p, err := OpenNDJSON(path)
+	require.NoError(t, err)
+
+	transfer := models.Event{ID: "7a1d2c44-96b1-4c1e-8e0f-2b3c4d5e6f70", Sequence: 5, Type: models.EventTransfer,
+		AccountID: 10, CounterpartyID: 11, Data: json.RawMessage(`{"amount":50.00}`), CreatedAt: deposit.CreatedAt}
+	require.NoError(t, p.Publish(deposit))
+	require.NoError(t, p.Publish(transfer))
+	require.NoError(t, p.Close())
+
+	// Reopening appends.
+	p, err = OpenNDJSON(path)
+	require.NoError(t, err)
+	require.NoError(t, p.Publish(deposit))
+	require.NoError(t, p.Close())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var got []models.Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e models.Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		got = append(got, e)
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, []models.Event{deposit, transfer, deposit}, got)
+
+	line, err := json.Marshal(transfer)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"id": "7a1d2c44-96b1-4c1e-8e0f-2b3c4d5e6f70", "sequence": 5, "type": "transfer.completed",
+		"account_id": 10, "counterparty_id": 11, "data": {"amount": 50.00}, "created_at": "2026-03-01T12:00:00Z"
+	}`, string(line))
+}
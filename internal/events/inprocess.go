@@ -0,0 +1,44 @@
+package events
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+// Handler handles an event. Returning an error makes the event be
+// published again, to every handler.
+type Handler func(event models.Event) error
+
+// InProcess delivers events synchronously to the handlers subscribed to
+// it, in the order they subscribed.
+type InProcess struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+func NewInProcess() *InProcess {
+	return &InProcess{}
+}
+
+func (p *InProcess) Subscribe(handler Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handlers = append(p.handlers, handler)
+}
+
+// Publish calls every handler, even after one fails, and returns their
+// errors joined.
+func (p *InProcess) Publish(event models.Event) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var errs []error
+	for _, handler := range p.handlers {
+		if err := handler(event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,52 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+// NDJSON writes each event as one line of JSON.
+type NDJSON struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewNDJSON(w io.Writer) *NDJSON {
+	return &NDJSON{w: w}
+}
+
+// OpenNDJSON appends events to the file at path, creating it if needed.
+func OpenNDJSON(path string) (*NDJSON, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return NewNDJSON(f), nil
+}
+
+// Publish writes the event and its newline in a single write, so lines
+// are never interleaved.
+func (p *NDJSON) Publish(event models.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.w.Write(line)
+	return err
+}
+
+// Close closes the underlying writer, if it can be closed.
+func (p *NDJSON) Close() error {
+	if c, ok := p.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
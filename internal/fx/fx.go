@@ -0,0 +1,258 @@
+// Package fx converts amounts between currencies.
+//
+// A rate says how many units of the quote currency one unit of the base
+// currency buys, e.g. USD/BRL 5.4321. Rates are exact decimals with eight
+// places, kept as integers like money.Money. The bank's spread, in basis
+// points, is taken off the rate before an amount is converted, and the
+// converted amount is rounded to the cent half-to-even.
+package fx
+
+import (
+	"bufio"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gregoryAlvim/gobank/internal/currency"
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
+// RateScale is the number of decimal places kept by Rate.
+const RateScale = 8
+
+const rateUnit = 100_000_000
+
+// MaxSpreadBps is the largest spread a rate can have: 100%.
+const MaxSpreadBps = 10_000
+
+var (
+	ErrInvalidRate = errors.New("invalid exchange rate")
+	// ErrAmountTooSmall is returned when an amount converts to less than
+	// a cent.
+	ErrAmountTooSmall = errors.New("amount is too small to convert")
+)
+
+// Rate is an exchange rate in units of 10^-8.
+type Rate int64
+
+// decimal matches the rates ParseRate accepts: digits, optionally followed
+// by a point and more digits. Signs, exponents, fractions and other bases
+// that big.Rat would take are not rates.
+var decimal = regexp.MustCompile(`^\d+(\.\d+)?$`)
+
+// ParseRate converts a decimal string such as "5.4321" into a Rate,
+// rounding to eight places half-to-even. Rates that round to zero are
+// rejected.
+func ParseRate(s string) (Rate, error) {
+	trimmed := strings.TrimSpace(s)
+	if !decimal.MatchString(trimmed) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidRate, s)
+	}
+	r, ok := new(big.Rat).SetString(trimmed)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidRate, s)
+	}
+	rate, err := fromRat(r.Mul(r, big.NewRat(rateUnit, 1)))
+	if err != nil {
+		return 0, err
+	}
+	if !rate.IsPositive() {
+		return 0, fmt.Errorf("%w: %q must be positive", ErrInvalidRate, s)
+	}
+	return rate, nil
+}
+
+// MustParseRate is like ParseRate but panics on error. It is intended for
+// tests.
+func MustParseRate(s string) Rate {
+	r, err := ParseRate(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// fromRat rounds a value expressed in units of 10^-8 to an integer
+// half-to-even.
+func fromRat(r *big.Rat) (Rate, error) {
+	q, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if rem.Sign() != 0 {
+		twice := new(big.Int).Abs(rem)
+		twice.Lsh(twice, 1)
+		if c := twice.Cmp(r.Denom()); c > 0 || c == 0 && q.Bit(0) == 1 {
+			q.Add(q, big.NewInt(int64(r.Num().Sign())))
+		}
+	}
+	if !q.IsInt64() {
+		return 0, fmt.Errorf("%w: out of range", ErrInvalidRate)
+	}
+	return Rate(q.Int64()), nil
+}
+
+// IsPositive reports whether the rate is greater than zero.
+func (r Rate) IsPositive() bool { return r > 0 }
+
+// ApplySpread returns the rate a customer gets once the bank's spread is
+// taken off. The spread must be below MaxSpreadBps.
+func (r Rate) ApplySpread(spreadBps int) (Rate, error) {
+	if spreadBps < 0 || spreadBps >= MaxSpreadBps {
+		return 0, fmt.Errorf("%w: spread of %d bps", ErrInvalidRate, spreadBps)
+	}
+	applied := new(big.Int).Mul(big.NewInt(int64(r)), big.NewInt(int64(MaxSpreadBps-spreadBps)))
+	return fromRat(new(big.Rat).SetFrac(applied, big.NewInt(MaxSpreadBps)))
+}
+
+// Convert returns amount multiplied by the rate, rounded to the cent.
+// Positive amounts that round to zero fail with ErrAmountTooSmall.
+func (r Rate) Convert(amount money.Money) (money.Money, error) {
+	converted, err := amount.MulFrac(int64(r), rateUnit)
+	if err != nil {
+		return 0, err
+	}
+	if amount.IsPositive() && !converted.IsPositive() {
+		return 0, ErrAmountTooSmall
+	}
+	return converted, nil
+}
+
+// String formats the rate without trailing zeros, e.g. "5.4321".
+func (r Rate) String() string {
+	sign := ""
+	v := uint64(r)
+	if r < 0 {
+		sign = "-"
+		v = uint64(-r)
+	}
+	s := fmt.Sprintf("%s%d.%08d", sign, v/rateUnit, v%rateUnit)
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
+
+// MarshalJSON encodes the rate as a JSON number.
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON accepts either a JSON number or a string holding a decimal.
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	v, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
+
+// Scan implements sql.Scanner for DECIMAL columns.
+func (r *Rate) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*r = 0
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("fx: cannot scan %T", src)
+	}
+	v, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
+
+// Value implements driver.Valuer, binding the rate as a decimal string.
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// Entry is the rate and spread of one currency pair.
+type Entry struct {
+	Base      string
+	Quote     string
+	Rate      Rate
+	SpreadBps int
+}
+
+// Load reads the rates in the file at path.
+func Load(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// Parse reads rates with one pair per line: the base and quote currencies,
+// the rate and, optionally, the spread in basis points, separated by
+// spaces, e.g. "USD BRL 5.4321 50". Blank lines are skipped and anything
+// after a # is a comment.
+func Parse(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		entry, err := parseEntry(fields)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+func parseEntry(fields []string) (Entry, error) {
+	if len(fields) != 3 && len(fields) != 4 {
+		return Entry{}, fmt.Errorf("want base, quote, rate and spread, got %q", strings.Join(fields, " "))
+	}
+	entry := Entry{Base: fields[0], Quote: fields[1]}
+	for _, code := range []string{entry.Base, entry.Quote} {
+		if !currency.Valid(code) {
+			return Entry{}, fmt.Errorf("unsupported currency %q", code)
+		}
+	}
+	if entry.Base == entry.Quote {
+		return Entry{}, fmt.Errorf("%s/%s converts a currency to itself", entry.Base, entry.Quote)
+	}
+
+	var err error
+	if entry.Rate, err = ParseRate(fields[2]); err != nil {
+		return Entry{}, err
+	}
+	if len(fields) == 4 {
+		if entry.SpreadBps, err = strconv.Atoi(fields[3]); err != nil || entry.SpreadBps < 0 || entry.SpreadBps >= MaxSpreadBps {
+			return Entry{}, fmt.Errorf("invalid spread %q", fields[3])
+		}
+	}
+	return entry, nil
+}
@@ -0,0 +1,126 @@
+package fx
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Rate
+		str  string
+	}{
+		{"5.4321", 543210000, "5.4321"},
+		{"1", 100000000, "1"},
+		{"0.18409", 18409000, "0.18409"},
+		{"0.123456785", 12345678, "0.12345678"}, // tie goes to the even neighbour
+		{"0.123456775", 12345678, "0.12345678"},
+		{"0.00000001", 1, "0.00000001"},
+		{" 2.5 ", 250000000, "2.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseRate(tt.in)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.str, got.String())
+		})
+	}
+
+	for _, bad := range []string{"abc", "", "1/3", "1e3", "1e-8", "0x10", "+1", "-5.4", ".5", "1.", "0", "0.000000004",
+		"99999999999999999999"} {
+		_, err := ParseRate(bad)
+		assert.ErrorIs(t, err, ErrInvalidRate, bad)
+	}
+}
+
+func TestRate_Convert(t *testing.T) {
+	rate := MustParseRate("5.4321")
+
+	// 50 bps off 5.4321 is 5.40493950.
+	applied, err := rate.ApplySpread(50)
+	assert.NoError(t, err)
+	assert.Equal(t, "5.4049395", applied.String())
+	unchanged, err := rate.ApplySpread(0)
+	assert.NoError(t, err)
+	assert.Equal(t, rate, unchanged)
+
+	_, err = rate.ApplySpread(MaxSpreadBps)
+	assert.ErrorIs(t, err, ErrInvalidRate)
+
+	converted, err := applied.Convert(money.New(100, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("540.49"), converted)
+
+	// 0.01 * 5.4049395 = 0.054049395 rounds to 0.05.
+	converted, err = applied.Convert(money.FromCents(1))
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromCents(5), converted)
+
+	_, err = MustParseRate("0.1841").Convert(money.FromCents(2))
+	assert.ErrorIs(t, err, ErrAmountTooSmall)
+
+	// Rate times spread does not fit in int64, but the result does.
+	applied, err = Rate(math.MaxInt64 / 2).ApplySpread(1)
+	assert.NoError(t, err)
+	assert.Equal(t, Rate(4611224849825545164), applied)
+}
+
+func TestRate_JSON(t *testing.T) {
+	var v struct {
+		Rate Rate `json:"rate"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(`{"rate": 0.1841}`), &v))
+	assert.Equal(t, Rate(18410000), v.Rate)
+	assert.NoError(t, json.Unmarshal([]byte(`{"rate": "0.1841"}`), &v))
+	assert.Equal(t, Rate(18410000), v.Rate)
+
+	data, err := json.Marshal(v)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"rate": 0.1841}`, string(data))
+}
+
+func TestRate_Scan(t *testing.T) {
+	var r Rate
+	assert.NoError(t, r.Scan([]byte("5.43210000")))
+	assert.Equal(t, MustParseRate("5.4321"), r)
+
+	// NULL, as from a LEFT JOIN with no conversion.
+	assert.NoError(t, r.Scan(nil))
+	assert.Equal(t, Rate(0), r)
+
+	assert.ErrorIs(t, r.Scan("1/3"), ErrInvalidRate)
+}
+
+func TestParse(t *testing.T) {
+	entries, err := Parse(strings.NewReader(`
+# Mid-market rates
+USD BRL 5.4321 50
+BRL USD 0.1841   # no spread
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{Base: "USD", Quote: "BRL", Rate: MustParseRate("5.4321"), SpreadBps: 50},
+		{Base: "BRL", Quote: "USD", Rate: MustParseRate("0.1841")},
+	}, entries)
+
+	for _, bad := range []string{
+		"USD BRL",
+		"USD XYZ 1.5",
+		"USD USD 1",
+		"USD BRL abc",
+		"USD BRL 0",
+		"USD BRL 5.4 -1",
+		"USD BRL 5.4 10000",
+	} {
+		_, err := Parse(strings.NewReader("\n" + bad))
+		assert.ErrorContains(t, err, "line 2", bad)
+	}
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/gregoryAlvim/gobank/internal/accountnumber"
+	"github.com/gregoryAlvim/gobank/internal/services"
+)
+
+// ResolveAccountNumbers lets the /account/{id} routes take an account
+// number (0001-00001234-5) wherever they take an internal account ID. The
+// number is swapped for the ID before the route's handler runs. Numbers
+// with a wrong check digit are rejected without touching the database.
+func ResolveAccountNumbers(service services.AccountServiceInterface) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			vars := mux.Vars(r)
+			ref, ok := vars["id"]
+			if !ok || !accountnumber.LooksLike(ref) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			number, err := accountnumber.Parse(ref)
+			if err != nil {
+				writeProblem(w, r, http.StatusBadRequest, CodeInvalidAccountNumber, "Invalid account number")
+				return
+			}
+			account, err := service.GetAccountByNumber(number)
+			if err != nil {
+				writeError(w, r, err)
+				return
+			}
+
+			resolved := make(map[string]string, len(vars))
+			for k, v := range vars {
+				resolved[k] = v
+			}
+			resolved["id"] = strconv.Itoa(account.ID)
+			next.ServeHTTP(w, mux.SetURLVars(r, resolved))
+		})
+	}
+}
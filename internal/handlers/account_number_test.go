@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/gregoryAlvim/gobank/internal/accountnumber"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	"github.com/gregoryAlvim/gobank/internal/services/mocks"
+)
+
+func newAccountRouter(service *mocks.AccountServiceInterface) *mux.Router {
+	handler := NewAccountHandler(service)
+	r := mux.NewRouter()
+	accounts := r.PathPrefix("/account").Subrouter()
+	accounts.Use(ResolveAccountNumbers(service))
+	accounts.HandleFunc("/{id}/balance", handler.GetBalance).Methods("GET")
+	accounts.HandleFunc("/{id}/transactions", handler.GetTransactions).Methods("GET")
+	accounts.HandleFunc("/{id}/deposit", handler.Deposit).Methods("POST")
+	accounts.HandleFunc("/{id}/withdraw", handler.Withdraw).Methods("POST")
+	accounts.HandleFunc("/transfer", handler.Transfer).Methods("POST")
+	accounts.HandleFunc("/{id}", handler.CloseAccount).Methods("DELETE")
+	return r
+}
+
+func TestResolveAccountNumbers(t *testing.T) {
+	number := accountnumber.Number{Branch: "0001", Account: "00000010", CheckDigit: "6"}
+
+	tests := []struct {
+		name       string
+		path       string
+		setup      func(*mocks.AccountServiceInterface)
+		wantStatus int
+	}{
+		{
+			name: "internal ID",
+			path: "/account/10/balance",
+			setup: func(m *mocks.AccountServiceInterface) {
+				m.On("GetBalance", 10).Return(&models.Balance{Ledger: money.New(100, 0), Available: money.New(100, 0)}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "account number",
+			path: "/account/0001-00000010-6/balance",
+			setup: func(m *mocks.AccountServiceInterface) {
+				m.On("GetAccountByNumber", number).Return(&models.Account{ID: 10}, nil)
+				m.On("GetBalance", 10).Return(&models.Balance{Ledger: money.New(100, 0), Available: money.New(100, 0)}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "wrong check digit",
+			path:       "/account/0001-00000010-7/balance",
+			setup:      func(m *mocks.AccountServiceInterface) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "malformed number",
+			path:       "/account/0001-10-6/balance",
+			setup:      func(m *mocks.AccountServiceInterface) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unknown number",
+			path: "/account/0001-00000010-6/balance",
+			setup: func(m *mocks.AccountServiceInterface) {
+				m.On("GetAccountByNumber", number).Return(nil, repositories.ErrAccountNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.AccountServiceInterface)
+			tt.setup(mockService)
+
+			req, err := http.NewRequest("GET", tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+
+			newAccountRouter(mockService).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccountHandler_Transfer_ByAccountNumber(t *testing.T) {
+	mockService := new(mocks.AccountServiceInterface)
+
+	body := `{"from_account": "0001-00000010-6", "to_account": "0001-00000011-4", "amount": 25}`
+	req, err := http.NewRequest("POST", "/account/transfer", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = withPrincipal(req, 1)
+	rr := httptest.NewRecorder()
+
+	mockService.On("GetAccountByNumber", accountnumber.Number{Branch: "0001", Account: "00000010", CheckDigit: "6"}).Return(&models.Account{ID: 10, CustomerID: 1}, nil)
+	mockService.On("GetAccountByNumber", accountnumber.Number{Branch: "0001", Account: "00000011", CheckDigit: "4"}).Return(&models.Account{ID: 11, CustomerID: 2}, nil)
+	mockService.On("GetAccount", 10).Return(&models.Account{ID: 10, CustomerID: 1}, nil)
+	mockService.On("Transfer", mock.Anything, 10, 11, money.New(25, 0), int64(0)).Return(nil, nil)
+
+	newAccountRouter(mockService).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+
+	// A bad check digit is rejected before any lookup.
+	mockService = new(mocks.AccountServiceInterface)
+	body = `{"from_account": "0001-00000010-6", "to_account": "0001-00000011-5", "amount": 25}`
+	req, err = http.NewRequest("POST", "/account/transfer", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr = httptest.NewRecorder()
+
+	newAccountRouter(mockService).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "GetAccountByNumber", mock.Anything)
+	mockService.AssertNotCalled(t, "Transfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/services"
+)
+
+// ListTransferApprovals lists transfers above the approval threshold,
+// newest first. It accepts the optional filters status and account_id,
+// limit and the cursor returned by the previous page.
+func (h *AccountHandler) ListTransferApprovals(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseApprovalFilter(r.URL.Query())
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.service.ListTransferApprovals(filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// ApproveTransfer executes a pending transfer. The body, with an optional
+// reason, may be omitted.
+func (h *AccountHandler) ApproveTransfer(w http.ResponseWriter, r *http.Request) {
+	h.reviewTransfer(w, r, h.service.ApproveTransfer)
+}
+
+// RejectTransfer cancels a pending transfer. The body carries the reason.
+func (h *AccountHandler) RejectTransfer(w http.ResponseWriter, r *http.Request) {
+	h.reviewTransfer(w, r, h.service.RejectTransfer)
+}
+
+type reviewFunc func(approvalID int64, reviewer *auth.Principal, request models.ReviewRequest) (*models.TransferApproval, error)
+
+func (h *AccountHandler) reviewTransfer(w http.ResponseWriter, r *http.Request, review reviewFunc) {
+	id, err := strconv.ParseInt(mux.Vars(r)["approval_id"], 10, 64)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid approval ID")
+		return
+	}
+
+	var req models.ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+		return
+	}
+
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		writeError(w, r, auth.ErrUnauthenticated)
+		return
+	}
+	approval, err := review(id, principal, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(approval)
+}
+
+func parseApprovalFilter(q url.Values) (models.ApprovalFilter, error) {
+	var filter models.ApprovalFilter
+
+	switch status := q.Get("status"); status {
+	case "", models.ApprovalPending, models.ApprovalApproved, models.ApprovalRejected, models.ApprovalExpired:
+		filter.Status = status
+	default:
+		return filter, errors.New("Invalid status")
+	}
+
+	positive := func(name string, dst *int) error {
+		v := q.Get(name)
+		if v == "" {
+			return nil
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return errors.New("Invalid " + name)
+		}
+		*dst = n
+		return nil
+	}
+	if err := positive("account_id", &filter.AccountID); err != nil {
+		return filter, err
+	}
+	if err := positive("limit", &filter.Limit); err != nil {
+		return filter, err
+	}
+	if v := q.Get("cursor"); v != "" {
+		beforeID, err := services.DecodeCursor(v)
+		if err != nil {
+			return filter, errors.New("Invalid cursor")
+		}
+		filter.BeforeID = beforeID
+	}
+	return filter, nil
+}
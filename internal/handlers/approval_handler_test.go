@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	repomocks "github.com/gregoryAlvim/gobank/internal/repositories/mocks"
+	"github.com/gregoryAlvim/gobank/internal/services"
+	"github.com/gregoryAlvim/gobank/internal/services/mocks"
+)
+
+func TestAccountHandler_Transfer_AwaitingApproval(t *testing.T) {
+	repo := repomocks.NewAccountRepository(t)
+	service := services.NewAccountService(repo)
+	service.ApprovalThreshold = money.New(10000, 0)
+	handler := NewAccountHandler(service)
+
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo.On("GetAccount", 10).Return(&models.Account{ID: 10, CustomerID: 3, Currency: "BRL"}, nil)
+	repo.On("CreateTransferApproval", mock.MatchedBy(func(a *models.TransferApproval) bool {
+		ttl := time.Until(a.ExpiresAt)
+		return a.FromAccountID == 10 && a.ToAccountID == 11 && a.Amount == money.New(15000, 0) &&
+			a.RequestedByCustomerID == 3 && ttl > 23*time.Hour && ttl <= services.DefaultApprovalTTL
+	})).Run(func(args mock.Arguments) {
+		a := args.Get(0).(*models.TransferApproval)
+		a.ID, a.Status, a.CreatedAt = 1, models.ApprovalPending, createdAt
+		a.ExpiresAt = createdAt.Add(services.DefaultApprovalTTL)
+	}).Return(nil)
+
+	req, _ := http.NewRequest("POST", "/account/transfer", bytes.NewBufferString(`{"from_id": 10, "to_id": 11, "amount": 15000}`))
+	rr := httptest.NewRecorder()
+	handler.Transfer(rr, withPrincipal(req, 3))
+
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.JSONEq(t, `{
+		"message": "Transfer awaiting approval",
+		"approval": {
+			"id": 1, "from_id": 10, "to_id": 11, "amount": 15000.00, "fee": 0.00, "status": "pending",
+			"requested_by_customer_id": 3,
+			"expires_at": "2026-03-02T12:00:00Z", "created_at": "2026-03-01T12:00:00Z"
+		}
+	}`, rr.Body.String())
+
+	// Transfers up to the threshold go straight through.
+	repo.On("TransferTx", 10, 11, money.New(10000, 0), int64(0), models.Actor{CustomerID: 3}).Return(nil)
+
+	req, _ = http.NewRequest("POST", "/account/transfer", bytes.NewBufferString(`{"from_id": 10, "to_id": 11, "amount": 10000}`))
+	rr = httptest.NewRecorder()
+	handler.Transfer(rr, withPrincipal(req, 3))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"message": "Transfer successful"}`, rr.Body.String())
+}
+
+func TestAccountHandler_ListTransferApprovals(t *testing.T) {
+	service := new(mocks.AccountServiceInterface)
+	handler := NewAccountHandler(service)
+
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service.On("ListTransferApprovals", models.ApprovalFilter{Status: "pending", AccountID: 10, Limit: 10}).Return(&models.ApprovalPage{
+		Approvals: []models.TransferApproval{{
+			ID: 4, FromAccountID: 10, ToAccountID: 11, Amount: money.New(15000, 0), Status: "pending",
+			RequestedByOperatorID: 2, ExpiresAt: createdAt.Add(24 * time.Hour), CreatedAt: createdAt,
+		}},
+		NextCursor: services.EncodeCursor(4),
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/transfer-approvals?status=pending&account_id=10&limit=10", nil)
+	rr := httptest.NewRecorder()
+	handler.ListTransferApprovals(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{
+		"approvals": [{
+			"id": 4, "from_id": 10, "to_id": 11, "amount": 15000.00, "fee": 0.00, "status": "pending",
+			"requested_by_operator_id": 2,
+			"expires_at": "2026-03-02T12:00:00Z", "created_at": "2026-03-01T12:00:00Z"
+		}],
+		"next_cursor": "`+services.EncodeCursor(4)+`"
+	}`, rr.Body.String())
+
+	for _, query := range []string{"status=done", "account_id=abc", "limit=0", "cursor=not-a-cursor"} {
+		req, _ := http.NewRequest("GET", "/transfer-approvals?"+query, nil)
+		rr := httptest.NewRecorder()
+		handler.ListTransferApprovals(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+	service.AssertExpectations(t)
+}
+
+func TestAccountHandler_ReviewTransfer(t *testing.T) {
+	// Approval 1 was requested by operator 2, approval 2 by a customer.
+	pending := func(id int64) *models.TransferApproval {
+		a := &models.TransferApproval{ID: id, FromAccountID: 10, ToAccountID: 11, Amount: money.New(15000, 0), Status: models.ApprovalPending}
+		if id == 1 {
+			a.RequestedByOperatorID = 2
+		} else {
+			a.RequestedByCustomerID = 3
+		}
+		return a
+	}
+	reviewed := func(id int64, status, reason string) *models.TransferApproval {
+		a := pending(id)
+		a.Status, a.ReviewedBy, a.ReviewReason = status, 7, reason
+		return a
+	}
+
+	tests := []struct {
+		name       string
+		reject     bool
+		reviewer   *auth.Principal
+		approvalID string
+		body       string
+		setup      func(repo *repomocks.AccountRepository)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "approve without a body",
+			reviewer:   &auth.Principal{OperatorID: 7, Role: auth.RoleSupervisor},
+			approvalID: "1",
+			setup: func(repo *repomocks.AccountRepository) {
+				repo.On("GetTransferApproval", int64(1)).Return(pending(1), nil)
+				repo.On("ApproveTransfer", int64(1), models.Actor{OperatorID: 7}, "").Return(reviewed(1, models.ApprovalApproved, ""), nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"status":"approved"`,
+		},
+		{
+			name:       "reject a customer's transfer",
+			reject:     true,
+			reviewer:   &auth.Principal{OperatorID: 7, Role: auth.RoleSupervisor},
+			approvalID: "2",
+			body:       `{"reason": "unknown payee"}`,
+			setup: func(repo *repomocks.AccountRepository) {
+				repo.On("GetTransferApproval", int64(2)).Return(pending(2), nil)
+				repo.On("RejectTransfer", int64(2), models.Actor{OperatorID: 7}, "unknown payee").Return(reviewed(2, models.ApprovalRejected, "unknown payee"), nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"review_reason":"unknown payee"`,
+		},
+		{
+			name:       "approve own transfer",
+			reviewer:   &auth.Principal{OperatorID: 2, Role: auth.RoleSupervisor},
+			approvalID: "1",
+			setup: func(repo *repomocks.AccountRepository) {
+				repo.On("GetTransferApproval", int64(1)).Return(pending(1), nil)
+			},
+			wantStatus: http.StatusForbidden,
+			wantBody:   `"code":"self_approval"`,
+		},
+		{
+			name:       "reject without reason",
+			reject:     true,
+			reviewer:   &auth.Principal{OperatorID: 7, Role: auth.RoleSupervisor},
+			approvalID: "1",
+			body:       `{}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   `"field":"reason"`,
+		},
+		{
+			name:       "customer reviewer",
+			reviewer:   &auth.Principal{CustomerID: 3, Role: auth.RoleCustomer},
+			approvalID: "2",
+			wantStatus: http.StatusForbidden,
+			wantBody:   `"code":"forbidden"`,
+		},
+		{
+			name:       "already reviewed",
+			reviewer:   &auth.Principal{OperatorID: 7, Role: auth.RoleSupervisor},
+			approvalID: "1",
+			setup: func(repo *repomocks.AccountRepository) {
+				repo.On("GetTransferApproval", int64(1)).Return(pending(1), nil)
+				repo.On("ApproveTransfer", int64(1), models.Actor{OperatorID: 7}, "").Return(nil, repositories.ErrApprovalReviewed)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   `"code":"approval_already_reviewed"`,
+		},
+		{
+			name:       "expired",
+			reviewer:   &auth.Principal{OperatorID: 7, Role: auth.RoleSupervisor},
+			approvalID: "1",
+			setup: func(repo *repomocks.AccountRepository) {
+				repo.On("GetTransferApproval", int64(1)).Return(pending(1), nil)
+				repo.On("ApproveTransfer", int64(1), models.Actor{OperatorID: 7}, "").Return(nil, repositories.ErrApprovalExpired)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   `"code":"approval_expired"`,
+		},
+		{
+			name:       "unknown approval",
+			reviewer:   &auth.Principal{OperatorID: 7, Role: auth.RoleSupervisor},
+			approvalID: "99",
+			setup: func(repo *repomocks.AccountRepository) {
+				repo.On("GetTransferApproval", int64(99)).Return(nil, repositories.ErrApprovalNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   `"code":"approval_not_found"`,
+		},
+		{
+			name:       "invalid ID",
+			reviewer:   &auth.Principal{OperatorID: 7, Role: auth.RoleSupervisor},
+			approvalID: "abc",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `"code":"bad_request"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repomocks.NewAccountRepository(t)
+			if tt.setup != nil {
+				tt.setup(repo)
+			}
+			handler := NewAccountHandler(services.NewAccountService(repo))
+			review := handler.ApproveTransfer
+			if tt.reject {
+				review = handler.RejectTransfer
+			}
+
+			req, _ := http.NewRequest("POST", "/transfer-approvals/"+tt.approvalID, bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"approval_id": tt.approvalID})
+			req = req.WithContext(auth.NewContext(req.Context(), tt.reviewer))
+			rr := httptest.NewRecorder()
+			review(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.wantBody)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/services"
+)
+
+type AuditHandler struct {
+	service services.AuditServiceInterface
+}
+
+func NewAuditHandler(service services.AuditServiceInterface) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+// ListEvents lists the audit trail, newest first. It accepts the optional
+// filters account_id and operator_id, limit and the cursor returned by the
+// previous page.
+func (h *AuditHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditFilter(r.URL.Query())
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.service.ListEvents(filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+func parseAuditFilter(q url.Values) (models.AuditFilter, error) {
+	var filter models.AuditFilter
+
+	positive := func(name string, dst *int) error {
+		v := q.Get(name)
+		if v == "" {
+			return nil
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return errors.New("Invalid " + name)
+		}
+		*dst = n
+		return nil
+	}
+	if err := positive("account_id", &filter.AccountID); err != nil {
+		return filter, err
+	}
+	if err := positive("operator_id", &filter.OperatorID); err != nil {
+		return filter, err
+	}
+	if err := positive("limit", &filter.Limit); err != nil {
+		return filter, err
+	}
+	if v := q.Get("cursor"); v != "" {
+		beforeID, err := services.DecodeCursor(v)
+		if err != nil {
+			return filter, errors.New("Invalid cursor")
+		}
+		filter.BeforeID = beforeID
+	}
+	return filter, nil
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/services"
+	"github.com/gregoryAlvim/gobank/internal/services/mocks"
+)
+
+func TestAuditHandler_ListEvents(t *testing.T) {
+	service := new(mocks.AuditServiceInterface)
+	handler := NewAuditHandler(service)
+
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service.On("ListEvents", models.AuditFilter{AccountID: 20, BeforeID: 9, Limit: 10}).Return(&models.AuditPage{
+		Events: []models.AuditEvent{{
+			ID: 8, OperatorID: 7, OperatorRole: "teller", Action: "account:freeze", Method: "POST",
+			Path: "/account/20/freeze", AccountID: 20, Reason: "chargeback", StatusCode: 200, CreatedAt: createdAt,
+		}},
+		NextCursor: services.EncodeCursor(8),
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/audit/events?account_id=20&limit=10&cursor="+services.EncodeCursor(9), nil)
+	rr := httptest.NewRecorder()
+	handler.ListEvents(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{
+		"events": [{
+			"id": 8, "operator_id": 7, "operator_role": "teller", "action": "account:freeze", "method": "POST",
+			"path": "/account/20/freeze", "account_id": 20, "reason": "chargeback", "status_code": 200,
+			"created_at": "2026-03-01T12:00:00Z"
+		}],
+		"next_cursor": "`+services.EncodeCursor(8)+`"
+	}`, rr.Body.String())
+
+	for _, query := range []string{"account_id=abc", "operator_id=0", "limit=-1", "cursor=not-a-cursor"} {
+		req, _ := http.NewRequest("GET", "/audit/events?"+query, nil)
+		rr := httptest.NewRecorder()
+		handler.ListEvents(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+	service.AssertExpectations(t)
+}
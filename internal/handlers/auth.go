@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/services"
+)
+
+type AuthHandler struct {
+	service services.AuthServiceInterface
+}
+
+func NewAuthHandler(service services.AuthServiceInterface) *AuthHandler {
+	return &AuthHandler{service: service}
+}
+
+// Login exchanges a CPF or CNPJ and password for an access token and a
+// refresh token.
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req models.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+		return
+	}
+
+	tokens, err := h.service.Login(req.TaxID, req.Password)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeTokens(w, tokens)
+}
+
+// Refresh exchanges a refresh token for a new pair of tokens. Each refresh
+// token works once.
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+		return
+	}
+
+	tokens, err := h.service.Refresh(req.RefreshToken)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeTokens(w, tokens)
+}
+
+// Logout revokes a refresh token.
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+		return
+	}
+
+	if err := h.service.Logout(req.RefreshToken); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// OperatorLogin exchanges an operator's username and password for an
+// access token. There is no refresh token; operators log in again once it
+// expires.
+func (h *AuthHandler) OperatorLogin(w http.ResponseWriter, r *http.Request) {
+	var req models.OperatorLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+		return
+	}
+
+	tokens, err := h.service.OperatorLogin(req.Username, req.Password)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeTokens(w, tokens)
+}
+
+func writeTokens(w http.ResponseWriter, tokens *models.TokenPair) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(tokens)
+}
+
+// Authenticate requires a valid "Authorization: Bearer" access token and
+// puts its principal, with the request's ID, in the request context.
+func Authenticate(tokens *auth.TokenIssuer) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			scheme, token, ok := strings.Cut(header, " ")
+			if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+				writeError(w, r, auth.ErrUnauthenticated)
+				return
+			}
+
+			principal, err := tokens.VerifyAccessToken(token)
+			if err != nil {
+				writeError(w, r, err)
+				return
+			}
+			principal.RequestID = RequestIDFromContext(r.Context())
+			next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), principal)))
+		})
+	}
+}
+
+// RequireAccountOwner only lets the owner of the account in {id} through.
+// Routes without {id} are passed on; their handlers check ownership
+// themselves. Operators are passed on too: they are not limited to their
+// own accounts, and Authorizer checks what their role allows. It must run
+// after ResolveAccountNumbers.
+func RequireAccountOwner(service services.AccountServiceInterface) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ref, ok := mux.Vars(r)["id"]
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			accountID, err := strconv.Atoi(ref)
+			if err != nil {
+				writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
+				return
+			}
+			if err := authorizeAccount(service, r, accountID); err != nil {
+				writeError(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireCustomer only lets a customer through to its own
+// /customers/{customer_id} routes. Operators are passed on.
+func RequireCustomer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := auth.FromContext(r.Context())
+		if !ok {
+			writeError(w, r, auth.ErrUnauthenticated)
+			return
+		}
+		if !principal.IsOperator() && mux.Vars(r)["customer_id"] != strconv.Itoa(principal.CustomerID) {
+			writeError(w, r, auth.ErrForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authorizeAccount checks that the caller owns the account. Operators may
+// act on any account.
+func authorizeAccount(service services.AccountServiceInterface, r *http.Request, accountID int) error {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		return auth.ErrUnauthenticated
+	}
+	if principal.IsOperator() {
+		return nil
+	}
+	account, err := service.GetAccount(accountID)
+	if err != nil {
+		return err
+	}
+	if account.CustomerID != principal.CustomerID {
+		return auth.ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	repomocks "github.com/gregoryAlvim/gobank/internal/repositories/mocks"
+	"github.com/gregoryAlvim/gobank/internal/services"
+	"github.com/gregoryAlvim/gobank/internal/services/mocks"
+)
+
+// withPrincipal authenticates req as the customer.
+func withPrincipal(req *http.Request, customerID int) *http.Request {
+	return req.WithContext(auth.NewContext(req.Context(), &auth.Principal{CustomerID: customerID, Role: auth.RoleCustomer}))
+}
+
+// withOperator authenticates req as an operator with the role.
+func withOperator(req *http.Request, operatorID int, role auth.Role) *http.Request {
+	return req.WithContext(auth.NewContext(req.Context(), &auth.Principal{OperatorID: operatorID, Role: role}))
+}
+
+func newTestTokenIssuer(t *testing.T) *auth.TokenIssuer {
+	keys, err := auth.NewKeySet(auth.Key{ID: "test", Secret: []byte("0123456789abcdef0123456789abcdef")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auth.NewTokenIssuer(keys, 15*time.Minute)
+}
+
+// newProtectedRouter wires the account routes the way main does.
+func newProtectedRouter(tokens *auth.TokenIssuer, service *mocks.AccountServiceInterface) *mux.Router {
+	handler := NewAccountHandler(service)
+	r := mux.NewRouter()
+	accounts := r.PathPrefix("/account").Subrouter()
+	accounts.Use(Authenticate(tokens), ResolveAccountNumbers(service), RequireAccountOwner(service))
+	accounts.HandleFunc("/{id}/balance", handler.GetBalance).Methods("GET")
+	accounts.HandleFunc("/{id}/withdraw", handler.Withdraw).Methods("POST")
+	accounts.HandleFunc("/transfer", handler.Transfer).Methods("POST")
+	accounts.HandleFunc("/{id}", handler.CloseAccount).Methods("DELETE")
+	customers := r.PathPrefix("/customers/{customer_id}").Subrouter()
+	customers.Use(Authenticate(tokens), RequireCustomer)
+	customers.HandleFunc("/accounts", handler.GetCustomerAccounts).Methods("GET")
+	return r
+}
+
+func TestAuthenticate(t *testing.T) {
+	tokens := newTestTokenIssuer(t)
+	valid, _, err := tokens.IssueAccessToken(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherKeys, _ := auth.NewKeySet(auth.Key{ID: "test", Secret: []byte("ffffffffffffffffffffffffffffffff")})
+	forged, _, _ := auth.NewTokenIssuer(otherKeys, time.Minute).IssueAccessToken(3)
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantCode      string
+	}{
+		{"valid token", "Bearer " + valid, http.StatusOK, ""},
+		{"lowercase scheme", "bearer " + valid, http.StatusOK, ""},
+		{"no header", "", http.StatusUnauthorized, CodeUnauthenticated},
+		{"basic auth", "Basic dXNlcjpwYXNz", http.StatusUnauthorized, CodeUnauthenticated},
+		{"garbage token", "Bearer not-a-jwt", http.StatusUnauthorized, CodeInvalidToken},
+		{"wrong signature", "Bearer " + forged, http.StatusUnauthorized, CodeInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mocks.AccountServiceInterface)
+			if tt.wantStatus == http.StatusOK {
+				service.On("GetAccount", 10).Return(&models.Account{ID: 10, CustomerID: 3}, nil)
+				service.On("GetBalance", 10).Return(&models.Balance{Ledger: money.New(100, 0), Available: money.New(100, 0)}, nil)
+			}
+
+			req, _ := http.NewRequest("GET", "/account/10/balance", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rr := httptest.NewRecorder()
+
+			newProtectedRouter(tokens, service).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantCode != "" {
+				assert.Contains(t, rr.Body.String(), `"code":"`+tt.wantCode+`"`)
+				assert.NotEmpty(t, rr.Header().Get("WWW-Authenticate"))
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRequireAccountOwner(t *testing.T) {
+	tokens := newTestTokenIssuer(t)
+	token, _, _ := tokens.IssueAccessToken(3)
+
+	// Account 20 belongs to customer 4.
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"balance", "GET", "/account/20/balance", ""},
+		{"withdraw", "POST", "/account/20/withdraw", `{"amount": 10}`},
+		{"close", "DELETE", "/account/20", ""},
+		{"transfer from someone else's account", "POST", "/account/transfer", `{"from_id": 20, "to_id": 10, "amount": 10}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mocks.AccountServiceInterface)
+			service.On("GetAccount", 20).Return(&models.Account{ID: 20, CustomerID: 4}, nil)
+
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Authorization", "Bearer "+token)
+			rr := httptest.NewRecorder()
+
+			newProtectedRouter(tokens, service).ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusForbidden, rr.Code)
+			assert.Contains(t, rr.Body.String(), `"code":"forbidden"`)
+			service.AssertNotCalled(t, "GetBalance", mock.Anything)
+			service.AssertNotCalled(t, "Withdraw", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			service.AssertNotCalled(t, "CloseAccount", mock.Anything, mock.Anything, mock.Anything)
+			service.AssertNotCalled(t, "Transfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+
+	// Transfers to another customer's account are allowed.
+	service := new(mocks.AccountServiceInterface)
+	service.On("GetAccount", 10).Return(&models.Account{ID: 10, CustomerID: 3}, nil)
+	service.On("Transfer", mock.Anything, 10, 20, money.New(10, 0), int64(0)).Return(nil, nil)
+
+	req, _ := http.NewRequest("POST", "/account/transfer", bytes.NewBufferString(`{"from_id": 10, "to_id": 20, "amount": 10}`))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+
+	newProtectedRouter(tokens, service).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	service.AssertExpectations(t)
+}
+
+func TestRequireCustomer(t *testing.T) {
+	tokens := newTestTokenIssuer(t)
+	token, _, _ := tokens.IssueAccessToken(3)
+
+	service := new(mocks.AccountServiceInterface)
+	service.On("GetCustomerAccounts", 3).Return([]models.Account{}, nil)
+
+	req, _ := http.NewRequest("GET", "/customers/3/accounts", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	newProtectedRouter(tokens, service).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	req, _ = http.NewRequest("GET", "/customers/4/accounts", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr = httptest.NewRecorder()
+	newProtectedRouter(tokens, service).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	// Operators may look at any customer.
+	service.On("GetCustomerAccounts", 4).Return([]models.Account{}, nil)
+	operatorToken, _, _ := tokens.IssueOperatorToken(7, auth.RoleTeller)
+	req, _ = http.NewRequest("GET", "/customers/4/accounts", nil)
+	req.Header.Set("Authorization", "Bearer "+operatorToken)
+	rr = httptest.NewRecorder()
+	newProtectedRouter(tokens, service).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	service.AssertExpectations(t)
+}
+
+func TestAuthHandler_Login(t *testing.T) {
+	tokens := newTestTokenIssuer(t)
+	hash, err := auth.HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := repomocks.NewAuthRepository(t)
+	handler := NewAuthHandler(services.NewAuthService(repo, tokens, 24*time.Hour))
+
+	repo.On("GetCredentials", "52998224725").Return(3, hash, nil)
+	repo.On("CreateRefreshToken", mock.MatchedBy(func(token *models.RefreshToken) bool {
+		return token.CustomerID == 3 && len(token.TokenHash) == 64
+	})).Return(nil).Once()
+
+	req, _ := http.NewRequest("POST", "/auth/login", bytes.NewBufferString(`{"tax_id": "529.982.247-25", "password": "correct horse"}`))
+	rr := httptest.NewRecorder()
+	handler.Login(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+	assert.Contains(t, rr.Body.String(), `"token_type":"Bearer"`)
+	assert.Contains(t, rr.Body.String(), `"expires_in":900`)
+
+	// Wrong password and unknown customer look the same.
+	req, _ = http.NewRequest("POST", "/auth/login", bytes.NewBufferString(`{"tax_id": "529.982.247-25", "password": "wrong"}`))
+	rr = httptest.NewRecorder()
+	handler.Login(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"code":"invalid_credentials"`)
+
+	repo.On("GetCredentials", "11222333000181").Return(0, "", repositories.ErrCustomerNotFound)
+	req, _ = http.NewRequest("POST", "/auth/login", bytes.NewBufferString(`{"tax_id": "11.222.333/0001-81", "password": "correct horse"}`))
+	rr = httptest.NewRecorder()
+	handler.Login(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"code":"invalid_credentials"`)
+}
+
+func TestAuthHandler_OperatorLogin(t *testing.T) {
+	tokens := newTestTokenIssuer(t)
+	hash, err := auth.HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := repomocks.NewAuthRepository(t)
+	handler := NewAuthHandler(services.NewAuthService(repo, tokens, 24*time.Hour))
+
+	repo.On("GetOperatorCredentials", "ana").Return(4, "teller", hash, nil)
+	repo.On("GetOperatorCredentials", "bob").Return(0, "", "", repositories.ErrOperatorNotFound)
+
+	req, _ := http.NewRequest("POST", "/auth/operators/login", bytes.NewBufferString(`{"username": "ana", "password": "correct horse"}`))
+	rr := httptest.NewRecorder()
+	handler.OperatorLogin(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "refresh_token")
+	var pair models.TokenPair
+	if err := json.Unmarshal(rr.Body.Bytes(), &pair); err != nil {
+		t.Fatal(err)
+	}
+	principal, err := tokens.VerifyAccessToken(pair.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, &auth.Principal{OperatorID: 4, Role: auth.RoleTeller}, principal)
+
+	for _, body := range []string{
+		`{"username": "ana", "password": "wrong"}`,
+		`{"username": "bob", "password": "correct horse"}`,
+	} {
+		req, _ = http.NewRequest("POST", "/auth/operators/login", bytes.NewBufferString(body))
+		rr = httptest.NewRecorder()
+		handler.OperatorLogin(rr, req)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"code":"invalid_credentials"`)
+	}
+}
+
+func TestAuthHandler_Refresh(t *testing.T) {
+	service := new(mocks.AuthServiceInterface)
+	handler := NewAuthHandler(service)
+
+	service.On("Refresh", "good").Return(&models.TokenPair{AccessToken: "a", TokenType: "Bearer", ExpiresIn: 900, RefreshToken: "next"}, nil)
+	service.On("Refresh", "reused").Return(nil, repositories.ErrRefreshTokenReused)
+
+	req, _ := http.NewRequest("POST", "/auth/refresh", bytes.NewBufferString(`{"refresh_token": "good"}`))
+	rr := httptest.NewRecorder()
+	handler.Refresh(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"access_token":"a","token_type":"Bearer","expires_in":900,"refresh_token":"next"}`, rr.Body.String())
+
+	req, _ = http.NewRequest("POST", "/auth/refresh", bytes.NewBufferString(`{"refresh_token": "reused"}`))
+	rr = httptest.NewRecorder()
+	handler.Refresh(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"code":"invalid_refresh_token"`)
+
+	req, _ = http.NewRequest("POST", "/auth/refresh", bytes.NewBufferString(`{}`))
+	rr = httptest.NewRecorder()
+	handler.Refresh(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	service.AssertExpectations(t)
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	service := new(mocks.AuthServiceInterface)
+	handler := NewAuthHandler(service)
+
+	service.On("Logout", "token").Return(nil)
+
+	req, _ := http.NewRequest("POST", "/auth/logout", bytes.NewBufferString(`{"refresh_token": "token"}`))
+	rr := httptest.NewRecorder()
+	handler.Logout(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	service.AssertExpectations(t)
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/services"
+)
+
+// maxAuditReasonLength matches the audit_events.reason column. Requests
+// that were refused never had their reason validated.
+const maxAuditReasonLength = 500
+
+// maxAuditBodySize caps how much of an operator's request Require reads
+// into memory. No back-office route takes anywhere near this much.
+const maxAuditBodySize = 1 << 20
+
+// maxAuditRequestLength caps the redacted request body kept in the audit
+// trail; longer bodies are cut and so may no longer be valid JSON.
+const maxAuditRequestLength = 2000
+
+// redactedFields hold personal data or secrets. Their values are never
+// written to the audit trail, at any depth of the request body.
+var redactedFields = map[string]bool{
+	"cpf":             true,
+	"cnpj":            true,
+	"tax_id":          true,
+	"full_name":       true,
+	"trade_name":      true,
+	"email":           true,
+	"corporate_email": true,
+	"phone_number":    true,
+	"monthly_income":  true,
+	"annual_revenue":  true,
+	"password":        true,
+	"secret":          true,
+	"access_token":    true,
+	"refresh_token":   true,
+}
+
+const redacted = "[redacted]"
+
+// Authorizer checks each request against the permission matrix in
+// internal/auth and records every request made by an operator, allowed or
+// not, in the audit trail. Ownership of the account or customer in the
+// route is checked separately, by RequireAccountOwner and RequireCustomer.
+type Authorizer struct {
+	audit services.AuditServiceInterface
+}
+
+func NewAuthorizer(audit services.AuditServiceInterface) *Authorizer {
+	return &Authorizer{audit: audit}
+}
+
+// Require only lets callers whose role grants perm through to next.
+func (a *Authorizer) Require(perm auth.Permission, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := auth.FromContext(r.Context())
+		if !ok {
+			writeError(w, r, auth.ErrUnauthenticated)
+			return
+		}
+		if !principal.IsOperator() {
+			if !principal.Role.Can(perm) {
+				writeError(w, r, auth.ErrForbidden)
+				return
+			}
+			next(w, r)
+			return
+		}
+
+		var body []byte
+		if r.Body != nil {
+			var err error
+			if body, err = io.ReadAll(http.MaxBytesReader(w, r.Body, maxAuditBodySize)); err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					writeProblem(w, r, http.StatusRequestEntityTooLarge, CodeBodyTooLarge, "Request body too large")
+					a.record(r, principal, perm, nil, http.StatusRequestEntityTooLarge)
+					return
+				}
+				writeProblem(w, r, http.StatusInternalServerError, CodeInternal, "Failed to read request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		if principal.Role.Can(perm) {
+			next(rec, r)
+		} else {
+			writeError(rec, r, auth.ErrForbidden)
+		}
+		a.record(r, principal, perm, body, rec.statusCode)
+	}
+}
+
+// record writes the audit event for an operator's request. The response
+// has already been sent, so a failure can only be logged.
+func (a *Authorizer) record(r *http.Request, principal *auth.Principal, perm auth.Permission, body []byte, statusCode int) {
+	vars := mux.Vars(r)
+	event := &models.AuditEvent{
+		OperatorID:   principal.OperatorID,
+		OperatorRole: string(principal.Role),
+		Action:       string(perm),
+		Method:       r.Method,
+		Path:         r.URL.Path,
+		StatusCode:   statusCode,
+	}
+	event.AccountID, _ = strconv.Atoi(vars["id"])
+	event.CustomerID, _ = strconv.Atoi(vars["customer_id"])
+	if len(body) > 0 {
+		event.Request = truncate(redactRequest(body), maxAuditRequestLength)
+		var reason struct {
+			Reason string `json:"reason"`
+		}
+		if json.Unmarshal(body, &reason) == nil {
+			event.Reason = truncate(reason.Reason, maxAuditReasonLength)
+		}
+	}
+
+	if err := a.audit.Record(event); err != nil {
+		log.Printf("audit: failed to record %s by operator %d on %s: %v", perm, principal.OperatorID, r.URL.Path, err)
+	}
+}
+
+// redactRequest returns body as compact JSON with the values of
+// redactedFields replaced. A body that is not JSON is dropped, since there
+// is no telling what it holds.
+func redactRequest(body []byte) string {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return ""
+	}
+	out, err := json.Marshal(redact(v))
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+func redact(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			if redactedFields[strings.ToLower(k)] {
+				v[k] = redacted
+			} else {
+				v[k] = redact(field)
+			}
+		}
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = redact(elem)
+		}
+	}
+	return v
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
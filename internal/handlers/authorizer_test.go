@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	repomocks "github.com/gregoryAlvim/gobank/internal/repositories/mocks"
+	"github.com/gregoryAlvim/gobank/internal/services"
+	"github.com/gregoryAlvim/gobank/internal/services/mocks"
+)
+
+// newAuthorizedRouter wires the account routes with their permissions the
+// way main does. Requests must already carry a principal.
+func newAuthorizedRouter(service *mocks.AccountServiceInterface, audit *mocks.AuditServiceInterface) *mux.Router {
+	handler := NewAccountHandler(service)
+	authz := NewAuthorizer(audit)
+	r := mux.NewRouter()
+	accounts := r.PathPrefix("/account").Subrouter()
+	accounts.Use(RequireAccountOwner(service))
+	accounts.HandleFunc("/{id}/balance", authz.Require(auth.PermViewAccount, handler.GetBalance)).Methods("GET")
+	accounts.HandleFunc("/{id}/deposit", authz.Require(auth.PermDeposit, handler.Deposit)).Methods("POST")
+	accounts.HandleFunc("/transfer", authz.Require(auth.PermTransfer, handler.Transfer)).Methods("POST")
+	accounts.HandleFunc("/{id}", authz.Require(auth.PermCloseAccount, handler.CloseAccount)).Methods("DELETE")
+	accounts.HandleFunc("/{id}/freeze", authz.Require(auth.PermFreezeAccount, handler.FreezeAccount)).Methods("POST")
+	accounts.HandleFunc("/{id}/unfreeze", authz.Require(auth.PermUnfreezeAccount, handler.UnfreezeAccount)).Methods("POST")
+	accounts.HandleFunc("/{id}/activate", authz.Require(auth.PermActivateAccount, handler.ActivateAccount)).Methods("POST")
+	accounts.HandleFunc("/{id}/balance-corrections", authz.Require(auth.PermCorrectBalance, handler.CorrectBalance)).Methods("POST")
+	accounts.HandleFunc("/{id}/overdraft", authz.Require(auth.PermManageOverdraft, handler.SetOverdraftLimit)).Methods("PUT")
+	accounts.HandleFunc("/{id}/fee-waivers/{fee_type}", authz.Require(auth.PermManageFees, handler.WaiveFee)).Methods("PUT")
+	r.HandleFunc("/transfer-approvals/{approval_id}/approve", authz.Require(auth.PermReviewTransfer, handler.ApproveTransfer)).Methods("POST")
+	return r
+}
+
+func TestAuthorizer_PermissionMatrix(t *testing.T) {
+	freeze := models.StatusChangeRequest{Reason: "chargeback"}
+	correction := models.BalanceCorrectionRequest{Balance: money.New(90, 0), Reason: "duplicated deposit"}
+	overdraftLimit := money.New(2000, 0)
+
+	// Account 20 belongs to customer 4; the caller is never its owner.
+	routes := map[string]struct {
+		method, path, body string
+		setup              func(m *mocks.AccountServiceInterface)
+	}{
+		"balance": {"GET", "/account/20/balance", "", func(m *mocks.AccountServiceInterface) {
+			m.On("GetBalance", 20).Return(&models.Balance{Ledger: money.New(100, 0), Available: money.New(100, 0)}, nil)
+		}},
+		"deposit": {"POST", "/account/20/deposit", `{"amount": 10}`, func(m *mocks.AccountServiceInterface) {
+			m.On("Deposit", mock.Anything, 20, money.New(10, 0), "").Return(nil)
+		}},
+		"transfer": {"POST", "/account/transfer", `{"from_id": 20, "to_id": 30, "amount": 10}`, func(m *mocks.AccountServiceInterface) {
+			m.On("Transfer", mock.Anything, 20, 30, money.New(10, 0), int64(0)).Return(nil, nil)
+		}},
+		"close": {"DELETE", "/account/20", `{"reason": "customer request"}`, func(m *mocks.AccountServiceInterface) {
+			m.On("CloseAccount", mock.Anything, 20, models.CloseAccountRequest{Reason: "customer request"}).Return(&models.AccountStatusChange{}, nil)
+		}},
+		"freeze": {"POST", "/account/20/freeze", `{"reason": "chargeback"}`, func(m *mocks.AccountServiceInterface) {
+			m.On("FreezeAccount", mock.Anything, 20, freeze).Return(&models.AccountStatusChange{}, nil)
+		}},
+		"unfreeze": {"POST", "/account/20/unfreeze", `{"reason": "chargeback"}`, func(m *mocks.AccountServiceInterface) {
+			m.On("UnfreezeAccount", mock.Anything, 20, freeze).Return(&models.AccountStatusChange{}, nil)
+		}},
+		"activate": {"POST", "/account/20/activate", `{"reason": "customer came back"}`, func(m *mocks.AccountServiceInterface) {
+			m.On("ActivateAccount", mock.Anything, 20, models.StatusChangeRequest{Reason: "customer came back"}).Return(&models.AccountStatusChange{}, nil)
+		}},
+		"correct balance": {"POST", "/account/20/balance-corrections", `{"balance": 90, "reason": "duplicated deposit"}`, func(m *mocks.AccountServiceInterface) {
+			m.On("CorrectBalance", mock.Anything, 20, correction).Return(nil)
+		}},
+		"overdraft limit": {"PUT", "/account/20/overdraft", `{"limit": 2000, "reason": "salary increase"}`, func(m *mocks.AccountServiceInterface) {
+			m.On("SetOverdraftLimit", 20, models.OverdraftLimitRequest{Limit: &overdraftLimit, Reason: "salary increase"}).Return(nil)
+		}},
+		"waive fee": {"PUT", "/account/20/fee-waivers/maintenance", `{"reason": "employee account"}`, func(m *mocks.AccountServiceInterface) {
+			m.On("WaiveFee", 20, "maintenance", models.FeeWaiverRequest{Reason: "employee account"}).Return(&models.FeeWaiver{AccountID: 20}, nil)
+		}},
+		"approve transfer": {"POST", "/transfer-approvals/5/approve", "", func(m *mocks.AccountServiceInterface) {
+			m.On("ApproveTransfer", int64(5), mock.Anything, models.ReviewRequest{}).Return(&models.TransferApproval{ID: 5}, nil)
+		}},
+	}
+
+	allowed := map[auth.Role][]string{
+		auth.RoleTeller:     {"balance", "deposit", "freeze", "activate"},
+		auth.RoleSupervisor: {"balance", "deposit", "transfer", "close", "freeze", "unfreeze", "activate", "correct balance", "overdraft limit", "waive fee", "approve transfer"},
+		auth.RoleAuditor:    {"balance"},
+	}
+
+	for role, names := range allowed {
+		for name, route := range routes {
+			want := http.StatusForbidden
+			for _, n := range names {
+				if n == name {
+					want = http.StatusOK
+				}
+			}
+
+			t.Run(string(role)+" "+name, func(t *testing.T) {
+				service := new(mocks.AccountServiceInterface)
+				audit := new(mocks.AuditServiceInterface)
+				if want == http.StatusOK {
+					route.setup(service)
+				}
+				audit.On("Record", mock.MatchedBy(func(e *models.AuditEvent) bool {
+					return e.OperatorID == 7 && e.OperatorRole == string(role) && e.StatusCode == want
+				})).Return(nil).Once()
+
+				req, _ := http.NewRequest(route.method, route.path, bytes.NewBufferString(route.body))
+				req = withOperator(req, 7, role)
+				rr := httptest.NewRecorder()
+
+				newAuthorizedRouter(service, audit).ServeHTTP(rr, req)
+
+				assert.Equal(t, want, rr.Code)
+				service.AssertExpectations(t)
+				service.AssertNotCalled(t, "GetAccount", mock.Anything)
+				audit.AssertExpectations(t)
+			})
+		}
+	}
+}
+
+func TestAuthorizer_CustomersAreNotAudited(t *testing.T) {
+	service := new(mocks.AccountServiceInterface)
+	audit := new(mocks.AuditServiceInterface)
+	service.On("GetAccount", 10).Return(&models.Account{ID: 10, CustomerID: 3}, nil)
+	service.On("GetBalance", 10).Return(&models.Balance{Ledger: money.New(100, 0), Available: money.New(100, 0)}, nil)
+
+	req, _ := http.NewRequest("GET", "/account/10/balance", nil)
+	rr := httptest.NewRecorder()
+	newAuthorizedRouter(service, audit).ServeHTTP(rr, withPrincipal(req, 3))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Customers cannot freeze even their own accounts.
+	req, _ = http.NewRequest("POST", "/account/10/freeze", bytes.NewBufferString(`{"reason": "lost card"}`))
+	rr = httptest.NewRecorder()
+	newAuthorizedRouter(service, audit).ServeHTTP(rr, withPrincipal(req, 3))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"code":"forbidden"`)
+
+	service.AssertNotCalled(t, "FreezeAccount", mock.Anything, mock.Anything, mock.Anything)
+	audit.AssertNotCalled(t, "Record", mock.Anything)
+}
+
+func TestAuthorizer_RecordsAuditEvent(t *testing.T) {
+	service := new(mocks.AccountServiceInterface)
+	audit := new(mocks.AuditServiceInterface)
+	service.On("FreezeAccount", mock.MatchedBy(func(p *auth.Principal) bool { return p.OperatorID == 7 }), 20,
+		models.StatusChangeRequest{Reason: "chargeback"}).Return(&models.AccountStatusChange{}, nil)
+	audit.On("Record", &models.AuditEvent{
+		OperatorID:   7,
+		OperatorRole: "teller",
+		Action:       "account:freeze",
+		Method:       "POST",
+		Path:         "/account/20/freeze",
+		AccountID:    20,
+		Reason:       "chargeback",
+		Request:      `{"reason":"chargeback"}`,
+		StatusCode:   http.StatusOK,
+	}).Return(nil)
+
+	req, _ := http.NewRequest("POST", "/account/20/freeze", bytes.NewBufferString(`{"reason": "chargeback"}`))
+	rr := httptest.NewRecorder()
+	newAuthorizedRouter(service, audit).ServeHTTP(rr, withOperator(req, 7, auth.RoleTeller))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	service.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+func TestAuthorizer_RedactsAuditedRequest(t *testing.T) {
+	service := new(mocks.AccountServiceInterface)
+	audit := new(mocks.AuditServiceInterface)
+	service.On("FreezeAccount", mock.Anything, 20, models.StatusChangeRequest{Reason: "chargeback"}).Return(&models.AccountStatusChange{}, nil)
+	audit.On("Record", mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.StatusCode == http.StatusOK && e.Reason == "chargeback" &&
+			e.Request == `{"customer":{"CPF":"[redacted]","full_name":"[redacted]","tags":[{"email":"[redacted]"}]},"reason":"chargeback"}`
+	})).Return(nil)
+
+	body := `{"reason": "chargeback", "customer": {"CPF": "123.456.789-09", "full_name": "Ana Souza", "tags": [{"email": "ana@example.com"}]}}`
+	req, _ := http.NewRequest("POST", "/account/20/freeze", bytes.NewBufferString(body))
+	rr := httptest.NewRecorder()
+	newAuthorizedRouter(service, audit).ServeHTTP(rr, withOperator(req, 7, auth.RoleTeller))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	audit.AssertExpectations(t)
+}
+
+func TestRedactRequest(t *testing.T) {
+	assert.Equal(t, `{"amount":10.005,"password":"[redacted]"}`, redactRequest([]byte(`{"amount": 10.005, "password": "hunter2"}`)))
+	assert.Equal(t, `[{"cnpj":"[redacted]"}]`, redactRequest([]byte(`[{"cnpj": "11.222.333/0001-81"}]`)))
+	assert.Equal(t, "", redactRequest([]byte(`cpf=12345678909`)))
+}
+
+func TestAuthorizer_TruncatesAuditedRequest(t *testing.T) {
+	service := new(mocks.AccountServiceInterface)
+	audit := new(mocks.AuditServiceInterface)
+	service.On("FreezeAccount", mock.Anything, 20, models.StatusChangeRequest{Reason: "chargeback"}).Return(&models.AccountStatusChange{}, nil)
+	audit.On("Record", mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return len(e.Request) == maxAuditRequestLength && e.Reason == "chargeback"
+	})).Return(nil)
+
+	body := `{"reason": "chargeback", "note": "` + strings.Repeat("a", 3*maxAuditRequestLength) + `"}`
+	req, _ := http.NewRequest("POST", "/account/20/freeze", bytes.NewBufferString(body))
+	rr := httptest.NewRecorder()
+	newAuthorizedRouter(service, audit).ServeHTTP(rr, withOperator(req, 7, auth.RoleTeller))
+
+	audit.AssertExpectations(t)
+}
+
+func TestAuthorizer_BodyTooLarge(t *testing.T) {
+	service := new(mocks.AccountServiceInterface)
+	audit := new(mocks.AuditServiceInterface)
+	audit.On("Record", mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.StatusCode == http.StatusRequestEntityTooLarge && e.Request == ""
+	})).Return(nil)
+
+	body := `{"reason": "` + strings.Repeat("a", maxAuditBodySize) + `"}`
+	req, _ := http.NewRequest("POST", "/account/20/freeze", bytes.NewBufferString(body))
+	rr := httptest.NewRecorder()
+	newAuthorizedRouter(service, audit).ServeHTTP(rr, withOperator(req, 7, auth.RoleTeller))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"code":"body_too_large"`)
+	service.AssertNotCalled(t, "FreezeAccount", mock.Anything, mock.Anything, mock.Anything)
+	audit.AssertExpectations(t)
+}
+
+func TestAuthorizer_AuditFailureKeepsResponse(t *testing.T) {
+	service := new(mocks.AccountServiceInterface)
+	audit := new(mocks.AuditServiceInterface)
+	service.On("GetBalance", 20).Return(&models.Balance{Ledger: money.New(100, 0), Available: money.New(100, 0)}, nil)
+	audit.On("Record", mock.Anything).Return(errors.New("pq: connection refused"))
+
+	req, _ := http.NewRequest("GET", "/account/20/balance", nil)
+	rr := httptest.NewRecorder()
+	newAuthorizedRouter(service, audit).ServeHTTP(rr, withOperator(req, 7, auth.RoleAuditor))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"ledger": 100.00, "available": 100.00}`, rr.Body.String())
+}
+
+func TestAccountHandler_FreezeAndCorrectBalance_Validation(t *testing.T) {
+	repo := repomocks.NewAccountRepository(t)
+	handler := NewAccountHandler(services.NewAccountService(repo))
+
+	tests := []struct {
+		name      string
+		handle    http.HandlerFunc
+		body      string
+		wantField string
+	}{
+		{"freeze without reason", handler.FreezeAccount, `{}`, "reason"},
+		{"unfreeze without reason", handler.UnfreezeAccount, `{"reason": ""}`, "reason"},
+		{"activate without reason", handler.ActivateAccount, `{"reason": ""}`, "reason"},
+		{"close without reason", handler.CloseAccount, `{"payout_account_id": 21}`, "reason"},
+		{"negative balance", handler.CorrectBalance, `{"balance": -1, "reason": "typo"}`, "balance"},
+		{"correction without reason", handler.CorrectBalance, `{"balance": 10}`, "reason"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/account/20/freeze", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": "20"})
+			rr := httptest.NewRecorder()
+			tt.handle(rr, req)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+			assert.Contains(t, rr.Body.String(), `"field":"`+tt.wantField+`"`)
+		})
+	}
+}
+
+func TestAccountHandler_WithdrawFromFrozenAccount(t *testing.T) {
+	service := new(mocks.AccountServiceInterface)
+	service.On("Withdraw", mock.Anything, 20, money.New(10, 0), "").Return(repositories.ErrAccountFrozen)
+
+	req, _ := http.NewRequest("POST", "/account/20/withdraw", bytes.NewBufferString(`{"amount": 10}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "20"})
+	rr := httptest.NewRecorder()
+	NewAccountHandler(service).Withdraw(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"code":"account_frozen"`)
+}
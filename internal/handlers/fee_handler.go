@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+// ListFees lists the withdrawal, transfer and maintenance fees.
+func (h *AccountHandler) ListFees(w http.ResponseWriter, r *http.Request) {
+	fees, err := h.service.ListFees()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]models.Fee{"fees": fees})
+}
+
+// SetFee sets the amount of the fee in the route: the withdrawal fee, the
+// transfer fee between two customer types or the maintenance fee of a
+// category.
+func (h *AccountHandler) SetFee(w http.ResponseWriter, r *http.Request) {
+	var fee models.Fee
+	if err := json.NewDecoder(r.Body).Decode(&fee); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+		return
+	}
+	vars := mux.Vars(r)
+	fee.Type, fee.FromType, fee.ToType, fee.Category = vars["type"], vars["from_type"], vars["to_type"], vars["category"]
+
+	updated, err := h.service.SetFee(fee)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// ListFeeWaivers lists the fees the account is exempt from.
+func (h *AccountHandler) ListFeeWaivers(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
+		return
+	}
+
+	waivers, err := h.service.ListFeeWaivers(id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]models.FeeWaiver{"waivers": waivers})
+}
+
+// WaiveFee exempts the account from the fee type in the route. The body
+// carries the reason.
+func (h *AccountHandler) WaiveFee(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
+		return
+	}
+
+	var req models.FeeWaiverRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+		return
+	}
+
+	waiver, err := h.service.WaiveFee(id, vars["fee_type"], req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(waiver)
+}
+
+// RemoveFeeWaiver charges the account the fee type in the route again.
+func (h *AccountHandler) RemoveFeeWaiver(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
+		return
+	}
+
+	if err := h.service.RemoveFeeWaiver(id, vars["fee_type"]); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Fee waiver removed"})
+}
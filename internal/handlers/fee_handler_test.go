@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	repomocks "github.com/gregoryAlvim/gobank/internal/repositories/mocks"
+	"github.com/gregoryAlvim/gobank/internal/services"
+	"github.com/gregoryAlvim/gobank/internal/services/mocks"
+)
+
+func TestAccountHandler_ListFees(t *testing.T) {
+	service := new(mocks.AccountServiceInterface)
+	handler := NewAccountHandler(service)
+
+	service.On("ListFees").Return([]models.Fee{
+		{Type: "maintenance", Category: "standard", Amount: money.New(15, 0)},
+		{Type: "transfer", FromType: "legal", ToType: "natural", Amount: money.FromCents(150)},
+		{Type: "withdrawal", Amount: money.FromCents(250)},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/fees", nil)
+	rr := httptest.NewRecorder()
+	handler.ListFees(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"fees": [
+		{"type": "maintenance", "category": "standard", "amount": 15.00},
+		{"type": "transfer", "from_type": "legal", "to_type": "natural", "amount": 1.50},
+		{"type": "withdrawal", "amount": 2.50}
+	]}`, rr.Body.String())
+	service.AssertExpectations(t)
+}
+
+func TestAccountHandler_SetFee(t *testing.T) {
+	tests := []struct {
+		name       string
+		vars       map[string]string
+		body       string
+		setup      func(repo *repomocks.AccountRepository)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "withdrawal",
+			vars: map[string]string{"type": "withdrawal"},
+			body: `{"amount": 3}`,
+			setup: func(repo *repomocks.AccountRepository) {
+				repo.On("SetFee", &models.Fee{Type: "withdrawal", Amount: money.New(3, 0)}).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"type": "withdrawal", "amount": 3.00}`,
+		},
+		{
+			name: "transfer",
+			vars: map[string]string{"type": "transfer", "from_type": "legal", "to_type": "legal"},
+			body: `{"amount": 2}`,
+			setup: func(repo *repomocks.AccountRepository) {
+				repo.On("SetFee", &models.Fee{Type: "transfer", FromType: "legal", ToType: "legal", Amount: money.New(2, 0)}).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"type": "transfer", "from_type": "legal", "to_type": "legal", "amount": 2.00}`,
+		},
+		{
+			name:       "unknown customer type",
+			vars:       map[string]string{"type": "transfer", "from_type": "legal", "to_type": "robot"},
+			body:       `{"amount": 2}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "unknown category",
+			vars:       map[string]string{"type": "maintenance", "category": "gold"},
+			body:       `{"amount": 20}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "negative amount",
+			vars:       map[string]string{"type": "maintenance", "category": "premium"},
+			body:       `{"amount": -1}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repomocks.NewAccountRepository(t)
+			if tt.setup != nil {
+				tt.setup(repo)
+			}
+			handler := NewAccountHandler(services.NewAccountService(repo))
+
+			req, _ := http.NewRequest("PUT", "/fees", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, tt.vars)
+			rr := httptest.NewRecorder()
+			handler.SetFee(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestAccountHandler_FeeWaivers(t *testing.T) {
+	repo := repomocks.NewAccountRepository(t)
+	handler := NewAccountHandler(services.NewAccountService(repo))
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	repo.On("WaiveFee", &models.FeeWaiver{AccountID: 10, FeeType: "maintenance", Reason: "employee account"}).Return(nil)
+	repo.On("ListFeeWaivers", 10).Return([]models.FeeWaiver{
+		{AccountID: 10, FeeType: "maintenance", Reason: "employee account", CreatedAt: createdAt},
+	}, nil)
+	repo.On("RemoveFeeWaiver", 10, "withdrawal").Return(repositories.ErrFeeWaiverNotFound)
+
+	req, _ := http.NewRequest("PUT", "/account/10/fee-waivers/maintenance", bytes.NewBufferString(`{"reason": "employee account"}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "10", "fee_type": "maintenance"})
+	rr := httptest.NewRecorder()
+	handler.WaiveFee(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	req, _ = http.NewRequest("PUT", "/account/10/fee-waivers/deposit", bytes.NewBufferString(`{}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "10", "fee_type": "deposit"})
+	rr = httptest.NewRecorder()
+	handler.WaiveFee(rr, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"field":"fee_type"`)
+	assert.Contains(t, rr.Body.String(), `"field":"reason"`)
+
+	req, _ = http.NewRequest("GET", "/account/10/fee-waivers", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "10"})
+	rr = httptest.NewRecorder()
+	handler.ListFeeWaivers(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"waivers": [{"account_id": 10, "fee_type": "maintenance", "reason": "employee account", "created_at": "2026-03-01T12:00:00Z"}]}`, rr.Body.String())
+
+	req, _ = http.NewRequest("DELETE", "/account/10/fee-waivers/withdrawal", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "10", "fee_type": "withdrawal"})
+	rr = httptest.NewRecorder()
+	handler.RemoveFeeWaiver(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"code":"fee_waiver_not_found"`)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/services"
+)
+
+type FXHandler struct {
+	service services.FXServiceInterface
+}
+
+func NewFXHandler(service services.FXServiceInterface) *FXHandler {
+	return &FXHandler{service: service}
+}
+
+// ListRates lists the exchange rate and spread of every currency pair.
+func (h *FXHandler) ListRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.service.ListRates()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]models.FXRate{"rates": rates})
+}
+
+// SetRate sets the exchange rate of the currency pair in the route.
+func (h *FXHandler) SetRate(w http.ResponseWriter, r *http.Request) {
+	var rate models.FXRate
+	if err := json.NewDecoder(r.Body).Decode(&rate); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+		return
+	}
+
+	vars := mux.Vars(r)
+	updated, err := h.service.SetRate(vars["base"], vars["quote"], rate)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// CreateQuote locks the rate for a transfer out of the account in the
+// route.
+func (h *FXHandler) CreateQuote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
+		return
+	}
+
+	var req models.FXQuoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+		return
+	}
+
+	quote, err := h.service.Quote(id, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(quote)
+}
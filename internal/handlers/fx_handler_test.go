@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/fx"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	"github.com/gregoryAlvim/gobank/internal/services/mocks"
+)
+
+func TestFXHandler_ListRates(t *testing.T) {
+	service := new(mocks.FXServiceInterface)
+	handler := NewFXHandler(service)
+
+	updatedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service.On("ListRates").Return([]models.FXRate{
+		{Base: "USD", Quote: "BRL", Rate: fx.MustParseRate("5.4321"), SpreadBps: 50, UpdatedAt: updatedAt},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/fx/rates", nil)
+	rr := httptest.NewRecorder()
+	handler.ListRates(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"rates": [
+		{"base": "USD", "quote": "BRL", "rate": 5.4321, "spread_bps": 50, "updated_at": "2026-03-01T12:00:00Z"}
+	]}`, rr.Body.String())
+}
+
+func TestFXHandler_SetRate(t *testing.T) {
+	service := new(mocks.FXServiceInterface)
+	handler := NewFXHandler(service)
+
+	updatedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service.On("SetRate", "USD", "BRL", models.FXRate{Rate: fx.MustParseRate("5.4321"), SpreadBps: 50}).Return(&models.FXRate{
+		Base: "USD", Quote: "BRL", Rate: fx.MustParseRate("5.4321"), SpreadBps: 50, UpdatedAt: updatedAt,
+	}, nil)
+
+	req, _ := http.NewRequest("PUT", "/fx/rates/USD/BRL", bytes.NewBufferString(`{"rate": "5.4321", "spread_bps": 50}`))
+	req = mux.SetURLVars(req, map[string]string{"base": "USD", "quote": "BRL"})
+	rr := httptest.NewRecorder()
+	handler.SetRate(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"base": "USD", "quote": "BRL", "rate": 5.4321, "spread_bps": 50, "updated_at": "2026-03-01T12:00:00Z"}`, rr.Body.String())
+}
+
+func TestFXHandler_CreateQuote(t *testing.T) {
+	service := new(mocks.FXServiceInterface)
+	handler := NewFXHandler(service)
+
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	request := models.FXQuoteRequest{ToCurrency: "BRL", Amount: money.New(100, 0)}
+	service.On("Quote", 10, request).Return(&models.FXQuote{
+		ID: 3, AccountID: 10, FromCurrency: "USD", ToCurrency: "BRL", Amount: money.New(100, 0),
+		ConvertedAmount: money.MustParse("540.49"), MidRate: fx.MustParseRate("5.4321"), SpreadBps: 50,
+		Rate: fx.MustParseRate("5.4049395"), ExpiresAt: createdAt.Add(time.Minute), CreatedAt: createdAt,
+	}, nil)
+
+	req, _ := http.NewRequest("POST", "/account/10/fx-quotes", bytes.NewBufferString(`{"to_currency": "BRL", "amount": 100}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "10"})
+	rr := httptest.NewRecorder()
+	handler.CreateQuote(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.JSONEq(t, `{
+		"id": 3, "account_id": 10, "from_currency": "USD", "to_currency": "BRL",
+		"amount": 100.00, "converted_amount": 540.49,
+		"mid_rate": 5.4321, "spread_bps": 50, "rate": 5.4049395,
+		"expires_at": "2026-03-01T12:01:00Z", "created_at": "2026-03-01T12:00:00Z"
+	}`, rr.Body.String())
+
+	service.On("Quote", 11, request).Return(nil, repositories.ErrFXRateNotFound)
+
+	req, _ = http.NewRequest("POST", "/account/11/fx-quotes", bytes.NewBufferString(`{"to_currency": "BRL", "amount": 100}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "11"})
+	rr = httptest.NewRecorder()
+	handler.CreateQuote(rr, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"code":"fx_rate_not_found"`)
+}
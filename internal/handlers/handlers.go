@@ -2,13 +2,22 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 
+	"github.com/gregoryAlvim/gobank/internal/accountnumber"
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/ledger"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
 	"github.com/gregoryAlvim/gobank/internal/services"
+	"github.com/gregoryAlvim/gobank/internal/validation"
 )
 
 type AccountHandler struct {
@@ -22,73 +31,243 @@ func NewAccountHandler(service services.AccountServiceInterface) *AccountHandler
 func (h *AccountHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 	accountType := r.URL.Query().Get("type")
 	if accountType == "" {
-		http.Error(w, "Account type is required", http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidAccountType, "Account type is required")
 		return
 	}
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "Failed to read request body", http.StatusInternalServerError)
+		writeProblem(w, r, http.StatusInternalServerError, CodeInternal, "Failed to read request body")
 		return
 	}
 
-	err = h.service.CreateAccount(accountType, body)
+	account, err := h.service.CreateAccount(accountType, body, RequestIDFromContext(r.Context()))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"message": "Account created successfully"})
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":        "Account created successfully",
+		"customer_id":    account.CustomerID,
+		"account_id":     account.ID,
+		"account_number": account.AccountNumber(),
+	})
 }
 
+// OpenAccount opens another account for an existing customer.
+func (h *AccountHandler) OpenAccount(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	customerID, err := strconv.Atoi(vars["customer_id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid customer ID")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, CodeInternal, "Failed to read request body")
+		return
+	}
+
+	var req models.OpenAccountRequest
+	if err := validation.DecodeJSON(body, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// RequireCustomer has made sure there is a principal.
+	principal, _ := auth.FromContext(r.Context())
+	account, err := h.service.OpenAccount(principal, customerID, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(account)
+}
+
+// GetCustomerAccounts lists every account held by a customer.
+func (h *AccountHandler) GetCustomerAccounts(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	customerID, err := strconv.Atoi(vars["customer_id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid customer ID")
+		return
+	}
+
+	accounts, err := h.service.GetCustomerAccounts(customerID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(accounts)
+}
+
+// GetBalance reports the ledger balance and the available balance, which
+// leaves out held funds. With as_of (RFC 3339) it reports the ledger
+// balance at that time instead.
 func (h *AccountHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid account ID", http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
 		return
 	}
-	accountType := r.URL.Query().Get("type")
-	if accountType == "" {
-		http.Error(w, "Account type is required", http.StatusBadRequest)
+
+	if v := r.URL.Query().Get("as_of"); v != "" {
+		asOf, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid as_of date")
+			return
+		}
+		if asOf.After(time.Now()) {
+			writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "as_of must not be in the future")
+			return
+		}
+		balance, err := h.service.GetBalanceAsOf(id, asOf)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(balance)
 		return
 	}
 
-	balance, err := h.service.GetBalance(id, accountType)
+	balance, err := h.service.GetBalance(id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]float64{"balance": balance})
+	json.NewEncoder(w).Encode(balance)
 }
 
+// GetTransactions lists the account's history, newest first. It accepts
+// the optional filters from, to (RFC 3339), direction (credit or debit),
+// min_amount, max_amount, limit and the cursor returned by the previous page.
+func (h *AccountHandler) GetTransactions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
+		return
+	}
+
+	filter, err := parseTransactionFilter(r.URL.Query())
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.service.GetTransactions(id, filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+func parseTransactionFilter(q url.Values) (models.TransactionFilter, error) {
+	var filter models.TransactionFilter
+
+	if v := q.Get("from"); v != "" {
+		from, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, errors.New("Invalid from date")
+		}
+		filter.From = &from
+	}
+	if v := q.Get("to"); v != "" {
+		to, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, errors.New("Invalid to date")
+		}
+		filter.To = &to
+	}
+	if v := q.Get("direction"); v != "" {
+		if v != ledger.Credit && v != ledger.Debit {
+			return filter, errors.New("Direction must be credit or debit")
+		}
+		filter.Direction = v
+	}
+	if v := q.Get("min_amount"); v != "" {
+		amount, err := money.Parse(v)
+		if err != nil {
+			return filter, errors.New("Invalid min_amount")
+		}
+		filter.MinAmount = &amount
+	}
+	if v := q.Get("max_amount"); v != "" {
+		amount, err := money.Parse(v)
+		if err != nil {
+			return filter, errors.New("Invalid max_amount")
+		}
+		filter.MaxAmount = &amount
+	}
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return filter, errors.New("Invalid limit")
+		}
+		filter.Limit = limit
+	}
+	if v := q.Get("cursor"); v != "" {
+		beforeID, err := services.DecodeCursor(v)
+		if err != nil {
+			return filter, errors.New("Invalid cursor")
+		}
+		filter.BeforeID = beforeID
+	}
+	return filter, nil
+}
+
+// AmountRequest carries the amount of a deposit or withdrawal. Currency
+// is optional; when set it must be the account's.
 type AmountRequest struct {
-	Amount float64 `json:"amount"`
+	Amount   money.Money `json:"amount"`
+	Currency string      `json:"currency,omitempty"`
+}
+
+// decodeAmountRequest decodes the body of a deposit, withdrawal or
+// transfer into dst and reports whether it could; when it could not, the
+// problem has been written. An amount with fractions of a cent is a
+// validation error on the amount rather than a malformed body.
+func decodeAmountRequest(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(dst)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, money.ErrSubCent):
+		writeError(w, r, validation.Errors{validation.SubCentError("amount")})
+	default:
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+	}
+	return false
 }
 
 func (h *AccountHandler) Deposit(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid account ID", http.StatusBadRequest)
-		return
-	}
-	accountType := r.URL.Query().Get("type")
-	if accountType == "" {
-		http.Error(w, "Account type is required", http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
 		return
 	}
 
 	var req AmountRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeAmountRequest(w, r, &req) {
 		return
 	}
 
-	if err := h.service.Deposit(id, req.Amount, accountType); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	principal, _ := auth.FromContext(r.Context())
+	if err := h.service.Deposit(principal, id, req.Amount, req.Currency); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
@@ -100,23 +279,18 @@ func (h *AccountHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid account ID", http.StatusBadRequest)
-		return
-	}
-	accountType := r.URL.Query().Get("type")
-	if accountType == "" {
-		http.Error(w, "Account type is required", http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
 		return
 	}
 
 	var req AmountRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeAmountRequest(w, r, &req) {
 		return
 	}
 
-	if err := h.service.Withdraw(id, req.Amount, accountType); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	principal, _ := auth.FromContext(r.Context())
+	if err := h.service.Withdraw(principal, id, req.Amount, req.Currency); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
@@ -124,48 +298,193 @@ func (h *AccountHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"message": "Withdrawal successful"})
 }
 
+// TransferRequest identifies each side either by internal ID or by account
+// number. An account number takes precedence over the ID. Amount is in the
+// source account's currency. QuoteID applies a quote made for the transfer
+// between accounts in different currencies.
 type TransferRequest struct {
-	FromID   int     `json:"from_id"`
-	ToID     int     `json:"to_id"`
-	FromType string  `json:"from_type"`
-	ToType   string  `json:"to_type"`
-	Amount   float64 `json:"amount"`
+	FromID      int         `json:"from_id"`
+	ToID        int         `json:"to_id"`
+	FromAccount string      `json:"from_account,omitempty"`
+	ToAccount   string      `json:"to_account,omitempty"`
+	Amount      money.Money `json:"amount"`
+	QuoteID     int64       `json:"quote_id,omitempty"`
 }
 
 func (h *AccountHandler) Transfer(w http.ResponseWriter, r *http.Request) {
 	var req TransferRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeAmountRequest(w, r, &req) {
+		return
+	}
+
+	// Check both numbers before looking either of them up.
+	var from, to accountnumber.Number
+	var err error
+	if req.FromAccount != "" {
+		if from, err = accountnumber.Parse(req.FromAccount); err != nil {
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidAccountNumber, "Invalid from_account number")
+			return
+		}
+	}
+	if req.ToAccount != "" {
+		if to, err = accountnumber.Parse(req.ToAccount); err != nil {
+			writeProblem(w, r, http.StatusBadRequest, CodeInvalidAccountNumber, "Invalid to_account number")
+			return
+		}
+	}
+	if req.FromAccount != "" {
+		account, err := h.service.GetAccountByNumber(from)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		req.FromID = account.ID
+	}
+	if req.ToAccount != "" {
+		account, err := h.service.GetAccountByNumber(to)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		req.ToID = account.ID
+	}
+
+	// Money can be sent to anyone's account, but only from the caller's own.
+	if err := authorizeAccount(h.service, r, req.FromID); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	if err := h.service.Transfer(req.FromID, req.ToID, req.Amount, req.FromType, req.ToType); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	// authorizeAccount has made sure there is a principal.
+	principal, _ := auth.FromContext(r.Context())
+	approval, err := h.service.Transfer(principal, req.FromID, req.ToID, req.Amount, req.QuoteID)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	if approval != nil {
+		// Large transfers wait for an operator to approve them.
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]interface{}{"message": "Transfer awaiting approval", "approval": approval})
+		return
+	}
 	json.NewEncoder(w).Encode(map[string]string{"message": "Transfer successful"})
 }
 
+// FreezeAccount stops an account from being debited. The body carries the
+// reason.
+func (h *AccountHandler) FreezeAccount(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.service.FreezeAccount, "Account frozen")
+}
+
+func (h *AccountHandler) UnfreezeAccount(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.service.UnfreezeAccount, "Account unfrozen")
+}
+
+// ActivateAccount makes a pending or dormant account active again.
+func (h *AccountHandler) ActivateAccount(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.service.ActivateAccount, "Account activated")
+}
+
+type statusChanger func(actor *auth.Principal, accountID int, request models.StatusChangeRequest) (*models.AccountStatusChange, error)
+
+func (h *AccountHandler) changeStatus(w http.ResponseWriter, r *http.Request, change statusChanger, message string) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
+		return
+	}
+
+	var req models.StatusChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	statusChange, err := change(principal, id, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"message": message, "status_change": statusChange})
+}
+
+// ListStatusChanges returns an account's status history, newest first.
+func (h *AccountHandler) ListStatusChanges(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
+		return
+	}
+
+	changes, err := h.service.ListStatusChanges(id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(changes)
+}
+
+// CorrectBalance sets an account's balance. The difference is journaled as
+// an adjustment.
+func (h *AccountHandler) CorrectBalance(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
+		return
+	}
+
+	var req models.BalanceCorrectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	if err := h.service.CorrectBalance(principal, id, req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Balance corrected"})
+}
+
+// CloseAccount closes an account for good. The body carries the reason and,
+// when the account still has a balance, the account to pay it out to.
 func (h *AccountHandler) CloseAccount(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid account ID", http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
 		return
 	}
-	accountType := r.URL.Query().Get("type")
-	if accountType == "" {
-		http.Error(w, "Account type is required", http.StatusBadRequest)
+
+	// DELETE requests may come without a body; the missing reason is then
+	// reported like any other invalid field.
+	var req models.CloseAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
 		return
 	}
 
-	if err := h.service.CloseAccount(id, accountType); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	principal, _ := auth.FromContext(r.Context())
+	change, err := h.service.CloseAccount(principal, id, req)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"message": "Account closed successfully"})
+	json.NewEncoder(w).Encode(map[string]interface{}{"message": "Account closed successfully", "status_change": change})
 }
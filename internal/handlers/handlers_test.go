@@ -5,12 +5,20 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	repomocks "github.com/gregoryAlvim/gobank/internal/repositories/mocks"
+	"github.com/gregoryAlvim/gobank/internal/services"
 	"github.com/gregoryAlvim/gobank/internal/services/mocks"
 )
 
@@ -19,6 +27,7 @@ func TestAccountHandler_CreateAccount(t *testing.T) {
 	handler := NewAccountHandler(mockService)
 
 	requestBody := map[string]interface{}{
+		"cpf":            "529.982.247-25",
 		"monthly_income": 5000,
 		"age":            30,
 		"full_name":      "John Doe",
@@ -36,23 +45,63 @@ func TestAccountHandler_CreateAccount(t *testing.T) {
 
 	rr := httptest.NewRecorder()
 
-	mockService.On("CreateAccount", "natural", mock.Anything).Return(nil)
+	mockService.On("CreateAccount", "natural", mock.Anything, mock.Anything).Return(&models.Account{
+		ID:         7,
+		CustomerID: 3,
+		Branch:     "0001",
+		Number:     "00000007",
+		CheckDigit: "6",
+		Category:   "standard",
+		Balance:    money.New(1000, 0),
+	}, nil)
 
 	handler.CreateAccount(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 
-	expectedResponse := `{"message":"Account created successfully"}`
+	expectedResponse := `{"message":"Account created successfully","customer_id":3,"account_id":7,"account_number":"0001-00000007-6"}`
 	assert.JSONEq(t, expectedResponse, rr.Body.String())
 
 	mockService.AssertExpectations(t)
 }
 
+func TestAccountHandler_CreateAccount_DuplicateTaxID(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"duplicate CPF", repositories.ErrDuplicateCPF, http.StatusConflict},
+		{"duplicate CNPJ", repositories.ErrDuplicateCNPJ, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.AccountServiceInterface)
+			handler := NewAccountHandler(mockService)
+
+			req, err := http.NewRequest("POST", "/account?type=natural", bytes.NewBufferString(`{"cpf": "529.982.247-25"}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+
+			mockService.On("CreateAccount", "natural", mock.Anything, mock.Anything).Return(nil, tt.err)
+
+			handler.CreateAccount(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.err.Error())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestAccountHandler_GetBalance(t *testing.T) {
 	mockService := new(mocks.AccountServiceInterface)
 	handler := NewAccountHandler(mockService)
 
-	req, err := http.NewRequest("GET", "/account/1/balance?type=natural", nil)
+	req, err := http.NewRequest("GET", "/account/1/balance", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -64,18 +113,43 @@ func TestAccountHandler_GetBalance(t *testing.T) {
 	}
 	req = mux.SetURLVars(req, vars)
 
-	mockService.On("GetBalance", 1, "natural").Return(123.45, nil)
+	mockService.On("GetBalance", 1).Return(&models.Balance{Ledger: money.FromCents(12345), Available: money.New(100, 0)}, nil)
 
 	handler.GetBalance(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 
-	expectedResponse := `{"balance":123.45}`
+	expectedResponse := `{"ledger":123.45,"available":100.00}`
 	assert.JSONEq(t, expectedResponse, rr.Body.String())
 
 	mockService.AssertExpectations(t)
 }
 
+func TestAccountHandler_GetBalance_AsOf(t *testing.T) {
+	mockService := new(mocks.AccountServiceInterface)
+	handler := NewAccountHandler(mockService)
+
+	asOf := time.Date(2026, 1, 31, 23, 59, 59, 0, time.UTC)
+	mockService.On("GetBalanceAsOf", 1, asOf).Return(&models.BalanceAsOf{Ledger: money.FromCents(12050), AsOf: asOf}, nil)
+
+	req := mux.SetURLVars(httptest.NewRequest("GET", "/account/1/balance?as_of=2026-01-31T23:59:59Z", nil), map[string]string{"id": "1"})
+	rr := httptest.NewRecorder()
+	handler.GetBalance(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"ledger":120.50,"as_of":"2026-01-31T23:59:59Z"}`, rr.Body.String())
+
+	for _, asOf := range []string{"2026-01-31", time.Now().Add(time.Hour).Format(time.RFC3339)} {
+		req := mux.SetURLVars(httptest.NewRequest("GET", "/account/1/balance?as_of="+url.QueryEscape(asOf), nil), map[string]string{"id": "1"})
+		rr := httptest.NewRecorder()
+		handler.GetBalance(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, asOf)
+	}
+
+	mockService.AssertExpectations(t)
+}
+
 func TestAccountHandler_Deposit(t *testing.T) {
 	mockService := new(mocks.AccountServiceInterface)
 	handler := NewAccountHandler(mockService)
@@ -83,7 +157,7 @@ func TestAccountHandler_Deposit(t *testing.T) {
 	requestBody := map[string]interface{}{"amount": 100}
 	body, _ := json.Marshal(requestBody)
 
-	req, err := http.NewRequest("POST", "/account/1/deposit?type=natural", bytes.NewBuffer(body))
+	req, err := http.NewRequest("POST", "/account/1/deposit", bytes.NewBuffer(body))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -95,7 +169,7 @@ func TestAccountHandler_Deposit(t *testing.T) {
 	}
 	req = mux.SetURLVars(req, vars)
 
-	mockService.On("Deposit", 1, 100.0, "natural").Return(nil)
+	mockService.On("Deposit", mock.Anything, 1, money.New(100, 0), "").Return(nil)
 
 	handler.Deposit(rr, req)
 
@@ -114,7 +188,7 @@ func TestAccountHandler_Withdraw(t *testing.T) {
 	requestBody := map[string]interface{}{"amount": 50}
 	body, _ := json.Marshal(requestBody)
 
-	req, err := http.NewRequest("POST", "/account/1/withdraw?type=natural", bytes.NewBuffer(body))
+	req, err := http.NewRequest("POST", "/account/1/withdraw", bytes.NewBuffer(body))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -126,7 +200,7 @@ func TestAccountHandler_Withdraw(t *testing.T) {
 	}
 	req = mux.SetURLVars(req, vars)
 
-	mockService.On("Withdraw", 1, 50.0, "natural").Return(nil)
+	mockService.On("Withdraw", mock.Anything, 1, money.New(50, 0), "").Return(nil)
 
 	handler.Withdraw(rr, req)
 
@@ -143,11 +217,9 @@ func TestAccountHandler_Transfer(t *testing.T) {
 	handler := NewAccountHandler(mockService)
 
 	requestBody := map[string]interface{}{
-		"from_id":   1,
-		"to_id":     2,
-		"from_type": "natural",
-		"to_type":   "legal",
-		"amount":    100,
+		"from_id": 1,
+		"to_id":   2,
+		"amount":  100,
 	}
 	body, _ := json.Marshal(requestBody)
 
@@ -155,10 +227,12 @@ func TestAccountHandler_Transfer(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	req = withPrincipal(req, 3)
 
 	rr := httptest.NewRecorder()
 
-	mockService.On("Transfer", 1, 2, 100.0, "natural", "legal").Return(nil)
+	mockService.On("GetAccount", 1).Return(&models.Account{ID: 1, CustomerID: 3}, nil)
+	mockService.On("Transfer", mock.Anything, 1, 2, money.New(100, 0), int64(0)).Return(nil, nil)
 
 	handler.Transfer(rr, req)
 
@@ -174,7 +248,7 @@ func TestAccountHandler_CloseAccount(t *testing.T) {
 	mockService := new(mocks.AccountServiceInterface)
 	handler := NewAccountHandler(mockService)
 
-	req, err := http.NewRequest("DELETE", "/account/1?type=natural", nil)
+	req, err := http.NewRequest("DELETE", "/account/1", bytes.NewBufferString(`{"reason": "moving abroad", "payout_account_id": 2}`))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -185,14 +259,306 @@ func TestAccountHandler_CloseAccount(t *testing.T) {
 		"id": "1",
 	}
 	req = mux.SetURLVars(req, vars)
+	req = withPrincipal(req, 1)
 
-	mockService.On("CloseAccount", 1, "natural").Return(nil)
+	payoutID, payout := 2, money.New(10, 0)
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockService.On("CloseAccount", mock.MatchedBy(func(p *auth.Principal) bool { return p.CustomerID == 1 }), 1,
+		models.CloseAccountRequest{Reason: "moving abroad", PayoutAccountID: 2}).
+		Return(&models.AccountStatusChange{ID: 3, AccountID: 1, FromStatus: "active", ToStatus: "closed", Reason: "moving abroad",
+			ChangedByCustomerID: 1, PayoutAccountID: &payoutID, PayoutAmount: &payout, CreatedAt: createdAt}, nil)
 
 	handler.CloseAccount(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 
-	expectedResponse := `{"message":"Account closed successfully"}`
+	expectedResponse := `{"message":"Account closed successfully","status_change":{"id":3,"account_id":1,"from_status":"active",
+		"to_status":"closed","reason":"moving abroad","changed_by_customer_id":1,"payout_account_id":2,"payout_amount":10.00,
+		"created_at":"2026-03-01T12:00:00Z"}}`
+	assert.JSONEq(t, expectedResponse, rr.Body.String())
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAccountHandler_AccountLifecycle_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		handle     func(h *AccountHandler) http.HandlerFunc
+		body       string
+		setup      func(repo *repomocks.AccountRepository)
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name: "payout to another customer's account", handle: func(h *AccountHandler) http.HandlerFunc { return h.CloseAccount },
+			body: `{"reason": "moving abroad", "payout_account_id": 30}`,
+			setup: func(repo *repomocks.AccountRepository) {
+				repo.On("GetAccount", 20).Return(&models.Account{ID: 20, CustomerID: 4, Status: "active"}, nil)
+				repo.On("GetAccount", 30).Return(&models.Account{ID: 30, CustomerID: 5, Status: "active"}, nil)
+			},
+			wantStatus: http.StatusUnprocessableEntity, wantCode: CodePayoutAccount,
+		},
+		{
+			name: "payout to the same account", handle: func(h *AccountHandler) http.HandlerFunc { return h.CloseAccount },
+			body:       `{"reason": "moving abroad", "payout_account_id": 20}`,
+			setup:      func(repo *repomocks.AccountRepository) {},
+			wantStatus: http.StatusUnprocessableEntity, wantCode: CodeSameAccount,
+		},
+		{
+			name: "close with balance left", handle: func(h *AccountHandler) http.HandlerFunc { return h.CloseAccount },
+			body: `{"reason": "moving abroad"}`,
+			setup: func(repo *repomocks.AccountRepository) {
+				repo.On("CloseAccount", mock.Anything, 0, mock.Anything).Return(repositories.ErrBalanceRemaining)
+			},
+			wantStatus: http.StatusConflict, wantCode: CodeBalanceRemaining,
+		},
+		{
+			name: "unfreeze an active account", handle: func(h *AccountHandler) http.HandlerFunc { return h.UnfreezeAccount },
+			body: `{"reason": "cleared"}`,
+			setup: func(repo *repomocks.AccountRepository) {
+				repo.On("GetAccount", 20).Return(&models.Account{ID: 20, CustomerID: 4, Status: "active"}, nil)
+			},
+			wantStatus: http.StatusConflict, wantCode: CodeStatusTransition,
+		},
+		{
+			name: "activate a closed account", handle: func(h *AccountHandler) http.HandlerFunc { return h.ActivateAccount },
+			body: `{"reason": "customer came back"}`,
+			setup: func(repo *repomocks.AccountRepository) {
+				repo.On("GetAccount", 20).Return(&models.Account{ID: 20, CustomerID: 4, Status: "closed"}, nil)
+			},
+			wantStatus: http.StatusConflict, wantCode: CodeStatusTransition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repomocks.NewAccountRepository(t)
+			tt.setup(repo)
+			handler := NewAccountHandler(services.NewAccountService(repo))
+
+			req, _ := http.NewRequest("POST", "/account/20", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": "20"})
+			req = withOperator(req, 7, auth.RoleSupervisor)
+			rr := httptest.NewRecorder()
+			tt.handle(handler)(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), `"code":"`+tt.wantCode+`"`)
+		})
+	}
+}
+
+func TestAccountHandler_ActivateAccount(t *testing.T) {
+	repo := repomocks.NewAccountRepository(t)
+	handler := NewAccountHandler(services.NewAccountService(repo))
+	repo.On("GetAccount", 20).Return(&models.Account{ID: 20, CustomerID: 4, Status: "dormant"}, nil)
+	repo.On("ChangeAccountStatus", &models.AccountStatusChange{AccountID: 20, ToStatus: "active", Reason: "customer came back", ChangedByOperatorID: 7},
+		models.Actor{OperatorID: 7}).
+		Run(func(args mock.Arguments) {
+			change := args.Get(0).(*models.AccountStatusChange)
+			change.ID, change.FromStatus = 5, "dormant"
+		}).Return(nil)
+
+	req, _ := http.NewRequest("POST", "/account/20/activate", bytes.NewBufferString(`{"reason": "customer came back"}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "20"})
+	rr := httptest.NewRecorder()
+	handler.ActivateAccount(rr, withOperator(req, 7, auth.RoleTeller))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"message":"Account activated","status_change":{"id":5,"account_id":20,"from_status":"dormant",
+		"to_status":"active","reason":"customer came back","changed_by_operator_id":7,"created_at":"0001-01-01T00:00:00Z"}}`, rr.Body.String())
+}
+
+func TestAccountHandler_Deposit_ExactDecimalAmount(t *testing.T) {
+	mockService := new(mocks.AccountServiceInterface)
+	handler := NewAccountHandler(mockService)
+
+	req, err := http.NewRequest("POST", "/account/1/deposit", bytes.NewBufferString(`{"amount": 0.10}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	rr := httptest.NewRecorder()
+
+	mockService.On("Deposit", mock.Anything, 1, money.FromCents(10), "").Return(nil)
+
+	handler.Deposit(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAccountHandler_Deposit_SubCentAmount(t *testing.T) {
+	mockService := new(mocks.AccountServiceInterface)
+	handler := NewAccountHandler(mockService)
+
+	req, err := http.NewRequest("POST", "/account/1/deposit", bytes.NewBufferString(`{"amount": 10.005}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	rr := httptest.NewRecorder()
+
+	handler.Deposit(rr, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"field":"amount"`)
+	mockService.AssertNotCalled(t, "Deposit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAccountHandler_GetTransactions(t *testing.T) {
+	mockService := new(mocks.AccountServiceInterface)
+	handler := NewAccountHandler(mockService)
+
+	cursor := services.EncodeCursor(42)
+	req, err := http.NewRequest("GET", "/account/1/transactions?from=2026-01-01T00:00:00Z&to=2026-02-01T00:00:00Z&direction=debit&min_amount=10&max_amount=50.5&limit=2&cursor="+cursor, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	rr := httptest.NewRecorder()
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	minAmount, maxAmount := money.New(10, 0), money.New(50, 50)
+	filter := models.TransactionFilter{
+		From:      &from,
+		To:        &to,
+		Direction: "debit",
+		MinAmount: &minAmount,
+		MaxAmount: &maxAmount,
+		BeforeID:  42,
+		Limit:     2,
+	}
+	createdAt := time.Date(2026, 1, 15, 9, 30, 0, 0, time.UTC)
+	counterpartyID := 2
+	mockService.On("GetTransactions", 1, filter).Return(&models.TransactionPage{
+		Transactions: []models.Transaction{
+			{ID: 41, JournalEntryID: 20, Kind: "transfer", Direction: "debit", Amount: money.New(20, 0), BalanceAfter: money.New(80, 0),
+				CounterpartyID: &counterpartyID, CreatedAt: createdAt},
+			{ID: 39, JournalEntryID: 18, Kind: "withdrawal", Direction: "debit", Amount: money.New(10, 0), BalanceAfter: money.New(100, 0),
+				CreatedAt: createdAt},
+		},
+		NextCursor: services.EncodeCursor(39),
+	}, nil)
+
+	handler.GetTransactions(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	expectedResponse := `{
+		"transactions": [
+			{"id": 41, "journal_entry_id": 20, "kind": "transfer", "direction": "debit", "amount": 20.00, "balance_after": 80.00,
+			 "counterparty_id": 2, "created_at": "2026-01-15T09:30:00Z"},
+			{"id": 39, "journal_entry_id": 18, "kind": "withdrawal", "direction": "debit", "amount": 10.00, "balance_after": 100.00,
+			 "created_at": "2026-01-15T09:30:00Z"}
+		],
+		"next_cursor": "` + services.EncodeCursor(39) + `"
+	}`
+	assert.JSONEq(t, expectedResponse, rr.Body.String())
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAccountHandler_GetTransactions_InvalidFilters(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"from", "from=yesterday"},
+		{"to", "to=2026-13-01"},
+		{"direction", "direction=sideways"},
+		{"min_amount", "min_amount=ten"},
+		{"max_amount", "max_amount=1,5"},
+		{"limit", "limit=0"},
+		{"cursor", "cursor=not-a-cursor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.AccountServiceInterface)
+			handler := NewAccountHandler(mockService)
+
+			req, err := http.NewRequest("GET", "/account/1/transactions?"+tt.query, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+			rr := httptest.NewRecorder()
+
+			handler.GetTransactions(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			mockService.AssertNotCalled(t, "GetTransactions", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAccountHandler_OpenAccount(t *testing.T) {
+	mockService := new(mocks.AccountServiceInterface)
+	handler := NewAccountHandler(mockService)
+
+	req, err := http.NewRequest("POST", "/customers/3/accounts", bytes.NewBufferString(`{"category": "savings", "balance": 50, "currency": "USD"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = mux.SetURLVars(req, map[string]string{"customer_id": "3"})
+
+	rr := httptest.NewRecorder()
+
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockService.On("OpenAccount", mock.Anything, 3, models.OpenAccountRequest{Category: "savings", Balance: money.New(50, 0), Currency: "USD"}).Return(&models.Account{
+		ID:         8,
+		CustomerID: 3,
+		Branch:     "0001",
+		Number:     "00000008",
+		CheckDigit: "4",
+		Category:   "savings",
+		Balance:    money.New(50, 0),
+		Currency:   "USD",
+		Status:     "active",
+		CreatedAt:  createdAt,
+	}, nil)
+
+	handler.OpenAccount(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	expectedResponse := `{"id": 8, "customer_id": 3, "branch": "0001", "number": "00000008", "check_digit": "4", "category": "savings", "balance": 50.00, "currency": "USD", "status": "active", "created_at": "2026-03-01T12:00:00Z"}`
+	assert.JSONEq(t, expectedResponse, rr.Body.String())
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAccountHandler_GetCustomerAccounts(t *testing.T) {
+	mockService := new(mocks.AccountServiceInterface)
+	handler := NewAccountHandler(mockService)
+
+	req, err := http.NewRequest("GET", "/customers/3/accounts", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = mux.SetURLVars(req, map[string]string{"customer_id": "3"})
+
+	rr := httptest.NewRecorder()
+
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockService.On("GetCustomerAccounts", 3).Return([]models.Account{
+		{ID: 7, CustomerID: 3, Branch: "0001", Number: "00000007", CheckDigit: "6", Category: "standard", Balance: money.New(1000, 0), Currency: "BRL", Status: "active", CreatedAt: createdAt},
+		{ID: 8, CustomerID: 3, Branch: "0001", Number: "00000008", CheckDigit: "4", Category: "savings", Balance: money.New(50, 0), Currency: "BRL", Status: "active", CreatedAt: createdAt},
+	}, nil)
+
+	handler.GetCustomerAccounts(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	expectedResponse := `[
+		{"id": 7, "customer_id": 3, "branch": "0001", "number": "00000007", "check_digit": "6", "category": "standard", "balance": 1000.00, "currency": "BRL", "status": "active", "created_at": "2026-03-01T12:00:00Z"},
+		{"id": 8, "customer_id": 3, "branch": "0001", "number": "00000008", "check_digit": "4", "category": "savings", "balance": 50.00, "currency": "BRL", "status": "active", "created_at": "2026-03-01T12:00:00Z"}
+	]`
 	assert.JSONEq(t, expectedResponse, rr.Body.String())
 
 	mockService.AssertExpectations(t)
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/services"
+)
+
+// PlaceHold reserves an amount on the account, as a card authorization
+// does.
+func (h *AccountHandler) PlaceHold(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
+		return
+	}
+
+	var req models.HoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+		return
+	}
+
+	hold, err := h.service.PlaceHold(id, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(hold)
+}
+
+// ListHolds lists the account's holds, newest first. It accepts the
+// optional status filter, limit and the cursor returned by the previous
+// page.
+func (h *AccountHandler) ListHolds(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
+		return
+	}
+
+	filter, err := parseHoldFilter(r.URL.Query())
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.service.ListHolds(id, filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// CaptureHold settles a hold. The body, with an optional amount for a
+// partial capture, may be omitted.
+func (h *AccountHandler) CaptureHold(w http.ResponseWriter, r *http.Request) {
+	id, holdID, ok := holdRouteIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CaptureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	hold, err := h.service.CaptureHold(principal, id, holdID, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hold)
+}
+
+// VoidHold releases a hold without debiting the account.
+func (h *AccountHandler) VoidHold(w http.ResponseWriter, r *http.Request) {
+	id, holdID, ok := holdRouteIDs(w, r)
+	if !ok {
+		return
+	}
+
+	hold, err := h.service.VoidHold(id, holdID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hold)
+}
+
+// holdRouteIDs reads the account and hold IDs from the route, answering
+// with a 400 when either is invalid.
+func holdRouteIDs(w http.ResponseWriter, r *http.Request) (int, int64, bool) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
+		return 0, 0, false
+	}
+	holdID, err := strconv.ParseInt(vars["hold_id"], 10, 64)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid hold ID")
+		return 0, 0, false
+	}
+	return id, holdID, true
+}
+
+func parseHoldFilter(q url.Values) (models.HoldFilter, error) {
+	var filter models.HoldFilter
+
+	switch status := q.Get("status"); status {
+	case "", models.HoldActive, models.HoldCaptured, models.HoldVoided, models.HoldExpired:
+		filter.Status = status
+	default:
+		return filter, errors.New("Invalid status")
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return filter, errors.New("Invalid limit")
+		}
+		filter.Limit = limit
+	}
+	if v := q.Get("cursor"); v != "" {
+		beforeID, err := services.DecodeCursor(v)
+		if err != nil {
+			return filter, errors.New("Invalid cursor")
+		}
+		filter.BeforeID = beforeID
+	}
+	return filter, nil
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	repomocks "github.com/gregoryAlvim/gobank/internal/repositories/mocks"
+	"github.com/gregoryAlvim/gobank/internal/services"
+	"github.com/gregoryAlvim/gobank/internal/services/mocks"
+)
+
+func TestAccountHandler_PlaceHold(t *testing.T) {
+	repo := repomocks.NewAccountRepository(t)
+	handler := NewAccountHandler(services.NewAccountService(repo))
+
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo.On("CreateHold", mock.MatchedBy(func(h *models.Hold) bool {
+		ttl := time.Until(h.ExpiresAt)
+		return h.AccountID == 10 && h.Amount == money.New(60, 0) && h.Description == "Hotel" &&
+			ttl > services.DefaultHoldTTL-time.Hour && ttl <= services.DefaultHoldTTL
+	})).Run(func(args mock.Arguments) {
+		h := args.Get(0).(*models.Hold)
+		h.ID, h.Status, h.CreatedAt = 5, models.HoldActive, createdAt
+		h.ExpiresAt = createdAt.Add(services.DefaultHoldTTL)
+	}).Return(nil)
+
+	req, _ := http.NewRequest("POST", "/account/10/holds", bytes.NewBufferString(`{"amount": 60, "description": "Hotel"}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "10"})
+	rr := httptest.NewRecorder()
+	handler.PlaceHold(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.JSONEq(t, `{
+		"id": 5, "account_id": 10, "amount": 60.00, "status": "active", "description": "Hotel",
+		"expires_at": "2026-03-08T12:00:00Z", "created_at": "2026-03-01T12:00:00Z"
+	}`, rr.Body.String())
+
+	tests := []struct {
+		name      string
+		body      string
+		wantField string
+	}{
+		{"zero amount", `{"amount": 0}`, "amount"},
+		{"expired", `{"amount": 10, "expires_at": "2020-01-01T00:00:00Z"}`, "expires_at"},
+		{"too long", `{"amount": 10, "expires_at": "` + time.Now().Add(services.MaxHoldTTL+time.Hour).Format(time.RFC3339) + `"}`, "expires_at"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/account/10/holds", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": "10"})
+			rr := httptest.NewRecorder()
+			handler.PlaceHold(rr, req)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+			assert.Contains(t, rr.Body.String(), `"field":"`+tt.wantField+`"`)
+		})
+	}
+}
+
+func TestAccountHandler_ListHolds(t *testing.T) {
+	service := new(mocks.AccountServiceInterface)
+	handler := NewAccountHandler(service)
+
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service.On("ListHolds", 10, models.HoldFilter{Status: "active", Limit: 10}).Return(&models.HoldPage{
+		Holds: []models.Hold{{
+			ID: 5, AccountID: 10, Amount: money.New(60, 0), Status: "active",
+			ExpiresAt: createdAt.Add(services.DefaultHoldTTL), CreatedAt: createdAt,
+		}},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/account/10/holds?status=active&limit=10", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "10"})
+	rr := httptest.NewRecorder()
+	handler.ListHolds(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"holds": [{
+		"id": 5, "account_id": 10, "amount": 60.00, "status": "active",
+		"expires_at": "2026-03-08T12:00:00Z", "created_at": "2026-03-01T12:00:00Z"
+	}]}`, rr.Body.String())
+
+	for _, query := range []string{"status=open", "limit=x", "cursor=not-a-cursor"} {
+		req, _ := http.NewRequest("GET", "/account/10/holds?"+query, nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "10"})
+		rr := httptest.NewRecorder()
+		handler.ListHolds(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+	service.AssertExpectations(t)
+}
+
+func TestAccountHandler_CaptureAndVoidHold(t *testing.T) {
+	partial := money.New(45, 0)
+
+	tests := []struct {
+		name       string
+		void       bool
+		body       string
+		holdID     string
+		setup      func(repo *repomocks.AccountRepository)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:   "full capture without a body",
+			holdID: "5",
+			setup: func(repo *repomocks.AccountRepository) {
+				repo.On("CaptureHold", 10, int64(5), money.Zero, models.Actor{CustomerID: 3}).Return(&models.Hold{ID: 5, Status: models.HoldCaptured}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"status":"captured"`,
+		},
+		{
+			name:   "partial capture",
+			holdID: "5",
+			body:   `{"amount": 45}`,
+			setup: func(repo *repomocks.AccountRepository) {
+				repo.On("CaptureHold", 10, int64(5), partial, models.Actor{CustomerID: 3}).Return(&models.Hold{ID: 5, Status: models.HoldCaptured, CapturedAmount: &partial}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"captured_amount":45.00`,
+		},
+		{
+			name:       "negative capture",
+			holdID:     "5",
+			body:       `{"amount": -1}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   `"field":"amount"`,
+		},
+		{
+			name:   "capture more than held",
+			holdID: "5",
+			body:   `{"amount": 100}`,
+			setup: func(repo *repomocks.AccountRepository) {
+				repo.On("CaptureHold", 10, int64(5), money.New(100, 0), models.Actor{CustomerID: 3}).Return(nil, repositories.ErrCaptureExceedsHold)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   `"code":"capture_exceeds_hold"`,
+		},
+		{
+			name:   "capture expired hold",
+			holdID: "5",
+			setup: func(repo *repomocks.AccountRepository) {
+				repo.On("CaptureHold", 10, int64(5), money.Zero, models.Actor{CustomerID: 3}).Return(nil, repositories.ErrHoldExpired)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   `"code":"hold_expired"`,
+		},
+		{
+			name:   "void",
+			void:   true,
+			holdID: "5",
+			setup: func(repo *repomocks.AccountRepository) {
+				repo.On("VoidHold", 10, int64(5)).Return(&models.Hold{ID: 5, Status: models.HoldVoided}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"status":"voided"`,
+		},
+		{
+			name:   "void twice",
+			void:   true,
+			holdID: "5",
+			setup: func(repo *repomocks.AccountRepository) {
+				repo.On("VoidHold", 10, int64(5)).Return(nil, repositories.ErrHoldReleased)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   `"code":"hold_already_released"`,
+		},
+		{
+			name:   "unknown hold",
+			void:   true,
+			holdID: "99",
+			setup: func(repo *repomocks.AccountRepository) {
+				repo.On("VoidHold", 10, int64(99)).Return(nil, repositories.ErrHoldNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   `"code":"hold_not_found"`,
+		},
+		{
+			name:       "invalid hold ID",
+			holdID:     "abc",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `"code":"bad_request"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repomocks.NewAccountRepository(t)
+			if tt.setup != nil {
+				tt.setup(repo)
+			}
+			handler := NewAccountHandler(services.NewAccountService(repo))
+			handle := handler.CaptureHold
+			if tt.void {
+				handle = handler.VoidHold
+			}
+
+			req, _ := http.NewRequest("POST", "/account/10/holds/"+tt.holdID, bytes.NewBufferString(tt.body))
+			req = withPrincipal(mux.SetURLVars(req, map[string]string{"id": "10", "hold_id": tt.holdID}), 3)
+			rr := httptest.NewRecorder()
+			handle(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.wantBody)
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses served from the store.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLength leaves room in the 255-character column for
+	// the caller prefix added by scopedKey.
+	maxIdempotencyKeyLength = 200
+)
+
+// IdempotencyMiddleware makes money-moving endpoints safe to retry. The
+// first response for an Idempotency-Key is stored together with a hash of
+// the request; repeating the same request replays that response instead of
+// running the handler again. Reusing a key for a different request is
+// rejected with 422. Requests without the header are passed through. Keys
+// are scoped to the authenticated customer or operator, so two callers can
+// never see each other's responses by picking the same key. A key stays
+// locked to its first request for lease, renewed every third of it while
+// the handler runs; if that request dies without finishing, a retry can
+// take the key over once the lease is up instead of getting 409 until the
+// key expires. The reservation's token keeps a request whose key was
+// taken over from storing or releasing over the one that took it.
+type IdempotencyMiddleware struct {
+	store repositories.IdempotencyRepository
+	ttl   time.Duration
+	lease time.Duration
+	now   func() time.Time
+}
+
+func NewIdempotencyMiddleware(store repositories.IdempotencyRepository, ttl, lease time.Duration) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{store: store, ttl: ttl, lease: lease, now: time.Now}
+}
+
+func (m *IdempotencyMiddleware) Wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Idempotency-Key is too long")
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeProblem(w, r, http.StatusInternalServerError, CodeInternal, "Failed to read request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		key = scopedKey(r, key)
+
+		requestHash := hashRequest(r, body)
+		now := m.now()
+		record, token, err := m.store.Reserve(key, requestHash, now.Add(m.lease), now.Add(m.ttl))
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		if token == "" {
+			switch {
+			case record.RequestHash != requestHash:
+				writeProblem(w, r, http.StatusUnprocessableEntity, CodeIdempotencyMismatch, "Idempotency-Key was already used for a different request")
+			case record.StatusCode == 0:
+				writeProblem(w, r, http.StatusConflict, CodeIdempotencyInFlight, "A request with this Idempotency-Key is still being processed")
+			default:
+				if record.ContentType != "" {
+					w.Header().Set("Content-Type", record.ContentType)
+				}
+				w.Header().Set(IdempotentReplayedHeader, "true")
+				w.WriteHeader(record.StatusCode)
+				w.Write(record.ResponseBody)
+			}
+			return
+		}
+
+		// A handler that panics has not answered, so the client may retry.
+		defer func() {
+			if p := recover(); p != nil {
+				m.release(key, token)
+				panic(p)
+			}
+		}()
+		stopRenewing := m.renew(key, token)
+		defer stopRenewing()
+
+		rec := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		next(rec, r)
+		stopRenewing()
+
+		// Server errors are not stored so the client can retry them.
+		if rec.statusCode >= http.StatusInternalServerError {
+			m.release(key, token)
+			return
+		}
+		if err := m.store.Complete(key, token, rec.statusCode, rec.Header().Get("Content-Type"), rec.body.Bytes()); err != nil {
+			log.Printf("idempotency: failed to store response for key %q: %v", key, err)
+		}
+	}
+}
+
+// renew extends the reservation's lease every third of it until the
+// returned function is called, which may be done more than once.
+func (m *IdempotencyMiddleware) renew(key, token string) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(m.lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := m.store.Renew(key, token, m.now().Add(m.lease)); err != nil {
+					log.Printf("idempotency: failed to renew key %q: %v", key, err)
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+func (m *IdempotencyMiddleware) release(key, token string) {
+	if err := m.store.Release(key, token); err != nil {
+		log.Printf("idempotency: failed to release key %q: %v", key, err)
+	}
+}
+
+// scopedKey prefixes key with the scope of the authenticated caller, if
+// any.
+func scopedKey(r *http.Request, key string) string {
+	if principal, ok := auth.FromContext(r.Context()); ok {
+		return principal.Scope() + ":" + key
+	}
+	return key
+}
+
+// hashRequest fingerprints the method, target and body of a request.
+func hashRequest(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method)
+	io.WriteString(h, "\n")
+	io.WriteString(h, r.URL.RequestURI())
+	io.WriteString(h, "\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder passes the response through while keeping a copy of the
+// status code and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(statusCode int) {
+	if !rec.wroteHeader {
+		rec.statusCode = statusCode
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	repomocks "github.com/gregoryAlvim/gobank/internal/repositories/mocks"
+	"github.com/gregoryAlvim/gobank/internal/services/mocks"
+)
+
+var idempotencyNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newIdempotentDeposit(store *repomocks.IdempotencyRepository, service *mocks.AccountServiceInterface) http.HandlerFunc {
+	m := NewIdempotencyMiddleware(store, 24*time.Hour, time.Minute)
+	m.now = func() time.Time { return idempotencyNow }
+	return m.Wrap(NewAccountHandler(service).Deposit)
+}
+
+func newDepositRequest(t *testing.T, key, body string) *http.Request {
+	req, err := http.NewRequest("POST", "/account/1/deposit", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	return mux.SetURLVars(req, map[string]string{"id": "1"})
+}
+
+func TestIdempotencyMiddleware_WithoutKey(t *testing.T) {
+	store := new(repomocks.IdempotencyRepository)
+	service := new(mocks.AccountServiceInterface)
+	handler := newIdempotentDeposit(store, service)
+
+	service.On("Deposit", mock.Anything, 1, money.New(100, 0), "").Return(nil)
+
+	rr := httptest.NewRecorder()
+	handler(rr, newDepositRequest(t, "", `{"amount": 100}`))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	store.AssertNotCalled(t, "Reserve", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	service.AssertExpectations(t)
+}
+
+func TestIdempotencyMiddleware_FirstRequestIsStored(t *testing.T) {
+	store := new(repomocks.IdempotencyRepository)
+	service := new(mocks.AccountServiceInterface)
+	handler := newIdempotentDeposit(store, service)
+
+	req := newDepositRequest(t, "key-1", `{"amount": 100}`)
+	hash := hashRequest(req, []byte(`{"amount": 100}`))
+
+	store.On("Reserve", "key-1", hash, idempotencyNow.Add(time.Minute), idempotencyNow.Add(24*time.Hour)).Return(nil, "tok", nil)
+	service.On("Deposit", mock.Anything, 1, money.New(100, 0), "").Return(nil)
+	store.On("Complete", "key-1", "tok", http.StatusOK, "application/json", []byte("{\"message\":\"Deposit successful\"}\n")).Return(nil)
+
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"message":"Deposit successful"}`, rr.Body.String())
+	store.AssertExpectations(t)
+	service.AssertExpectations(t)
+}
+
+func TestIdempotencyMiddleware_ReplaysStoredResponse(t *testing.T) {
+	store := new(repomocks.IdempotencyRepository)
+	service := new(mocks.AccountServiceInterface)
+	handler := newIdempotentDeposit(store, service)
+
+	req := newDepositRequest(t, "key-1", `{"amount": 100}`)
+	hash := hashRequest(req, []byte(`{"amount": 100}`))
+
+	store.On("Reserve", "key-1", hash, mock.Anything, mock.Anything).Return(&models.IdempotencyRecord{
+		Key:          "key-1",
+		RequestHash:  hash,
+		StatusCode:   http.StatusOK,
+		ContentType:  "application/json",
+		ResponseBody: []byte(`{"message":"Deposit successful"}`),
+	}, "", nil)
+
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "true", rr.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"message":"Deposit successful"}`, rr.Body.String())
+	service.AssertNotCalled(t, "Deposit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIdempotencyMiddleware_KeyReusedWithDifferentPayload(t *testing.T) {
+	store := new(repomocks.IdempotencyRepository)
+	service := new(mocks.AccountServiceInterface)
+	handler := newIdempotentDeposit(store, service)
+
+	store.On("Reserve", "key-1", mock.Anything, mock.Anything, mock.Anything).Return(&models.IdempotencyRecord{
+		Key:         "key-1",
+		RequestHash: "hash-of-another-request",
+		StatusCode:  http.StatusOK,
+	}, "", nil)
+
+	rr := httptest.NewRecorder()
+	handler(rr, newDepositRequest(t, "key-1", `{"amount": 999}`))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	service.AssertNotCalled(t, "Deposit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIdempotencyMiddleware_RequestInProgress(t *testing.T) {
+	store := new(repomocks.IdempotencyRepository)
+	service := new(mocks.AccountServiceInterface)
+	handler := newIdempotentDeposit(store, service)
+
+	req := newDepositRequest(t, "key-1", `{"amount": 100}`)
+	hash := hashRequest(req, []byte(`{"amount": 100}`))
+
+	store.On("Reserve", "key-1", hash, mock.Anything, mock.Anything).Return(&models.IdempotencyRecord{Key: "key-1", RequestHash: hash}, "", nil)
+
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	service.AssertNotCalled(t, "Deposit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIdempotencyMiddleware_ServerErrorReleasesKey(t *testing.T) {
+	store := new(repomocks.IdempotencyRepository)
+	service := new(mocks.AccountServiceInterface)
+	handler := newIdempotentDeposit(store, service)
+
+	store.On("Reserve", "key-1", mock.Anything, mock.Anything, mock.Anything).Return(nil, "tok", nil)
+	service.On("Deposit", mock.Anything, 1, money.New(100, 0), "").Return(assert.AnError)
+	store.On("Release", "key-1", "tok").Return(nil)
+
+	rr := httptest.NewRecorder()
+	handler(rr, newDepositRequest(t, "key-1", `{"amount": 100}`))
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	store.AssertExpectations(t)
+	store.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIdempotencyMiddleware_PanicReleasesKey(t *testing.T) {
+	store := new(repomocks.IdempotencyRepository)
+	service := new(mocks.AccountServiceInterface)
+	handler := newIdempotentDeposit(store, service)
+
+	store.On("Reserve", "key-1", mock.Anything, mock.Anything, mock.Anything).Return(nil, "tok", nil)
+	service.On("Deposit", mock.Anything, 1, money.New(100, 0), "").Run(func(mock.Arguments) { panic("boom") })
+	store.On("Release", "key-1", "tok").Return(nil)
+
+	assert.PanicsWithValue(t, "boom", func() {
+		handler(httptest.NewRecorder(), newDepositRequest(t, "key-1", `{"amount": 100}`))
+	})
+	store.AssertExpectations(t)
+	store.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIdempotencyMiddleware_KeysAreScopedToCustomer(t *testing.T) {
+	store := new(repomocks.IdempotencyRepository)
+	service := new(mocks.AccountServiceInterface)
+	handler := newIdempotentDeposit(store, service)
+
+	req := withPrincipal(newDepositRequest(t, "key-1", `{"amount": 100}`), 7)
+
+	store.On("Reserve", "7:key-1", mock.Anything, mock.Anything, mock.Anything).Return(nil, "tok", nil)
+	service.On("Deposit", mock.Anything, 1, money.New(100, 0), "").Return(nil)
+	store.On("Complete", "7:key-1", "tok", http.StatusOK, mock.Anything, mock.Anything).Return(nil)
+
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	store.AssertExpectations(t)
+}
+
+func TestIdempotencyMiddleware_RenewsLeaseWhileRunning(t *testing.T) {
+	store := new(repomocks.IdempotencyRepository)
+	service := new(mocks.AccountServiceInterface)
+	m := NewIdempotencyMiddleware(store, 24*time.Hour, 30*time.Millisecond)
+	handler := m.Wrap(NewAccountHandler(service).Deposit)
+
+	store.On("Reserve", "key-1", mock.Anything, mock.Anything, mock.Anything).Return(nil, "tok", nil)
+	// The handler outlives the lease several times over.
+	service.On("Deposit", mock.Anything, 1, money.New(100, 0), "").Run(func(mock.Arguments) { time.Sleep(100 * time.Millisecond) }).Return(nil)
+	store.On("Renew", "key-1", "tok", mock.Anything).Return(nil)
+	store.On("Complete", "key-1", "tok", http.StatusOK, mock.Anything, mock.Anything).Return(nil)
+
+	rr := httptest.NewRecorder()
+	handler(rr, newDepositRequest(t, "key-1", `{"amount": 100}`))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	store.AssertExpectations(t)
+	renewals := len(store.Calls) - 2
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, renewals, len(store.Calls)-2, "renewed after the handler returned")
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/services"
+)
+
+type InterestHandler struct {
+	service services.InterestServiceInterface
+}
+
+func NewInterestHandler(service services.InterestServiceInterface) *InterestHandler {
+	return &InterestHandler{service: service}
+}
+
+// ListRates lists the interest rate of each category that earns interest.
+func (h *InterestHandler) ListRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.service.ListRates()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]models.InterestRate{"rates": rates})
+}
+
+// SetRate sets the interest rate of the category in the route.
+func (h *InterestHandler) SetRate(w http.ResponseWriter, r *http.Request) {
+	var rate models.InterestRate
+	if err := json.NewDecoder(r.Body).Decode(&rate); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+		return
+	}
+
+	updated, err := h.service.SetRate(mux.Vars(r)["category"], rate)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// SetCDIRate publishes the CDI for the day in the route, written as
+// 2006-01-02.
+func (h *InterestHandler) SetCDIRate(w http.ResponseWriter, r *http.Request) {
+	date, err := time.Parse(time.DateOnly, mux.Vars(r)["date"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid date")
+		return
+	}
+
+	var rate models.CDIRate
+	if err := json.NewDecoder(r.Body).Decode(&rate); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+		return
+	}
+	rate.Date = date
+
+	updated, err := h.service.SetCDIRate(rate)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// Run accrues and credits interest now instead of waiting for the
+// background job. With dry_run=true nothing is written and the report
+// says what would be accrued and credited.
+func (h *InterestHandler) Run(w http.ResponseWriter, r *http.Request) {
+	var dryRun bool
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid dry_run")
+			return
+		}
+	}
+
+	report, err := h.service.Run(time.Now(), dryRun)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
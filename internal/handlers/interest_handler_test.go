@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	repomocks "github.com/gregoryAlvim/gobank/internal/repositories/mocks"
+	"github.com/gregoryAlvim/gobank/internal/services"
+	"github.com/gregoryAlvim/gobank/internal/services/mocks"
+)
+
+func TestInterestHandler_Rates(t *testing.T) {
+	repo := repomocks.NewInterestRepository(t)
+	handler := NewInterestHandler(services.NewInterestService(repo))
+	effectiveFrom := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	repo.On("ListRates").Return([]models.InterestRate{
+		{Category: "savings", Type: "fixed", RateBps: 600, EffectiveFrom: effectiveFrom},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/interest/rates", nil)
+	rr := httptest.NewRecorder()
+	handler.ListRates(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"rates": [{"category": "savings", "type": "fixed", "rate_bps": 600, "effective_from": "2026-01-01T00:00:00Z"}]}`, rr.Body.String())
+
+	repo.On("SetRate", &models.InterestRate{Category: "premium", Type: "cdi", RateBps: 9500}).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.InterestRate).EffectiveFrom = effectiveFrom
+	}).Return(nil)
+
+	req, _ = http.NewRequest("PUT", "/interest/rates/premium", bytes.NewBufferString(`{"type": "cdi", "rate_bps": 9500}`))
+	req = mux.SetURLVars(req, map[string]string{"category": "premium"})
+	rr = httptest.NewRecorder()
+	handler.SetRate(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"category": "premium", "type": "cdi", "rate_bps": 9500, "effective_from": "2026-01-01T00:00:00Z"}`, rr.Body.String())
+
+	tests := []struct {
+		category, body, wantField string
+	}{
+		{"gold", `{"type": "fixed", "rate_bps": 600}`, "category"},
+		{"savings", `{"type": "floating", "rate_bps": 600}`, "type"},
+		{"savings", `{"type": "fixed", "rate_bps": 20000}`, "rate_bps"},
+		{"savings", `{"rate_bps": 600}`, "type"},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest("PUT", "/interest/rates/"+tt.category, bytes.NewBufferString(tt.body))
+		req = mux.SetURLVars(req, map[string]string{"category": tt.category})
+		rr := httptest.NewRecorder()
+		handler.SetRate(rr, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code, tt.body)
+		assert.Contains(t, rr.Body.String(), `"field":"`+tt.wantField+`"`, tt.body)
+	}
+}
+
+func TestInterestHandler_SetCDIRate(t *testing.T) {
+	repo := repomocks.NewInterestRepository(t)
+	handler := NewInterestHandler(services.NewInterestService(repo))
+
+	repo.On("SetCDIRate", &models.CDIRate{Date: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), AnnualRateBps: 1490}).Return(nil)
+
+	req, _ := http.NewRequest("PUT", "/interest/cdi/2026-03-02", bytes.NewBufferString(`{"annual_rate_bps": 1490}`))
+	req = mux.SetURLVars(req, map[string]string{"date": "2026-03-02"})
+	rr := httptest.NewRecorder()
+	handler.SetCDIRate(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"date": "2026-03-02T00:00:00Z", "annual_rate_bps": 1490}`, rr.Body.String())
+
+	req, _ = http.NewRequest("PUT", "/interest/cdi/02-03-2026", bytes.NewBufferString(`{"annual_rate_bps": 1490}`))
+	req = mux.SetURLVars(req, map[string]string{"date": "02-03-2026"})
+	rr = httptest.NewRecorder()
+	handler.SetCDIRate(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	req, _ = http.NewRequest("PUT", "/interest/cdi/2026-03-02", bytes.NewBufferString(`{"annual_rate_bps": -5}`))
+	req = mux.SetURLVars(req, map[string]string{"date": "2026-03-02"})
+	rr = httptest.NewRecorder()
+	handler.SetCDIRate(rr, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"field":"annual_rate_bps"`)
+}
+
+func TestInterestHandler_Run(t *testing.T) {
+	service := new(mocks.InterestServiceInterface)
+	handler := NewInterestHandler(service)
+
+	service.On("Run", mock.Anything, true).Return(&models.InterestReport{
+		DryRun:         true,
+		AccruedThrough: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+		Accounts: []models.InterestAccountReport{
+			{AccountID: 10, DaysAccrued: 2, Accrued: money.MustParsePrecise("3.00438356"), Credited: money.New(31, 0)},
+		},
+	}, nil)
+
+	req, _ := http.NewRequest("POST", "/interest/runs?dry_run=true", nil)
+	rr := httptest.NewRecorder()
+	handler.Run(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{
+		"dry_run": true,
+		"accrued_through": "2026-04-01T00:00:00Z",
+		"accounts": [{"account_id": 10, "days_accrued": 2, "accrued": 3.00438356, "credited": 31.00}]
+	}`, rr.Body.String())
+
+	req, _ = http.NewRequest("POST", "/interest/runs?dry_run=maybe", nil)
+	rr = httptest.NewRecorder()
+	handler.Run(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	service.AssertExpectations(t)
+}
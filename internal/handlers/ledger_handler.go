@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/services"
+)
+
+type LedgerHandler struct {
+	service services.LedgerServiceInterface
+}
+
+func NewLedgerHandler(service services.LedgerServiceInterface) *LedgerHandler {
+	return &LedgerHandler{service: service}
+}
+
+// GetLedger returns a check of an account's stored balance against the
+// balance derived from its postings, with one page of the postings,
+// newest first. The query takes an optional limit and the cursor of the
+// previous page.
+func (h *LedgerHandler) GetLedger(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
+		return
+	}
+	filter, err := parsePostingFilter(r.URL.Query())
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, err.Error())
+		return
+	}
+
+	reconciliation, err := h.service.Reconcile(id, filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reconciliation)
+}
+
+func parsePostingFilter(q url.Values) (models.PostingFilter, error) {
+	var filter models.PostingFilter
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return filter, errors.New("Invalid limit")
+		}
+		filter.Limit = n
+	}
+	if v := q.Get("cursor"); v != "" {
+		beforeID, err := services.DecodeCursor(v)
+		if err != nil {
+			return filter, errors.New("Invalid cursor")
+		}
+		filter.BeforeID = beforeID
+	}
+	return filter, nil
+}
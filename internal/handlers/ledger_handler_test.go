@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	"github.com/gregoryAlvim/gobank/internal/services/mocks"
+)
+
+func TestLedgerHandler_GetLedger(t *testing.T) {
+	mockService := new(mocks.LedgerServiceInterface)
+	handler := NewLedgerHandler(mockService)
+
+	req, err := http.NewRequest("GET", "/account/1/ledger", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	rr := httptest.NewRecorder()
+
+	mockService.On("Reconcile", 1, models.PostingFilter{}).Return(&models.Reconciliation{
+		AccountID:     1,
+		Balance:       money.New(100, 0),
+		LedgerBalance: money.New(100, 0),
+		Balanced:      true,
+		Postings: []models.Posting{
+			{ID: 1, JournalEntryID: 1, AccountID: 1, Direction: "credit", Amount: money.New(100, 0), Currency: "BRL"},
+		},
+	}, nil)
+
+	handler.GetLedger(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	expectedResponse := `{
+		"account_id": 1,
+		"balance": 100.00,
+		"ledger_balance": 100.00,
+		"balanced": true,
+		"postings": [
+			{"id": 1, "journal_entry_id": 1, "account_id": 1, "direction": "credit", "amount": 100.00, "currency": "BRL"}
+		]
+	}`
+	assert.JSONEq(t, expectedResponse, rr.Body.String())
+
+	mockService.AssertExpectations(t)
+}
+
+func TestLedgerHandler_GetLedger_NotFound(t *testing.T) {
+	mockService := new(mocks.LedgerServiceInterface)
+	handler := NewLedgerHandler(mockService)
+
+	req, err := http.NewRequest("GET", "/account/99/ledger", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = mux.SetURLVars(req, map[string]string{"id": "99"})
+
+	rr := httptest.NewRecorder()
+
+	mockService.On("Reconcile", 99, models.PostingFilter{}).Return(nil, repositories.ErrAccountNotFound)
+
+	handler.GetLedger(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"code":"account_not_found"`)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestLedgerHandler_GetLedger_Page(t *testing.T) {
+	mockService := new(mocks.LedgerServiceInterface)
+	handler := NewLedgerHandler(mockService)
+
+	mockService.On("Reconcile", 1, models.PostingFilter{BeforeID: 9, Limit: 20}).Return(&models.Reconciliation{
+		AccountID: 1, Postings: []models.Posting{}, NextCursor: "NQ",
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/account/1/ledger?limit=20&cursor=OQ", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	rr := httptest.NewRecorder()
+	handler.GetLedger(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"next_cursor":"NQ"`)
+
+	for _, query := range []string{"limit=0", "limit=abc", "cursor=!"} {
+		req, _ := http.NewRequest("GET", "/account/1/ledger?"+query, nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		rr := httptest.NewRecorder()
+		handler.GetLedger(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+// GetOverdraft returns the account's overdraft limit, how much of it is
+// used and what remains.
+func (h *AccountHandler) GetOverdraft(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
+		return
+	}
+
+	overdraft, err := h.service.GetOverdraft(id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(overdraft)
+}
+
+// SetOverdraftLimit gives the account its own overdraft limit. A null
+// limit goes back to the category's. The body carries the reason.
+func (h *AccountHandler) SetOverdraftLimit(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
+		return
+	}
+
+	var req models.OverdraftLimitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+		return
+	}
+
+	if err := h.service.SetOverdraftLimit(id, req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Overdraft limit updated"})
+}
+
+// ListOverdraftPolicies lists the overdraft limit and interest rate of
+// each account category.
+func (h *AccountHandler) ListOverdraftPolicies(w http.ResponseWriter, r *http.Request) {
+	policies, err := h.service.ListOverdraftPolicies()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]models.OverdraftPolicy{"policies": policies})
+}
+
+// SetOverdraftPolicy sets the overdraft limit and interest rate of the
+// category in the route.
+func (h *AccountHandler) SetOverdraftPolicy(w http.ResponseWriter, r *http.Request) {
+	var policy models.OverdraftPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+		return
+	}
+
+	updated, err := h.service.SetOverdraftPolicy(mux.Vars(r)["category"], policy)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
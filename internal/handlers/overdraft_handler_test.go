@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	repomocks "github.com/gregoryAlvim/gobank/internal/repositories/mocks"
+	"github.com/gregoryAlvim/gobank/internal/services"
+	"github.com/gregoryAlvim/gobank/internal/services/mocks"
+)
+
+func TestAccountHandler_GetOverdraft(t *testing.T) {
+	service := new(mocks.AccountServiceInterface)
+	handler := NewAccountHandler(service)
+
+	service.On("GetOverdraft", 10).Return(&models.Overdraft{
+		Limit: money.New(500, 0), Used: money.New(120, 0), Remaining: money.New(380, 0), MonthlyRateBps: 800,
+	}, nil)
+	service.On("GetOverdraft", 99).Return(nil, repositories.ErrAccountNotFound)
+
+	req, _ := http.NewRequest("GET", "/account/10/overdraft", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "10"})
+	rr := httptest.NewRecorder()
+	handler.GetOverdraft(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"limit": 500.00, "used": 120.00, "remaining": 380.00, "monthly_rate_bps": 800, "custom": false}`, rr.Body.String())
+
+	req, _ = http.NewRequest("GET", "/account/99/overdraft", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "99"})
+	rr = httptest.NewRecorder()
+	handler.GetOverdraft(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	service.AssertExpectations(t)
+}
+
+func TestAccountHandler_SetOverdraftLimit(t *testing.T) {
+	limit := money.New(2000, 0)
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func(repo *repomocks.AccountRepository)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "own limit",
+			body: `{"limit": 2000, "reason": "salary increase"}`,
+			setup: func(repo *repomocks.AccountRepository) {
+				repo.On("SetOverdraftLimit", 10, &limit).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"message":"Overdraft limit updated"`,
+		},
+		{
+			name: "back to the category's",
+			body: `{"limit": null, "reason": "review"}`,
+			setup: func(repo *repomocks.AccountRepository) {
+				repo.On("SetOverdraftLimit", 10, (*money.Money)(nil)).Return(nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"message":"Overdraft limit updated"`,
+		},
+		{
+			name:       "negative limit",
+			body:       `{"limit": -1, "reason": "typo"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   `"field":"limit"`,
+		},
+		{
+			name:       "missing reason",
+			body:       `{"limit": 100}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   `"field":"reason"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repomocks.NewAccountRepository(t)
+			if tt.setup != nil {
+				tt.setup(repo)
+			}
+			handler := NewAccountHandler(services.NewAccountService(repo))
+
+			req, _ := http.NewRequest("PUT", "/account/10/overdraft", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": "10"})
+			rr := httptest.NewRecorder()
+			handler.SetOverdraftLimit(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+func TestAccountHandler_OverdraftPolicies(t *testing.T) {
+	repo := repomocks.NewAccountRepository(t)
+	handler := NewAccountHandler(services.NewAccountService(repo))
+
+	repo.On("ListOverdraftPolicies").Return([]models.OverdraftPolicy{
+		{Category: "premium", Limit: money.New(5000, 0), MonthlyRateBps: 600},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/overdraft-policies", nil)
+	rr := httptest.NewRecorder()
+	handler.ListOverdraftPolicies(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"policies": [{"category": "premium", "limit": 5000.00, "monthly_rate_bps": 600}]}`, rr.Body.String())
+
+	policy := &models.OverdraftPolicy{Category: "standard", Limit: money.New(800, 0), MonthlyRateBps: 750}
+	repo.On("SetOverdraftPolicy", policy).Return(nil)
+
+	req, _ = http.NewRequest("PUT", "/overdraft-policies/standard", bytes.NewBufferString(`{"limit": 800, "monthly_rate_bps": 750}`))
+	req = mux.SetURLVars(req, map[string]string{"category": "standard"})
+	rr = httptest.NewRecorder()
+	handler.SetOverdraftPolicy(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"category": "standard", "limit": 800.00, "monthly_rate_bps": 750}`, rr.Body.String())
+
+	for category, body := range map[string]string{
+		"gold":     `{"limit": 800, "monthly_rate_bps": 750}`,
+		"standard": `{"limit": 800, "monthly_rate_bps": 20000}`,
+	} {
+		req, _ := http.NewRequest("PUT", "/overdraft-policies/"+category, bytes.NewBufferString(body))
+		req = mux.SetURLVars(req, map[string]string{"category": category})
+		rr := httptest.NewRecorder()
+		handler.SetOverdraftPolicy(rr, req)
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code, body)
+	}
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gregoryAlvim/gobank/internal/accountnumber"
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/fx"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	"github.com/gregoryAlvim/gobank/internal/services"
+	"github.com/gregoryAlvim/gobank/internal/validation"
+)
+
+const problemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 error body. Type is always about:blank, so Title
+// is the HTTP status text; Code is the stable, machine-readable reason
+// clients should switch on.
+type Problem struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail,omitempty"`
+	Instance string            `json:"instance,omitempty"`
+	Code     string            `json:"code"`
+	Errors   validation.Errors `json:"errors,omitempty"`
+}
+
+// Problem codes. Each one always comes with the same status.
+const (
+	CodeBadRequest           = "bad_request"
+	CodeUnauthenticated      = "unauthenticated"
+	CodeInvalidToken         = "invalid_token"
+	CodeInvalidCredentials   = "invalid_credentials"
+	CodeInvalidRefreshToken  = "invalid_refresh_token"
+	CodeForbidden            = "forbidden"
+	CodeInvalidBody          = "invalid_body"
+	CodeBodyTooLarge         = "body_too_large"
+	CodeValidationFailed     = "validation_failed"
+	CodeInvalidAccountNumber = "invalid_account_number"
+	CodeInvalidAccountType   = "invalid_account_type"
+	CodeInvalidAmount        = "invalid_amount"
+	CodeSameAccount          = "same_account"
+	CodeInsufficientFunds    = "insufficient_funds"
+	CodeAccountFrozen        = "account_frozen"
+	CodeAccountDormant       = "account_dormant"
+	CodeAccountPending       = "account_pending"
+	CodeAccountClosed        = "account_closed"
+	CodeStatusTransition     = "invalid_status_transition"
+	CodeBalanceRemaining     = "balance_remaining"
+	CodeNegativeBalance      = "negative_balance"
+	CodeFundsOnHold          = "funds_on_hold"
+	CodePayoutAccount        = "invalid_payout_account"
+	CodeAccountNotFound      = "account_not_found"
+	CodeApprovalNotFound     = "approval_not_found"
+	CodeApprovalReviewed     = "approval_already_reviewed"
+	CodeApprovalExpired      = "approval_expired"
+	CodeSelfApproval         = "self_approval"
+	CodeHoldNotFound         = "hold_not_found"
+	CodeHoldReleased         = "hold_already_released"
+	CodeHoldExpired          = "hold_expired"
+	CodeCaptureExceedsHold   = "capture_exceeds_hold"
+	CodeFeeWaiverNotFound    = "fee_waiver_not_found"
+	CodeCurrencyMismatch     = "currency_mismatch"
+	CodeFXRateNotFound       = "fx_rate_not_found"
+	CodeQuoteNotFound        = "fx_quote_not_found"
+	CodeQuoteExpired         = "fx_quote_expired"
+	CodeQuoteUsed            = "fx_quote_used"
+	CodeQuoteMismatch        = "fx_quote_mismatch"
+	CodeAmountTooSmall       = "amount_too_small"
+	CodeScheduleNotFound     = "scheduled_transfer_not_found"
+	CodeScheduleInactive     = "scheduled_transfer_inactive"
+	CodeCustomerNotFound     = "customer_not_found"
+	CodeWebhookNotFound      = "webhook_not_found"
+	CodeDeliveryNotFound     = "webhook_delivery_not_found"
+	CodeDuplicateCPF         = "duplicate_cpf"
+	CodeDuplicateCNPJ        = "duplicate_cnpj"
+	CodeIdempotencyMismatch  = "idempotency_key_mismatch"
+	CodeIdempotencyInFlight  = "idempotency_key_in_flight"
+	CodeInternal             = "internal_error"
+)
+
+// errorProblems maps domain errors to their status and code. Entries are
+// matched with errors.Is, in order.
+var errorProblems = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{auth.ErrUnauthenticated, http.StatusUnauthorized, CodeUnauthenticated},
+	{auth.ErrInvalidToken, http.StatusUnauthorized, CodeInvalidToken},
+	{auth.ErrInvalidCredentials, http.StatusUnauthorized, CodeInvalidCredentials},
+	{repositories.ErrInvalidRefreshToken, http.StatusUnauthorized, CodeInvalidRefreshToken},
+	{repositories.ErrRefreshTokenReused, http.StatusUnauthorized, CodeInvalidRefreshToken},
+	{auth.ErrForbidden, http.StatusForbidden, CodeForbidden},
+	{services.ErrSelfApproval, http.StatusForbidden, CodeSelfApproval},
+	{repositories.ErrAccountNotFound, http.StatusNotFound, CodeAccountNotFound},
+	{repositories.ErrCustomerNotFound, http.StatusNotFound, CodeCustomerNotFound},
+	{repositories.ErrDuplicateCPF, http.StatusConflict, CodeDuplicateCPF},
+	{repositories.ErrDuplicateCNPJ, http.StatusConflict, CodeDuplicateCNPJ},
+	{repositories.ErrInsufficientFunds, http.StatusUnprocessableEntity, CodeInsufficientFunds},
+	{repositories.ErrAccountFrozen, http.StatusConflict, CodeAccountFrozen},
+	{repositories.ErrAccountDormant, http.StatusConflict, CodeAccountDormant},
+	{repositories.ErrAccountPending, http.StatusConflict, CodeAccountPending},
+	{repositories.ErrAccountClosed, http.StatusConflict, CodeAccountClosed},
+	{repositories.ErrStatusTransition, http.StatusConflict, CodeStatusTransition},
+	{repositories.ErrBalanceRemaining, http.StatusConflict, CodeBalanceRemaining},
+	{repositories.ErrNegativeBalance, http.StatusConflict, CodeNegativeBalance},
+	{repositories.ErrFundsOnHold, http.StatusConflict, CodeFundsOnHold},
+	{repositories.ErrApprovalNotFound, http.StatusNotFound, CodeApprovalNotFound},
+	{repositories.ErrApprovalReviewed, http.StatusConflict, CodeApprovalReviewed},
+	{repositories.ErrApprovalExpired, http.StatusConflict, CodeApprovalExpired},
+	{repositories.ErrHoldNotFound, http.StatusNotFound, CodeHoldNotFound},
+	{repositories.ErrHoldReleased, http.StatusConflict, CodeHoldReleased},
+	{repositories.ErrHoldExpired, http.StatusConflict, CodeHoldExpired},
+	{repositories.ErrCaptureExceedsHold, http.StatusUnprocessableEntity, CodeCaptureExceedsHold},
+	{repositories.ErrFeeWaiverNotFound, http.StatusNotFound, CodeFeeWaiverNotFound},
+	{repositories.ErrCurrencyMismatch, http.StatusUnprocessableEntity, CodeCurrencyMismatch},
+	{repositories.ErrFXRateNotFound, http.StatusUnprocessableEntity, CodeFXRateNotFound},
+	{repositories.ErrQuoteNotFound, http.StatusNotFound, CodeQuoteNotFound},
+	{repositories.ErrQuoteExpired, http.StatusConflict, CodeQuoteExpired},
+	{repositories.ErrQuoteUsed, http.StatusConflict, CodeQuoteUsed},
+	{repositories.ErrQuoteMismatch, http.StatusUnprocessableEntity, CodeQuoteMismatch},
+	{fx.ErrAmountTooSmall, http.StatusUnprocessableEntity, CodeAmountTooSmall},
+	{repositories.ErrScheduledTransferNotFound, http.StatusNotFound, CodeScheduleNotFound},
+	{repositories.ErrScheduledTransferInactive, http.StatusConflict, CodeScheduleInactive},
+	{repositories.ErrWebhookNotFound, http.StatusNotFound, CodeWebhookNotFound},
+	{repositories.ErrDeliveryNotFound, http.StatusNotFound, CodeDeliveryNotFound},
+	{services.ErrInvalidAmount, http.StatusUnprocessableEntity, CodeInvalidAmount},
+	{money.ErrOverflow, http.StatusUnprocessableEntity, CodeInvalidAmount},
+	{services.ErrSameAccount, http.StatusUnprocessableEntity, CodeSameAccount},
+	{services.ErrPayoutAccount, http.StatusUnprocessableEntity, CodePayoutAccount},
+	{services.ErrInvalidAccountType, http.StatusBadRequest, CodeInvalidAccountType},
+	{validation.ErrMalformedJSON, http.StatusBadRequest, CodeInvalidBody},
+	{accountnumber.ErrInvalidFormat, http.StatusBadRequest, CodeInvalidAccountNumber},
+	{accountnumber.ErrInvalidCheckDigit, http.StatusBadRequest, CodeInvalidAccountNumber},
+	{accountnumber.ErrOutOfRange, http.StatusBadRequest, CodeInvalidAccountNumber},
+}
+
+// writeProblem writes a problem+json response.
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, code, detail string) {
+	writeProblemBody(w, &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+		Code:     code,
+	})
+}
+
+func writeProblemBody(w http.ResponseWriter, p *Problem) {
+	if p.Status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="gobank"`)
+	}
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// writeError answers with the problem matching err. Field errors become a
+// 422 listing every field; errors that match nothing are logged and
+// reported as a 500 without their details.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var fieldErrs validation.Errors
+	if errors.As(err, &fieldErrs) {
+		writeProblemBody(w, &Problem{
+			Type:     "about:blank",
+			Title:    http.StatusText(http.StatusUnprocessableEntity),
+			Status:   http.StatusUnprocessableEntity,
+			Detail:   "Validation failed",
+			Instance: r.URL.Path,
+			Code:     CodeValidationFailed,
+			Errors:   fieldErrs,
+		})
+		return
+	}
+
+	for _, p := range errorProblems {
+		if errors.Is(err, p.err) {
+			writeProblem(w, r, p.status, p.code, err.Error())
+			return
+		}
+	}
+
+	log.Printf("%s %s: %v", r.Method, r.URL.Path, err)
+	writeProblem(w, r, http.StatusInternalServerError, CodeInternal, "An unexpected error occurred")
+}
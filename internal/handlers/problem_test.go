@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	"github.com/gregoryAlvim/gobank/internal/services"
+	"github.com/gregoryAlvim/gobank/internal/services/mocks"
+)
+
+func TestAccountHandler_ErrorStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		setup      func(m *mocks.AccountServiceInterface)
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name: "balance of unknown account", method: "GET", path: "/account/99/balance",
+			setup: func(m *mocks.AccountServiceInterface) {
+				m.On("GetBalance", 99).Return(nil, repositories.ErrAccountNotFound)
+			},
+			wantStatus: http.StatusNotFound, wantCode: CodeAccountNotFound,
+		},
+		{
+			name: "transactions of unknown account", method: "GET", path: "/account/99/transactions",
+			setup: func(m *mocks.AccountServiceInterface) {
+				m.On("GetTransactions", 99, mock.Anything).Return(nil, repositories.ErrAccountNotFound)
+			},
+			wantStatus: http.StatusNotFound, wantCode: CodeAccountNotFound,
+		},
+		{
+			name: "non-positive deposit", method: "POST", path: "/account/1/deposit", body: `{"amount": 0}`,
+			setup: func(m *mocks.AccountServiceInterface) {
+				m.On("Deposit", mock.Anything, 1, money.Zero, "").Return(fmt.Errorf("deposit %w", services.ErrInvalidAmount))
+			},
+			wantStatus: http.StatusUnprocessableEntity, wantCode: CodeInvalidAmount,
+		},
+		{
+			name: "withdrawal without funds", method: "POST", path: "/account/1/withdraw", body: `{"amount": 500}`,
+			setup: func(m *mocks.AccountServiceInterface) {
+				m.On("Withdraw", mock.Anything, 1, money.New(500, 0), "").Return(repositories.ErrInsufficientFunds)
+			},
+			wantStatus: http.StatusUnprocessableEntity, wantCode: CodeInsufficientFunds,
+		},
+		{
+			name: "transfer to the same account", method: "POST", path: "/account/transfer", body: `{"from_id": 1, "to_id": 1, "amount": 5}`,
+			setup: func(m *mocks.AccountServiceInterface) {
+				m.On("GetAccount", 1).Return(&models.Account{ID: 1, CustomerID: 1}, nil)
+				m.On("Transfer", mock.Anything, 1, 1, money.New(5, 0), int64(0)).Return(nil, services.ErrSameAccount)
+			},
+			wantStatus: http.StatusUnprocessableEntity, wantCode: CodeSameAccount,
+		},
+		{
+			name: "transfer to unknown account", method: "POST", path: "/account/transfer", body: `{"from_id": 1, "to_id": 99, "amount": 5}`,
+			setup: func(m *mocks.AccountServiceInterface) {
+				m.On("GetAccount", 1).Return(&models.Account{ID: 1, CustomerID: 1}, nil)
+				m.On("Transfer", mock.Anything, 1, 99, money.New(5, 0), int64(0)).Return(nil, repositories.ErrAccountNotFound)
+			},
+			wantStatus: http.StatusNotFound, wantCode: CodeAccountNotFound,
+		},
+		{
+			name: "close unknown account", method: "DELETE", path: "/account/99",
+			setup: func(m *mocks.AccountServiceInterface) {
+				m.On("CloseAccount", mock.Anything, 99, models.CloseAccountRequest{}).Return(nil, repositories.ErrAccountNotFound)
+			},
+			wantStatus: http.StatusNotFound, wantCode: CodeAccountNotFound,
+		},
+		{
+			name: "invalid account ID", method: "GET", path: "/account/abc/balance",
+			setup:      func(m *mocks.AccountServiceInterface) {},
+			wantStatus: http.StatusBadRequest, wantCode: CodeBadRequest,
+		},
+		{
+			name: "malformed amount body", method: "POST", path: "/account/1/deposit", body: `{"amount": }`,
+			setup:      func(m *mocks.AccountServiceInterface) {},
+			wantStatus: http.StatusBadRequest, wantCode: CodeInvalidBody,
+		},
+		{
+			name: "unexpected error", method: "GET", path: "/account/1/balance",
+			setup: func(m *mocks.AccountServiceInterface) {
+				m.On("GetBalance", 1).Return(nil, errors.New("pq: connection refused"))
+			},
+			wantStatus: http.StatusInternalServerError, wantCode: CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.AccountServiceInterface)
+			tt.setup(mockService)
+
+			req, err := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = withPrincipal(req, 1)
+			rr := httptest.NewRecorder()
+
+			newAccountRouter(mockService).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+			assert.Contains(t, rr.Body.String(), `"code":"`+tt.wantCode+`"`)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccountHandler_CustomerErrorStatus(t *testing.T) {
+	mockService := new(mocks.AccountServiceInterface)
+	handler := NewAccountHandler(mockService)
+
+	mockService.On("CreateAccount", "robot", mock.Anything, mock.Anything).Return(nil, services.ErrInvalidAccountType)
+	mockService.On("OpenAccount", mock.Anything, 99, models.OpenAccountRequest{Category: "savings"}).Return(nil, repositories.ErrCustomerNotFound)
+	mockService.On("GetCustomerAccounts", 99).Return(nil, repositories.ErrCustomerNotFound)
+
+	req, _ := http.NewRequest("POST", "/account?type=robot", bytes.NewBufferString(`{}`))
+	rr := httptest.NewRecorder()
+	handler.CreateAccount(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"code":"invalid_account_type"`)
+
+	req, _ = http.NewRequest("POST", "/customers/99/accounts", bytes.NewBufferString(`{"category": "savings"}`))
+	req = mux.SetURLVars(req, map[string]string{"customer_id": "99"})
+	rr = httptest.NewRecorder()
+	handler.OpenAccount(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"code":"customer_not_found"`)
+
+	req, _ = http.NewRequest("GET", "/customers/99/accounts", nil)
+	req = mux.SetURLVars(req, map[string]string{"customer_id": "99"})
+	rr = httptest.NewRecorder()
+	handler.GetCustomerAccounts(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"code":"customer_not_found"`)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestWriteError_Body(t *testing.T) {
+	req := httptest.NewRequest("POST", "/account/10/withdraw", nil)
+	rr := httptest.NewRecorder()
+
+	writeError(rr, req, repositories.ErrInsufficientFunds)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Unprocessable Entity",
+		"status": 422,
+		"detail": "insufficient funds",
+		"instance": "/account/10/withdraw",
+		"code": "insufficient_funds"
+	}`, rr.Body.String())
+
+	// Unmapped errors do not leak their message.
+	rr = httptest.NewRecorder()
+	writeError(rr, req, errors.New("pq: password authentication failed"))
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "password")
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/auth"
+)
+
+func TestRequestID(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{"client ID", "9b2c-41d0.retry:1", true},
+		{"missing", "", false},
+		{"too long", strings.Repeat("a", 129), false},
+		{"unsafe characters", "id\nX-Injected: 1", false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = RequestIDFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set(RequestIDHeader, tt.header)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, seen, rr.Header().Get(RequestIDHeader))
+			if tt.keep {
+				assert.Equal(t, tt.header, seen)
+			} else {
+				assert.Regexp(t, "^[0-9a-f]{32}$", seen)
+			}
+		})
+	}
+
+	assert.Empty(t, RequestIDFromContext(httptest.NewRequest("GET", "/", nil).Context()))
+}
+
+func TestAuthenticate_RequestID(t *testing.T) {
+	tokens := newTestTokenIssuer(t)
+	token, _, err := tokens.IssueAccessToken(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var principal *auth.Principal
+	r := mux.NewRouter()
+	r.Use(RequestID, Authenticate(tokens))
+	r.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		principal, _ = auth.FromContext(r.Context())
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set(RequestIDHeader, "req-42")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if assert.NotNil(t, principal) {
+		assert.Equal(t, 3, principal.CustomerID)
+		assert.Equal(t, "req-42", principal.RequestID)
+	}
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/services"
+)
+
+type ScheduledTransferHandler struct {
+	service services.ScheduledTransferServiceInterface
+}
+
+func NewScheduledTransferHandler(service services.ScheduledTransferServiceInterface) *ScheduledTransferHandler {
+	return &ScheduledTransferHandler{service: service}
+}
+
+// Create schedules a transfer from the account in the route, once or as a
+// standing order.
+func (h *ScheduledTransferHandler) Create(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
+		return
+	}
+
+	var req models.ScheduledTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+		return
+	}
+
+	// authorizeAccount has made sure there is a principal.
+	principal, _ := auth.FromContext(r.Context())
+	schedule, err := h.service.Create(principal, id, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(schedule)
+}
+
+// List lists the transfers scheduled from the account, newest first.
+func (h *ScheduledTransferHandler) List(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
+		return
+	}
+
+	schedules, err := h.service.List(id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]models.ScheduledTransfer{"scheduled_transfers": schedules})
+}
+
+func (h *ScheduledTransferHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, scheduleID, ok := scheduleRouteIDs(w, r)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.service.Get(id, scheduleID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schedule)
+}
+
+// Update replaces the amount, description and end of an active scheduled
+// transfer.
+func (h *ScheduledTransferHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, scheduleID, ok := scheduleRouteIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateScheduledTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+		return
+	}
+
+	schedule, err := h.service.Update(id, scheduleID, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schedule)
+}
+
+// Cancel stops a scheduled transfer and returns it.
+func (h *ScheduledTransferHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, scheduleID, ok := scheduleRouteIDs(w, r)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.service.Cancel(id, scheduleID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schedule)
+}
+
+// ListRuns lists every attempt at a scheduled transfer and its outcome,
+// newest first.
+func (h *ScheduledTransferHandler) ListRuns(w http.ResponseWriter, r *http.Request) {
+	id, scheduleID, ok := scheduleRouteIDs(w, r)
+	if !ok {
+		return
+	}
+
+	runs, err := h.service.ListRuns(id, scheduleID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]models.ScheduledTransferRun{"runs": runs})
+}
+
+func scheduleRouteIDs(w http.ResponseWriter, r *http.Request) (int, int64, bool) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid account ID")
+		return 0, 0, false
+	}
+	scheduleID, err := strconv.ParseInt(vars["schedule_id"], 10, 64)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid scheduled transfer ID")
+		return 0, 0, false
+	}
+	return id, scheduleID, true
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	"github.com/gregoryAlvim/gobank/internal/services/mocks"
+)
+
+func TestScheduledTransferHandler_Create(t *testing.T) {
+	service := new(mocks.ScheduledTransferServiceInterface)
+	handler := NewScheduledTransferHandler(service)
+	start := models.Date{Time: time.Date(2026, 5, 5, 0, 0, 0, 0, time.UTC)}
+	createdAt := time.Date(2026, 4, 14, 10, 0, 0, 0, time.UTC)
+	maxRuns := 12
+
+	request := models.ScheduledTransferRequest{ToAccountID: 30, Amount: money.New(100, 0), Description: "rent",
+		Frequency: "monthly", StartDate: start, DayOfMonth: 5, MaxRuns: &maxRuns}
+	service.On("Create", &auth.Principal{CustomerID: 4, Role: auth.RoleCustomer}, 20, request).Return(&models.ScheduledTransfer{
+		ID: 7, FromAccountID: 20, ToAccountID: 30, Amount: money.New(100, 0), Description: "rent", Frequency: "monthly",
+		StartDate: start, DayOfMonth: 5, MaxRuns: &maxRuns, Status: "active", NextRunDate: &start, NextAttemptAt: &start.Time,
+		CreatedByCustomerID: 4, CreatedByRole: "customer", CreatedAt: createdAt,
+	}, nil)
+
+	body := `{"to_id": 30, "amount": 100, "description": "rent", "frequency": "monthly", "start_date": "2026-05-05", "day_of_month": 5, "max_runs": 12}`
+	req, _ := http.NewRequest("POST", "/account/20/scheduled-transfers", bytes.NewBufferString(body))
+	req = mux.SetURLVars(withPrincipal(req, 4), map[string]string{"id": "20"})
+	rr := httptest.NewRecorder()
+	handler.Create(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.JSONEq(t, `{"id": 7, "from_id": 20, "to_id": 30, "amount": 100.00, "description": "rent", "frequency": "monthly",
+		"start_date": "2026-05-05", "day_of_month": 5, "max_runs": 12, "runs_count": 0, "status": "active",
+		"next_run_date": "2026-05-05", "next_attempt_at": "2026-05-05T00:00:00Z", "attempts": 0,
+		"created_by_customer_id": 4, "created_at": "2026-04-14T10:00:00Z"}`, rr.Body.String())
+
+	req, _ = http.NewRequest("POST", "/account/20/scheduled-transfers", bytes.NewBufferString(`{"to_id": 30, "start_date": "05/05/2026"}`))
+	req = mux.SetURLVars(withPrincipal(req, 4), map[string]string{"id": "20"})
+	rr = httptest.NewRecorder()
+	handler.Create(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"code":"invalid_body"`)
+	service.AssertExpectations(t)
+}
+
+func TestScheduledTransferHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		call       func(h *ScheduledTransferHandler) http.HandlerFunc
+		setup      func(m *mocks.ScheduledTransferServiceInterface)
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:   "cancel a completed transfer",
+			method: "DELETE",
+			call:   func(h *ScheduledTransferHandler) http.HandlerFunc { return h.Cancel },
+			setup: func(m *mocks.ScheduledTransferServiceInterface) {
+				m.On("Cancel", 20, int64(7)).Return(nil, repositories.ErrScheduledTransferInactive)
+			},
+			wantStatus: http.StatusConflict,
+			wantCode:   CodeScheduleInactive,
+		},
+		{
+			name:   "runs of another account's transfer",
+			method: "GET",
+			call:   func(h *ScheduledTransferHandler) http.HandlerFunc { return h.ListRuns },
+			setup: func(m *mocks.ScheduledTransferServiceInterface) {
+				m.On("ListRuns", 20, int64(7)).Return(nil, repositories.ErrScheduledTransferNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+			wantCode:   CodeScheduleNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mocks.ScheduledTransferServiceInterface)
+			tt.setup(service)
+
+			req, _ := http.NewRequest(tt.method, "/account/20/scheduled-transfers/7", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "20", "schedule_id": "7"})
+			rr := httptest.NewRecorder()
+			tt.call(NewScheduledTransferHandler(service))(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), `"code":"`+tt.wantCode+`"`)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestScheduledTransferHandler_ListRuns(t *testing.T) {
+	service := new(mocks.ScheduledTransferServiceInterface)
+	handler := NewScheduledTransferHandler(service)
+	ranAt := time.Date(2026, 4, 14, 10, 0, 0, 0, time.UTC)
+	runDate := models.Date{Time: time.Date(2026, 4, 14, 0, 0, 0, 0, time.UTC)}
+
+	service.On("ListRuns", 20, int64(7)).Return([]models.ScheduledTransferRun{
+		{ID: 2, ScheduledTransferID: 7, RunDate: runDate, Attempt: 2, Status: "succeeded", RanAt: ranAt.Add(time.Hour)},
+		{ID: 1, ScheduledTransferID: 7, RunDate: runDate, Attempt: 1, Status: "retrying", Error: "insufficient funds", RanAt: ranAt},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/account/20/scheduled-transfers/7/runs", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "20", "schedule_id": "7"})
+	rr := httptest.NewRecorder()
+	handler.ListRuns(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"runs": [
+		{"id": 2, "scheduled_transfer_id": 7, "run_date": "2026-04-14", "attempt": 2, "status": "succeeded", "ran_at": "2026-04-14T11:00:00Z"},
+		{"id": 1, "scheduled_transfer_id": 7, "run_date": "2026-04-14", "attempt": 1, "status": "retrying", "error": "insufficient funds", "ran_at": "2026-04-14T10:00:00Z"}
+	]}`, rr.Body.String())
+	service.AssertExpectations(t)
+}
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	repomocks "github.com/gregoryAlvim/gobank/internal/repositories/mocks"
+	"github.com/gregoryAlvim/gobank/internal/services"
+	"github.com/gregoryAlvim/gobank/internal/validation"
+)
+
+func validNaturalPerson() map[string]interface{} {
+	return map[string]interface{}{
+		"cpf":            "529.982.247-25",
+		"monthly_income": 5000,
+		"age":            30,
+		"full_name":      "John Doe",
+		"phone_number":   "123456789",
+		"email":          "john.doe@example.com",
+		"category":       "standard",
+		"balance":        1000,
+		"password":       "correct horse",
+	}
+}
+
+func validLegalPerson() map[string]interface{} {
+	return map[string]interface{}{
+		"cnpj":            "11.222.333/0001-81",
+		"annual_revenue":  100000,
+		"age":             10,
+		"trade_name":      "Acme Ltda",
+		"phone_number":    "123456789",
+		"corporate_email": "contact@acme.com.br",
+		"category":        "business",
+		"balance":         1000,
+		"password":        "correct horse",
+	}
+}
+
+func TestAccountHandler_CreateAccount_Validation(t *testing.T) {
+	tests := []struct {
+		name         string
+		customerType string
+		base         func() map[string]interface{}
+		set          map[string]interface{}
+		remove       string
+		want         []validation.FieldError
+	}{
+		{
+			name: "missing CPF", customerType: "natural", base: validNaturalPerson,
+			remove: "cpf",
+			want:   []validation.FieldError{{Field: "cpf", Code: validation.CodeRequired, Message: "is required"}},
+		},
+		{
+			name: "invalid CPF", customerType: "natural", base: validNaturalPerson,
+			set:  map[string]interface{}{"cpf": "529.982.247-24"},
+			want: []validation.FieldError{{Field: "cpf", Code: validation.CodeInvalid, Message: "must be a valid CPF"}},
+		},
+		{
+			name: "blank full name", customerType: "natural", base: validNaturalPerson,
+			set:  map[string]interface{}{"full_name": "   "},
+			want: []validation.FieldError{{Field: "full_name", Code: validation.CodeRequired, Message: "is required"}},
+		},
+		{
+			name: "full name too long", customerType: "natural", base: validNaturalPerson,
+			set:  map[string]interface{}{"full_name": string(bytes.Repeat([]byte("a"), 256))},
+			want: []validation.FieldError{{Field: "full_name", Code: validation.CodeTooLong, Message: "must be at most 255 characters"}},
+		},
+		{
+			name: "malformed email", customerType: "natural", base: validNaturalPerson,
+			set:  map[string]interface{}{"email": "john.doe"},
+			want: []validation.FieldError{{Field: "email", Code: validation.CodeInvalid, Message: "must be a valid email address"}},
+		},
+		{
+			name: "phone number too long", customerType: "natural", base: validNaturalPerson,
+			set:  map[string]interface{}{"phone_number": "+55 11 91234-5678 ext 99"},
+			want: []validation.FieldError{{Field: "phone_number", Code: validation.CodeTooLong, Message: "must be at most 20 characters"}},
+		},
+		{
+			name: "negative age", customerType: "natural", base: validNaturalPerson,
+			set:  map[string]interface{}{"age": -1},
+			want: []validation.FieldError{{Field: "age", Code: validation.CodeOutOfRange, Message: "must be between 0 and 150"}},
+		},
+		{
+			name: "negative monthly income", customerType: "natural", base: validNaturalPerson,
+			set:  map[string]interface{}{"monthly_income": -10},
+			want: []validation.FieldError{{Field: "monthly_income", Code: validation.CodeOutOfRange, Message: "must not be negative"}},
+		},
+		{
+			name: "unknown field", customerType: "natural", base: validNaturalPerson,
+			set:  map[string]interface{}{"nickname": "JD"},
+			want: []validation.FieldError{{Field: "nickname", Code: validation.CodeUnknownField, Message: "is not a known field"}},
+		},
+		{
+			name: "wrong type", customerType: "natural", base: validNaturalPerson,
+			set: map[string]interface{}{"age": "thirty", "balance": "lots"},
+			want: []validation.FieldError{
+				{Field: "age", Code: validation.CodeInvalidType, Message: "must be an integer"},
+				{Field: "balance", Code: validation.CodeInvalidType, Message: "must be a decimal amount, e.g. 10.50"},
+			},
+		},
+		{
+			name: "unknown category", customerType: "natural", base: validNaturalPerson,
+			set:  map[string]interface{}{"category": "gold"},
+			want: []validation.FieldError{{Field: "category", Code: validation.CodeNotAllowed, Message: "must be one of: standard, premium, business, savings"}},
+		},
+		{
+			name: "missing category", customerType: "natural", base: validNaturalPerson,
+			remove: "category",
+			want:   []validation.FieldError{{Field: "category", Code: validation.CodeRequired, Message: "is required"}},
+		},
+		{
+			name: "negative opening balance", customerType: "natural", base: validNaturalPerson,
+			set:  map[string]interface{}{"balance": -5},
+			want: []validation.FieldError{{Field: "balance", Code: validation.CodeOutOfRange, Message: "must not be negative"}},
+		},
+		{
+			name: "invalid CNPJ", customerType: "legal", base: validLegalPerson,
+			set:  map[string]interface{}{"cnpj": "11.222.333/0001-80"},
+			want: []validation.FieldError{{Field: "cnpj", Code: validation.CodeInvalid, Message: "must be a valid CNPJ"}},
+		},
+		{
+			name: "missing password", customerType: "natural", base: validNaturalPerson,
+			remove: "password",
+			want:   []validation.FieldError{{Field: "password", Code: validation.CodeRequired, Message: "is required"}},
+		},
+		{
+			name: "short password", customerType: "legal", base: validLegalPerson,
+			set:  map[string]interface{}{"password": "abc123"},
+			want: []validation.FieldError{{Field: "password", Code: validation.CodeTooShort, Message: "must be at least 8 characters"}},
+		},
+		{
+			name: "company fields", customerType: "legal", base: validLegalPerson,
+			set: map[string]interface{}{"trade_name": "", "corporate_email": "acme@", "age": 1001, "annual_revenue": -1},
+			want: []validation.FieldError{
+				{Field: "trade_name", Code: validation.CodeRequired, Message: "is required"},
+				{Field: "corporate_email", Code: validation.CodeInvalid, Message: "must be a valid email address"},
+				{Field: "age", Code: validation.CodeOutOfRange, Message: "must be between 0 and 1000"},
+				{Field: "annual_revenue", Code: validation.CodeOutOfRange, Message: "must not be negative"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repomocks.NewAccountRepository(t)
+			handler := NewAccountHandler(services.NewAccountService(repo))
+
+			payload := tt.base()
+			for k, v := range tt.set {
+				payload[k] = v
+			}
+			delete(payload, tt.remove)
+			body, _ := json.Marshal(payload)
+
+			req, err := http.NewRequest("POST", "/account?type="+tt.customerType, bytes.NewBuffer(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.CreateAccount(rr, req)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+			assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+
+			var problem Problem
+			if err := json.Unmarshal(rr.Body.Bytes(), &problem); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, CodeValidationFailed, problem.Code)
+			assert.Equal(t, http.StatusUnprocessableEntity, problem.Status)
+			assert.Equal(t, validation.Errors(tt.want), problem.Errors)
+		})
+	}
+}
+
+func TestAccountHandler_CreateAccount_ValidPayload(t *testing.T) {
+	repo := repomocks.NewAccountRepository(t)
+	handler := NewAccountHandler(services.NewAccountService(repo))
+
+	body, _ := json.Marshal(validNaturalPerson())
+	req, err := http.NewRequest("POST", "/account?type=natural", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(RequestIDHeader, "signup-1")
+	rr := httptest.NewRecorder()
+
+	// The request ID reaches the audit log.
+	repo.On("CreateNaturalPerson", mock.MatchedBy(func(p *models.NaturalPerson) bool {
+		return p.CPF == "52998224725" && p.FullName == "John Doe" && auth.CheckPassword(p.PasswordHash, "correct horse")
+	}), mock.AnythingOfType("*models.Account"), "signup-1").Return(nil)
+
+	RequestID(http.HandlerFunc(handler.CreateAccount)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestAccountHandler_CreateAccount_MalformedJSON(t *testing.T) {
+	for _, body := range []string{`{"cpf": `, `[]`, `null`} {
+		t.Run(body, func(t *testing.T) {
+			repo := repomocks.NewAccountRepository(t)
+			handler := NewAccountHandler(services.NewAccountService(repo))
+
+			req, err := http.NewRequest("POST", "/account?type=natural", bytes.NewBufferString(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.CreateAccount(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.Contains(t, rr.Body.String(), `"code":"invalid_body"`)
+		})
+	}
+}
+
+func TestAccountHandler_OpenAccount_Validation(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantField  string
+	}{
+		{"unknown category", `{"category": "gold", "balance": 10}`, http.StatusUnprocessableEntity, "category"},
+		{"negative balance", `{"category": "savings", "balance": -10}`, http.StatusUnprocessableEntity, "balance"},
+		{"unknown field", `{"category": "savings", "overdraft": 10}`, http.StatusUnprocessableEntity, "overdraft"},
+		{"malformed body", `{"category": `, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repomocks.NewAccountRepository(t)
+			handler := NewAccountHandler(services.NewAccountService(repo))
+
+			req, err := http.NewRequest("POST", "/customers/3/accounts", bytes.NewBufferString(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = mux.SetURLVars(req, map[string]string{"customer_id": "3"})
+			rr := httptest.NewRecorder()
+
+			handler.OpenAccount(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantField != "" {
+				var problem Problem
+				if err := json.Unmarshal(rr.Body.Bytes(), &problem); err != nil {
+					t.Fatal(err)
+				}
+				if assert.Len(t, problem.Errors, 1) {
+					assert.Equal(t, tt.wantField, problem.Errors[0].Field)
+				}
+			}
+		})
+	}
+}
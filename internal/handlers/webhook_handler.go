@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/services"
+)
+
+// WebhookHandler serves both the customers' subscriptions, under
+// /customers/{customer_id}/webhooks, and the partners', under /webhooks.
+// Routes without a customer act on the partners'.
+type WebhookHandler struct {
+	service services.WebhookServiceInterface
+}
+
+func NewWebhookHandler(service services.WebhookServiceInterface) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// CreateSubscription subscribes a URL to events. The response carries the
+// secret the deliveries are signed with; it is not shown again.
+func (h *WebhookHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := webhookCustomerID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.WebhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+		return
+	}
+
+	subscription, err := h.service.CreateSubscription(customerID, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(subscription)
+}
+
+func (h *WebhookHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := webhookCustomerID(w, r)
+	if !ok {
+		return
+	}
+
+	subscriptions, err := h.service.ListSubscriptions(customerID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]models.WebhookSubscription{"webhooks": subscriptions})
+}
+
+// DeleteSubscription stops the deliveries to a subscription and forgets
+// them.
+func (h *WebhookHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := webhookCustomerID(w, r)
+	if !ok {
+		return
+	}
+	subscriptionID, ok := webhookRouteID(w, r, "webhook_id", "Invalid webhook ID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteSubscription(customerID, subscriptionID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries lists a subscription's deliveries, newest first. It takes
+// an optional status filter and limit.
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := webhookCustomerID(w, r)
+	if !ok {
+		return
+	}
+	subscriptionID, ok := webhookRouteID(w, r, "webhook_id", "Invalid webhook ID")
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	status := q.Get("status")
+	switch status {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryDead:
+	default:
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid status")
+		return
+	}
+	limit := 0
+	if v := q.Get("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil || limit <= 0 {
+			writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid limit")
+			return
+		}
+	}
+
+	deliveries, err := h.service.ListDeliveries(customerID, subscriptionID, status, limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]models.WebhookDelivery{"deliveries": deliveries})
+}
+
+// Resend sends a delivery again, with all of its attempts.
+func (h *WebhookHandler) Resend(w http.ResponseWriter, r *http.Request) {
+	customerID, ok := webhookCustomerID(w, r)
+	if !ok {
+		return
+	}
+	subscriptionID, ok := webhookRouteID(w, r, "webhook_id", "Invalid webhook ID")
+	if !ok {
+		return
+	}
+	deliveryID, ok := webhookRouteID(w, r, "delivery_id", "Invalid delivery ID")
+	if !ok {
+		return
+	}
+
+	delivery, err := h.service.Resend(customerID, subscriptionID, deliveryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(delivery)
+}
+
+// webhookCustomerID reads the customer from the route, or zero for the
+// partner routes, answering with a 400 when it is invalid.
+func webhookCustomerID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	v, ok := mux.Vars(r)["customer_id"]
+	if !ok {
+		return 0, true
+	}
+	customerID, err := strconv.Atoi(v)
+	if err != nil || customerID <= 0 {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, "Invalid customer ID")
+		return 0, false
+	}
+	return customerID, true
+}
+
+// webhookRouteID reads an ID from the route, answering with a 400 and
+// detail when it is invalid.
+func webhookRouteID(w http.ResponseWriter, r *http.Request, name, detail string) (int64, bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)[name], 10, 64)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, CodeBadRequest, detail)
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	"github.com/gregoryAlvim/gobank/internal/services/mocks"
+)
+
+func TestWebhookHandler_CreateSubscription(t *testing.T) {
+	service := new(mocks.WebhookServiceInterface)
+	handler := NewWebhookHandler(service)
+
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	request := models.WebhookSubscriptionRequest{URL: "https://example.com/hooks", EventTypes: []string{"deposit.completed"}}
+	service.On("CreateSubscription", 3, request).Return(&models.WebhookSubscription{
+		ID: 5, CustomerID: 3, URL: "https://example.com/hooks", EventTypes: []string{"deposit.completed"}, Secret: "whsec_test",
+		CreatedAt: createdAt,
+	}, nil)
+
+	req, _ := http.NewRequest("POST", "/customers/3/webhooks",
+		bytes.NewBufferString(`{"url": "https://example.com/hooks", "event_types": ["deposit.completed"]}`))
+	req = mux.SetURLVars(req, map[string]string{"customer_id": "3"})
+	rr := httptest.NewRecorder()
+	handler.CreateSubscription(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.JSONEq(t, `{"id": 5, "customer_id": 3, "url": "https://example.com/hooks", "event_types": ["deposit.completed"],
+		"secret": "whsec_test", "created_at": "2026-03-01T12:00:00Z"}`, rr.Body.String())
+
+	// The partner routes have no customer.
+	partnerRequest := models.WebhookSubscriptionRequest{Partner: "acme", URL: "https://acme.example/hooks"}
+	service.On("CreateSubscription", 0, partnerRequest).Return(&models.WebhookSubscription{
+		ID: 6, Partner: "acme", URL: "https://acme.example/hooks", EventTypes: []string{}, Secret: "whsec_acme", CreatedAt: createdAt,
+	}, nil)
+
+	req, _ = http.NewRequest("POST", "/webhooks", bytes.NewBufferString(`{"partner": "acme", "url": "https://acme.example/hooks"}`))
+	rr = httptest.NewRecorder()
+	handler.CreateSubscription(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	service.AssertExpectations(t)
+}
+
+func TestWebhookHandler_DeleteSubscription_NotFound(t *testing.T) {
+	service := new(mocks.WebhookServiceInterface)
+	handler := NewWebhookHandler(service)
+
+	service.On("DeleteSubscription", 3, int64(5)).Return(repositories.ErrWebhookNotFound)
+
+	req, _ := http.NewRequest("DELETE", "/customers/3/webhooks/5", nil)
+	req = mux.SetURLVars(req, map[string]string{"customer_id": "3", "webhook_id": "5"})
+	rr := httptest.NewRecorder()
+	handler.DeleteSubscription(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	var problem Problem
+	json.Unmarshal(rr.Body.Bytes(), &problem)
+	assert.Equal(t, CodeWebhookNotFound, problem.Code)
+}
+
+func TestWebhookHandler_ListDeliveries(t *testing.T) {
+	service := new(mocks.WebhookServiceInterface)
+	handler := NewWebhookHandler(service)
+
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	code := 503
+	service.On("ListDeliveries", 3, int64(5), "dead", 10).Return([]models.WebhookDelivery{{
+		ID: 9, SubscriptionID: 5, EventID: "e1", EventType: "deposit.completed", Payload: json.RawMessage(`{"id": "e1"}`),
+		Status: "dead", Attempts: 10, LastStatusCode: &code, LastError: "receiver responded 503", CreatedAt: createdAt,
+	}}, nil)
+
+	req, _ := http.NewRequest("GET", "/customers/3/webhooks/5/deliveries?status=dead&limit=10", nil)
+	req = mux.SetURLVars(req, map[string]string{"customer_id": "3", "webhook_id": "5"})
+	rr := httptest.NewRecorder()
+	handler.ListDeliveries(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"deliveries": [{"id": 9, "subscription_id": 5, "event_id": "e1", "event_type": "deposit.completed",
+		"payload": {"id": "e1"}, "status": "dead", "attempts": 10, "last_status_code": 503,
+		"last_error": "receiver responded 503", "created_at": "2026-03-01T12:00:00Z"}]}`, rr.Body.String())
+
+	for _, query := range []string{"status=failed", "limit=0"} {
+		req, _ = http.NewRequest("GET", "/customers/3/webhooks/5/deliveries?"+query, nil)
+		req = mux.SetURLVars(req, map[string]string{"customer_id": "3", "webhook_id": "5"})
+		rr = httptest.NewRecorder()
+		handler.ListDeliveries(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
+func TestWebhookHandler_Resend(t *testing.T) {
+	service := new(mocks.WebhookServiceInterface)
+	handler := NewWebhookHandler(service)
+
+	service.On("Resend", 0, int64(6), int64(9)).Return(nil, repositories.ErrDeliveryNotFound)
+
+	req, _ := http.NewRequest("POST", "/webhooks/6/deliveries/9/resend", nil)
+	req = mux.SetURLVars(req, map[string]string{"webhook_id": "6", "delivery_id": "9"})
+	rr := httptest.NewRecorder()
+	handler.Resend(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	var problem Problem
+	json.Unmarshal(rr.Body.Bytes(), &problem)
+	assert.Equal(t, CodeDeliveryNotFound, problem.Code)
+}
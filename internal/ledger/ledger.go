@@ -0,0 +1,208 @@
+// Package ledger builds double-entry journal entries.
+//
+// Every movement of money is recorded as a journal entry made of postings.
+// A posting debits or credits a single account, and the debits of an entry
+// must always equal its credits. Customer accounts are liabilities of the
+// bank, so a credit increases their balance and a debit decreases it.
+// Money entering or leaving the bank goes through the system cash account,
+// and opening balances, manual adjustments, fees and interest, charged or
+// paid, are booked against equity.
+//
+// Each posting is in one currency and an entry balances per currency.
+// A transfer between accounts in different currencies is converted
+// through equity: equity takes the source amount in one currency and pays
+// the converted amount in the other.
+package ledger
+
+import (
+	"errors"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
+const (
+	Debit  = "debit"
+	Credit = "credit"
+)
+
+// Journal entry kinds.
+const (
+	KindOpening           = "opening"
+	KindDeposit           = "deposit"
+	KindWithdrawal        = "withdrawal"
+	KindTransfer          = "transfer"
+	KindAdjustment        = "adjustment"
+	KindCapture           = "capture"
+	KindOverdraftInterest = "overdraft_interest"
+	KindInterest          = "interest"
+	KindWithdrawalFee     = "withdrawal_fee"
+	KindTransferFee       = "transfer_fee"
+	KindMaintenanceFee    = "maintenance_fee"
+)
+
+// System accounts are rows of the accounts table with a system_code and no
+// customer. Their balances are only derived from postings.
+const (
+	// CashAccountID is the settlement account for money entering or
+	// leaving the bank.
+	CashAccountID = 1
+	// EquityAccountID offsets opening balances, manual adjustments, fees
+	// and the interest the bank charges and pays.
+	EquityAccountID = 2
+)
+
+var (
+	ErrUnbalancedEntry = errors.New("journal entry debits and credits do not match")
+	ErrInvalidPosting  = errors.New("invalid posting")
+)
+
+// NewDeposit debits cash and credits the customer account.
+func NewDeposit(accountID int, amount money.Money) *models.JournalEntry {
+	return newEntry(KindDeposit, "Deposit", CashAccountID, accountID, amount)
+}
+
+// NewWithdrawal debits the customer account and credits cash.
+func NewWithdrawal(accountID int, amount money.Money) *models.JournalEntry {
+	return newEntry(KindWithdrawal, "Withdrawal", accountID, CashAccountID, amount)
+}
+
+// NewCapture settles a hold: it debits the customer account and credits
+// cash, like a withdrawal.
+func NewCapture(accountID int, amount money.Money) *models.JournalEntry {
+	return newEntry(KindCapture, "Hold capture", accountID, CashAccountID, amount)
+}
+
+// NewTransfer debits the source account and credits the destination.
+func NewTransfer(fromID, toID int, amount money.Money) *models.JournalEntry {
+	return newEntry(KindTransfer, "Transfer", fromID, toID, amount)
+}
+
+// NewFXTransfer debits the source account in the conversion's source
+// currency and credits the destination in its target currency. Equity is
+// credited and debited in between, keeping each currency balanced.
+func NewFXTransfer(fromID, toID int, conversion *models.FXConversion) *models.JournalEntry {
+	from, to := conversion.FromCurrency, conversion.ToCurrency
+	return &models.JournalEntry{
+		Kind:        KindTransfer,
+		Description: "Transfer " + from + " to " + to,
+		Postings: []models.Posting{
+			{AccountID: fromID, Direction: Debit, Amount: conversion.SourceAmount, Currency: from},
+			{AccountID: EquityAccountID, Direction: Credit, Amount: conversion.SourceAmount, Currency: from},
+			{AccountID: EquityAccountID, Direction: Debit, Amount: conversion.ConvertedAmount, Currency: to},
+			{AccountID: toID, Direction: Credit, Amount: conversion.ConvertedAmount, Currency: to},
+		},
+	}
+}
+
+// NewOverdraftInterest charges interest on a negative balance: it debits
+// the customer account and credits equity.
+func NewOverdraftInterest(accountID int, amount money.Money) *models.JournalEntry {
+	return newEntry(KindOverdraftInterest, "Overdraft interest", accountID, EquityAccountID, amount)
+}
+
+// NewInterest pays interest earned on savings: it debits equity and
+// credits the customer account.
+func NewInterest(accountID int, amount money.Money) *models.JournalEntry {
+	return newEntry(KindInterest, "Interest", EquityAccountID, accountID, amount)
+}
+
+// NewWithdrawalFee, NewTransferFee and NewMaintenanceFee charge a fee:
+// they debit the customer account and credit equity. Each fee is its own
+// entry, so it shows as a separate line in the account's history.
+func NewWithdrawalFee(accountID int, amount money.Money) *models.JournalEntry {
+	return newEntry(KindWithdrawalFee, "Withdrawal fee", accountID, EquityAccountID, amount)
+}
+
+func NewTransferFee(accountID int, amount money.Money) *models.JournalEntry {
+	return newEntry(KindTransferFee, "Transfer fee", accountID, EquityAccountID, amount)
+}
+
+func NewMaintenanceFee(accountID int, amount money.Money) *models.JournalEntry {
+	return newEntry(KindMaintenanceFee, "Maintenance fee", accountID, EquityAccountID, amount)
+}
+
+// NewOpeningBalance books the initial balance of a new account against
+// equity. A negative balance debits the account instead.
+func NewOpeningBalance(accountID int, balance money.Money) *models.JournalEntry {
+	if balance.IsNegative() {
+		return newEntry(KindOpening, "Opening balance", accountID, EquityAccountID, balance.Neg())
+	}
+	return newEntry(KindOpening, "Opening balance", EquityAccountID, accountID, balance)
+}
+
+// NewAdjustment books a correction of delta on the account against equity.
+func NewAdjustment(accountID int, delta money.Money) *models.JournalEntry {
+	if delta.IsNegative() {
+		return newEntry(KindAdjustment, "Balance adjustment", accountID, EquityAccountID, delta.Neg())
+	}
+	return newEntry(KindAdjustment, "Balance adjustment", EquityAccountID, accountID, delta)
+}
+
+func newEntry(kind, description string, debit, credit int, amount money.Money) *models.JournalEntry {
+	return &models.JournalEntry{
+		Kind:        kind,
+		Description: description,
+		Postings: []models.Posting{
+			{AccountID: debit, Direction: Debit, Amount: amount},
+			{AccountID: credit, Direction: Credit, Amount: amount},
+		},
+	}
+}
+
+// Validate checks that the entry has at least two postings, that every
+// posting has a positive amount and a known direction, and that the entry
+// is balanced in each of its currencies.
+func Validate(entry *models.JournalEntry) error {
+	if len(entry.Postings) < 2 {
+		return ErrInvalidPosting
+	}
+
+	// The debits less the credits of each currency.
+	net := make(map[string]money.Money)
+	for _, p := range entry.Postings {
+		if !p.Amount.IsPositive() {
+			return ErrInvalidPosting
+		}
+		switch p.Direction {
+		case Debit:
+			net[p.Currency] = net[p.Currency].Add(p.Amount)
+		case Credit:
+			net[p.Currency] = net[p.Currency].Sub(p.Amount)
+		default:
+			return ErrInvalidPosting
+		}
+	}
+
+	for _, n := range net {
+		if !n.IsZero() {
+			return ErrUnbalancedEntry
+		}
+	}
+	return nil
+}
+
+// SignedAmount returns the effect of a posting on its account's balance:
+// positive for credits, negative for debits.
+func SignedAmount(p models.Posting) money.Money {
+	if p.Direction == Debit {
+		return p.Amount.Neg()
+	}
+	return p.Amount
+}
+
+// IsSystemAccount reports whether the account belongs to the bank itself.
+func IsSystemAccount(accountID int) bool {
+	return accountID == CashAccountID || accountID == EquityAccountID
+}
+
+// Balance derives the balance of an account from its postings.
+func Balance(accountID int, postings []models.Posting) money.Money {
+	var balance money.Money
+	for _, p := range postings {
+		if p.AccountID == accountID {
+			balance = balance.Add(SignedAmount(p))
+		}
+	}
+	return balance
+}
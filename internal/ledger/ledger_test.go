@@ -0,0 +1,79 @@
+package ledger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
+func TestValidate(t *testing.T) {
+	customer, other := 10, 11
+
+	tests := []struct {
+		name  string
+		entry *models.JournalEntry
+		err   error
+	}{
+		{"deposit", NewDeposit(customer, money.New(10, 0)), nil},
+		{"withdrawal", NewWithdrawal(customer, money.New(10, 0)), nil},
+		{"capture", NewCapture(customer, money.New(10, 0)), nil},
+		{"overdraft interest", NewOverdraftInterest(customer, money.FromCents(13)), nil},
+		{"interest", NewInterest(customer, money.FromCents(27)), nil},
+		{"transfer", NewTransfer(customer, other, money.FromCents(1)), nil},
+		{"transfer fee", NewTransferFee(customer, money.FromCents(150)), nil},
+		{"negative opening", NewOpeningBalance(customer, money.New(-5, 0)), nil},
+		{"fx transfer", NewFXTransfer(customer, other, &models.FXConversion{
+			FromCurrency: "BRL", ToCurrency: "USD", SourceAmount: money.New(100, 0), ConvertedAmount: money.FromCents(1832),
+		}), nil},
+		{"zero amount", NewDeposit(customer, money.Zero), ErrInvalidPosting},
+		{"single posting", &models.JournalEntry{Postings: []models.Posting{
+			{AccountID: customer, Direction: Credit, Amount: money.New(1, 0)},
+		}}, ErrInvalidPosting},
+		{"unknown direction", &models.JournalEntry{Postings: []models.Posting{
+			{AccountID: customer, Direction: "up", Amount: money.New(1, 0)},
+			{AccountID: CashAccountID, Direction: Debit, Amount: money.New(1, 0)},
+		}}, ErrInvalidPosting},
+		{"balanced across currencies only", &models.JournalEntry{Postings: []models.Posting{
+			{AccountID: customer, Direction: Debit, Amount: money.New(1, 0), Currency: "BRL"},
+			{AccountID: other, Direction: Credit, Amount: money.New(1, 0), Currency: "USD"},
+		}}, ErrUnbalancedEntry},
+		{"unbalanced", &models.JournalEntry{Postings: []models.Posting{
+			{AccountID: customer, Direction: Credit, Amount: money.New(2, 0)},
+			{AccountID: CashAccountID, Direction: Debit, Amount: money.New(1, 0)},
+		}}, ErrUnbalancedEntry},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.err, Validate(tt.entry))
+		})
+	}
+}
+
+func TestBalance(t *testing.T) {
+	a, b := 10, 11
+
+	var postings []models.Posting
+	for _, e := range []*models.JournalEntry{
+		NewOpeningBalance(a, money.New(100, 0)),
+		NewDeposit(a, money.New(50, 0)),
+		NewWithdrawal(a, money.New(30, 0)),
+		NewWithdrawalFee(a, money.FromCents(250)),
+		NewMaintenanceFee(a, money.New(15, 0)),
+		NewTransfer(a, b, money.FromCents(1050)),
+		NewAdjustment(a, money.FromCents(-50)),
+	} {
+		postings = append(postings, e.Postings...)
+	}
+
+	assert.Equal(t, money.FromCents(9150), Balance(a, postings))
+	assert.Equal(t, money.FromCents(1050), Balance(b, postings))
+
+	// Every entry is balanced, so all accounts together sum to zero.
+	total := Balance(a, postings).Add(Balance(b, postings)).
+		Add(Balance(CashAccountID, postings)).Add(Balance(EquityAccountID, postings))
+	assert.True(t, total.IsZero())
+}
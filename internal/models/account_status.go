@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/validation"
+)
+
+// Account statuses. Pending, frozen and dormant accounts take credits but
+// not debits; closed accounts take neither and never change again.
+const (
+	AccountPending = "pending"
+	AccountActive  = "active"
+	AccountFrozen  = "frozen"
+	AccountDormant = "dormant"
+	AccountClosed  = "closed"
+)
+
+// accountTransitions lists the statuses each status can change to.
+var accountTransitions = map[string][]string{
+	AccountPending: {AccountActive, AccountClosed},
+	AccountActive:  {AccountFrozen, AccountDormant, AccountClosed},
+	AccountFrozen:  {AccountActive},
+	AccountDormant: {AccountActive, AccountFrozen, AccountClosed},
+}
+
+// CanChangeStatus reports whether an account can go from one status to
+// the other. A frozen account must be unfrozen before it is closed.
+func CanChangeStatus(from, to string) bool {
+	for _, allowed := range accountTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// AccountStatusChange records a change of an account's status. Exactly
+// one of ChangedByCustomerID and ChangedByOperatorID is set, or neither
+// when the bank made the change itself. PayoutAccountID and PayoutAmount
+// are set when the balance of a closed account was moved to another one.
+type AccountStatusChange struct {
+	ID                  int64        `json:"id"`
+	AccountID           int          `json:"account_id"`
+	FromStatus          string       `json:"from_status"`
+	ToStatus            string       `json:"to_status"`
+	Reason              string       `json:"reason"`
+	ChangedByCustomerID int          `json:"changed_by_customer_id,omitempty"`
+	ChangedByOperatorID int          `json:"changed_by_operator_id,omitempty"`
+	PayoutAccountID     *int         `json:"payout_account_id,omitempty"`
+	PayoutAmount        *money.Money `json:"payout_amount,omitempty"`
+	CreatedAt           time.Time    `json:"created_at"`
+}
+
+// StatusChangeRequest carries the reason for freezing, unfreezing or
+// reactivating an account.
+type StatusChangeRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Validate checks the reason.
+func (r *StatusChangeRequest) Validate(v *validation.Validator) {
+	if v.Required("reason", r.Reason) {
+		v.MaxLength("reason", r.Reason, maxReasonLength)
+	}
+}
+
+// CloseAccountRequest closes an account. An account with a balance needs
+// PayoutAccountID, another account of the same customer to move it to.
+type CloseAccountRequest struct {
+	Reason          string `json:"reason"`
+	PayoutAccountID int    `json:"payout_account_id"`
+}
+
+// Validate checks the reason.
+func (r *CloseAccountRequest) Validate(v *validation.Validator) {
+	if v.Required("reason", r.Reason) {
+		v.MaxLength("reason", r.Reason, maxReasonLength)
+	}
+}
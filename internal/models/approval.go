@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/validation"
+)
+
+// Statuses of a transfer approval. A pending approval past its ExpiresAt
+// is reported as expired.
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+	ApprovalExpired  = "expired"
+)
+
+// TransferApproval is a transfer above the approval threshold, waiting for
+// an operator other than the one who requested it. Exactly one of
+// RequestedByCustomerID and RequestedByOperatorID is set. Key, when set,
+// keeps the transfer from being requested twice. Fee is the transfer fee
+// held on the source account along with Amount while the approval is
+// pending.
+type TransferApproval struct {
+	ID                    int64       `json:"id"`
+	FromAccountID         int         `json:"from_id"`
+	ToAccountID           int         `json:"to_id"`
+	Amount                money.Money `json:"amount"`
+	Fee                   money.Money `json:"fee"`
+	Status                string      `json:"status"`
+	RequestedByCustomerID int         `json:"requested_by_customer_id,omitempty"`
+	RequestedByOperatorID int         `json:"requested_by_operator_id,omitempty"`
+	ReviewedBy            int         `json:"reviewed_by,omitempty"`
+	ReviewReason          string      `json:"review_reason,omitempty"`
+	ExpiresAt             time.Time   `json:"expires_at"`
+	CreatedAt             time.Time   `json:"created_at"`
+	ReviewedAt            *time.Time  `json:"reviewed_at,omitempty"`
+	Key                   string      `json:"-"`
+}
+
+// ApprovalFilter narrows the list of transfer approvals. An empty Status
+// lists every status; AccountID matches either side of the transfer.
+// BeforeID is the decoded pagination cursor.
+type ApprovalFilter struct {
+	Status    string
+	AccountID int
+	BeforeID  int64
+	Limit     int
+}
+
+type ApprovalPage struct {
+	Approvals  []TransferApproval `json:"approvals"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+// ReviewRequest carries the reason an operator gives for approving or
+// rejecting a transfer. It is required to reject.
+type ReviewRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Validate checks the reason. required is set when rejecting.
+func (r *ReviewRequest) Validate(v *validation.Validator, required bool) {
+	if required && !v.Required("reason", r.Reason) {
+		return
+	}
+	v.MaxLength("reason", r.Reason, maxReasonLength)
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// AuditEvent records a request made by a back-office operator, whether or
+// not it was allowed. AccountID and CustomerID are set when the route
+// names an account or a customer. Request is the request body, if any.
+type AuditEvent struct {
+	ID           int64     `json:"id"`
+	OperatorID   int       `json:"operator_id"`
+	OperatorRole string    `json:"operator_role"`
+	Action       string    `json:"action"`
+	Method       string    `json:"method"`
+	Path         string    `json:"path"`
+	AccountID    int       `json:"account_id,omitempty"`
+	CustomerID   int       `json:"customer_id,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+	Request      string    `json:"request,omitempty"`
+	StatusCode   int       `json:"status_code"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AuditFilter narrows the audit trail. Zero values mean no filter.
+// BeforeID is the decoded pagination cursor.
+type AuditFilter struct {
+	AccountID  int
+	OperatorID int
+	BeforeID   int64
+	Limit      int
+}
+
+type AuditPage struct {
+	Events     []AuditEvent `json:"events"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
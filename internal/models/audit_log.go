@@ -0,0 +1,84 @@
+package models
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
+// Actions recorded in the audit log.
+const (
+	AuditCreateAccount     = "create_account"
+	AuditDeposit           = "deposit"
+	AuditWithdraw          = "withdraw"
+	AuditTransfer          = "transfer"
+	AuditCaptureHold       = "capture_hold"
+	AuditCorrectBalance    = "correct_balance"
+	AuditChangeStatus      = "change_status"
+	AuditCloseAccount      = "close_account"
+	AuditOverdraftInterest = "overdraft_interest"
+	AuditMaintenanceFee    = "maintenance_fee"
+	AuditPayInterest       = "pay_interest"
+)
+
+// Actor is who made a change, as the audit log records it, and the ID of
+// the request the change was made in. Exactly one of CustomerID,
+// OperatorID and System is set; System names a job of the bank's own.
+type Actor struct {
+	CustomerID int
+	OperatorID int
+	System     string
+	RequestID  string
+}
+
+// String names the actor, e.g. "customer:4", "operator:2" or
+// "system:scheduler".
+func (a Actor) String() string {
+	switch {
+	case a.OperatorID != 0:
+		return "operator:" + strconv.Itoa(a.OperatorID)
+	case a.CustomerID != 0:
+		return "customer:" + strconv.Itoa(a.CustomerID)
+	case a.System != "":
+		return "system:" + a.System
+	default:
+		return "anonymous"
+	}
+}
+
+// AccountState is what the audit log records of an account before and
+// after a change. Status is only set by the actions that can change it.
+type AccountState struct {
+	AccountID int         `json:"account_id"`
+	Balance   money.Money `json:"balance"`
+	Status    string      `json:"status,omitempty"`
+}
+
+// AuditRecord is an entry of the tamper-evident audit log. Sequence
+// numbers have no gaps, and Hash is the SHA-256 of the record chained to
+// the hash of the one before, PrevHash. Before is null for an account
+// that did not exist. Before and After hold the account states, as
+// written.
+type AuditRecord struct {
+	Sequence       int64           `json:"sequence"`
+	Action         string          `json:"action"`
+	Actor          string          `json:"actor"`
+	RequestID      string          `json:"request_id,omitempty"`
+	AccountID      int             `json:"account_id"`
+	CounterpartyID int             `json:"counterparty_id,omitempty"`
+	Before         json.RawMessage `json:"before"`
+	After          json.RawMessage `json:"after"`
+	CreatedAt      time.Time       `json:"created_at"`
+	PrevHash       string          `json:"prev_hash"`
+	Hash           string          `json:"hash"`
+}
+
+// AuditLogHead is the last record of a verified audit log. Kept outside
+// the database, it lets a later verification tell whether records were
+// cut off the end of the log, which the chain alone cannot show.
+type AuditLogHead struct {
+	Sequence int64  `json:"sequence"`
+	Hash     string `json:"hash"`
+}
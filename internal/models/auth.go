@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/validation"
+)
+
+// Password limits. bcrypt ignores everything past 72 bytes.
+const (
+	minPasswordLength = 8
+	maxPasswordBytes  = 72
+)
+
+// Credentials carries the password chosen when a customer registers.
+type Credentials struct {
+	Password string `json:"password"`
+}
+
+// Validate checks the password.
+func (c *Credentials) Validate(v *validation.Validator) {
+	if v.Required("password", c.Password) {
+		v.MinLength("password", c.Password, minPasswordLength)
+		v.Check(len(c.Password) <= maxPasswordBytes, "password", validation.CodeTooLong, "must be at most 72 bytes")
+	}
+}
+
+// LoginRequest identifies a customer by CPF or CNPJ, with or without the
+// mask.
+type LoginRequest struct {
+	TaxID    string `json:"tax_id"`
+	Password string `json:"password"`
+}
+
+// OperatorLoginRequest identifies a back-office operator.
+type OperatorLoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// TokenPair is returned by login and refresh. ExpiresIn is the lifetime of
+// the access token in seconds. Operators get no refresh token and log in
+// again instead.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// RefreshToken is a stored refresh token. Only the hash of the token is
+// kept. A token is used once: refreshing revokes it and issues the next.
+type RefreshToken struct {
+	ID         int64
+	CustomerID int
+	TokenHash  string
+	ExpiresAt  time.Time
+}
+
+// Operator is a back-office user. Role is one of auth.OperatorRoles.
+type Operator struct {
+	ID           int
+	Username     string
+	Role         string
+	PasswordHash string
+}
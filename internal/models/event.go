@@ -0,0 +1,93 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
+// Domain event types.
+const (
+	EventAccountCreated = "account.created"
+	EventDeposit        = "deposit.completed"
+	EventWithdrawal     = "withdrawal.completed"
+	EventTransfer       = "transfer.completed"
+	EventHoldCaptured   = "hold.captured"
+	EventAccountClosed  = "account.closed"
+)
+
+// EventTypes lists every domain event type.
+var EventTypes = []string{EventAccountCreated, EventDeposit, EventWithdrawal, EventTransfer, EventHoldCaptured, EventAccountClosed}
+
+// Event is a domain event, written to the outbox in the same transaction
+// as the change it describes and published afterwards at least once. ID
+// stays the same across redeliveries, so consumers can drop duplicates.
+// Events of an account are published in Sequence order; a transfer is
+// ordered with the events of both of its accounts.
+type Event struct {
+	ID             string          `json:"id"`
+	Sequence       int64           `json:"sequence"`
+	Type           string          `json:"type"`
+	AccountID      int             `json:"account_id"`
+	CounterpartyID int             `json:"counterparty_id,omitempty"`
+	Data           json.RawMessage `json:"data"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// AccountIDs returns the accounts the event is ordered with.
+func (e Event) AccountIDs() []int {
+	if e.CounterpartyID == 0 {
+		return []int{e.AccountID}
+	}
+	return []int{e.AccountID, e.CounterpartyID}
+}
+
+// AccountCreatedEvent is the data of an account.created event.
+type AccountCreatedEvent struct {
+	AccountID     int         `json:"account_id"`
+	CustomerID    int         `json:"customer_id"`
+	AccountNumber string      `json:"account_number"`
+	Category      string      `json:"category"`
+	Currency      string      `json:"currency"`
+	Status        string      `json:"status"`
+	Balance       money.Money `json:"balance"`
+}
+
+// MovementEvent is the data of deposit.completed and withdrawal.completed
+// events. Fee is the withdrawal fee charged with it, if any.
+type MovementEvent struct {
+	JournalEntryID int64       `json:"journal_entry_id"`
+	AccountID      int         `json:"account_id"`
+	Amount         money.Money `json:"amount"`
+	Currency       string      `json:"currency"`
+	Fee            money.Money `json:"fee"`
+}
+
+// CaptureEvent is the data of a hold.captured event: the movement of the
+// captured amount, with the withdrawal fee charged with it, if any.
+type CaptureEvent struct {
+	MovementEvent
+	HoldID int64 `json:"hold_id"`
+}
+
+// TransferEvent is the data of a transfer.completed event. Amount and Fee
+// are in Currency, the source account's; FX is set when the destination
+// was credited in another currency.
+type TransferEvent struct {
+	JournalEntryID int64         `json:"journal_entry_id"`
+	FromAccountID  int           `json:"from_account_id"`
+	ToAccountID    int           `json:"to_account_id"`
+	Amount         money.Money   `json:"amount"`
+	Currency       string        `json:"currency"`
+	Fee            money.Money   `json:"fee"`
+	FX             *FXConversion `json:"fx,omitempty"`
+}
+
+// AccountClosedEvent is the data of an account.closed event.
+type AccountClosedEvent struct {
+	AccountID       int          `json:"account_id"`
+	Reason          string       `json:"reason"`
+	PayoutAccountID *int         `json:"payout_account_id,omitempty"`
+	PayoutAmount    *money.Money `json:"payout_amount,omitempty"`
+}
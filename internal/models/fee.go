@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/validation"
+)
+
+// Fee types.
+const (
+	FeeTypeWithdrawal  = "withdrawal"
+	FeeTypeTransfer    = "transfer"
+	FeeTypeMaintenance = "maintenance"
+)
+
+// FeeTypes lists every type of fee.
+var FeeTypes = []string{FeeTypeWithdrawal, FeeTypeTransfer, FeeTypeMaintenance}
+
+// Fee is the amount charged for a withdrawal, a transfer or a month of
+// account maintenance. Transfer fees depend on the types of the customers
+// on both sides, FromType and ToType, and are paid by the sender.
+// Maintenance fees depend on the account's Category. Withdrawal fees are
+// the same for every account.
+type Fee struct {
+	Type     string      `json:"type"`
+	FromType string      `json:"from_type,omitempty"`
+	ToType   string      `json:"to_type,omitempty"`
+	Category string      `json:"category,omitempty"`
+	Amount   money.Money `json:"amount"`
+}
+
+// Validate checks the type, the fields the type depends on and the amount.
+func (f *Fee) Validate(v *validation.Validator) {
+	v.NonNegative("amount", f.Amount)
+	if !v.Required("type", f.Type) {
+		return
+	}
+	v.OneOf("type", f.Type, FeeTypes...)
+
+	if f.Type == FeeTypeTransfer {
+		if v.Required("from_type", f.FromType) {
+			v.OneOf("from_type", f.FromType, CustomerTypeNatural, CustomerTypeLegal)
+		}
+		if v.Required("to_type", f.ToType) {
+			v.OneOf("to_type", f.ToType, CustomerTypeNatural, CustomerTypeLegal)
+		}
+	} else {
+		v.Check(f.FromType == "", "from_type", validation.CodeNotAllowed, "only applies to transfer fees")
+		v.Check(f.ToType == "", "to_type", validation.CodeNotAllowed, "only applies to transfer fees")
+	}
+
+	if f.Type == FeeTypeMaintenance {
+		if v.Required("category", f.Category) {
+			v.OneOf("category", f.Category, AccountCategories...)
+		}
+	} else {
+		v.Check(f.Category == "", "category", validation.CodeNotAllowed, "only applies to maintenance fees")
+	}
+}
+
+// FeeWaiver exempts an account from one type of fee.
+type FeeWaiver struct {
+	AccountID int       `json:"account_id"`
+	FeeType   string    `json:"fee_type"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// FeeWaiverRequest carries the reason an account is exempted from a fee.
+type FeeWaiverRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Validate checks the reason.
+func (r *FeeWaiverRequest) Validate(v *validation.Validator) {
+	if v.Required("reason", r.Reason) {
+		v.MaxLength("reason", r.Reason, maxReasonLength)
+	}
+}
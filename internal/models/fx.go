@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/currency"
+	"github.com/gregoryAlvim/gobank/internal/fx"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/validation"
+)
+
+// FXRate is how many units of Quote one unit of Base buys, before the
+// bank's spread. SpreadBps is taken off Rate when an amount is converted.
+type FXRate struct {
+	Base      string    `json:"base"`
+	Quote     string    `json:"quote"`
+	Rate      fx.Rate   `json:"rate"`
+	SpreadBps int       `json:"spread_bps"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the currency pair, the rate and the spread.
+func (r *FXRate) Validate(v *validation.Validator) {
+	v.Check(currency.Valid(r.Base), "base", validation.CodeInvalid, "must be a supported currency")
+	v.Check(currency.Valid(r.Quote), "quote", validation.CodeInvalid, "must be a supported currency")
+	v.Check(r.Base != r.Quote, "quote", validation.CodeInvalid, "must differ from the base currency")
+	v.Check(r.Rate.IsPositive(), "rate", validation.CodeInvalid, "must be positive")
+	v.Between("spread_bps", r.SpreadBps, 0, fx.MaxSpreadBps-1)
+}
+
+// Convert converts amount of Base into Quote at the rate less the spread.
+func (r *FXRate) Convert(amount money.Money) (*FXConversion, error) {
+	applied, err := r.Rate.ApplySpread(r.SpreadBps)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := applied.Convert(amount)
+	if err != nil {
+		return nil, err
+	}
+	return &FXConversion{
+		FromCurrency:    r.Base,
+		ToCurrency:      r.Quote,
+		SourceAmount:    amount,
+		ConvertedAmount: converted,
+		MidRate:         r.Rate,
+		SpreadBps:       r.SpreadBps,
+		Rate:            applied,
+	}, nil
+}
+
+// FXConversion is the conversion applied by a cross-currency transfer:
+// SourceAmount left the source account and ConvertedAmount reached the
+// destination at Rate, which is MidRate less the spread. QuoteID is set
+// when the transfer used a quote.
+type FXConversion struct {
+	JournalEntryID  int64       `json:"journal_entry_id,omitempty"`
+	QuoteID         *int64      `json:"quote_id,omitempty"`
+	FromCurrency    string      `json:"from_currency"`
+	ToCurrency      string      `json:"to_currency"`
+	SourceAmount    money.Money `json:"source_amount"`
+	ConvertedAmount money.Money `json:"converted_amount"`
+	MidRate         fx.Rate     `json:"mid_rate"`
+	SpreadBps       int         `json:"spread_bps"`
+	Rate            fx.Rate     `json:"rate"`
+}
+
+// FXQuote locks the conversion of Amount out of an account until
+// ExpiresAt. A transfer of exactly Amount from the account to one in
+// ToCurrency can use it once, by its ID.
+type FXQuote struct {
+	ID              int64       `json:"id"`
+	AccountID       int         `json:"account_id"`
+	FromCurrency    string      `json:"from_currency"`
+	ToCurrency      string      `json:"to_currency"`
+	Amount          money.Money `json:"amount"`
+	ConvertedAmount money.Money `json:"converted_amount"`
+	MidRate         fx.Rate     `json:"mid_rate"`
+	SpreadBps       int         `json:"spread_bps"`
+	Rate            fx.Rate     `json:"rate"`
+	ExpiresAt       time.Time   `json:"expires_at"`
+	UsedAt          *time.Time  `json:"used_at,omitempty"`
+	CreatedAt       time.Time   `json:"created_at"`
+}
+
+// FXQuoteRequest asks for a quote to convert Amount of the account's
+// currency into ToCurrency.
+type FXQuoteRequest struct {
+	ToCurrency string      `json:"to_currency"`
+	Amount     money.Money `json:"amount"`
+}
+
+// Validate checks the currency and the amount.
+func (r *FXQuoteRequest) Validate(v *validation.Validator) {
+	if v.Required("to_currency", r.ToCurrency) {
+		v.Check(currency.Valid(r.ToCurrency), "to_currency", validation.CodeInvalid, "must be a supported currency")
+	}
+	v.Positive("amount", r.Amount)
+}
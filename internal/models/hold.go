@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/validation"
+)
+
+// Statuses of a hold. An active hold past its ExpiresAt is reported as
+// expired and no longer reserves anything.
+const (
+	HoldActive   = "active"
+	HoldCaptured = "captured"
+	HoldVoided   = "voided"
+	HoldExpired  = "expired"
+)
+
+const maxHoldDescriptionLength = 255
+
+// Hold reserves Amount on an account until it is captured, voided or
+// expires. CapturedAmount is set once it is captured; the rest of the
+// hold is released.
+type Hold struct {
+	ID             int64        `json:"id"`
+	AccountID      int          `json:"account_id"`
+	Amount         money.Money  `json:"amount"`
+	CapturedAmount *money.Money `json:"captured_amount,omitempty"`
+	Status         string       `json:"status"`
+	Description    string       `json:"description,omitempty"`
+	ExpiresAt      time.Time    `json:"expires_at"`
+	CreatedAt      time.Time    `json:"created_at"`
+	ReleasedAt     *time.Time   `json:"released_at,omitempty"`
+}
+
+// HoldRequest places a hold. Without ExpiresAt the hold lasts the
+// service's default.
+type HoldRequest struct {
+	Amount      money.Money `json:"amount"`
+	Description string      `json:"description"`
+	ExpiresAt   *time.Time  `json:"expires_at"`
+}
+
+// Validate checks the amount and the description. The expiry is checked
+// by the service, which knows the longest hold allowed.
+func (r *HoldRequest) Validate(v *validation.Validator) {
+	v.Positive("amount", r.Amount)
+	v.MaxLength("description", r.Description, maxHoldDescriptionLength)
+}
+
+// CaptureRequest settles a hold. Without Amount the whole hold is
+// captured.
+type CaptureRequest struct {
+	Amount *money.Money `json:"amount"`
+}
+
+// Validate checks the amount, when there is one.
+func (r *CaptureRequest) Validate(v *validation.Validator) {
+	if r.Amount != nil {
+		v.Positive("amount", *r.Amount)
+	}
+}
+
+// HoldFilter narrows an account's holds. An empty Status lists every
+// status. BeforeID is the decoded pagination cursor.
+type HoldFilter struct {
+	Status   string
+	BeforeID int64
+	Limit    int
+}
+
+type HoldPage struct {
+	Holds      []Hold `json:"holds"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Balance is an account's balance as booked in the ledger, and what is
+// left of it to spend once holds and pending transfer approvals are taken
+// out.
+type Balance struct {
+	Ledger    money.Money `json:"ledger"`
+	Available money.Money `json:"available"`
+}
+
+// BalanceAsOf is an account's ledger balance at a point in the past,
+// reconstructed from its history. Holds are not kept in the history, so
+// there is no available balance.
+type BalanceAsOf struct {
+	Ledger money.Money `json:"ledger"`
+	AsOf   time.Time   `json:"as_of"`
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/validation"
+)
+
+// Interest rate types.
+const (
+	RateTypeFixed = "fixed"
+	RateTypeCDI   = "cdi"
+)
+
+// Caps on interest rates, in basis points: 100% a year for fixed rates
+// and 300% of the CDI.
+const (
+	maxFixedRateBps = 10000
+	maxCDIShareBps  = 30000
+	maxCDIRateBps   = 10000
+)
+
+// InterestRate is the interest earned by every account in a category. For
+// a fixed rate RateBps is the annual rate; for a CDI rate it is the share
+// of the CDI, 10000 meaning 100% of the CDI. Accounts earn from
+// EffectiveFrom on, or from the day they were opened if later.
+type InterestRate struct {
+	Category      string    `json:"category"`
+	Type          string    `json:"type"`
+	RateBps       int       `json:"rate_bps"`
+	EffectiveFrom time.Time `json:"effective_from"`
+}
+
+// Validate checks the type and the rate.
+func (r *InterestRate) Validate(v *validation.Validator) {
+	if !v.Required("type", r.Type) {
+		return
+	}
+	v.OneOf("type", r.Type, RateTypeFixed, RateTypeCDI)
+	if r.Type == RateTypeCDI {
+		v.Between("rate_bps", r.RateBps, 0, maxCDIShareBps)
+	} else {
+		v.Between("rate_bps", r.RateBps, 0, maxFixedRateBps)
+	}
+}
+
+// DailyInterest is the interest balance earns in one day: the annual rate
+// divided by 365. cdi is the CDI that applies to the day and is only used
+// by CDI rates. Balances that are not positive earn nothing. The interest
+// is not rounded to the cent; only the month's total is, when it is paid.
+func (r *InterestRate) DailyInterest(balance money.Money, cdi *CDIRate) (money.Precise, error) {
+	if !balance.IsPositive() {
+		return 0, nil
+	}
+	num, den := int64(r.RateBps), int64(10000*365)
+	if r.Type == RateTypeCDI {
+		if cdi == nil {
+			return 0, nil
+		}
+		num, den = num*int64(cdi.AnnualRateBps), den*10000
+	}
+	return balance.MulFracPrecise(num, den)
+}
+
+// CDIRate is the annual CDI rate published for a day, in basis points.
+type CDIRate struct {
+	Date          time.Time `json:"date"`
+	AnnualRateBps int       `json:"annual_rate_bps"`
+}
+
+// Validate checks the rate.
+func (r *CDIRate) Validate(v *validation.Validator) {
+	v.Between("annual_rate_bps", r.AnnualRateBps, 0, maxCDIRateBps)
+}
+
+// AccrualAccount is an account that earns interest. Start is the first
+// day it earns; LastAccrued is the last day already accrued, if any.
+type AccrualAccount struct {
+	AccountID   int
+	Rate        InterestRate
+	Start       time.Time
+	LastAccrued *time.Time
+}
+
+// InterestAccrual is the interest an account earned on one day, on its
+// balance at the end of that day. It is credited in the monthly payout.
+type InterestAccrual struct {
+	AccountID  int
+	Date       time.Time
+	Balance    money.Money
+	Rate       InterestRate
+	CDIRateBps *int
+	Amount     money.Precise
+}
+
+// UnpaidInterest is the sum of an account's accruals not yet credited.
+type UnpaidInterest struct {
+	AccountID int
+	Amount    money.Precise
+}
+
+// InterestReport describes an interest run: the days accrued and the
+// interest credited on each account, or what would be with DryRun.
+type InterestReport struct {
+	DryRun         bool                    `json:"dry_run"`
+	AccruedThrough time.Time               `json:"accrued_through"`
+	Accounts       []InterestAccountReport `json:"accounts"`
+}
+
+// InterestAccountReport is one account's part of an interest run.
+// Accrued is not rounded to the cent; Credited is. Error is set when the
+// account could not be processed; the other accounts are still processed.
+type InterestAccountReport struct {
+	AccountID   int           `json:"account_id"`
+	DaysAccrued int           `json:"days_accrued"`
+	Accrued     money.Precise `json:"accrued"`
+	Credited    money.Money   `json:"credited"`
+	Error       string        `json:"error,omitempty"`
+}
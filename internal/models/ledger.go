@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
+type Posting struct {
+	ID             int64       `json:"id"`
+	JournalEntryID int64       `json:"journal_entry_id"`
+	AccountID      int         `json:"account_id"`
+	Direction      string      `json:"direction"`
+	Amount         money.Money `json:"amount"`
+	Currency       string      `json:"currency"`
+}
+
+type JournalEntry struct {
+	ID          int64     `json:"id"`
+	Kind        string    `json:"kind"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	Postings    []Posting `json:"postings"`
+}
+
+// Reconciliation compares the stored balance of an account with the
+// balance derived from all its postings. Postings is one page of them,
+// newest first; NextCursor is set when older postings remain.
+type Reconciliation struct {
+	AccountID     int         `json:"account_id"`
+	Balance       money.Money `json:"balance"`
+	LedgerBalance money.Money `json:"ledger_balance"`
+	Balanced      bool        `json:"balanced"`
+	Postings      []Posting   `json:"postings"`
+	NextCursor    string      `json:"next_cursor,omitempty"`
+}
+
+// PostingFilter pages through an account's postings. BeforeID is the
+// decoded pagination cursor.
+type PostingFilter struct {
+	BeforeID int64
+	Limit    int
+}
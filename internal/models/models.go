@@ -1,23 +1,170 @@
 package models
 
+import (
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/accountnumber"
+	"github.com/gregoryAlvim/gobank/internal/currency"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/taxid"
+	"github.com/gregoryAlvim/gobank/internal/validation"
+)
+
+// Customer types.
+const (
+	CustomerTypeNatural = "natural"
+	CustomerTypeLegal   = "legal"
+)
+
+// Account categories.
+const (
+	AccountCategoryStandard = "standard"
+	AccountCategoryPremium  = "premium"
+	AccountCategoryBusiness = "business"
+	AccountCategorySavings  = "savings"
+)
+
+// AccountCategories lists every category an account can be opened in.
+var AccountCategories = []string{
+	AccountCategoryStandard,
+	AccountCategoryPremium,
+	AccountCategoryBusiness,
+	AccountCategorySavings,
+}
+
+// Limits shared by the customer models. Text limits match the column sizes.
+const (
+	maxNameLength   = 255
+	maxEmailLength  = 255
+	maxPhoneLength  = 20
+	maxPersonAge    = 150
+	maxCompanyAge   = 1000
+	maxReasonLength = 500
+)
+
+// NaturalPerson is an individual customer.
 type NaturalPerson struct {
-	ID            int     `json:"id"`
-	MonthlyIncome float64 `json:"monthly_income"`
-	Age           int     `json:"age"`
-	FullName      string  `json:"full_name"`
-	PhoneNumber   string  `json:"phone_number"`
-	Email         string  `json:"email"`
-	Category      string  `json:"category"`
-	Balance       float64 `json:"balance"`
+	ID            int         `json:"id"`
+	CPF           string      `json:"cpf"`
+	MonthlyIncome money.Money `json:"monthly_income"`
+	Age           int         `json:"age"`
+	FullName      string      `json:"full_name"`
+	PhoneNumber   string      `json:"phone_number"`
+	Email         string      `json:"email"`
+	PasswordHash  string      `json:"-"`
 }
 
+// Validate checks the fields of an individual customer.
+func (p *NaturalPerson) Validate(v *validation.Validator) {
+	if v.Required("cpf", p.CPF) {
+		v.Check(taxid.ValidCPF(p.CPF), "cpf", validation.CodeInvalid, "must be a valid CPF")
+	}
+	if v.Required("full_name", p.FullName) {
+		v.MaxLength("full_name", p.FullName, maxNameLength)
+	}
+	if v.Required("email", p.Email) {
+		v.MaxLength("email", p.Email, maxEmailLength)
+		v.Email("email", p.Email)
+	}
+	v.MaxLength("phone_number", p.PhoneNumber, maxPhoneLength)
+	v.Between("age", p.Age, 0, maxPersonAge)
+	v.NonNegative("monthly_income", p.MonthlyIncome)
+}
+
+// LegalPerson is a company customer.
 type LegalPerson struct {
-	ID             int     `json:"id"`
-	AnnualRevenue  float64 `json:"annual_revenue"`
-	Age            int     `json:"age"`
-	TradeName      string  `json:"trade_name"`
-	PhoneNumber    string  `json:"phone_number"`
-	CorporateEmail string  `json:"corporate_email"`
-	Category       string  `json:"category"`
-	Balance        float64 `json:"balance"`
+	ID             int         `json:"id"`
+	CNPJ           string      `json:"cnpj"`
+	AnnualRevenue  money.Money `json:"annual_revenue"`
+	Age            int         `json:"age"`
+	TradeName      string      `json:"trade_name"`
+	PhoneNumber    string      `json:"phone_number"`
+	CorporateEmail string      `json:"corporate_email"`
+	PasswordHash   string      `json:"-"`
+}
+
+// Validate checks the fields of a company customer. Age is the company's
+// age in years.
+func (p *LegalPerson) Validate(v *validation.Validator) {
+	if v.Required("cnpj", p.CNPJ) {
+		v.Check(taxid.ValidCNPJ(p.CNPJ), "cnpj", validation.CodeInvalid, "must be a valid CNPJ")
+	}
+	if v.Required("trade_name", p.TradeName) {
+		v.MaxLength("trade_name", p.TradeName, maxNameLength)
+	}
+	if v.Required("corporate_email", p.CorporateEmail) {
+		v.MaxLength("corporate_email", p.CorporateEmail, maxEmailLength)
+		v.Email("corporate_email", p.CorporateEmail)
+	}
+	v.MaxLength("phone_number", p.PhoneNumber, maxPhoneLength)
+	v.Between("age", p.Age, 0, maxCompanyAge)
+	v.NonNegative("annual_revenue", p.AnnualRevenue)
+}
+
+// Account holds money for a customer. Its ID is unique across all
+// customers, so it identifies the account on its own. Branch, Number and
+// CheckDigit form the account number shown to customers. Balance is in
+// Currency, an ISO 4217 code. Only active accounts can be debited; see the
+// Account* statuses.
+type Account struct {
+	ID         int         `json:"id"`
+	CustomerID int         `json:"customer_id"`
+	Branch     string      `json:"branch"`
+	Number     string      `json:"number"`
+	CheckDigit string      `json:"check_digit"`
+	Category   string      `json:"category"`
+	Balance    money.Money `json:"balance"`
+	Currency   string      `json:"currency"`
+	Status     string      `json:"status"`
+	CreatedAt  time.Time   `json:"created_at"`
+	ClosedAt   *time.Time  `json:"closed_at,omitempty"`
+}
+
+// AccountNumber returns the formatted account number, e.g. 0001-00001234-5.
+func (a *Account) AccountNumber() string {
+	return accountnumber.Number{Branch: a.Branch, Account: a.Number, CheckDigit: a.CheckDigit}.String()
+}
+
+// OpenAccountRequest carries the fields chosen when an account is opened.
+// Balance is the opening balance, in Currency. Without a currency the
+// account holds currency.Default.
+type OpenAccountRequest struct {
+	Category string      `json:"category"`
+	Balance  money.Money `json:"balance"`
+	Currency string      `json:"currency"`
+}
+
+// Validate checks the category, opening balance and currency.
+func (r *OpenAccountRequest) Validate(v *validation.Validator) {
+	if v.Required("category", r.Category) {
+		v.OneOf("category", r.Category, AccountCategories...)
+	}
+	v.NonNegative("balance", r.Balance)
+	if r.Currency != "" {
+		v.Check(currency.Valid(r.Currency), "currency", validation.CodeInvalid, "must be a supported currency")
+	}
+}
+
+// Account returns the account the request opens for customerID.
+func (r *OpenAccountRequest) Account(customerID int) Account {
+	account := Account{CustomerID: customerID, Category: r.Category, Balance: r.Balance, Currency: r.Currency}
+	if account.Currency == "" {
+		account.Currency = currency.Default
+	}
+	return account
+}
+
+// BalanceCorrectionRequest sets an account's balance to Balance. The
+// difference is journaled as an adjustment.
+type BalanceCorrectionRequest struct {
+	Balance money.Money `json:"balance"`
+	Reason  string      `json:"reason"`
+}
+
+// Validate checks the new balance and the reason.
+func (r *BalanceCorrectionRequest) Validate(v *validation.Validator) {
+	v.NonNegative("balance", r.Balance)
+	if v.Required("reason", r.Reason) {
+		v.MaxLength("reason", r.Reason, maxReasonLength)
+	}
 }
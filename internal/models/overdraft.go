@@ -0,0 +1,64 @@
+package models
+
+import (
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/validation"
+)
+
+// maxMonthlyRateBps caps overdraft interest at 100% a month.
+const maxMonthlyRateBps = 10000
+
+// OverdraftPolicy is the overdraft (cheque especial) of every account in a
+// category. MonthlyRateBps is the interest on negative balances, in basis
+// points a month.
+type OverdraftPolicy struct {
+	Category       string      `json:"category"`
+	Limit          money.Money `json:"limit"`
+	MonthlyRateBps int         `json:"monthly_rate_bps"`
+}
+
+// Validate checks the limit and the rate.
+func (p *OverdraftPolicy) Validate(v *validation.Validator) {
+	v.NonNegative("limit", p.Limit)
+	v.Between("monthly_rate_bps", p.MonthlyRateBps, 0, maxMonthlyRateBps)
+}
+
+// DailyInterest is the interest charged for one day on balance. Positive
+// balances are not charged; the monthly rate is spread over 30 days.
+func (p *OverdraftPolicy) DailyInterest(balance money.Money) money.Money {
+	if !balance.IsNegative() {
+		return money.Zero
+	}
+	// The interest is a fraction of the balance, so it cannot overflow.
+	interest, _ := balance.Neg().MulFrac(int64(p.MonthlyRateBps), 30*10000)
+	return interest
+}
+
+// Overdraft is how much an account may still go below zero. Used counts
+// the negative part of the available balance, so held funds use the limit
+// too. Custom is set when the account has its own limit rather than its
+// category's.
+type Overdraft struct {
+	Limit          money.Money `json:"limit"`
+	Used           money.Money `json:"used"`
+	Remaining      money.Money `json:"remaining"`
+	MonthlyRateBps int         `json:"monthly_rate_bps"`
+	Custom         bool        `json:"custom"`
+}
+
+// OverdraftLimitRequest sets an account's own overdraft limit. A null
+// Limit goes back to the category's.
+type OverdraftLimitRequest struct {
+	Limit  *money.Money `json:"limit"`
+	Reason string       `json:"reason"`
+}
+
+// Validate checks the limit and the reason.
+func (r *OverdraftLimitRequest) Validate(v *validation.Validator) {
+	if r.Limit != nil {
+		v.NonNegative("limit", *r.Limit)
+	}
+	if v.Required("reason", r.Reason) {
+		v.MaxLength("reason", r.Reason, maxReasonLength)
+	}
+}
@@ -0,0 +1,204 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/validation"
+)
+
+// How often a scheduled transfer runs. Weekly transfers run on the
+// weekday of their start date; monthly ones on DayOfMonth, or on the last
+// day of shorter months.
+const (
+	FrequencyOnce    = "once"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+)
+
+// Statuses of a scheduled transfer.
+const (
+	ScheduleActive    = "active"
+	ScheduleCompleted = "completed"
+	ScheduleCancelled = "cancelled"
+)
+
+// Outcomes of a scheduled transfer run. A retrying run failed and will be
+// tried again; a failed one was given up on, and the schedule moved on to
+// its next date.
+const (
+	RunSucceeded       = "succeeded"
+	RunPendingApproval = "pending_approval"
+	RunRetrying        = "retrying"
+	RunFailed          = "failed"
+)
+
+const maxScheduleDescriptionLength = 255
+
+// ScheduledTransfer is a transfer set up to run on a future date, once or
+// as a standing order. A standing order ends after EndDate or MaxRuns
+// runs, whichever comes first, or never if neither is set.
+//
+// NextRunDate is the day the next transfer is due. It runs on the first
+// business day from then on, at NextAttemptAt, which also holds the time
+// of the next retry after a failure. Attempts counts the failed attempts
+// at the current date. Exactly one of CreatedByCustomerID and
+// CreatedByOperatorID is set; transfers run on their behalf, with the
+// role they had when they scheduled it.
+type ScheduledTransfer struct {
+	ID                  int64       `json:"id"`
+	FromAccountID       int         `json:"from_id"`
+	ToAccountID         int         `json:"to_id"`
+	Amount              money.Money `json:"amount"`
+	Description         string      `json:"description,omitempty"`
+	Frequency           string      `json:"frequency"`
+	StartDate           Date        `json:"start_date"`
+	DayOfMonth          int         `json:"day_of_month,omitempty"`
+	EndDate             *Date       `json:"end_date,omitempty"`
+	MaxRuns             *int        `json:"max_runs,omitempty"`
+	RunsCount           int         `json:"runs_count"`
+	Status              string      `json:"status"`
+	NextRunDate         *Date       `json:"next_run_date,omitempty"`
+	NextAttemptAt       *time.Time  `json:"next_attempt_at,omitempty"`
+	Attempts            int         `json:"attempts"`
+	CreatedByCustomerID int         `json:"created_by_customer_id,omitempty"`
+	CreatedByOperatorID int         `json:"created_by_operator_id,omitempty"`
+	CreatedByRole       string      `json:"-"`
+	CreatedAt           time.Time   `json:"created_at"`
+}
+
+// NextOccurrence returns the first date after after on which the transfer
+// is due, before moving it to a business day. It reports false when there
+// is none: the transfer ran its last time or the next date is past the
+// end date.
+func (s *ScheduledTransfer) NextOccurrence(after Date) (Date, bool) {
+	if s.MaxRuns != nil && s.RunsCount >= *s.MaxRuns {
+		return Date{}, false
+	}
+
+	next := s.StartDate
+	switch s.Frequency {
+	case FrequencyWeekly:
+		if !after.Before(s.StartDate.Time) {
+			days := int(after.Sub(s.StartDate.Time).Hours() / 24)
+			next = s.StartDate.AddDays(7 * (days/7 + 1))
+		}
+	case FrequencyMonthly:
+		months := 0
+		if after.After(s.StartDate.Time) {
+			months = (after.Year()-s.StartDate.Year())*12 + int(after.Month()-s.StartDate.Month())
+		}
+		for {
+			next = monthDay(s.StartDate.Year(), s.StartDate.Month()+time.Month(months), s.DayOfMonth)
+			if !next.Before(s.StartDate.Time) && next.After(after.Time) {
+				break
+			}
+			months++
+		}
+	default:
+		if !next.After(after.Time) {
+			return Date{}, false
+		}
+	}
+
+	if s.EndDate != nil && next.After(s.EndDate.Time) {
+		return Date{}, false
+	}
+	return next, true
+}
+
+// monthDay returns day of the month, or the month's last day if it is
+// shorter. month may be past December.
+func monthDay(year int, month time.Month, day int) Date {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > last {
+		day = last
+	}
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// ScheduledTransferRequest sets up a scheduled transfer from the account
+// in the route. DayOfMonth defaults to the start date's day.
+type ScheduledTransferRequest struct {
+	ToAccountID int         `json:"to_id"`
+	Amount      money.Money `json:"amount"`
+	Description string      `json:"description"`
+	Frequency   string      `json:"frequency"`
+	StartDate   Date        `json:"start_date"`
+	DayOfMonth  int         `json:"day_of_month"`
+	EndDate     *Date       `json:"end_date"`
+	MaxRuns     *int        `json:"max_runs"`
+}
+
+// Validate checks the fields. That the start date is not in the past is
+// checked by the service, which knows the current day.
+func (r *ScheduledTransferRequest) Validate(v *validation.Validator) {
+	v.Check(r.ToAccountID > 0, "to_id", validation.CodeRequired, "is required")
+	v.Positive("amount", r.Amount)
+	v.MaxLength("description", r.Description, maxScheduleDescriptionLength)
+	v.Check(!r.StartDate.IsZero(), "start_date", validation.CodeRequired, "is required")
+	if v.Required("frequency", r.Frequency) {
+		v.OneOf("frequency", r.Frequency, FrequencyOnce, FrequencyWeekly, FrequencyMonthly)
+	}
+
+	if r.Frequency == FrequencyMonthly {
+		if r.DayOfMonth != 0 {
+			v.Between("day_of_month", r.DayOfMonth, 1, 31)
+		}
+	} else {
+		v.Check(r.DayOfMonth == 0, "day_of_month", validation.CodeNotAllowed, "only applies to monthly transfers")
+	}
+	if r.Frequency == FrequencyOnce {
+		v.Check(r.EndDate == nil, "end_date", validation.CodeNotAllowed, "only applies to recurring transfers")
+		v.Check(r.MaxRuns == nil, "max_runs", validation.CodeNotAllowed, "only applies to recurring transfers")
+		return
+	}
+	validateScheduleEnd(v, r.StartDate, r.EndDate, r.MaxRuns)
+}
+
+func validateScheduleEnd(v *validation.Validator, start Date, end *Date, maxRuns *int) {
+	if end != nil {
+		v.Check(!end.Before(start.Time), "end_date", validation.CodeOutOfRange, "must not be before start_date")
+	}
+	if maxRuns != nil {
+		v.Check(*maxRuns >= 1, "max_runs", validation.CodeOutOfRange, "must be at least 1")
+	}
+}
+
+// UpdateScheduledTransferRequest changes an active scheduled transfer.
+// Every field replaces the current value, so a null EndDate or MaxRuns
+// removes it. To change the dates or the frequency, cancel the transfer
+// and schedule a new one.
+type UpdateScheduledTransferRequest struct {
+	Amount      money.Money `json:"amount"`
+	Description string      `json:"description"`
+	EndDate     *Date       `json:"end_date"`
+	MaxRuns     *int        `json:"max_runs"`
+}
+
+// Validate checks the fields against the transfer being changed.
+func (r *UpdateScheduledTransferRequest) Validate(v *validation.Validator, schedule *ScheduledTransfer) {
+	v.Positive("amount", r.Amount)
+	v.MaxLength("description", r.Description, maxScheduleDescriptionLength)
+	if schedule.Frequency == FrequencyOnce {
+		v.Check(r.EndDate == nil, "end_date", validation.CodeNotAllowed, "only applies to recurring transfers")
+		v.Check(r.MaxRuns == nil, "max_runs", validation.CodeNotAllowed, "only applies to recurring transfers")
+		return
+	}
+	validateScheduleEnd(v, schedule.StartDate, r.EndDate, r.MaxRuns)
+}
+
+// ScheduledTransferRun is one attempt at a scheduled transfer. RunDate is
+// the date the transfer was due; Attempt counts from 1 for each date.
+// ApprovalID is set when the transfer is waiting for an operator's
+// approval.
+type ScheduledTransferRun struct {
+	ID                  int64     `json:"id"`
+	ScheduledTransferID int64     `json:"scheduled_transfer_id"`
+	RunDate             Date      `json:"run_date"`
+	Attempt             int       `json:"attempt"`
+	Status              string    `json:"status"`
+	Error               string    `json:"error,omitempty"`
+	ApprovalID          *int64    `json:"approval_id,omitempty"`
+	RanAt               time.Time `json:"ran_at"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
+// Transaction is one line of an account's history: the effect of a journal
+// entry on that account and the balance right after it. FX is set on
+// transfers between accounts in different currencies.
+type Transaction struct {
+	ID             int64         `json:"id"`
+	JournalEntryID int64         `json:"journal_entry_id"`
+	Kind           string        `json:"kind"`
+	Direction      string        `json:"direction"`
+	Amount         money.Money   `json:"amount"`
+	BalanceAfter   money.Money   `json:"balance_after"`
+	CounterpartyID *int          `json:"counterparty_id,omitempty"`
+	FX             *FXConversion `json:"fx,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+// TransactionFilter narrows an account's history. Zero values mean no
+// filter. BeforeID is the decoded pagination cursor: only transactions
+// older than it are returned.
+type TransactionFilter struct {
+	From      *time.Time
+	To        *time.Time
+	Direction string
+	MinAmount *money.Money
+	MaxAmount *money.Money
+	BeforeID  int64
+	Limit     int
+}
+
+type TransactionPage struct {
+	Transactions []Transaction `json:"transactions"`
+	NextCursor   string        `json:"next_cursor,omitempty"`
+}
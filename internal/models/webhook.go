@@ -0,0 +1,88 @@
+package models
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/validation"
+)
+
+// Statuses of a webhook delivery. A dead delivery ran out of attempts and
+// is only sent again by hand.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+const (
+	maxWebhookURLLength     = 2048
+	maxWebhookPartnerLength = 100
+)
+
+// WebhookSubscription sends the events of EventTypes, or of every type
+// when it is empty, to URL. A customer's subscription gets the events of
+// the customer's accounts; a partner's, set up by the bank, gets the
+// events of every account. Secret signs the deliveries and is only shown
+// when the subscription is created.
+type WebhookSubscription struct {
+	ID         int64     `json:"id"`
+	CustomerID int       `json:"customer_id,omitempty"`
+	Partner    string    `json:"partner,omitempty"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Secret     string    `json:"secret,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type WebhookSubscriptionRequest struct {
+	Partner    string   `json:"partner"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+}
+
+// Validate checks the URL and event types. Partner is only checked when
+// forPartner is set; customers do not name one.
+func (r *WebhookSubscriptionRequest) Validate(v *validation.Validator, forPartner bool) {
+	if forPartner && v.Required("partner", r.Partner) {
+		v.MaxLength("partner", r.Partner, maxWebhookPartnerLength)
+	}
+	if !forPartner {
+		v.Check(r.Partner == "", "partner", validation.CodeNotAllowed, "is only set on partner subscriptions")
+	}
+	if v.Required("url", r.URL) {
+		u, err := url.Parse(r.URL)
+		v.Check(err == nil && u.Scheme == "https" && u.Host != "", "url", validation.CodeInvalid, "must be an absolute https URL")
+		v.MaxLength("url", r.URL, maxWebhookURLLength)
+	}
+	for _, t := range r.EventTypes {
+		v.OneOf("event_types", t, EventTypes...)
+	}
+}
+
+// WebhookDelivery is an event sent, or to be sent, to a subscription.
+// Payload is the event as it is posted. NextAttemptAt is set while the
+// delivery is pending.
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	SubscriptionID int64           `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	LastStatusCode *int            `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+// DueWebhookDelivery is a delivery claimed for sending, with where to send
+// it and the secret to sign it with.
+type DueWebhookDelivery struct {
+	WebhookDelivery
+	URL    string
+	Secret string
+}
@@ -0,0 +1,287 @@
+// Package money implements an exact monetary amount type.
+//
+// Amounts are stored as integer minor units (cents), so arithmetic between
+// amounts never drifts the way float64 does. Values with more than two
+// decimal places are rounded to the nearest cent using round-half-to-even
+// (banker's rounding): 10.005 becomes 10.00 and 10.015 becomes 10.02. The same
+// rule applies when an amount is scaled by a fraction with MulFrac. Amounts
+// decoded from JSON are given by clients and are never rounded: those
+// with fractions of a cent are refused with ErrSubCent.
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of decimal places kept by Money.
+const Scale = 2
+
+const centsPerUnit = 100
+
+var (
+	ErrInvalidAmount = errors.New("invalid monetary amount")
+	ErrOverflow      = errors.New("monetary amount out of range")
+	ErrSubCent       = errors.New("monetary amount has fractions of a cent")
+)
+
+// Money is an amount expressed in cents.
+type Money int64
+
+// Zero is the zero amount.
+const Zero Money = 0
+
+// FromCents returns the amount represented by the given number of cents.
+func FromCents(cents int64) Money {
+	return Money(cents)
+}
+
+// New returns units plus cents, e.g. New(10, 50) is 10.50.
+// The sign of units is applied to the whole amount.
+func New(units, cents int64) Money {
+	if units < 0 {
+		return Money(units*centsPerUnit - cents)
+	}
+	return Money(units*centsPerUnit + cents)
+}
+
+// decimal is the only syntax Parse accepts. big.Rat alone would also take
+// fractions ("1/3"), exponents and hex ("0x10").
+var decimal = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// Parse converts a plain decimal string such as "123.45" or "-0.5" into
+// Money, rounding to the nearest cent half-to-even.
+func Parse(s string) (Money, error) {
+	cents, err := parseCents(s)
+	if err != nil {
+		return 0, err
+	}
+	return fromRat(cents)
+}
+
+// ParseExact is like Parse but refuses amounts with fractions of a cent,
+// such as "10.005", with ErrSubCent instead of rounding them. Trailing
+// zeros are fine: "10.500" is 10.50.
+func ParseExact(s string) (Money, error) {
+	cents, err := parseCents(s)
+	if err != nil {
+		return 0, err
+	}
+	if !cents.IsInt() {
+		return 0, fmt.Errorf("%w: %q", ErrSubCent, s)
+	}
+	return fromRat(cents)
+}
+
+// parseCents reads a plain decimal string as a number of cents.
+func parseCents(s string) (*big.Rat, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, ErrInvalidAmount
+	}
+	if !decimal.MatchString(s) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	return r.Mul(r, big.NewRat(centsPerUnit, 1)), nil
+}
+
+// MustParse is like Parse but panics on error. It is intended for constants
+// and tests.
+func MustParse(s string) Money {
+	m, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// fromRat rounds a value expressed in cents to an integer half-to-even.
+func fromRat(cents *big.Rat) (Money, error) {
+	v, err := roundRat(cents)
+	return Money(v), err
+}
+
+// roundRat rounds r to an integer half-to-even.
+func roundRat(r *big.Rat) (int64, error) {
+	num := new(big.Int).Set(r.Num())
+	den := r.Denom()
+
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() != 0 {
+		// Compare twice the remainder with the denominator to decide the
+		// rounding direction; ties go to the even neighbour.
+		twice := new(big.Int).Abs(rem)
+		twice.Lsh(twice, 1)
+		switch c := twice.Cmp(den); {
+		case c > 0, c == 0 && q.Bit(0) == 1:
+			if num.Sign() < 0 {
+				q.Sub(q, big.NewInt(1))
+			} else {
+				q.Add(q, big.NewInt(1))
+			}
+		}
+	}
+
+	if !q.IsInt64() {
+		return 0, ErrOverflow
+	}
+	return q.Int64(), nil
+}
+
+// Cents returns the amount in minor units.
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+func (m Money) Add(o Money) Money { return m + o }
+
+func (m Money) Sub(o Money) Money { return m - o }
+
+// CheckedAdd is like Add but returns ErrOverflow instead of wrapping around
+// when the sum does not fit.
+func (m Money) CheckedAdd(o Money) (Money, error) {
+	sum := m + o
+	if (sum > m) != (o > 0) {
+		return 0, ErrOverflow
+	}
+	return sum, nil
+}
+
+// CheckedSub is like Sub but returns ErrOverflow instead of wrapping
+// around when the difference does not fit.
+func (m Money) CheckedSub(o Money) (Money, error) {
+	diff := m - o
+	if (diff < m) != (o > 0) {
+		return 0, ErrOverflow
+	}
+	return diff, nil
+}
+
+func (m Money) Neg() Money { return -m }
+
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+func (m Money) IsZero() bool { return m == 0 }
+
+func (m Money) IsPositive() bool { return m > 0 }
+
+func (m Money) IsNegative() bool { return m < 0 }
+
+// Cmp returns -1, 0 or +1 depending on whether m is less than, equal to or
+// greater than o.
+func (m Money) Cmp(o Money) int {
+	switch {
+	case m < o:
+		return -1
+	case m > o:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// MulFrac returns m * num / den rounded to the nearest cent half-to-even.
+// It is used to apply rates and percentages without leaving integer math.
+func (m Money) MulFrac(num, den int64) (Money, error) {
+	if den == 0 {
+		return 0, errors.New("money: division by zero")
+	}
+	r := new(big.Rat).SetFrac(big.NewInt(int64(m)), big.NewInt(1))
+	r.Mul(r, new(big.Rat).SetFrac(big.NewInt(num), big.NewInt(den)))
+	return fromRat(r)
+}
+
+// MulRat returns m * r rounded to the nearest cent half-to-even.
+func (m Money) MulRat(r *big.Rat) (Money, error) {
+	v := new(big.Rat).SetInt64(int64(m))
+	return fromRat(v.Mul(v, r))
+}
+
+// String formats the amount with exactly two decimal places, e.g. "-12.30".
+func (m Money) String() string {
+	sign := ""
+	v := uint64(m)
+	if m < 0 {
+		sign = "-"
+		v = uint64(-m)
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/centsPerUnit, v%centsPerUnit)
+}
+
+// MarshalJSON encodes the amount as a JSON number with two decimal places.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts either a JSON number or a string holding a decimal.
+// The literal is parsed directly, never through float64, with ParseExact:
+// an amount with fractions of a cent is refused rather than rounded.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	v, err := ParseExact(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Scan implements sql.Scanner for DECIMAL/NUMERIC columns.
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	case int64:
+		if v > math.MaxInt64/centsPerUnit || v < math.MinInt64/centsPerUnit {
+			return ErrOverflow
+		}
+		*m = Money(v * centsPerUnit)
+		return nil
+	case float64:
+		// Some drivers hand back floats; format with the shortest exact
+		// representation before parsing so 0.1 stays 0.10.
+		return m.scanString(strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		return fmt.Errorf("money: cannot scan %T", src)
+	}
+}
+
+func (m *Money) scanString(s string) error {
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Value implements driver.Valuer, binding the amount as a decimal string.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
@@ -0,0 +1,184 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+	}{
+		{"0", 0},
+		{"1", 100},
+		{"123.45", 12345},
+		{"-0.5", -50},
+		{"0.1", 10},
+		// Round half to even at the third decimal place.
+		{"10.005", 1000},
+		{"10.015", 1002},
+		{"10.025", 1002},
+		{"10.0051", 1001},
+		{"-10.005", -1000},
+		{"-10.015", -1002},
+		{"2.675", 268},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseExact(t *testing.T) {
+	for in, want := range map[string]Money{"10": 1000, "10.5": 1050, "10.500": 1050, "-0.01": -1} {
+		got, err := ParseExact(in)
+		assert.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"10.005", "0.001", "-10.015"} {
+		_, err := ParseExact(in)
+		assert.ErrorIs(t, err, ErrSubCent, in)
+	}
+	_, err := ParseExact("ten")
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, in := range []string{"", "abc", "1.2.3", "1e100", "1e3", "1/3", "0x10", "+1", ".5", "1.", "1_000", "Inf", "99999999999999999999"} {
+		_, err := Parse(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "0.00", Zero.String())
+	assert.Equal(t, "123.45", FromCents(12345).String())
+	assert.Equal(t, "-0.05", FromCents(-5).String())
+	assert.Equal(t, "-12.30", New(-12, 30).String())
+}
+
+func TestMoney_NoFloatDrift(t *testing.T) {
+	// 0.1 + 0.2 is the classic float64 failure.
+	sum := MustParse("0.1").Add(MustParse("0.2"))
+	assert.Equal(t, MustParse("0.3"), sum)
+
+	var total Money
+	for i := 0; i < 1000; i++ {
+		total = total.Add(MustParse("0.01"))
+	}
+	assert.Equal(t, New(10, 0), total)
+}
+
+func TestMoney_Checked(t *testing.T) {
+	const max, min = Money(math.MaxInt64), Money(math.MinInt64)
+
+	sum, err := FromCents(10).CheckedAdd(FromCents(-25))
+	assert.NoError(t, err)
+	assert.Equal(t, FromCents(-15), sum)
+
+	diff, err := FromCents(10).CheckedSub(FromCents(-25))
+	assert.NoError(t, err)
+	assert.Equal(t, FromCents(35), diff)
+
+	_, err = max.CheckedAdd(FromCents(1))
+	assert.ErrorIs(t, err, ErrOverflow)
+	_, err = min.CheckedAdd(FromCents(-1))
+	assert.ErrorIs(t, err, ErrOverflow)
+	_, err = min.CheckedSub(FromCents(1))
+	assert.ErrorIs(t, err, ErrOverflow)
+	_, err = Zero.CheckedSub(min)
+	assert.ErrorIs(t, err, ErrOverflow)
+
+	sum, err = max.CheckedAdd(min)
+	assert.NoError(t, err)
+	assert.Equal(t, FromCents(-1), sum)
+}
+
+func TestMoney_MulFrac(t *testing.T) {
+	tests := []struct {
+		name     string
+		m        Money
+		num, den int64
+		want     Money
+	}{
+		{"exact", New(100, 0), 1, 4, New(25, 0)},
+		{"round down", FromCents(1), 1, 3, 0},
+		{"half to even down", FromCents(5), 1, 2, FromCents(2)},
+		{"half to even up", FromCents(15), 1, 10, FromCents(2)},
+		{"negative", FromCents(-15), 1, 10, FromCents(-2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.MulFrac(tt.num, tt.den)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := FromCents(1).MulFrac(1, 0)
+	assert.Error(t, err)
+}
+
+func TestMoney_JSON(t *testing.T) {
+	var v struct {
+		Amount Money `json:"amount"`
+	}
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount": 100.10}`), &v))
+	assert.Equal(t, FromCents(10010), v.Amount)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount": "0.30"}`), &v))
+	assert.Equal(t, FromCents(30), v.Amount)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"amount": "ten"}`), &v))
+
+	// Amounts from clients are not rounded.
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount": 10.500}`), &v))
+	assert.Equal(t, FromCents(1050), v.Amount)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"amount": 10.005}`), &v), ErrSubCent)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"amount": "0.001"}`), &v), ErrSubCent)
+
+	out, err := json.Marshal(map[string]Money{"balance": FromCents(12345)})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"balance":123.45}`, string(out))
+	assert.Equal(t, `{"balance":123.45}`, string(out))
+}
+
+func TestMoney_Scan(t *testing.T) {
+	tests := []struct {
+		name string
+		src  interface{}
+		want Money
+	}{
+		{"bytes", []byte("99.99"), FromCents(9999)},
+		{"string", "0.10", FromCents(10)},
+		{"float", 0.1, FromCents(10)},
+		{"int", int64(7), FromCents(700)},
+		{"null", nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Money
+			assert.NoError(t, m.Scan(tt.src))
+			assert.Equal(t, tt.want, m)
+		})
+	}
+
+	var m Money
+	assert.ErrorIs(t, m.Scan(int64(math.MaxInt64/10)), ErrOverflow)
+	assert.ErrorIs(t, m.Scan(int64(math.MinInt64/10)), ErrOverflow)
+
+	v, err := FromCents(-150).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "-1.50", v)
+}
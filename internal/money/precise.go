@@ -0,0 +1,131 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// PreciseScale is the number of decimal places kept by Precise.
+const PreciseScale = 8
+
+const preciseUnitsPerCent = 1000000
+
+// Precise is an amount in hundred-millionths of a unit. It holds amounts
+// that are added up before they are paid, such as daily interest, so that
+// only their total is rounded to the cent.
+type Precise int64
+
+// ParsePrecise converts a plain decimal string such as "0.16438356" into
+// Precise, rounding to the nearest hundred-millionth half-to-even.
+func ParsePrecise(s string) (Precise, error) {
+	s = strings.TrimSpace(s)
+	if !decimal.MatchString(s) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	v, err := roundRat(r.Mul(r, big.NewRat(centsPerUnit*preciseUnitsPerCent, 1)))
+	return Precise(v), err
+}
+
+// MustParsePrecise is like ParsePrecise but panics on error. It is
+// intended for constants and tests.
+func MustParsePrecise(s string) Precise {
+	p, err := ParsePrecise(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// MulFracPrecise returns m * num / den rounded to the nearest
+// hundred-millionth half-to-even.
+func (m Money) MulFracPrecise(num, den int64) (Precise, error) {
+	if den == 0 {
+		return 0, errors.New("money: division by zero")
+	}
+	r := new(big.Rat).SetFrac(big.NewInt(int64(m)), big.NewInt(1))
+	r.Mul(r, new(big.Rat).SetFrac(big.NewInt(num), big.NewInt(den)))
+	v, err := roundRat(r.Mul(r, big.NewRat(preciseUnitsPerCent, 1)))
+	return Precise(v), err
+}
+
+// CheckedAdd returns p + o, or ErrOverflow when the sum does not fit.
+func (p Precise) CheckedAdd(o Precise) (Precise, error) {
+	sum := p + o
+	if (sum > p) != (o > 0) {
+		return 0, ErrOverflow
+	}
+	return sum, nil
+}
+
+// Round rounds the amount to the nearest cent half-to-even.
+func (p Precise) Round() Money {
+	// Money has fewer decimal places, so the result always fits.
+	v, _ := roundRat(big.NewRat(int64(p), preciseUnitsPerCent))
+	return Money(v)
+}
+
+func (p Precise) IsPositive() bool { return p > 0 }
+
+// String formats the amount with exactly eight decimal places, e.g.
+// "0.16438356".
+func (p Precise) String() string {
+	sign := ""
+	v := uint64(p)
+	if p < 0 {
+		sign = "-"
+		v = uint64(-p)
+	}
+	const perUnit = centsPerUnit * preciseUnitsPerCent
+	return fmt.Sprintf("%s%d.%08d", sign, v/perUnit, v%perUnit)
+}
+
+// MarshalJSON encodes the amount as a JSON number with eight decimal
+// places.
+func (p Precise) MarshalJSON() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// Scan implements sql.Scanner for DECIMAL/NUMERIC columns.
+func (p *Precise) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*p = 0
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case int64:
+		const perUnit = centsPerUnit * preciseUnitsPerCent
+		if v > math.MaxInt64/perUnit || v < math.MinInt64/perUnit {
+			return ErrOverflow
+		}
+		*p = Precise(v * perUnit)
+		return nil
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("money: cannot scan %T", src)
+	}
+	v, err := ParsePrecise(s)
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
+
+// Value implements driver.Valuer, binding the amount as a decimal string.
+func (p Precise) Value() (driver.Value, error) {
+	return p.String(), nil
+}
@@ -0,0 +1,79 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoney_MulFracPrecise(t *testing.T) {
+	// 6% a year on 1000.00 for one day: 0.164383561643...
+	got, err := New(1000, 0).MulFracPrecise(600, 10000*365)
+	assert.NoError(t, err)
+	assert.Equal(t, Precise(16438356), got)
+	assert.Equal(t, "0.16438356", got.String())
+
+	_, err = New(1, 0).MulFracPrecise(1, 0)
+	assert.Error(t, err)
+	_, err = Money(math.MaxInt64).MulFracPrecise(1, 1)
+	assert.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestPrecise_Round(t *testing.T) {
+	// Thirty days of 0.164383... each are 4.93, where thirty rounded days
+	// of 0.16 would be 4.80.
+	var total Precise
+	for i := 0; i < 30; i++ {
+		daily, err := New(1000, 0).MulFracPrecise(600, 10000*365)
+		assert.NoError(t, err)
+		total, err = total.CheckedAdd(daily)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, MustParse("4.93"), total.Round())
+
+	// Half a cent goes to the even neighbour.
+	assert.Equal(t, FromCents(2), Precise(2500000).Round())
+	assert.Equal(t, FromCents(4), Precise(3500000).Round())
+	assert.Equal(t, FromCents(-2), Precise(-2500000).Round())
+}
+
+func TestPrecise_CheckedAdd_Overflow(t *testing.T) {
+	_, err := Precise(math.MaxInt64).CheckedAdd(1)
+	assert.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestParsePrecise(t *testing.T) {
+	for in, want := range map[string]Precise{
+		"0":             0,
+		"0.16438356":    16438356,
+		"-1.5":          -150000000,
+		"0.000000015":   2,
+		"0.000000025":   2,
+		"12.3456789012": 1234567890,
+	} {
+		got, err := ParsePrecise(in)
+		assert.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"", "abc", "1/3", "1e3"} {
+		_, err := ParsePrecise(in)
+		assert.ErrorIs(t, err, ErrInvalidAmount, in)
+	}
+}
+
+func TestPrecise_Scan(t *testing.T) {
+	var p Precise
+	assert.NoError(t, p.Scan([]byte("0.16438356")))
+	assert.Equal(t, Precise(16438356), p)
+	assert.NoError(t, p.Scan(int64(2)))
+	assert.Equal(t, Precise(200000000), p)
+	assert.NoError(t, p.Scan(nil))
+	assert.Equal(t, Precise(0), p)
+	assert.ErrorIs(t, p.Scan(int64(math.MaxInt64/100)), ErrOverflow)
+
+	data, err := json.Marshal(Precise(16438356))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `0.16438356`, string(data))
+}
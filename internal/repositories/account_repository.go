@@ -1,12 +1,86 @@
 package repositories
 
-import "github.com/gregoryAlvim/gobank/internal/models"
+import (
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/accountnumber"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
 
 type AccountRepository interface {
-	CreateNaturalPerson(person *models.NaturalPerson) error
-	CreateLegalPerson(person *models.LegalPerson) error
-	GetAccountBalance(accountID int, accountType string) (float64, error)
-	UpdateAccountBalance(accountID int, newBalance float64, accountType string) error
-	DeleteAccount(accountID int, accountType string) error
-	TransferTx(fromID, toID int, amount float64, fromType, toType string) error
+	// CreateNaturalPerson and CreateLegalPerson create a customer and, when
+	// account is not nil, open its first account in the same transaction.
+	// The audit log records the new customer as the actor of the request.
+	CreateNaturalPerson(person *models.NaturalPerson, account *models.Account, requestID string) error
+	CreateLegalPerson(person *models.LegalPerson, account *models.Account, requestID string) error
+	CreateAccount(account *models.Account, actor models.Actor) error
+	GetAccount(accountID int) (*models.Account, error)
+	GetAccountByNumber(number accountnumber.Number) (*models.Account, error)
+	GetCustomerAccounts(customerID int) ([]models.Account, error)
+	GetAccountBalance(accountID int) (money.Money, error)
+	GetBalances(accountID int) (*models.Balance, error)
+	// GetBalanceAsOf reconstructs the ledger balance at asOf from the
+	// account's history, starting from its last daily snapshot.
+	GetBalanceAsOf(accountID int, asOf time.Time) (money.Money, error)
+	// SnapshotBalances records every account's balance at the end of date
+	// (UTC), once per day.
+	SnapshotBalances(date time.Time) (int, error)
+	LastBalanceSnapshotDate() (time.Time, error)
+	GetTransactions(accountID int, filter models.TransactionFilter) ([]models.Transaction, error)
+	UpdateAccountBalance(accountID int, newBalance money.Money, actor models.Actor) error
+	// ChangeAccountStatus fills in the change's ID, FromStatus and
+	// CreatedAt.
+	ChangeAccountStatus(change *models.AccountStatusChange, actor models.Actor) error
+	// CloseAccount pays the remaining balance out to payoutID, which may be
+	// zero when there is nothing left.
+	CloseAccount(change *models.AccountStatusChange, payoutID int, actor models.Actor) error
+	ListStatusChanges(accountID int) ([]models.AccountStatusChange, error)
+	MarkDormantAccounts(since time.Time, reason string) (int, error)
+	// DepositTx, WithdrawTx, TransferTx, CreateAccount, CaptureHold,
+	// UpdateAccountBalance, ChangeAccountStatus and CloseAccount append a
+	// record of the change, made by actor, to the audit log in the same
+	// transaction. MarkDormantAccounts, ChargeOverdraftInterest and
+	// ChargeMaintenanceFee append theirs as made by the bank's own jobs.
+	DepositTx(accountID int, amount money.Money, actor models.Actor) error
+	WithdrawTx(accountID int, amount money.Money, actor models.Actor) error
+	// TransferTx converts amount at the rate of quoteID between accounts in
+	// different currencies, or at the current rate when quoteID is zero.
+	TransferTx(fromID, toID int, amount money.Money, quoteID int64, actor models.Actor) error
+	// TransferOnceTx makes the transfer unless one was already made with
+	// key, in which case it returns nil without moving anything.
+	TransferOnceTx(key string, fromID, toID int, amount money.Money, actor models.Actor) error
+	// CreateTransferApproval holds the transfer's amount on the source
+	// account until the approval is reviewed or expires.
+	CreateTransferApproval(approval *models.TransferApproval) error
+	GetTransferApproval(approvalID int64) (*models.TransferApproval, error)
+	ListTransferApprovals(filter models.ApprovalFilter) ([]models.TransferApproval, error)
+	// ApproveTransfer makes the transfer on behalf of reviewer, an
+	// operator.
+	ApproveTransfer(approvalID int64, reviewer models.Actor, reason string) (*models.TransferApproval, error)
+	RejectTransfer(approvalID int64, reviewer models.Actor, reason string) (*models.TransferApproval, error)
+	// CreateHold reserves the hold's amount on the account until it is
+	// captured, voided or expires.
+	CreateHold(hold *models.Hold) error
+	ListHolds(accountID int, filter models.HoldFilter) ([]models.Hold, error)
+	// CaptureHold captures the whole hold when amount is zero.
+	CaptureHold(accountID int, holdID int64, amount money.Money, actor models.Actor) (*models.Hold, error)
+	VoidHold(accountID int, holdID int64) (*models.Hold, error)
+	GetOverdraft(accountID int) (*models.Overdraft, error)
+	// SetOverdraftLimit goes back to the category's limit when limit is nil.
+	SetOverdraftLimit(accountID int, limit *money.Money) error
+	ListOverdraftPolicies() ([]models.OverdraftPolicy, error)
+	SetOverdraftPolicy(policy *models.OverdraftPolicy) error
+	ListOverdrawnAccounts() ([]int, error)
+	// ChargeOverdraftInterest charges each account at most once per date.
+	ChargeOverdraftInterest(accountID int, date time.Time) (money.Money, error)
+	ListFees() ([]models.Fee, error)
+	SetFee(fee *models.Fee) error
+	ListFeeWaivers(accountID int) ([]models.FeeWaiver, error)
+	WaiveFee(waiver *models.FeeWaiver) error
+	RemoveFeeWaiver(accountID int, feeType string) error
+	ListMaintenanceFeeAccounts(period time.Time) ([]int, error)
+	// ChargeMaintenanceFee charges each account at most once per period,
+	// the first day of a month.
+	ChargeMaintenanceFee(accountID int, period time.Time) (money.Money, error)
 }
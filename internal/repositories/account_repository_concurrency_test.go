@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
+// openTestDB connects to the migrated database in TEST_DATABASE_URL. Tests
+// that need real row locking are skipped when it is not set.
+func openTestDB(t *testing.T) *sql.DB {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", url)
+	require.NoError(t, err)
+	require.NoError(t, db.Ping())
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// waiveFees exempts the account from the given fee types, so fees do not
+// get in the way of balance checks.
+func waiveFees(t *testing.T, repo *PsqlAccountRepository, accountID int, feeTypes ...string) {
+	for _, feeType := range feeTypes {
+		require.NoError(t, repo.WaiveFee(&models.FeeWaiver{AccountID: accountID, FeeType: feeType, Reason: "test"}))
+	}
+}
+
+func TestPsqlAccountRepository_ConcurrentWithdrawals(t *testing.T) {
+	db := openTestDB(t)
+	repo := &PsqlAccountRepository{DB: db}
+	ledgerRepo := &PsqlLedgerRepository{DB: db}
+
+	// Savings accounts have no overdraft.
+	account := &models.Account{Category: "savings", Balance: money.New(1000, 0)}
+	require.NoError(t, repo.CreateNaturalPerson(&models.NaturalPerson{FullName: "Concurrent Withdrawals"}, account, ""))
+	waiveFees(t, repo, account.ID, models.FeeTypeWithdrawal)
+
+	// 100 withdrawals of 15.00 against 1000.00: exactly 66 fit.
+	const workers = 100
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded, insufficient := 0, 0
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := repo.WithdrawTx(account.ID, money.New(15, 0), models.Actor{})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, ErrInsufficientFunds):
+				insufficient++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 66, succeeded)
+	assert.Equal(t, workers-66, insufficient)
+
+	balance, err := repo.GetAccountBalance(account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(10, 0), balance)
+
+	reconciliation, err := ledgerRepo.GetReconciliation(account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, balance, reconciliation.LedgerBalance)
+}
+
+func TestPsqlAccountRepository_ConcurrentDepositsAndWithdrawals(t *testing.T) {
+	db := openTestDB(t)
+	repo := &PsqlAccountRepository{DB: db}
+
+	account := &models.Account{Category: "standard", Balance: money.New(500, 0)}
+	require.NoError(t, repo.CreateNaturalPerson(&models.NaturalPerson{FullName: "Concurrent Mixed"}, account, ""))
+	waiveFees(t, repo, account.ID, models.FeeTypeWithdrawal)
+
+	// 50 deposits and 50 withdrawals of 10.00 each. The starting balance
+	// covers every withdrawal, so all must succeed and cancel out.
+	const workers = 50
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, repo.DepositTx(account.ID, money.New(10, 0), models.Actor{}))
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, repo.WithdrawTx(account.ID, money.New(10, 0), models.Actor{}))
+		}()
+	}
+	wg.Wait()
+
+	balance, err := repo.GetAccountBalance(account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(500, 0), balance)
+}
+
+func TestPsqlAccountRepository_ConcurrentOppositeTransfers(t *testing.T) {
+	db := openTestDB(t)
+	repo := &PsqlAccountRepository{DB: db}
+
+	a := &models.Account{Category: "standard", Balance: money.New(1000, 0)}
+	b := &models.Account{Category: "business", Balance: money.New(1000, 0)}
+	require.NoError(t, repo.CreateNaturalPerson(&models.NaturalPerson{FullName: "Opposite Transfers A"}, a, ""))
+	require.NoError(t, repo.CreateLegalPerson(&models.LegalPerson{TradeName: "Opposite Transfers B"}, b, ""))
+	waiveFees(t, repo, b.ID, models.FeeTypeTransfer)
+
+	// A→B and B→A at the same time would deadlock without a canonical
+	// lock order. Every transfer must succeed and the total is preserved.
+	const workers = 50
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, repo.TransferTx(a.ID, b.ID, money.New(5, 0), 0, models.Actor{}))
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, repo.TransferTx(b.ID, a.ID, money.New(5, 0), 0, models.Actor{}))
+		}()
+	}
+	wg.Wait()
+
+	balanceA, err := repo.GetAccountBalance(a.ID)
+	require.NoError(t, err)
+	balanceB, err := repo.GetAccountBalance(b.ID)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(1000, 0), balanceA)
+	assert.Equal(t, money.New(1000, 0), balanceB)
+}
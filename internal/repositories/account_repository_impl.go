@@ -5,149 +5,1904 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
+	"time"
 
+	"github.com/lib/pq"
+
+	"github.com/gregoryAlvim/gobank/internal/accountnumber"
+	"github.com/gregoryAlvim/gobank/internal/currency"
 	"github.com/gregoryAlvim/gobank/internal/database"
+	"github.com/gregoryAlvim/gobank/internal/ledger"
 	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
+var (
+	ErrAccountNotFound    = errors.New("account not found")
+	ErrCustomerNotFound   = errors.New("customer not found")
+	ErrInsufficientFunds  = errors.New("insufficient funds")
+	ErrAccountFrozen      = errors.New("account is frozen")
+	ErrAccountDormant     = errors.New("account is dormant")
+	ErrAccountPending     = errors.New("account is not active yet")
+	ErrAccountClosed      = errors.New("account is closed")
+	ErrStatusTransition   = errors.New("account status cannot change")
+	ErrBalanceRemaining   = errors.New("account still has a balance to pay out")
+	ErrNegativeBalance    = errors.New("account has a negative balance")
+	ErrFundsOnHold        = errors.New("account has funds on hold")
+	ErrDuplicateCPF       = errors.New("a customer with this CPF already exists")
+	ErrDuplicateCNPJ      = errors.New("a customer with this CNPJ already exists")
+	ErrApprovalNotFound   = errors.New("transfer approval not found")
+	ErrApprovalReviewed   = errors.New("transfer approval has already been reviewed")
+	ErrApprovalExpired    = errors.New("transfer approval has expired")
+	ErrHoldNotFound       = errors.New("hold not found")
+	ErrHoldReleased       = errors.New("hold has already been captured or voided")
+	ErrHoldExpired        = errors.New("hold has expired")
+	ErrCaptureExceedsHold = errors.New("capture amount exceeds the hold")
+	ErrFeeWaiverNotFound  = errors.New("fee waiver not found")
+	ErrCurrencyMismatch   = errors.New("amount is not in the account's currency")
+	ErrFXRateNotFound     = errors.New("no exchange rate for the currency pair")
+	ErrQuoteNotFound      = errors.New("exchange quote not found")
+	ErrQuoteExpired       = errors.New("exchange quote has expired")
+	ErrQuoteUsed          = errors.New("exchange quote has already been used")
+	ErrQuoteMismatch      = errors.New("exchange quote does not match the transfer")
+	ErrTransferMade       = errors.New("transfer has already been made")
 )
 
 type PsqlAccountRepository struct {
 	DB *sql.DB
+	// Branch is the branch new accounts are opened in. Empty means
+	// accountnumber.DefaultBranch.
+	Branch string
+	// TxRetry controls retries of TransferTx after deadlocks and
+	// serialization failures. The zero value uses DefaultRetryPolicy.
+	TxRetry RetryPolicy
 }
 
 func NewPsqlAccountRepository() *PsqlAccountRepository {
 	return &PsqlAccountRepository{DB: database.DB}
 }
 
-func (r *PsqlAccountRepository) CreateNaturalPerson(person *models.NaturalPerson) error {
-	query := `INSERT INTO natural_person (monthly_income, age, full_name, phone_number, email, category, balance)
-			  VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
-	err := r.DB.QueryRow(query, person.MonthlyIncome, person.Age, person.FullName, person.PhoneNumber, person.Email, person.Category, person.Balance).Scan(&person.ID)
-	return err
+func (r *PsqlAccountRepository) CreateNaturalPerson(person *models.NaturalPerson, account *models.Account, requestID string) error {
+	tx, err := r.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO customers (type, cpf, monthly_income, age, full_name, phone_number, email, password_hash)
+			  VALUES ('natural', NULLIF($1, ''), $2, $3, $4, $5, $6, NULLIF($7, '')) RETURNING id`
+	err = tx.QueryRow(query, person.CPF, person.MonthlyIncome, person.Age, person.FullName, person.PhoneNumber, person.Email,
+		person.PasswordHash).Scan(&person.ID)
+	if err != nil {
+		if isUniqueViolation(err, "customers_cpf_key") {
+			return ErrDuplicateCPF
+		}
+		return err
+	}
+
+	if account != nil {
+		account.CustomerID = person.ID
+		// Customers sign themselves up.
+		actor := models.Actor{CustomerID: person.ID, RequestID: requestID}
+		if err := r.insertAccountTx(tx, account, actor); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
-func (r *PsqlAccountRepository) CreateLegalPerson(person *models.LegalPerson) error {
-	query := `INSERT INTO legal_person (annual_revenue, age, trade_name, phone_number, corporate_email, category, balance)
-			  VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
-	err := r.DB.QueryRow(query, person.AnnualRevenue, person.Age, person.TradeName, person.PhoneNumber, person.CorporateEmail, person.Category, person.Balance).Scan(&person.ID)
-	return err
+func (r *PsqlAccountRepository) CreateLegalPerson(person *models.LegalPerson, account *models.Account, requestID string) error {
+	tx, err := r.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO customers (type, cnpj, annual_revenue, age, trade_name, phone_number, corporate_email, password_hash)
+			  VALUES ('legal', NULLIF($1, ''), $2, $3, $4, $5, $6, NULLIF($7, '')) RETURNING id`
+	err = tx.QueryRow(query, person.CNPJ, person.AnnualRevenue, person.Age, person.TradeName, person.PhoneNumber, person.CorporateEmail,
+		person.PasswordHash).Scan(&person.ID)
+	if err != nil {
+		if isUniqueViolation(err, "customers_cnpj_key") {
+			return ErrDuplicateCNPJ
+		}
+		return err
+	}
+
+	if account != nil {
+		account.CustomerID = person.ID
+		// Customers sign themselves up.
+		actor := models.Actor{CustomerID: person.ID, RequestID: requestID}
+		if err := r.insertAccountTx(tx, account, actor); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
-func (r *PsqlAccountRepository) GetAccountBalance(accountID int, accountType string) (float64, error) {
-	var balance float64
-	var query string
+// isUniqueViolation reports whether err violates the named unique constraint.
+func isUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == constraint
+}
 
-	switch accountType {
-	case "natural":
-		query = "SELECT balance FROM natural_person WHERE id = $1"
-	case "legal":
-		query = "SELECT balance FROM legal_person WHERE id = $1"
-	default:
-		return 0, errors.New("invalid account type")
+// CreateAccount opens another account for an existing customer.
+func (r *PsqlAccountRepository) CreateAccount(account *models.Account, actor models.Actor) error {
+	tx, err := r.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.insertAccountTx(tx, account, actor); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return ErrCustomerNotFound
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertAccountTx assigns the account a number, inserts it, journals its
+// opening balance, writes an account.created event and appends the
+// account to the audit log.
+func (r *PsqlAccountRepository) insertAccountTx(tx *sql.Tx, account *models.Account, actor models.Actor) error {
+	branch := r.Branch
+	if branch == "" {
+		branch = accountnumber.DefaultBranch
+	}
+
+	var seq int64
+	if err := tx.QueryRow("SELECT nextval('account_number_seq')").Scan(&seq); err != nil {
+		return err
+	}
+	number, err := accountnumber.New(branch, seq)
+	if err != nil {
+		return err
+	}
+	account.Branch, account.Number, account.CheckDigit = number.Branch, number.Account, number.CheckDigit
+
+	if account.Currency == "" {
+		account.Currency = currency.Default
+	}
+	query := `INSERT INTO accounts (customer_id, branch, number, check_digit, category, balance, currency)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, status, created_at`
+	err = tx.QueryRow(query, account.CustomerID, account.Branch, account.Number, account.CheckDigit, account.Category, account.Balance,
+		account.Currency).Scan(&account.ID, &account.Status, &account.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if err := insertOpeningBalanceTx(tx, account.ID, account.Balance); err != nil {
+		return err
+	}
+	err = insertEventTx(tx, models.EventAccountCreated, account.ID, 0, models.AccountCreatedEvent{
+		AccountID:     account.ID,
+		CustomerID:    account.CustomerID,
+		AccountNumber: account.AccountNumber(),
+		Category:      account.Category,
+		Currency:      account.Currency,
+		Status:        account.Status,
+		Balance:       account.Balance,
+	})
+	if err != nil {
+		return err
+	}
+	after := []models.AccountState{{AccountID: account.ID, Balance: account.Balance, Status: account.Status}}
+	return appendAuditTx(tx, models.AuditCreateAccount, actor, account.ID, 0, nil, after)
+}
+
+// insertOpeningBalanceTx journals the balance an account was created with.
+func insertOpeningBalanceTx(tx *sql.Tx, accountID int, balance money.Money) error {
+	if balance.IsZero() {
+		return nil
 	}
+	return recordEntryTx(tx, ledger.NewOpeningBalance(accountID, balance), map[int]money.Money{accountID: balance})
+}
+
+// recordEntryTx journals the entry and adds a history line for every
+// customer account it touches. balances holds each account's balance after
+// the entry is applied.
+func recordEntryTx(tx *sql.Tx, entry *models.JournalEntry, balances map[int]money.Money) error {
+	if err := insertJournalEntryTx(tx, entry); err != nil {
+		return err
+	}
+	return insertTransactionsTx(tx, entry, balances)
+}
+
+func insertTransactionsTx(tx *sql.Tx, entry *models.JournalEntry, balances map[int]money.Money) error {
+	for _, p := range entry.Postings {
+		if ledger.IsSystemAccount(p.AccountID) {
+			continue
+		}
+
+		var counterpartyID sql.NullInt64
+		if other, ok := counterparty(entry, p); ok {
+			counterpartyID = sql.NullInt64{Int64: int64(other), Valid: true}
+		}
+
+		query := `INSERT INTO account_transactions (journal_entry_id, account_id, kind, direction, amount, balance_after, counterparty_id, created_at)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+		_, err := tx.Exec(query, entry.ID, p.AccountID, entry.Kind, p.Direction, p.Amount, balances[p.AccountID], counterpartyID, entry.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// counterparty returns the customer account on the other side of the
+// entry from p. It reports false when the other side is only system
+// accounts or more than one customer account.
+func counterparty(entry *models.JournalEntry, p models.Posting) (int, bool) {
+	var found []int
+	for _, other := range entry.Postings {
+		if other.Direction != p.Direction && !ledger.IsSystemAccount(other.AccountID) {
+			found = append(found, other.AccountID)
+		}
+	}
+	if len(found) != 1 {
+		return 0, false
+	}
+	return found[0], true
+}
+
+const accountColumns = "id, customer_id, branch, number, check_digit, category, balance, currency, status, created_at, closed_at"
+
+func scanAccount(row interface{ Scan(...interface{}) error }, account *models.Account) error {
+	return row.Scan(&account.ID, &account.CustomerID, &account.Branch, &account.Number, &account.CheckDigit,
+		&account.Category, &account.Balance, &account.Currency, &account.Status, &account.CreatedAt, &account.ClosedAt)
+}
+
+func (r *PsqlAccountRepository) GetAccount(accountID int) (*models.Account, error) {
+	var account models.Account
+	query := "SELECT " + accountColumns + " FROM accounts WHERE id = $1 AND customer_id IS NOT NULL"
+	if err := scanAccount(r.DB.QueryRow(query, accountID), &account); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+	return &account, nil
+}
+
+// GetAccountByNumber looks an account up by the number shown to customers.
+func (r *PsqlAccountRepository) GetAccountByNumber(number accountnumber.Number) (*models.Account, error) {
+	var account models.Account
+	query := "SELECT " + accountColumns + " FROM accounts WHERE branch = $1 AND number = $2 AND check_digit = $3"
+	if err := scanAccount(r.DB.QueryRow(query, number.Branch, number.Account, number.CheckDigit), &account); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *PsqlAccountRepository) GetCustomerAccounts(customerID int) ([]models.Account, error) {
+	var exists bool
+	if err := r.DB.QueryRow("SELECT EXISTS (SELECT 1 FROM customers WHERE id = $1)", customerID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrCustomerNotFound
+	}
+
+	query := "SELECT " + accountColumns + " FROM accounts WHERE customer_id = $1 ORDER BY id"
+	rows, err := r.DB.Query(query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := []models.Account{}
+	for rows.Next() {
+		var account models.Account
+		if err := scanAccount(rows, &account); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, rows.Err()
+}
+
+func (r *PsqlAccountRepository) GetAccountBalance(accountID int) (money.Money, error) {
+	var balance money.Money
+	query := "SELECT balance FROM accounts WHERE id = $1 AND customer_id IS NOT NULL"
 
 	err := r.DB.QueryRow(query, accountID).Scan(&balance)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return 0, fmt.Errorf("account not found")
+			return 0, ErrAccountNotFound
 		}
 		return 0, err
 	}
 	return balance, nil
 }
 
-func (r *PsqlAccountRepository) UpdateAccountBalance(accountID int, newBalance float64, accountType string) error {
-	var query string
-	switch accountType {
-	case "natural":
-		query = "UPDATE natural_person SET balance = $1 WHERE id = $2"
-	case "legal":
-		query = "UPDATE legal_person SET balance = $1 WHERE id = $2"
-	default:
-		return errors.New("invalid account type")
+// GetBalances returns the account's ledger balance and what is available
+// once held funds are taken out.
+func (r *PsqlAccountRepository) GetBalances(accountID int) (*models.Balance, error) {
+	var balance models.Balance
+	query := "SELECT balance, balance - " + heldFunds + " FROM accounts WHERE id = $1 AND customer_id IS NOT NULL"
+	if err := r.DB.QueryRow(query, accountID).Scan(&balance.Ledger, &balance.Available); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
 	}
+	return &balance, nil
+}
 
-	_, err := r.DB.Exec(query, newBalance, accountID)
-	return err
+// signedAmount is a row of account_transactions as a change to the
+// account's balance.
+const signedAmount = "CASE WHEN direction = 'credit' THEN amount ELSE -amount END"
+
+// lastSnapshot joins the last balance snapshot of accounts a taken before
+// the date in $%d, if any.
+const lastSnapshot = `LEFT JOIN LATERAL (SELECT snapshot_date, balance FROM balance_snapshots
+					  WHERE account_id = a.id AND snapshot_date < $%d ORDER BY snapshot_date DESC LIMIT 1) s ON true`
+
+// sinceSnapshot is the instant the day after snapshot s starts. Snapshot
+// dates are UTC days, so the date is converted explicitly rather than in
+// the session's TimeZone.
+const sinceSnapshot = "COALESCE((s.snapshot_date + 1)::timestamp AT TIME ZONE 'UTC', '-infinity')"
+
+// GetBalanceAsOf reconstructs the account's balance at asOf from the last
+// snapshot taken before asOf's day (UTC) and the movements since. Before
+// the account was opened the balance is zero.
+func (r *PsqlAccountRepository) GetBalanceAsOf(accountID int, asOf time.Time) (money.Money, error) {
+	query := `SELECT COALESCE(s.balance, 0) + COALESCE((SELECT SUM(` + signedAmount + `) FROM account_transactions
+			  WHERE account_id = a.id AND created_at <= $2 AND created_at >= ` + sinceSnapshot + `), 0)
+			  FROM accounts a ` + fmt.Sprintf(lastSnapshot, 3) + `
+			  WHERE a.id = $1 AND a.customer_id IS NOT NULL`
+	var balance money.Money
+	err := r.DB.QueryRow(query, accountID, asOf, asOf.UTC().Truncate(24*time.Hour)).Scan(&balance)
+	if err == sql.ErrNoRows {
+		return 0, ErrAccountNotFound
+	}
+	return balance, err
 }
 
-func (r *PsqlAccountRepository) DeleteAccount(accountID int, accountType string) error {
-	var query string
-	switch accountType {
-	case "natural":
-		query = "DELETE FROM natural_person WHERE id = $1"
-	case "legal":
-		query = "DELETE FROM legal_person WHERE id = $1"
-	default:
-		return errors.New("invalid account type")
+// SnapshotBalances records the balance at the end of date (UTC) of every
+// customer account open at some point that day, and returns how many it
+// recorded. Days already recorded are left alone.
+func (r *PsqlAccountRepository) SnapshotBalances(date time.Time) (int, error) {
+	query := `INSERT INTO balance_snapshots (account_id, snapshot_date, balance)
+			  SELECT a.id, $1, COALESCE(s.balance, 0) + COALESCE((SELECT SUM(` + signedAmount + `) FROM account_transactions
+			  WHERE account_id = a.id AND created_at < $2 AND created_at >= ` + sinceSnapshot + `), 0)
+			  FROM accounts a ` + fmt.Sprintf(lastSnapshot, 1) + `
+			  WHERE a.customer_id IS NOT NULL AND a.created_at < $2 AND (a.closed_at IS NULL OR a.closed_at >= $1::date::timestamp AT TIME ZONE 'UTC')
+			  ON CONFLICT (account_id, snapshot_date) DO NOTHING`
+	res, err := r.DB.Exec(query, date, date.AddDate(0, 0, 1))
+	if err != nil {
+		return 0, err
 	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
 
-	_, err := r.DB.Exec(query, accountID)
-	return err
+// LastBalanceSnapshotDate returns the latest day with snapshots, or the
+// zero time when none were taken.
+func (r *PsqlAccountRepository) LastBalanceSnapshotDate() (time.Time, error) {
+	var date sql.NullTime
+	if err := r.DB.QueryRow("SELECT MAX(snapshot_date) FROM balance_snapshots").Scan(&date); err != nil {
+		return time.Time{}, err
+	}
+	return date.Time, nil
+}
+
+// GetTransactions returns the history of an account, newest first, applying
+// the filter. Up to filter.Limit rows are returned. Cross-currency
+// transfers come with their conversion.
+func (r *PsqlAccountRepository) GetTransactions(accountID int, filter models.TransactionFilter) ([]models.Transaction, error) {
+	query := `SELECT id, account_transactions.journal_entry_id, kind, direction, amount, balance_after, counterparty_id, created_at,
+			  quote_id, from_currency, to_currency, source_amount, converted_amount, mid_rate, spread_bps, rate
+			  FROM account_transactions LEFT JOIN fx_conversions ON fx_conversions.journal_entry_id = account_transactions.journal_entry_id
+			  WHERE account_id = $1`
+	args := []interface{}{accountID}
+
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		query += fmt.Sprintf(" AND "+condition, len(args))
+	}
+	if filter.BeforeID > 0 {
+		addCondition("id < $%d", filter.BeforeID)
+	}
+	if filter.From != nil {
+		addCondition("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("created_at < $%d", *filter.To)
+	}
+	if filter.Direction != "" {
+		addCondition("direction = $%d", filter.Direction)
+	}
+	if filter.MinAmount != nil {
+		addCondition("amount >= $%d", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		addCondition("amount <= $%d", *filter.MaxAmount)
+	}
+
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transactions := []models.Transaction{}
+	for rows.Next() {
+		var t models.Transaction
+		var counterpartyID sql.NullInt64
+		var conversion nullFXConversion
+		if err := rows.Scan(&t.ID, &t.JournalEntryID, &t.Kind, &t.Direction, &t.Amount, &t.BalanceAfter, &counterpartyID, &t.CreatedAt,
+			&conversion.QuoteID, &conversion.FromCurrency, &conversion.ToCurrency, &conversion.SourceAmount,
+			&conversion.ConvertedAmount, &conversion.MidRate, &conversion.SpreadBps, &conversion.Rate); err != nil {
+			return nil, err
+		}
+		if counterpartyID.Valid {
+			id := int(counterpartyID.Int64)
+			t.CounterpartyID = &id
+		}
+		t.FX = conversion.get(t.JournalEntryID)
+		transactions = append(transactions, t)
+	}
+	return transactions, rows.Err()
 }
 
-func (r *PsqlAccountRepository) TransferTx(fromID, toID int, amount float64, fromType, toType string) error {
+// UpdateAccountBalance sets the balance of an account. The difference from
+// the current balance is journaled as an adjustment so the ledger still
+// explains the new balance, and the correction is appended to the audit
+// log.
+func (r *PsqlAccountRepository) UpdateAccountBalance(accountID int, newBalance money.Money, actor models.Actor) error {
 	tx, err := r.DB.BeginTx(context.Background(), nil)
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback() // Rollback on any error.
+	defer tx.Rollback()
+
+	locked, err := r.lockAccountsTx(tx, accountID)
+	if err != nil {
+		return err
+	}
 
-	// 1. Get and check fromAccount's balance
-	fromBalance, err := r.getAccountBalanceTx(tx, fromID, fromType)
+	delta, err := newBalance.CheckedSub(locked[accountID].balance)
 	if err != nil {
 		return err
 	}
-	if fromBalance < amount {
-		return errors.New("insufficient funds")
+	if delta.IsZero() {
+		return tx.Commit()
+	}
+
+	if err := r.updateAccountBalanceTx(tx, accountID, newBalance); err != nil {
+		return err
+	}
+	if err := recordEntryTx(tx, ledger.NewAdjustment(accountID, delta), map[int]money.Money{accountID: newBalance}); err != nil {
+		return err
+	}
+	before := []models.AccountState{{AccountID: accountID, Balance: locked[accountID].balance}}
+	after := []models.AccountState{{AccountID: accountID, Balance: newBalance}}
+	if err := appendAuditTx(tx, models.AuditCorrectBalance, actor, accountID, 0, before, after); err != nil {
+		return err
 	}
+	return tx.Commit()
+}
 
-	// 2. Get toAccount's balance
-	toBalance, err := r.getAccountBalanceTx(tx, toID, toType)
+// ChangeAccountStatus moves the account to change.ToStatus and records
+// the change, filling in its ID, previous status and time, and appends it
+// to the audit log. Accounts are closed with CloseAccount instead.
+func (r *PsqlAccountRepository) ChangeAccountStatus(change *models.AccountStatusChange, actor models.Actor) error {
+	tx, err := r.DB.BeginTx(context.Background(), nil)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	// 3. Update balances
-	if err := r.updateAccountBalanceTx(tx, fromID, fromBalance-amount, fromType); err != nil {
+	var balance money.Money
+	query := "SELECT status, balance FROM accounts WHERE id = $1 AND customer_id IS NOT NULL FOR UPDATE"
+	if err := tx.QueryRow(query, change.AccountID).Scan(&change.FromStatus, &balance); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrAccountNotFound
+		}
 		return err
 	}
-	if err := r.updateAccountBalanceTx(tx, toID, toBalance+amount, toType); err != nil {
+	if change.ToStatus == models.AccountClosed {
+		return fmt.Errorf("%w: accounts are closed with CloseAccount", ErrStatusTransition)
+	}
+	if err := checkStatusChange(change.FromStatus, change.ToStatus); err != nil {
 		return err
 	}
 
-	// 4. Commit the transaction
+	if _, err := tx.Exec("UPDATE accounts SET status = $2 WHERE id = $1", change.AccountID, change.ToStatus); err != nil {
+		return err
+	}
+	if err := insertStatusChangeTx(tx, change); err != nil {
+		return err
+	}
+	before := []models.AccountState{{AccountID: change.AccountID, Balance: balance, Status: change.FromStatus}}
+	after := []models.AccountState{{AccountID: change.AccountID, Balance: balance, Status: change.ToStatus}}
+	if err := appendAuditTx(tx, models.AuditChangeStatus, actor, change.AccountID, 0, before, after); err != nil {
+		return err
+	}
 	return tx.Commit()
 }
 
-// Helper functions to be used within a transaction
-func (r *PsqlAccountRepository) getAccountBalanceTx(tx *sql.Tx, accountID int, accountType string) (float64, error) {
-	var balance float64
-	var query string
+// checkStatusChange reports why an account cannot go from one status to
+// the other, if it cannot.
+func checkStatusChange(from, to string) error {
+	if from == models.AccountClosed {
+		return ErrAccountClosed
+	}
+	if !models.CanChangeStatus(from, to) {
+		return fmt.Errorf("%w from %s to %s", ErrStatusTransition, from, to)
+	}
+	return nil
+}
 
-	switch accountType {
-	case "natural":
-		query = "SELECT balance FROM natural_person WHERE id = $1 FOR UPDATE"
-	case "legal":
-		query = "SELECT balance FROM legal_person WHERE id = $1 FOR UPDATE"
-	default:
-		return 0, errors.New("invalid account type")
+func insertStatusChangeTx(tx *sql.Tx, change *models.AccountStatusChange) error {
+	query := `INSERT INTO account_status_changes (account_id, from_status, to_status, reason, changed_by_customer_id,
+			  changed_by_operator_id, payout_account_id, payout_amount)
+			  VALUES ($1, $2, $3, $4, NULLIF($5, 0), NULLIF($6, 0), $7, $8) RETURNING id, created_at`
+	return tx.QueryRow(query, change.AccountID, change.FromStatus, change.ToStatus, change.Reason, change.ChangedByCustomerID,
+		change.ChangedByOperatorID, change.PayoutAccountID, change.PayoutAmount).Scan(&change.ID, &change.CreatedAt)
+}
+
+// CloseAccount closes the account for good and records the change. Unpaid
+// interest is credited first. What is left of the balance is then moved
+// to payoutID, journaled as a transfer; without a payout account the
+// balance must be zero. The payout account must hold the same currency.
+// Accounts with funds on hold or a negative balance
+// cannot be closed. Transfers scheduled from or to the account are
+// cancelled. Like TransferTx, it is retried after deadlocks.
+func (r *PsqlAccountRepository) CloseAccount(change *models.AccountStatusChange, payoutID int, actor models.Actor) error {
+	return retryTx("close account", r.TxRetry, func() error {
+		return r.closeAccountTx(change, payoutID, actor)
+	})
+}
+
+func (r *PsqlAccountRepository) closeAccountTx(change *models.AccountStatusChange, payoutID int, actor models.Actor) error {
+	tx, err := r.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
-	err := tx.QueryRow(query, accountID).Scan(&balance)
-	return balance, err
+	accountID := change.AccountID
+	// The interest accruals are locked before the account, as the interest
+	// job does.
+	interest, _, err := payInterestTx(tx, accountID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	ids := []int{accountID}
+	if payoutID != 0 {
+		ids = append(ids, payoutID)
+	}
+	locked, err := r.lockAccountsTx(tx, ids...)
+	if err != nil {
+		return err
+	}
+	account := locked[accountID]
+	change.FromStatus, change.ToStatus = account.status, models.AccountClosed
+	if err := checkStatusChange(change.FromStatus, change.ToStatus); err != nil {
+		return err
+	}
+	switch {
+	case account.held.IsPositive():
+		return ErrFundsOnHold
+	case account.balance.IsNegative():
+		return ErrNegativeBalance
+	}
+	before := []models.AccountState{{AccountID: accountID, Balance: account.balance, Status: account.status}}
+	after := []models.AccountState{{AccountID: accountID, Balance: 0, Status: models.AccountClosed}}
+
+	if balance := account.balance; balance.IsPositive() {
+		if payoutID == 0 {
+			return ErrBalanceRemaining
+		}
+		if err := locked[payoutID].canCredit(); err != nil {
+			return err
+		}
+		if locked[payoutID].currency != account.currency {
+			return ErrCurrencyMismatch
+		}
+		payoutBalance, err := locked[payoutID].balance.CheckedAdd(balance)
+		if err != nil {
+			return err
+		}
+		if err := r.updateAccountBalanceTx(tx, accountID, 0); err != nil {
+			return err
+		}
+		if err := r.updateAccountBalanceTx(tx, payoutID, payoutBalance); err != nil {
+			return err
+		}
+		balances := map[int]money.Money{accountID: 0, payoutID: payoutBalance}
+		if err := recordEntryTx(tx, ledger.NewTransfer(accountID, payoutID, balance), balances); err != nil {
+			return err
+		}
+		change.PayoutAccountID, change.PayoutAmount = &payoutID, &balance
+		before = append(before, models.AccountState{AccountID: payoutID, Balance: locked[payoutID].balance})
+		after = append(after, models.AccountState{AccountID: payoutID, Balance: payoutBalance})
+	}
+
+	if _, err := tx.Exec("UPDATE accounts SET status = 'closed', closed_at = now() WHERE id = $1", accountID); err != nil {
+		return err
+	}
+	query := `UPDATE scheduled_transfers SET status = 'cancelled', next_run_date = NULL, next_attempt_at = NULL
+			  WHERE (from_account_id = $1 OR to_account_id = $1) AND status = 'active'`
+	if _, err := tx.Exec(query, accountID); err != nil {
+		return err
+	}
+	if err := insertStatusChangeTx(tx, change); err != nil {
+		return err
+	}
+	err = insertEventTx(tx, models.EventAccountClosed, accountID, payoutID, models.AccountClosedEvent{
+		AccountID:       accountID,
+		Reason:          change.Reason,
+		PayoutAccountID: change.PayoutAccountID,
+		PayoutAmount:    change.PayoutAmount,
+	})
+	if err != nil {
+		return err
+	}
+	if interest.IsPositive() {
+		paid := []models.AccountState{{AccountID: accountID, Balance: account.balance}}
+		unpaid := []models.AccountState{{AccountID: accountID, Balance: account.balance.Sub(interest)}}
+		if err := appendAuditTx(tx, models.AuditPayInterest, actor, accountID, 0, unpaid, paid); err != nil {
+			return err
+		}
+	}
+	if err := appendAuditTx(tx, models.AuditCloseAccount, actor, accountID, payoutID, before, after); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func (r *PsqlAccountRepository) updateAccountBalanceTx(tx *sql.Tx, accountID int, newBalance float64, accountType string) error {
-	var query string
-	switch accountType {
-	case "natural":
-		query = "UPDATE natural_person SET balance = $1 WHERE id = $2"
-	case "legal":
-		query = "UPDATE legal_person SET balance = $1 WHERE id = $2"
-	default:
-		return errors.New("invalid account type")
+// ListStatusChanges returns the account's status changes, newest first.
+func (r *PsqlAccountRepository) ListStatusChanges(accountID int) ([]models.AccountStatusChange, error) {
+	query := `SELECT id, account_id, from_status, to_status, reason, COALESCE(changed_by_customer_id, 0),
+			  COALESCE(changed_by_operator_id, 0), payout_account_id, payout_amount, created_at
+			  FROM account_status_changes WHERE account_id = $1 ORDER BY id DESC`
+	rows, err := r.DB.Query(query, accountID)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	_, err := tx.Exec(query, newBalance, accountID)
-	return err
+	changes := []models.AccountStatusChange{}
+	for rows.Next() {
+		var c models.AccountStatusChange
+		if err := rows.Scan(&c.ID, &c.AccountID, &c.FromStatus, &c.ToStatus, &c.Reason, &c.ChangedByCustomerID,
+			&c.ChangedByOperatorID, &c.PayoutAccountID, &c.PayoutAmount, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
+// MarkDormantAccounts marks dormant the active accounts opened before
+// since with no deposit, withdrawal, transfer or capture since then, and
+// returns how many it marked. Interest, fees and other movements made by
+// the bank do not count. The changes are recorded with reason and no
+// actor, and appended to the audit log as made by the dormancy job.
+func (r *PsqlAccountRepository) MarkDormantAccounts(since time.Time, reason string) (int, error) {
+	tx, err := r.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `WITH dormant AS (
+				UPDATE accounts SET status = 'dormant'
+				WHERE customer_id IS NOT NULL AND status = 'active' AND created_at < $1
+				AND NOT EXISTS (SELECT 1 FROM account_transactions WHERE account_id = accounts.id AND created_at >= $1
+								AND kind IN ('opening', 'deposit', 'withdrawal', 'transfer', 'capture'))
+				RETURNING id, balance
+			  ), changes AS (
+				INSERT INTO account_status_changes (account_id, from_status, to_status, reason)
+				SELECT id, 'active', 'dormant', $2 FROM dormant
+			  )
+			  SELECT id, balance FROM dormant ORDER BY id`
+	rows, err := tx.Query(query, since, reason)
+	if err != nil {
+		return 0, err
+	}
+	var marked []models.AccountState
+	for rows.Next() {
+		var state models.AccountState
+		if err := rows.Scan(&state.AccountID, &state.Balance); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		marked = append(marked, state)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, state := range marked {
+		before, after := state, state
+		before.Status, after.Status = models.AccountActive, models.AccountDormant
+		err := appendAuditTx(tx, models.AuditChangeStatus, dormancyActor, state.AccountID, 0,
+			[]models.AccountState{before}, []models.AccountState{after})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(marked), tx.Commit()
+}
+
+// DepositTx credits the account, journals the deposit, writes a
+// deposit.completed event and appends the deposit to the audit log in one
+// transaction.
+func (r *PsqlAccountRepository) DepositTx(accountID int, amount money.Money, actor models.Actor) error {
+	tx, err := r.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	entry := ledger.NewDeposit(accountID, amount)
+	balances, err := postEntryTx(tx, entry)
+	if err != nil {
+		return err
+	}
+	if err := insertEventTx(tx, models.EventDeposit, accountID, 0, movementEvent(entry, accountID, money.Zero)); err != nil {
+		return err
+	}
+	before := []models.AccountState{{AccountID: accountID, Balance: balances[accountID].Sub(amount)}}
+	after := []models.AccountState{{AccountID: accountID, Balance: balances[accountID]}}
+	if err := appendAuditTx(tx, models.AuditDeposit, actor, accountID, 0, before, after); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// WithdrawTx debits the account and journals the withdrawal in one
+// transaction, together with the withdrawal fee unless the account has it
+// waived. The funds check and the debit are a single conditional UPDATE,
+// so concurrent withdrawals can never go past the overdraft limit, and a
+// balance that covers the withdrawal but not its fee fails both. Accounts
+// that are not active fail with the error for their status. A
+// withdrawal.completed event and an audit log record are written with
+// them.
+func (r *PsqlAccountRepository) WithdrawTx(accountID int, amount money.Money, actor models.Actor) error {
+	tx, err := r.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	entry := ledger.NewWithdrawal(accountID, amount)
+	balances, err := postEntryTx(tx, entry)
+	if err != nil {
+		return err
+	}
+	fee, err := feeTx(tx, withdrawalFee, accountID)
+	if err != nil {
+		return err
+	}
+	if fee.IsPositive() {
+		if balances, err = postEntryTx(tx, ledger.NewWithdrawalFee(accountID, fee)); err != nil {
+			return err
+		}
+	}
+	if err := insertEventTx(tx, models.EventWithdrawal, accountID, 0, movementEvent(entry, accountID, fee)); err != nil {
+		return err
+	}
+	before := []models.AccountState{{AccountID: accountID, Balance: balances[accountID].Add(amount).Add(fee)}}
+	after := []models.AccountState{{AccountID: accountID, Balance: balances[accountID]}}
+	if err := appendAuditTx(tx, models.AuditWithdraw, actor, accountID, 0, before, after); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// movementEvent is the data of the event of a deposit or withdrawal entry
+// on the account, once the entry is journaled.
+func movementEvent(entry *models.JournalEntry, accountID int, fee money.Money) models.MovementEvent {
+	event := models.MovementEvent{JournalEntryID: entry.ID, AccountID: accountID, Fee: fee}
+	for _, p := range entry.Postings {
+		if p.AccountID == accountID {
+			event.Amount, event.Currency = p.Amount, p.Currency
+		}
+	}
+	return event
+}
+
+// postEntryTx writes a journal entry inside tx, applies each posting to
+// the balance of the customer account it touches and returns the new
+// balances.
+func postEntryTx(tx *sql.Tx, entry *models.JournalEntry) (map[int]money.Money, error) {
+	if err := insertJournalEntryTx(tx, entry); err != nil {
+		return nil, err
+	}
+
+	balances := make(map[int]money.Money)
+	for _, p := range entry.Postings {
+		if ledger.IsSystemAccount(p.AccountID) {
+			continue
+		}
+		balance, err := applyPostingTx(tx, p)
+		if err != nil {
+			return nil, err
+		}
+		balances[p.AccountID] = balance
+	}
+	return balances, insertTransactionsTx(tx, entry, balances)
+}
+
+// TransferTx moves amount, in the source account's currency, between two
+// accounts in one transaction. Between accounts in different currencies
+// the amount is converted at the rate of quoteID, or at the current rate
+// when quoteID is zero. It is retried automatically if Postgres aborts it
+// with a deadlock or a serialization failure.
+func (r *PsqlAccountRepository) TransferTx(fromID, toID int, amount money.Money, quoteID int64, actor models.Actor) error {
+	return retryTx("transfer", r.TxRetry, func() error {
+		return r.transferTx("", fromID, toID, amount, quoteID, actor)
+	})
+}
+
+// TransferOnceTx is TransferTx for a transfer that must be made at most
+// once. key is taken in the transfer's transaction; when a transfer was
+// already made with it, nothing happens and nil is returned.
+func (r *PsqlAccountRepository) TransferOnceTx(key string, fromID, toID int, amount money.Money, actor models.Actor) error {
+	return retryTx("transfer", r.TxRetry, func() error {
+		return r.transferTx(key, fromID, toID, amount, 0, actor)
+	})
+}
+
+func (r *PsqlAccountRepository) transferTx(key string, fromID, toID int, amount money.Money, quoteID int64, actor models.Actor) error {
+	tx, err := r.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // Rollback on any error.
+
+	if key != "" {
+		if _, claimed, err := claimTransferKeyTx(tx, key); err != nil || !claimed {
+			return err
+		}
+	}
+	if err := r.moveFundsTx(tx, fromID, toID, amount, quoteID, actor); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// moveFundsTx is the body of a transfer, run inside tx.
+func (r *PsqlAccountRepository) moveFundsTx(tx *sql.Tx, fromID, toID int, amount money.Money, quoteID int64, actor models.Actor) error {
+	// 1. Lock both accounts in canonical order so that opposite transfers
+	// between the same pair cannot deadlock each other
+	locked, err := r.lockAccountsTx(tx, fromID, toID)
+	if err != nil {
+		return err
+	}
+	fromBalance, toBalance := locked[fromID].balance, locked[toID].balance
+	if err := locked[toID].canCredit(); err != nil {
+		return err
+	}
+
+	// The destination is credited the converted amount when the accounts
+	// hold different currencies
+	credited := amount
+	var conversion *models.FXConversion
+	if from, to := locked[fromID].currency, locked[toID].currency; from != to {
+		if conversion, err = convertTx(tx, fromID, from, to, amount, quoteID); err != nil {
+			return err
+		}
+		credited = conversion.ConvertedAmount
+	} else if quoteID != 0 {
+		return ErrQuoteMismatch
+	}
+
+	// 2. Check fromAccount can be debited the amount and the transfer fee,
+	// both in its currency. Held funds cannot be spent, but the overdraft
+	// limit can
+	fee, err := feeTx(tx, transferFee, fromID, toID)
+	if err != nil {
+		return err
+	}
+	debit, err := amount.CheckedAdd(fee)
+	if err != nil {
+		return err
+	}
+	if err := locked[fromID].canDebit(debit); err != nil {
+		return err
+	}
+
+	// 3. Update balances
+	fromAfter, err := fromBalance.CheckedSub(debit)
+	if err != nil {
+		return err
+	}
+	toAfter, err := toBalance.CheckedAdd(credited)
+	if err != nil {
+		return err
+	}
+	if err := r.updateAccountBalanceTx(tx, fromID, fromAfter); err != nil {
+		return err
+	}
+	if err := r.updateAccountBalanceTx(tx, toID, toAfter); err != nil {
+		return err
+	}
+
+	// 4. Journal the transfer and its conversion, then the fee as an entry
+	// of its own
+	balances := map[int]money.Money{fromID: fromBalance.Sub(amount), toID: toAfter}
+	var entry *models.JournalEntry
+	if conversion == nil {
+		entry = ledger.NewTransfer(fromID, toID, amount)
+		err = recordEntryTx(tx, entry, balances)
+	} else {
+		entry = ledger.NewFXTransfer(fromID, toID, conversion)
+		err = recordConversionTx(tx, entry, balances, conversion)
+	}
+	if err != nil {
+		return err
+	}
+	if fee.IsPositive() {
+		err := recordEntryTx(tx, ledger.NewTransferFee(fromID, fee), map[int]money.Money{fromID: fromAfter})
+		if err != nil {
+			return err
+		}
+	}
+
+	// 5. Write the transfer.completed event
+	err = insertEventTx(tx, models.EventTransfer, fromID, toID, models.TransferEvent{
+		JournalEntryID: entry.ID,
+		FromAccountID:  fromID,
+		ToAccountID:    toID,
+		Amount:         amount,
+		Currency:       locked[fromID].currency,
+		Fee:            fee,
+		FX:             conversion,
+	})
+	if err != nil {
+		return err
+	}
+
+	// 6. Append it to the audit log
+	before := []models.AccountState{{AccountID: fromID, Balance: fromBalance}, {AccountID: toID, Balance: toBalance}}
+	after := []models.AccountState{
+		{AccountID: fromID, Balance: fromAfter},
+		{AccountID: toID, Balance: toAfter},
+	}
+	return appendAuditTx(tx, models.AuditTransfer, actor, fromID, toID, before, after)
+}
+
+// Helper functions to be used within a transaction
+
+// heldFunds is the amount held on accounts.id by active holds and pending
+// transfer approvals that have not expired. An approval holds its amount
+// and its fee.
+const heldFunds = "((SELECT COALESCE(SUM(amount), 0) FROM holds " +
+	"WHERE account_id = accounts.id AND status = 'active' AND expires_at > now()) + " +
+	"(SELECT COALESCE(SUM(amount + fee), 0) FROM transfer_approvals " +
+	"WHERE from_account_id = accounts.id AND status = 'pending' AND expires_at > now()))"
+
+// overdraftLimit is how far below zero accounts.id may go: its own limit,
+// or else its category's.
+const overdraftLimit = "COALESCE(accounts.overdraft_limit, " +
+	"(SELECT limit_amount FROM overdraft_policies WHERE category = accounts.category), 0)"
+
+// lockedAccount is the state of an account read under lock.
+type lockedAccount struct {
+	balance   money.Money
+	status    string
+	held      money.Money
+	overdraft money.Money
+	currency  string
+}
+
+// canDebit reports why amount cannot be taken from the account, if it
+// cannot.
+func (a lockedAccount) canDebit(amount money.Money) error {
+	if err := debitStatusError(a.status); err != nil {
+		return err
+	}
+	spendable, err := a.spendable()
+	if err != nil {
+		return err
+	}
+	if spendable.Cmp(amount) < 0 {
+		return ErrInsufficientFunds
+	}
+	return nil
+}
+
+// spendable is how much can be taken from the account: its balance less
+// held funds, plus its overdraft limit. It is negative when the account
+// is already past the limit.
+func (a lockedAccount) spendable() (money.Money, error) {
+	spendable, err := a.balance.CheckedSub(a.held)
+	if err != nil {
+		return 0, err
+	}
+	return spendable.CheckedAdd(a.overdraft)
+}
+
+// canCredit reports why the account cannot be credited, if it cannot.
+func (a lockedAccount) canCredit() error {
+	if a.status == models.AccountClosed {
+		return ErrAccountClosed
+	}
+	return nil
+}
+
+// debitStatusError returns the error for debiting an account in status,
+// or nil when the account is active.
+func debitStatusError(status string) error {
+	switch status {
+	case models.AccountActive:
+		return nil
+	case models.AccountFrozen:
+		return ErrAccountFrozen
+	case models.AccountDormant:
+		return ErrAccountDormant
+	case models.AccountPending:
+		return ErrAccountPending
+	default:
+		return ErrAccountClosed
+	}
+}
+
+// lockAccountsTx locks the given accounts with SELECT ... FOR UPDATE,
+// always in ascending ID order regardless of the order they are passed in,
+// and returns their state.
+func (r *PsqlAccountRepository) lockAccountsTx(tx *sql.Tx, accountIDs ...int) (map[int]lockedAccount, error) {
+	ordered := append([]int(nil), accountIDs...)
+	sort.Ints(ordered)
+
+	locked := make(map[int]lockedAccount, len(ordered))
+	for _, accountID := range ordered {
+		if _, ok := locked[accountID]; ok {
+			continue
+		}
+		account, err := r.lockAccountTx(tx, accountID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, ErrAccountNotFound
+			}
+			return nil, err
+		}
+		locked[accountID] = account
+	}
+	return locked, nil
+}
+
+func (r *PsqlAccountRepository) lockAccountTx(tx *sql.Tx, accountID int) (lockedAccount, error) {
+	var account lockedAccount
+	query := "SELECT balance, status, " + heldFunds + ", " + overdraftLimit +
+		", currency FROM accounts WHERE id = $1 AND customer_id IS NOT NULL FOR UPDATE"
+	err := tx.QueryRow(query, accountID).Scan(&account.balance, &account.status, &account.held, &account.overdraft, &account.currency)
+	return account, err
+}
+
+func (r *PsqlAccountRepository) updateAccountBalanceTx(tx *sql.Tx, accountID int, newBalance money.Money) error {
+	_, err := tx.Exec("UPDATE accounts SET balance = $1 WHERE id = $2", newBalance, accountID)
+	return err
+}
+
+// applyPostingTx applies the posting to the account balance with a single
+// atomic UPDATE and returns the new balance. A debit only matches while the
+// account is active and the balance, less any held funds, covers it with
+// the help of the overdraft limit. A credit matches unless the account is
+// closed.
+func applyPostingTx(tx *sql.Tx, p models.Posting) (money.Money, error) {
+	var balance money.Money
+	var query string
+	if p.Direction == ledger.Debit {
+		query = "UPDATE accounts SET balance = balance - $1 WHERE id = $2 AND customer_id IS NOT NULL AND status = 'active' AND balance - " +
+			heldFunds + " + " + overdraftLimit + " >= $1 RETURNING balance"
+	} else {
+		query = "UPDATE accounts SET balance = balance + $1 WHERE id = $2 AND customer_id IS NOT NULL AND status <> 'closed' RETURNING balance"
+	}
+
+	err := tx.QueryRow(query, p.Amount, p.AccountID).Scan(&balance)
+	if err == sql.ErrNoRows {
+		var status string
+		err := tx.QueryRow("SELECT status FROM accounts WHERE id = $1 AND customer_id IS NOT NULL", p.AccountID).Scan(&status)
+		switch {
+		case err == sql.ErrNoRows:
+			return 0, ErrAccountNotFound
+		case err != nil:
+			return 0, err
+		case p.Direction != ledger.Debit:
+			return 0, ErrAccountClosed
+		}
+		if err := debitStatusError(status); err != nil {
+			return 0, err
+		}
+		return 0, ErrInsufficientFunds
+	}
+	if err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
+
+// approvalStatus reports pending approvals past their expiry as expired.
+const approvalStatus = "CASE WHEN status = 'pending' AND expires_at <= now() THEN 'expired' ELSE status END"
+
+const approvalColumns = "id, from_account_id, to_account_id, amount, fee, " + approvalStatus + ", requested_by_customer_id, " +
+	"requested_by_operator_id, reviewed_by, review_reason, expires_at, created_at, reviewed_at"
+
+func scanApproval(row interface{ Scan(...interface{}) error }, approval *models.TransferApproval) error {
+	var customerID, operatorID, reviewedBy sql.NullInt64
+	var reason sql.NullString
+	var reviewedAt sql.NullTime
+	err := row.Scan(&approval.ID, &approval.FromAccountID, &approval.ToAccountID, &approval.Amount, &approval.Fee, &approval.Status,
+		&customerID, &operatorID, &reviewedBy, &reason, &approval.ExpiresAt, &approval.CreatedAt, &reviewedAt)
+	if err != nil {
+		return err
+	}
+	approval.RequestedByCustomerID = int(customerID.Int64)
+	approval.RequestedByOperatorID = int(operatorID.Int64)
+	approval.ReviewedBy = int(reviewedBy.Int64)
+	approval.ReviewReason = reason.String
+	if reviewedAt.Valid {
+		approval.ReviewedAt = &reviewedAt.Time
+	}
+	return nil
+}
+
+// CreateTransferApproval records a transfer waiting for approval and holds
+// its amount and transfer fee on the source account. The source must be
+// able to cover both now, exactly as if the transfer were executed. When approval.Key is set
+// and an approval was already made with it, that approval is loaded into
+// approval instead; when a transfer was, ErrTransferMade is returned.
+func (r *PsqlAccountRepository) CreateTransferApproval(approval *models.TransferApproval) error {
+	tx, err := r.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if approval.Key != "" {
+		approvalID, claimed, err := claimTransferKeyTx(tx, approval.Key)
+		switch {
+		case err != nil:
+			return err
+		case claimed:
+		case approvalID.Valid:
+			query := "SELECT " + approvalColumns + " FROM transfer_approvals WHERE id = $1"
+			return scanApproval(tx.QueryRow(query, approvalID.Int64), approval)
+		default:
+			return ErrTransferMade
+		}
+	}
+
+	locked, err := r.lockAccountsTx(tx, approval.FromAccountID, approval.ToAccountID)
+	if err != nil {
+		return err
+	}
+	if approval.Fee, err = feeTx(tx, transferFee, approval.FromAccountID, approval.ToAccountID); err != nil {
+		return err
+	}
+	debit, err := approval.Amount.CheckedAdd(approval.Fee)
+	if err != nil {
+		return err
+	}
+	if err := locked[approval.FromAccountID].canDebit(debit); err != nil {
+		return err
+	}
+	if err := locked[approval.ToAccountID].canCredit(); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO transfer_approvals (from_account_id, to_account_id, amount, fee, requested_by_customer_id, requested_by_operator_id, expires_at)
+			  VALUES ($1, $2, $3, $4, NULLIF($5, 0), NULLIF($6, 0), $7) RETURNING id, status, created_at`
+	err = tx.QueryRow(query, approval.FromAccountID, approval.ToAccountID, approval.Amount, approval.Fee, approval.RequestedByCustomerID,
+		approval.RequestedByOperatorID, approval.ExpiresAt).Scan(&approval.ID, &approval.Status, &approval.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if approval.Key != "" {
+		if _, err := tx.Exec("UPDATE transfer_requests SET approval_id = $1 WHERE key = $2", approval.ID, approval.Key); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// claimTransferKeyTx takes key for the transfer or approval made in tx.
+// When the key is already taken, it reports claimed false and the approval
+// made with it, if it was an approval. A claim of a key another
+// transaction has just taken waits for that transaction to end.
+func claimTransferKeyTx(tx *sql.Tx, key string) (approvalID sql.NullInt64, claimed bool, err error) {
+	res, err := tx.Exec("INSERT INTO transfer_requests (key) VALUES ($1) ON CONFLICT (key) DO NOTHING", key)
+	if err != nil {
+		return approvalID, false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return approvalID, false, err
+	}
+	if n == 1 {
+		return approvalID, true, nil
+	}
+	err = tx.QueryRow("SELECT approval_id FROM transfer_requests WHERE key = $1", key).Scan(&approvalID)
+	return approvalID, false, err
+}
+
+func (r *PsqlAccountRepository) GetTransferApproval(approvalID int64) (*models.TransferApproval, error) {
+	var approval models.TransferApproval
+	query := "SELECT " + approvalColumns + " FROM transfer_approvals WHERE id = $1"
+	if err := scanApproval(r.DB.QueryRow(query, approvalID), &approval); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrApprovalNotFound
+		}
+		return nil, err
+	}
+	return &approval, nil
+}
+
+// ListTransferApprovals returns transfer approvals, newest first, applying
+// the filter. Up to filter.Limit rows are returned.
+func (r *PsqlAccountRepository) ListTransferApprovals(filter models.ApprovalFilter) ([]models.TransferApproval, error) {
+	query := "SELECT " + approvalColumns + " FROM transfer_approvals WHERE true"
+	var args []interface{}
+
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		query += fmt.Sprintf(" AND "+condition, len(args))
+	}
+	if filter.Status != "" {
+		addCondition("("+approvalStatus+") = $%d", filter.Status)
+	}
+	if filter.AccountID > 0 {
+		addCondition("$%d IN (from_account_id, to_account_id)", filter.AccountID)
+	}
+	if filter.BeforeID > 0 {
+		addCondition("id < $%d", filter.BeforeID)
+	}
+
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	approvals := []models.TransferApproval{}
+	for rows.Next() {
+		var approval models.TransferApproval
+		if err := scanApproval(rows, &approval); err != nil {
+			return nil, err
+		}
+		approvals = append(approvals, approval)
+	}
+	return approvals, rows.Err()
+}
+
+// ApproveTransfer marks a pending approval as approved by reviewerID and
+// executes the transfer in the same transaction, releasing the hold. Like
+// TransferTx, it is retried after deadlocks and serialization failures.
+func (r *PsqlAccountRepository) ApproveTransfer(approvalID int64, reviewer models.Actor, reason string) (*models.TransferApproval, error) {
+	var approval *models.TransferApproval
+	err := retryTx("approval", r.TxRetry, func() error {
+		var err error
+		approval, err = r.reviewTransferTx(approvalID, reviewer, reason, models.ApprovalApproved)
+		return err
+	})
+	return approval, err
+}
+
+// RejectTransfer marks a pending approval as rejected, releasing the hold.
+func (r *PsqlAccountRepository) RejectTransfer(approvalID int64, reviewer models.Actor, reason string) (*models.TransferApproval, error) {
+	return r.reviewTransferTx(approvalID, reviewer, reason, models.ApprovalRejected)
+}
+
+func (r *PsqlAccountRepository) reviewTransferTx(approvalID int64, reviewer models.Actor, reason, status string) (*models.TransferApproval, error) {
+	tx, err := r.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var approval models.TransferApproval
+	query := "SELECT " + approvalColumns + " FROM transfer_approvals WHERE id = $1 FOR UPDATE"
+	if err := scanApproval(tx.QueryRow(query, approvalID), &approval); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrApprovalNotFound
+		}
+		return nil, err
+	}
+	switch approval.Status {
+	case models.ApprovalPending:
+	case models.ApprovalExpired:
+		return nil, ErrApprovalExpired
+	default:
+		return nil, ErrApprovalReviewed
+	}
+
+	query = `UPDATE transfer_approvals SET status = $1, reviewed_by = $2, review_reason = NULLIF($3, ''), reviewed_at = now()
+			 WHERE id = $4 RETURNING reviewed_at`
+	var reviewedAt time.Time
+	if err := tx.QueryRow(query, status, reviewer.OperatorID, reason, approvalID).Scan(&reviewedAt); err != nil {
+		return nil, err
+	}
+	approval.Status, approval.ReviewedBy, approval.ReviewReason, approval.ReviewedAt = status, reviewer.OperatorID, reason, &reviewedAt
+
+	// The approval is no longer pending, so its hold does not count
+	// against the transfer it was holding funds for. A cross-currency
+	// transfer is converted at the rate of the moment it is approved, and
+	// the fee charged is the one in force then.
+	if status == models.ApprovalApproved {
+		if err := r.moveFundsTx(tx, approval.FromAccountID, approval.ToAccountID, approval.Amount, 0, reviewer); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &approval, nil
+}
+
+// holdStatus reports active holds past their expiry as expired.
+const holdStatus = "CASE WHEN status = 'active' AND expires_at <= now() THEN 'expired' ELSE status END"
+
+const holdColumns = "id, account_id, amount, captured_amount, " + holdStatus + ", description, expires_at, created_at, released_at"
+
+func scanHold(row interface{ Scan(...interface{}) error }, hold *models.Hold) error {
+	var description sql.NullString
+	err := row.Scan(&hold.ID, &hold.AccountID, &hold.Amount, &hold.CapturedAmount, &hold.Status, &description,
+		&hold.ExpiresAt, &hold.CreatedAt, &hold.ReleasedAt)
+	hold.Description = description.String
+	return err
+}
+
+// CreateHold reserves hold.Amount on the account. The available balance
+// must cover it, exactly as if it were withdrawn.
+func (r *PsqlAccountRepository) CreateHold(hold *models.Hold) error {
+	tx, err := r.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	locked, err := r.lockAccountsTx(tx, hold.AccountID)
+	if err != nil {
+		return err
+	}
+	if err := locked[hold.AccountID].canDebit(hold.Amount); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO holds (account_id, amount, description, expires_at)
+			  VALUES ($1, $2, NULLIF($3, ''), $4) RETURNING id, status, created_at`
+	err = tx.QueryRow(query, hold.AccountID, hold.Amount, hold.Description, hold.ExpiresAt).
+		Scan(&hold.ID, &hold.Status, &hold.CreatedAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListHolds returns the account's holds, newest first, applying the
+// filter. Up to filter.Limit rows are returned.
+func (r *PsqlAccountRepository) ListHolds(accountID int, filter models.HoldFilter) ([]models.Hold, error) {
+	query := "SELECT " + holdColumns + " FROM holds WHERE account_id = $1"
+	args := []interface{}{accountID}
+
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		query += fmt.Sprintf(" AND "+condition, len(args))
+	}
+	if filter.Status != "" {
+		addCondition("("+holdStatus+") = $%d", filter.Status)
+	}
+	if filter.BeforeID > 0 {
+		addCondition("id < $%d", filter.BeforeID)
+	}
+
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	holds := []models.Hold{}
+	for rows.Next() {
+		var hold models.Hold
+		if err := scanHold(rows, &hold); err != nil {
+			return nil, err
+		}
+		holds = append(holds, hold)
+	}
+	return holds, rows.Err()
+}
+
+// CaptureHold settles amount of an active hold, or all of it when amount
+// is zero, and releases the rest. A capture takes money out of the account
+// like a withdrawal does: in the same transaction the captured amount is
+// debited and journaled together with the withdrawal fee, unless the
+// account has it waived, and a hold.captured event and an audit log record
+// are written.
+func (r *PsqlAccountRepository) CaptureHold(accountID int, holdID int64, amount money.Money, actor models.Actor) (*models.Hold, error) {
+	tx, err := r.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	hold, err := lockHoldTx(tx, accountID, holdID)
+	if err != nil {
+		return nil, err
+	}
+	if amount.IsZero() {
+		amount = hold.Amount
+	}
+	if amount.Cmp(hold.Amount) > 0 {
+		return nil, ErrCaptureExceedsHold
+	}
+
+	query := "UPDATE holds SET status = 'captured', captured_amount = $1, released_at = now() WHERE id = $2 RETURNING released_at"
+	var releasedAt time.Time
+	if err := tx.QueryRow(query, amount, holdID).Scan(&releasedAt); err != nil {
+		return nil, err
+	}
+	hold.Status, hold.CapturedAmount, hold.ReleasedAt = models.HoldCaptured, &amount, &releasedAt
+
+	// The hold is no longer active, so it does not count against its own
+	// capture.
+	locked, err := r.lockAccountsTx(tx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	fee, err := feeTx(tx, withdrawalFee, accountID)
+	if err != nil {
+		return nil, err
+	}
+	debit, err := amount.CheckedAdd(fee)
+	if err != nil {
+		return nil, err
+	}
+	if err := locked[accountID].canDebit(debit); err != nil {
+		return nil, err
+	}
+	before := locked[accountID].balance
+	balance, err := before.CheckedSub(debit)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.updateAccountBalanceTx(tx, accountID, balance); err != nil {
+		return nil, err
+	}
+	entry := ledger.NewCapture(accountID, amount)
+	if err := recordEntryTx(tx, entry, map[int]money.Money{accountID: balance.Add(fee)}); err != nil {
+		return nil, err
+	}
+	if fee.IsPositive() {
+		if err := recordEntryTx(tx, ledger.NewWithdrawalFee(accountID, fee), map[int]money.Money{accountID: balance}); err != nil {
+			return nil, err
+		}
+	}
+	event := models.CaptureEvent{HoldID: holdID, MovementEvent: movementEvent(entry, accountID, fee)}
+	if err := insertEventTx(tx, models.EventHoldCaptured, accountID, 0, event); err != nil {
+		return nil, err
+	}
+	err = appendAuditTx(tx, models.AuditCaptureHold, actor, accountID, 0,
+		[]models.AccountState{{AccountID: accountID, Balance: before}},
+		[]models.AccountState{{AccountID: accountID, Balance: balance}})
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// VoidHold releases an active hold without moving any money.
+func (r *PsqlAccountRepository) VoidHold(accountID int, holdID int64) (*models.Hold, error) {
+	tx, err := r.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	hold, err := lockHoldTx(tx, accountID, holdID)
+	if err != nil {
+		return nil, err
+	}
+
+	var releasedAt time.Time
+	if err := tx.QueryRow("UPDATE holds SET status = 'voided', released_at = now() WHERE id = $1 RETURNING released_at", holdID).Scan(&releasedAt); err != nil {
+		return nil, err
+	}
+	hold.Status, hold.ReleasedAt = models.HoldVoided, &releasedAt
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// lockHoldTx locks one of the account's holds and makes sure it is still
+// active.
+func lockHoldTx(tx *sql.Tx, accountID int, holdID int64) (*models.Hold, error) {
+	var hold models.Hold
+	query := "SELECT " + holdColumns + " FROM holds WHERE id = $1 AND account_id = $2 FOR UPDATE"
+	if err := scanHold(tx.QueryRow(query, holdID, accountID), &hold); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrHoldNotFound
+		}
+		return nil, err
+	}
+	switch hold.Status {
+	case models.HoldActive:
+		return &hold, nil
+	case models.HoldExpired:
+		return nil, ErrHoldExpired
+	default:
+		return nil, ErrHoldReleased
+	}
+}
+
+// GetOverdraft returns the account's overdraft limit and how much of it
+// the available balance uses.
+func (r *PsqlAccountRepository) GetOverdraft(accountID int) (*models.Overdraft, error) {
+	var overdraft models.Overdraft
+	var available money.Money
+	query := "SELECT balance - " + heldFunds + ", " + overdraftLimit + ", overdraft_limit IS NOT NULL, " +
+		"COALESCE((SELECT monthly_rate_bps FROM overdraft_policies WHERE category = accounts.category), 0) " +
+		"FROM accounts WHERE id = $1 AND customer_id IS NOT NULL"
+	err := r.DB.QueryRow(query, accountID).Scan(&available, &overdraft.Limit, &overdraft.Custom, &overdraft.MonthlyRateBps)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+
+	if available.IsNegative() {
+		overdraft.Used = available.Neg()
+	}
+	if overdraft.Used.Cmp(overdraft.Limit) < 0 {
+		overdraft.Remaining = overdraft.Limit.Sub(overdraft.Used)
+	}
+	return &overdraft, nil
+}
+
+// SetOverdraftLimit gives the account its own overdraft limit, or goes
+// back to its category's when limit is nil.
+func (r *PsqlAccountRepository) SetOverdraftLimit(accountID int, limit *money.Money) error {
+	res, err := r.DB.Exec("UPDATE accounts SET overdraft_limit = $1 WHERE id = $2 AND customer_id IS NOT NULL", limit, accountID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrAccountNotFound
+	}
+	return nil
+}
+
+func (r *PsqlAccountRepository) ListOverdraftPolicies() ([]models.OverdraftPolicy, error) {
+	rows, err := r.DB.Query("SELECT category, limit_amount, monthly_rate_bps FROM overdraft_policies ORDER BY category")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	policies := []models.OverdraftPolicy{}
+	for rows.Next() {
+		var p models.OverdraftPolicy
+		if err := rows.Scan(&p.Category, &p.Limit, &p.MonthlyRateBps); err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+	return policies, rows.Err()
+}
+
+// SetOverdraftPolicy creates or replaces the policy of its category.
+func (r *PsqlAccountRepository) SetOverdraftPolicy(policy *models.OverdraftPolicy) error {
+	query := `INSERT INTO overdraft_policies (category, limit_amount, monthly_rate_bps) VALUES ($1, $2, $3)
+			  ON CONFLICT (category) DO UPDATE SET limit_amount = EXCLUDED.limit_amount, monthly_rate_bps = EXCLUDED.monthly_rate_bps`
+	_, err := r.DB.Exec(query, policy.Category, policy.Limit, policy.MonthlyRateBps)
+	return err
+}
+
+// ListOverdrawnAccounts returns the IDs of the accounts with a negative
+// balance.
+func (r *PsqlAccountRepository) ListOverdrawnAccounts() ([]int, error) {
+	rows, err := r.DB.Query("SELECT id FROM accounts WHERE customer_id IS NOT NULL AND balance < 0 ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ChargeOverdraftInterest debits one day of interest on the account's
+// negative balance and returns the amount charged. It charges nothing when
+// the balance is no longer negative or the account was already charged
+// for date, so it is safe to call again for the same day. Frozen accounts
+// are charged too.
+func (r *PsqlAccountRepository) ChargeOverdraftInterest(accountID int, date time.Time) (money.Money, error) {
+	tx, err := r.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	locked, err := r.lockAccountsTx(tx, accountID)
+	if err != nil {
+		return 0, err
+	}
+	balance := locked[accountID].balance
+	if !balance.IsNegative() {
+		return 0, nil
+	}
+
+	var charged bool
+	query := "SELECT EXISTS (SELECT 1 FROM overdraft_interest_charges WHERE account_id = $1 AND charge_date = $2)"
+	if err := tx.QueryRow(query, accountID, date).Scan(&charged); err != nil {
+		return 0, err
+	}
+	if charged {
+		return 0, nil
+	}
+
+	var policy models.OverdraftPolicy
+	query = "SELECT COALESCE((SELECT monthly_rate_bps FROM overdraft_policies WHERE category = accounts.category), 0) FROM accounts WHERE id = $1"
+	if err := tx.QueryRow(query, accountID).Scan(&policy.MonthlyRateBps); err != nil {
+		return 0, err
+	}
+	interest := policy.DailyInterest(balance)
+	if interest.IsZero() {
+		return 0, nil
+	}
+
+	newBalance, err := balance.CheckedSub(interest)
+	if err != nil {
+		return 0, err
+	}
+	if err := r.updateAccountBalanceTx(tx, accountID, newBalance); err != nil {
+		return 0, err
+	}
+	entry := ledger.NewOverdraftInterest(accountID, interest)
+	if err := recordEntryTx(tx, entry, map[int]money.Money{accountID: newBalance}); err != nil {
+		return 0, err
+	}
+
+	query = `INSERT INTO overdraft_interest_charges (account_id, charge_date, balance, amount, journal_entry_id)
+			 VALUES ($1, $2, $3, $4, $5)`
+	if _, err := tx.Exec(query, accountID, date, balance, interest, entry.ID); err != nil {
+		return 0, err
+	}
+	before := []models.AccountState{{AccountID: accountID, Balance: balance}}
+	after := []models.AccountState{{AccountID: accountID, Balance: newBalance}}
+	if err := appendAuditTx(tx, models.AuditOverdraftInterest, overdraftInterestActor, accountID, 0, before, after); err != nil {
+		return 0, err
+	}
+	return interest, tx.Commit()
+}
+
+// feeWaived matches fees the account in $1 is exempt from.
+const feeWaived = "EXISTS (SELECT 1 FROM fee_waivers WHERE account_id = $1 AND fee_type = fees.fee_type)"
+
+// Queries for the fee an account in $1 pays. They return no rows when the
+// fee is not set or the account has it waived. transferFee takes the
+// destination account in $2.
+const (
+	withdrawalFee = "SELECT amount FROM fees WHERE fee_type = 'withdrawal' AND NOT " + feeWaived
+	transferFee   = "SELECT fees.amount FROM fees, " +
+		"accounts src JOIN customers sc ON sc.id = src.customer_id, " +
+		"accounts dst JOIN customers dc ON dc.id = dst.customer_id " +
+		"WHERE fees.fee_type = 'transfer' AND src.id = $1 AND dst.id = $2 " +
+		"AND fees.from_type = sc.type AND fees.to_type = dc.type AND NOT " + feeWaived
+	maintenanceFee = "SELECT fees.amount FROM fees JOIN accounts ON accounts.category = fees.category " +
+		"WHERE fees.fee_type = 'maintenance' AND accounts.id = $1 AND NOT " + feeWaived
+)
+
+// feeTx runs one of the fee queries and returns the fee, or zero when
+// there is none to charge.
+func feeTx(tx *sql.Tx, query string, args ...interface{}) (money.Money, error) {
+	var fee money.Money
+	err := tx.QueryRow(query, args...).Scan(&fee)
+	if err == sql.ErrNoRows {
+		return money.Zero, nil
+	}
+	return fee, err
+}
+
+func (r *PsqlAccountRepository) ListFees() ([]models.Fee, error) {
+	rows, err := r.DB.Query("SELECT fee_type, from_type, to_type, category, amount FROM fees ORDER BY fee_type, from_type, to_type, category")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fees := []models.Fee{}
+	for rows.Next() {
+		var fee models.Fee
+		if err := rows.Scan(&fee.Type, &fee.FromType, &fee.ToType, &fee.Category, &fee.Amount); err != nil {
+			return nil, err
+		}
+		fees = append(fees, fee)
+	}
+	return fees, rows.Err()
+}
+
+func (r *PsqlAccountRepository) SetFee(fee *models.Fee) error {
+	query := `INSERT INTO fees (fee_type, from_type, to_type, category, amount) VALUES ($1, $2, $3, $4, $5)
+			  ON CONFLICT (fee_type, from_type, to_type, category) DO UPDATE SET amount = EXCLUDED.amount`
+	_, err := r.DB.Exec(query, fee.Type, fee.FromType, fee.ToType, fee.Category, fee.Amount)
+	return err
+}
+
+func (r *PsqlAccountRepository) ListFeeWaivers(accountID int) ([]models.FeeWaiver, error) {
+	query := "SELECT account_id, fee_type, reason, created_at FROM fee_waivers WHERE account_id = $1 ORDER BY fee_type"
+	rows, err := r.DB.Query(query, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	waivers := []models.FeeWaiver{}
+	for rows.Next() {
+		var waiver models.FeeWaiver
+		if err := rows.Scan(&waiver.AccountID, &waiver.FeeType, &waiver.Reason, &waiver.CreatedAt); err != nil {
+			return nil, err
+		}
+		waivers = append(waivers, waiver)
+	}
+	return waivers, rows.Err()
+}
+
+// WaiveFee exempts the account from the waiver's fee type. Waiving a fee
+// again only replaces the reason.
+func (r *PsqlAccountRepository) WaiveFee(waiver *models.FeeWaiver) error {
+	query := `INSERT INTO fee_waivers (account_id, fee_type, reason)
+			  SELECT id, $2, $3 FROM accounts WHERE id = $1 AND customer_id IS NOT NULL
+			  ON CONFLICT (account_id, fee_type) DO UPDATE SET reason = EXCLUDED.reason
+			  RETURNING created_at`
+	err := r.DB.QueryRow(query, waiver.AccountID, waiver.FeeType, waiver.Reason).Scan(&waiver.CreatedAt)
+	if err == sql.ErrNoRows {
+		return ErrAccountNotFound
+	}
+	return err
+}
+
+func (r *PsqlAccountRepository) RemoveFeeWaiver(accountID int, feeType string) error {
+	res, err := r.DB.Exec("DELETE FROM fee_waivers WHERE account_id = $1 AND fee_type = $2", accountID, feeType)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrFeeWaiverNotFound
+	}
+	return nil
+}
+
+// ListMaintenanceFeeAccounts returns the accounts that owe a maintenance
+// fee for period: those opened before it, in a category with a fee, that
+// have not been charged for it or had the fee waived.
+func (r *PsqlAccountRepository) ListMaintenanceFeeAccounts(period time.Time) ([]int, error) {
+	query := `SELECT accounts.id FROM accounts
+			  JOIN fees ON fees.fee_type = 'maintenance' AND fees.category = accounts.category AND fees.amount > 0
+			  WHERE accounts.customer_id IS NOT NULL AND accounts.status <> 'closed' AND accounts.created_at < $1
+			  AND NOT EXISTS (SELECT 1 FROM fee_waivers WHERE account_id = accounts.id AND fee_type = 'maintenance')
+			  AND NOT EXISTS (SELECT 1 FROM maintenance_fee_charges WHERE account_id = accounts.id AND period = $1)
+			  ORDER BY accounts.id`
+	rows, err := r.DB.Query(query, period)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ChargeMaintenanceFee debits the account's maintenance fee for period,
+// the first day of a month, and returns the amount charged. It charges
+// nothing when the account was already charged for period or has the fee
+// waived, so it is safe to call again for the same month. Like overdraft
+// interest, the fee is charged to frozen and dormant accounts too, but
+// never to closed ones. It does not take the account past its overdraft
+// limit: only what the available balance and the limit cover is charged,
+// and an account with nothing left to charge is charged nothing, so a
+// later run that month tries again.
+func (r *PsqlAccountRepository) ChargeMaintenanceFee(accountID int, period time.Time) (money.Money, error) {
+	tx, err := r.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	locked, err := r.lockAccountsTx(tx, accountID)
+	if err != nil {
+		return 0, err
+	}
+	if locked[accountID].status == models.AccountClosed {
+		return 0, nil
+	}
+
+	var charged bool
+	query := "SELECT EXISTS (SELECT 1 FROM maintenance_fee_charges WHERE account_id = $1 AND period = $2)"
+	if err := tx.QueryRow(query, accountID, period).Scan(&charged); err != nil {
+		return 0, err
+	}
+	if charged {
+		return 0, nil
+	}
+
+	fee, err := feeTx(tx, maintenanceFee, accountID)
+	if err != nil {
+		return 0, err
+	}
+	spendable, err := locked[accountID].spendable()
+	if err != nil {
+		return 0, err
+	}
+	if fee.Cmp(spendable) > 0 {
+		fee = spendable
+	}
+	if !fee.IsPositive() {
+		return 0, nil
+	}
+
+	newBalance, err := locked[accountID].balance.CheckedSub(fee)
+	if err != nil {
+		return 0, err
+	}
+	if err := r.updateAccountBalanceTx(tx, accountID, newBalance); err != nil {
+		return 0, err
+	}
+	entry := ledger.NewMaintenanceFee(accountID, fee)
+	if err := recordEntryTx(tx, entry, map[int]money.Money{accountID: newBalance}); err != nil {
+		return 0, err
+	}
+
+	query = "INSERT INTO maintenance_fee_charges (account_id, period, amount, journal_entry_id) VALUES ($1, $2, $3, $4)"
+	if _, err := tx.Exec(query, accountID, period, fee, entry.ID); err != nil {
+		return 0, err
+	}
+	before := []models.AccountState{{AccountID: accountID, Balance: locked[accountID].balance}}
+	after := []models.AccountState{{AccountID: accountID, Balance: newBalance}}
+	if err := appendAuditTx(tx, models.AuditMaintenanceFee, maintenanceFeeActor, accountID, 0, before, after); err != nil {
+		return 0, err
+	}
+	return fee, tx.Commit()
 }
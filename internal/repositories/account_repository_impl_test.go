@@ -2,12 +2,22 @@ package repositories
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"expvar"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/gregoryAlvim/gobank/internal/accountnumber"
+	"github.com/gregoryAlvim/gobank/internal/fx"
+	"github.com/gregoryAlvim/gobank/internal/ledger"
 	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
 )
 
 func TestPsqlAccountRepository_CreateNaturalPerson(t *testing.T) {
@@ -20,25 +30,42 @@ func TestPsqlAccountRepository_CreateNaturalPerson(t *testing.T) {
 	repo := &PsqlAccountRepository{DB: db}
 
 	person := &models.NaturalPerson{
-		MonthlyIncome: 5000,
+		CPF:           "52998224725",
+		MonthlyIncome: money.New(5000, 0),
 		Age:           30,
 		FullName:      "John Doe",
 		PhoneNumber:   "123456789",
 		Email:         "john.doe@example.com",
-		Category:      "standard",
-		Balance:       1000,
+		PasswordHash:  "$2a$10$hash",
 	}
+	account := &models.Account{Category: "standard", Balance: money.New(1000, 0)}
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
 
-	rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
-
-	mock.ExpectQuery(`INSERT INTO natural_person`).
-		WithArgs(person.MonthlyIncome, person.Age, person.FullName, person.PhoneNumber, person.Email, person.Category, person.Balance).
-		WillReturnRows(rows)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO customers`).
+		WithArgs(person.CPF, person.MonthlyIncome, person.Age, person.FullName, person.PhoneNumber, person.Email, person.PasswordHash).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(`SELECT nextval\('account_number_seq'\)`).WillReturnRows(sqlmock.NewRows([]string{"nextval"}).AddRow(10))
+	mock.ExpectQuery(`INSERT INTO accounts`).
+		WithArgs(1, "0001", "00000010", "6", account.Category, account.Balance, "BRL").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(10, "active", createdAt))
+	expectJournalEntry(mock, "opening",
+		expectedPosting{ledger.EquityAccountID, "debit", account.Balance},
+		expectedPosting{10, "credit", account.Balance})
+	expectTransaction(mock, 10, "opening", "credit", account.Balance, account.Balance)
+	expectEvent(mock, "account.created", 10, 0)
+	expectAudit(mock, "create_account", models.Actor{CustomerID: 1, RequestID: "req-1"}, 10, 0, "",
+		`[{"account_id": 10, "balance": 1000, "status": "active"}]`)
+	mock.ExpectCommit()
 
-	err = repo.CreateNaturalPerson(person)
+	err = repo.CreateNaturalPerson(person, account, "req-1")
 
 	assert.NoError(t, err)
 	assert.Equal(t, 1, person.ID)
+	assert.Equal(t, 10, account.ID)
+	assert.Equal(t, 1, account.CustomerID)
+	assert.Equal(t, createdAt, account.CreatedAt)
+	assert.Equal(t, "0001-00000010-6", account.AccountNumber())
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
@@ -55,32 +82,55 @@ func TestPsqlAccountRepository_CreateLegalPerson(t *testing.T) {
 	repo := &PsqlAccountRepository{DB: db}
 
 	person := &models.LegalPerson{
-		AnnualRevenue:  100000,
+		CNPJ:           "11222333000181",
+		AnnualRevenue:  money.New(100000, 0),
 		Age:            5,
 		TradeName:      "ABC Inc.",
 		PhoneNumber:    "987654321",
 		CorporateEmail: "contact@abcinc.com",
-		Category:       "business",
-		Balance:        50000,
 	}
+	account := &models.Account{Category: "business", Balance: money.New(50000, 0)}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO customers`).
+		WithArgs(person.CNPJ, person.AnnualRevenue, person.Age, person.TradeName, person.PhoneNumber, person.CorporateEmail, person.PasswordHash).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery(`SELECT nextval`).WillReturnRows(sqlmock.NewRows([]string{"nextval"}).AddRow(11))
+	mock.ExpectQuery(`INSERT INTO accounts`).
+		WithArgs(2, "0001", "00000011", "4", account.Category, account.Balance, "BRL").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(11, "active", time.Now()))
+	expectJournalEntry(mock, "opening",
+		expectedPosting{ledger.EquityAccountID, "debit", account.Balance},
+		expectedPosting{11, "credit", account.Balance})
+	expectTransaction(mock, 11, "opening", "credit", account.Balance, account.Balance)
+	expectEvent(mock, "account.created", 11, 0)
+	expectAudit(mock, "create_account", models.Actor{CustomerID: 2}, 11, 0, "",
+		`[{"account_id": 11, "balance": 50000, "status": "active"}]`)
+	mock.ExpectCommit()
 
-	rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
+	err = repo.CreateLegalPerson(person, account, "")
 
-	mock.ExpectQuery(`INSERT INTO legal_person`).
-		WithArgs(person.AnnualRevenue, person.Age, person.TradeName, person.PhoneNumber, person.CorporateEmail, person.Category, person.Balance).
-		WillReturnRows(rows)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, person.ID)
+	assert.Equal(t, 11, account.ID)
 
-	err = repo.CreateLegalPerson(person)
+	// A customer can be registered without an account.
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO customers`).
+		WithArgs(person.CNPJ, person.AnnualRevenue, person.Age, person.TradeName, person.PhoneNumber, person.CorporateEmail, person.PasswordHash).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectCommit()
 
+	err = repo.CreateLegalPerson(person, nil, "")
 	assert.NoError(t, err)
-	assert.Equal(t, 1, person.ID)
+	assert.Equal(t, 3, person.ID)
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 }
 
-func TestPsqlAccountRepository_GetAccountBalance(t *testing.T) {
+func TestPsqlAccountRepository_CreateCustomer_DuplicateTaxID(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
@@ -89,24 +139,167 @@ func TestPsqlAccountRepository_GetAccountBalance(t *testing.T) {
 
 	repo := &PsqlAccountRepository{DB: db}
 
-	// Test for natural person
-	rows := sqlmock.NewRows([]string{"balance"}).AddRow(123.45)
-	mock.ExpectQuery("SELECT balance FROM natural_person").WithArgs(1).WillReturnRows(rows)
-	balance, err := repo.GetAccountBalance(1, "natural")
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO customers`).WillReturnError(&pq.Error{Code: "23505", Constraint: "customers_cpf_key"})
+	mock.ExpectRollback()
+
+	err = repo.CreateNaturalPerson(&models.NaturalPerson{CPF: "52998224725"}, &models.Account{}, "")
+	assert.ErrorIs(t, err, ErrDuplicateCPF)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO customers`).WillReturnError(&pq.Error{Code: "23505", Constraint: "customers_cnpj_key"})
+	mock.ExpectRollback()
+
+	err = repo.CreateLegalPerson(&models.LegalPerson{CNPJ: "11222333000181"}, &models.Account{}, "")
+	assert.ErrorIs(t, err, ErrDuplicateCNPJ)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_CreateAccount(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db, Branch: "0042"}
+
+	// A zero opening balance is not journaled.
+	account := &models.Account{CustomerID: 1, Category: "savings"}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT nextval`).WillReturnRows(sqlmock.NewRows([]string{"nextval"}).AddRow(12))
+	mock.ExpectQuery(`INSERT INTO accounts`).
+		WithArgs(1, "0042", "00000012", "0", "savings", money.Money(0), "BRL").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(12, "active", time.Now()))
+	expectEvent(mock, "account.created", 12, 0)
+	expectAudit(mock, "create_account", models.Actor{OperatorID: 3}, 12, 0, "", `[{"account_id": 12, "balance": 0, "status": "active"}]`)
+	mock.ExpectCommit()
+
+	err = repo.CreateAccount(account, models.Actor{OperatorID: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, 12, account.ID)
+	assert.Equal(t, "0042-00000012-0", account.AccountNumber())
+
+	// Unknown customers violate the foreign key.
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT nextval`).WillReturnRows(sqlmock.NewRows([]string{"nextval"}).AddRow(13))
+	mock.ExpectQuery(`INSERT INTO accounts`).
+		WithArgs(99, "0042", "00000013", sqlmock.AnyArg(), "savings", money.Money(0), "BRL").
+		WillReturnError(&pq.Error{Code: "23503"})
+	mock.ExpectRollback()
+
+	err = repo.CreateAccount(&models.Account{CustomerID: 99, Category: "savings"}, models.Actor{})
+	assert.ErrorIs(t, err, ErrCustomerNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_GetCustomerAccounts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM customers`).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`FROM accounts WHERE customer_id = \$1`).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(accountColumnNames).
+			AddRow(10, 1, "0001", "00000010", "6", "standard", "1000.00", "BRL", "active", createdAt, nil).
+			AddRow(12, 1, "0001", "00000012", "2", "savings", "0.00", "BRL", "active", createdAt, nil))
+
+	accounts, err := repo.GetCustomerAccounts(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Account{
+		{ID: 10, CustomerID: 1, Branch: "0001", Number: "00000010", CheckDigit: "6", Category: "standard", Balance: money.New(1000, 0), Currency: "BRL", Status: "active", CreatedAt: createdAt},
+		{ID: 12, CustomerID: 1, Branch: "0001", Number: "00000012", CheckDigit: "2", Category: "savings", Balance: 0, Currency: "BRL", Status: "active", CreatedAt: createdAt},
+	}, accounts)
+
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM customers`).WithArgs(99).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	_, err = repo.GetCustomerAccounts(99)
+	assert.ErrorIs(t, err, ErrCustomerNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+var accountColumnNames = []string{"id", "customer_id", "branch", "number", "check_digit", "category", "balance", "currency", "status", "created_at", "closed_at"}
+
+// lockRows is the row returned when an account with no held funds is
+// locked for update.
+func lockRows(balance interface{}, status string) *sqlmock.Rows {
+	return heldLockRows(balance, status, "0")
+}
+
+func heldLockRows(balance interface{}, status string, held interface{}) *sqlmock.Rows {
+	return overdraftLockRows(balance, status, held, "0")
+}
+
+func overdraftLockRows(balance interface{}, status string, held, overdraft interface{}) *sqlmock.Rows {
+	return currencyLockRows(balance, status, held, overdraft, "BRL")
+}
+
+func currencyLockRows(balance interface{}, status string, held, overdraft interface{}, currency string) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"balance", "status", "held", "overdraft", "currency"}).AddRow(balance, status, held, overdraft, currency)
+}
+
+func TestPsqlAccountRepository_GetAccountByNumber(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`FROM accounts WHERE branch = \$1 AND number = \$2 AND check_digit = \$3`).
+		WithArgs("0001", "00000010", "6").
+		WillReturnRows(sqlmock.NewRows(accountColumnNames).AddRow(10, 1, "0001", "00000010", "6", "standard", "1000.00", "BRL", "active", createdAt, nil))
+
+	account, err := repo.GetAccountByNumber(accountnumber.Number{Branch: "0001", Account: "00000010", CheckDigit: "6"})
 	assert.NoError(t, err)
-	assert.Equal(t, 123.45, balance)
+	assert.Equal(t, 10, account.ID)
+	assert.Equal(t, money.New(1000, 0), account.Balance)
+
+	mock.ExpectQuery(`FROM accounts WHERE branch`).WithArgs("0001", "00000099", "8").WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetAccountByNumber(accountnumber.Number{Branch: "0001", Account: "00000099", CheckDigit: "8"})
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_GetAccountBalance(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
 
-	// Test for legal person
-	rows = sqlmock.NewRows([]string{"balance"}).AddRow(543.21)
-	mock.ExpectQuery("SELECT balance FROM legal_person").WithArgs(1).WillReturnRows(rows)
-	balance, err = repo.GetAccountBalance(1, "legal")
+	repo := &PsqlAccountRepository{DB: db}
+
+	rows := sqlmock.NewRows([]string{"balance"}).AddRow(123.45)
+	mock.ExpectQuery("SELECT balance FROM accounts").WithArgs(10).WillReturnRows(rows)
+	balance, err := repo.GetAccountBalance(10)
 	assert.NoError(t, err)
-	assert.Equal(t, 543.21, balance)
+	assert.Equal(t, money.FromCents(12345), balance)
 
 	// Test for not found
-	mock.ExpectQuery("SELECT balance FROM natural_person").WithArgs(1).WillReturnError(sql.ErrNoRows)
-	_, err = repo.GetAccountBalance(1, "natural")
-	assert.Error(t, err)
+	mock.ExpectQuery("SELECT balance FROM accounts").WithArgs(99).WillReturnError(sql.ErrNoRows)
+	_, err = repo.GetAccountBalance(99)
+	assert.ErrorIs(t, err, ErrAccountNotFound)
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
@@ -122,12 +315,38 @@ func TestPsqlAccountRepository_UpdateAccountBalance(t *testing.T) {
 
 	repo := &PsqlAccountRepository{DB: db}
 
-	mock.ExpectExec("UPDATE natural_person").WithArgs(200.0, 1).WillReturnResult(sqlmock.NewResult(1, 1))
-	err = repo.UpdateAccountBalance(1, 200.0, "natural")
+	// The difference is journaled as an adjustment against equity.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows("150.00", "active"))
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(200, 0), 10).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectJournalEntry(mock, "adjustment",
+		expectedPosting{ledger.EquityAccountID, "debit", money.New(50, 0)},
+		expectedPosting{10, "credit", money.New(50, 0)})
+	expectTransaction(mock, 10, "adjustment", "credit", money.New(50, 0), money.New(200, 0))
+	expectAudit(mock, "correct_balance", models.Actor{OperatorID: 7, RequestID: "req-1"}, 10, 0,
+		`[{"account_id": 10, "balance": 150}]`, `[{"account_id": 10, "balance": 200}]`)
+	mock.ExpectCommit()
+	err = repo.UpdateAccountBalance(10, money.New(200, 0), models.Actor{OperatorID: 7, RequestID: "req-1"})
+	assert.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows("1500.00", "active"))
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(1000, 0), 11).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectJournalEntry(mock, "adjustment",
+		expectedPosting{11, "debit", money.New(500, 0)},
+		expectedPosting{ledger.EquityAccountID, "credit", money.New(500, 0)})
+	expectTransaction(mock, 11, "adjustment", "debit", money.New(500, 0), money.New(1000, 0))
+	expectAudit(mock, "correct_balance", models.Actor{OperatorID: 7}, 11, 0,
+		`[{"account_id": 11, "balance": 1500}]`, `[{"account_id": 11, "balance": 1000}]`)
+	mock.ExpectCommit()
+	err = repo.UpdateAccountBalance(11, money.New(1000, 0), models.Actor{OperatorID: 7})
 	assert.NoError(t, err)
 
-	mock.ExpectExec("UPDATE legal_person").WithArgs(1000.0, 1).WillReturnResult(sqlmock.NewResult(1, 1))
-	err = repo.UpdateAccountBalance(1, 1000.0, "legal")
+	// No change means no journal entry or audit record.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows("1000.00", "active"))
+	mock.ExpectCommit()
+	err = repo.UpdateAccountBalance(11, money.New(1000, 0), models.Actor{OperatorID: 7})
 	assert.NoError(t, err)
 
 	if err := mock.ExpectationsWereMet(); err != nil {
@@ -135,7 +354,7 @@ func TestPsqlAccountRepository_UpdateAccountBalance(t *testing.T) {
 	}
 }
 
-func TestPsqlAccountRepository_DeleteAccount(t *testing.T) {
+func TestPsqlAccountRepository_ChangeAccountStatus(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
@@ -143,14 +362,216 @@ func TestPsqlAccountRepository_DeleteAccount(t *testing.T) {
 	defer db.Close()
 
 	repo := &PsqlAccountRepository{DB: db}
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
 
-	mock.ExpectExec("DELETE FROM natural_person").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
-	err = repo.DeleteAccount(1, "natural")
-	assert.NoError(t, err)
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT status, balance FROM accounts WHERE id = \\$1 AND customer_id IS NOT NULL FOR UPDATE").WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"status", "balance"}).AddRow("active", "80.00"))
+	mock.ExpectExec("UPDATE accounts SET status = \\$2 WHERE id = \\$1").WithArgs(10, "frozen").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO account_status_changes").
+		WithArgs(10, "active", "frozen", "court order", 0, 7, nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, createdAt))
+	expectAudit(mock, "change_status", models.Actor{OperatorID: 7}, 10, 0,
+		`[{"account_id": 10, "balance": 80, "status": "active"}]`, `[{"account_id": 10, "balance": 80, "status": "frozen"}]`)
+	mock.ExpectCommit()
+
+	change := &models.AccountStatusChange{AccountID: 10, ToStatus: "frozen", Reason: "court order", ChangedByOperatorID: 7}
+	assert.NoError(t, repo.ChangeAccountStatus(change, models.Actor{OperatorID: 7}))
+	assert.Equal(t, int64(3), change.ID)
+	assert.Equal(t, "active", change.FromStatus)
+	assert.Equal(t, createdAt, change.CreatedAt)
+
+	// A frozen account must be unfrozen before anything else.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT status, balance FROM accounts").WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"status", "balance"}).AddRow("frozen", "80.00"))
+	mock.ExpectRollback()
+
+	err = repo.ChangeAccountStatus(&models.AccountStatusChange{AccountID: 10, ToStatus: "dormant", Reason: "x"}, models.Actor{OperatorID: 7})
+	assert.ErrorIs(t, err, ErrStatusTransition)
+
+	// Closed accounts never change again.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT status, balance FROM accounts").WithArgs(11).
+		WillReturnRows(sqlmock.NewRows([]string{"status", "balance"}).AddRow("closed", "0"))
+	mock.ExpectRollback()
+
+	err = repo.ChangeAccountStatus(&models.AccountStatusChange{AccountID: 11, ToStatus: "active", Reason: "x"}, models.Actor{OperatorID: 7})
+	assert.ErrorIs(t, err, ErrAccountClosed)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT status, balance FROM accounts").WithArgs(99).WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	err = repo.ChangeAccountStatus(&models.AccountStatusChange{AccountID: 99, ToStatus: "frozen", Reason: "x"}, models.Actor{OperatorID: 7})
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_CloseAccount(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// The balance is paid out as a transfer and the account is kept.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT amount FROM interest_accruals").WithArgs(10, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"amount"}))
+	mock.ExpectExec("UPDATE interest_accruals SET paid_at").WithArgs(10, sqlmock.AnyArg(), nil).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows("120.00", "active"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows("30.00", "active"))
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.Zero, 10).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(150, 0), 11).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectJournalEntry(mock, "transfer",
+		expectedPosting{10, "debit", money.New(120, 0)},
+		expectedPosting{11, "credit", money.New(120, 0)})
+	expectTransaction(mock, 10, "transfer", "debit", money.New(120, 0), money.Zero)
+	expectTransaction(mock, 11, "transfer", "credit", money.New(120, 0), money.New(150, 0))
+	mock.ExpectExec("UPDATE accounts SET status = 'closed', closed_at = now\\(\\) WHERE id = \\$1").WithArgs(10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE scheduled_transfers SET status = 'cancelled'").WithArgs(10).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO account_status_changes").
+		WithArgs(10, "active", "closed", "customer request", 1, 0, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(4, createdAt))
+	expectEvent(mock, "account.closed", 10, 11)
+	expectAudit(mock, "close_account", models.Actor{CustomerID: 1}, 10, 11,
+		`[{"account_id": 10, "balance": 120, "status": "active"}, {"account_id": 11, "balance": 30}]`,
+		`[{"account_id": 10, "balance": 0, "status": "closed"}, {"account_id": 11, "balance": 150}]`)
+	mock.ExpectCommit()
+
+	change := &models.AccountStatusChange{AccountID: 10, Reason: "customer request", ChangedByCustomerID: 1}
+	assert.NoError(t, repo.CloseAccount(change, 11, models.Actor{CustomerID: 1}))
+	assert.Equal(t, "closed", change.ToStatus)
+	assert.Equal(t, 11, *change.PayoutAccountID)
+	assert.Equal(t, money.New(120, 0), *change.PayoutAmount)
+
+	// Interest paid on the way out is audited before the closing.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT amount FROM interest_accruals").WithArgs(12, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"amount"}).AddRow("0.50"))
+	expectJournalEntry(mock, "interest",
+		expectedPosting{ledger.EquityAccountID, "debit", money.FromCents(50)},
+		expectedPosting{12, "credit", money.FromCents(50)})
+	mock.ExpectQuery("UPDATE accounts SET balance = balance \\+ \\$1").WithArgs(money.FromCents(50), 12).
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("0.50"))
+	expectTransaction(mock, 12, "interest", "credit", money.FromCents(50), money.FromCents(50))
+	mock.ExpectExec("UPDATE interest_accruals SET paid_at").WithArgs(12, sqlmock.AnyArg(), int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows("150.00", "active"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(12).WillReturnRows(lockRows("0.50", "active"))
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.Zero, 12).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.FromCents(15050), 11).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectJournalEntry(mock, "transfer",
+		expectedPosting{12, "debit", money.FromCents(50)},
+		expectedPosting{11, "credit", money.FromCents(50)})
+	expectTransaction(mock, 12, "transfer", "debit", money.FromCents(50), money.Zero)
+	expectTransaction(mock, 11, "transfer", "credit", money.FromCents(50), money.FromCents(15050))
+	mock.ExpectExec("UPDATE accounts SET status = 'closed'").WithArgs(12).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE scheduled_transfers SET status = 'cancelled'").WithArgs(12).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("INSERT INTO account_status_changes").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, createdAt))
+	expectEvent(mock, "account.closed", 12, 11)
+	expectAudit(mock, "pay_interest", models.Actor{CustomerID: 1}, 12, 0,
+		`[{"account_id": 12, "balance": 0}]`, `[{"account_id": 12, "balance": 0.5}]`)
+	expectAudit(mock, "close_account", models.Actor{CustomerID: 1}, 12, 11,
+		`[{"account_id": 12, "balance": 0.5, "status": "active"}, {"account_id": 11, "balance": 150}]`,
+		`[{"account_id": 12, "balance": 0, "status": "closed"}, {"account_id": 11, "balance": 150.5}]`)
+	mock.ExpectCommit()
+
+	change = &models.AccountStatusChange{AccountID: 12, Reason: "customer request", ChangedByCustomerID: 1}
+	assert.NoError(t, repo.CloseAccount(change, 11, models.Actor{CustomerID: 1}))
+
+	// A balance with nowhere to go.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT amount FROM interest_accruals").WithArgs(10, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"amount"}))
+	mock.ExpectExec("UPDATE interest_accruals SET paid_at").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows("0.01", "active"))
+	mock.ExpectRollback()
+
+	err = repo.CloseAccount(&models.AccountStatusChange{AccountID: 10, Reason: "x"}, 0, models.Actor{})
+	assert.ErrorIs(t, err, ErrBalanceRemaining)
+
+	// The balance is not converted on the way out.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT amount FROM interest_accruals").WithArgs(10, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"amount"}))
+	mock.ExpectExec("UPDATE interest_accruals SET paid_at").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows("20.00", "active"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(13).WillReturnRows(currencyLockRows("0", "active", "0", "0", "USD"))
+	mock.ExpectRollback()
+
+	err = repo.CloseAccount(&models.AccountStatusChange{AccountID: 10, Reason: "x"}, 13, models.Actor{})
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	// Held funds and overdrafts must be settled first.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT amount FROM interest_accruals").WithArgs(10, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"amount"}))
+	mock.ExpectExec("UPDATE interest_accruals SET paid_at").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(heldLockRows("50.00", "active", "10.00"))
+	mock.ExpectRollback()
+
+	err = repo.CloseAccount(&models.AccountStatusChange{AccountID: 10, Reason: "x"}, 0, models.Actor{})
+	assert.ErrorIs(t, err, ErrFundsOnHold)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT amount FROM interest_accruals").WithArgs(10, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"amount"}))
+	mock.ExpectExec("UPDATE interest_accruals SET paid_at").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows("-5.00", "active"))
+	mock.ExpectRollback()
 
-	mock.ExpectExec("DELETE FROM legal_person").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
-	err = repo.DeleteAccount(1, "legal")
+	err = repo.CloseAccount(&models.AccountStatusChange{AccountID: 10, Reason: "x"}, 0, models.Actor{})
+	assert.ErrorIs(t, err, ErrNegativeBalance)
+
+	// Frozen accounts are not closed.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT amount FROM interest_accruals").WithArgs(10, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"amount"}))
+	mock.ExpectExec("UPDATE interest_accruals SET paid_at").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows("0", "frozen"))
+	mock.ExpectRollback()
+
+	err = repo.CloseAccount(&models.AccountStatusChange{AccountID: 10, Reason: "x"}, 0, models.Actor{})
+	assert.ErrorIs(t, err, ErrStatusTransition)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_MarkDormantAccounts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+	since := time.Date(2025, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	// Each account marked is audited as changed by the dormancy job.
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE accounts SET status = 'dormant'.*INSERT INTO account_status_changes.*SELECT id, balance FROM dormant").
+		WithArgs(since, "no customer activity").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow(10, "25.00").AddRow(12, "0"))
+	expectAudit(mock, "change_status", models.Actor{System: "dormancy"}, 10, 0,
+		`[{"account_id": 10, "balance": 25, "status": "active"}]`, `[{"account_id": 10, "balance": 25, "status": "dormant"}]`)
+	expectAudit(mock, "change_status", models.Actor{System: "dormancy"}, 12, 0,
+		`[{"account_id": 12, "balance": 0, "status": "active"}]`, `[{"account_id": 12, "balance": 0, "status": "dormant"}]`)
+	mock.ExpectCommit()
+
+	n, err := repo.MarkDormantAccounts(since, "no customer activity")
 	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
@@ -166,25 +587,1615 @@ func TestPsqlAccountRepository_TransferTx(t *testing.T) {
 
 	repo := &PsqlAccountRepository{DB: db}
 
+	// Rows are locked in ID order: 10 before 11.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows(1000.0, "active"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows(500.0, "active"))
+	expectFee(mock, "transfer", money.Zero, 11, 10)
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(400, 0), 11).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(1100, 0), 10).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectJournalEntry(mock, "transfer",
+		expectedPosting{11, "debit", money.New(100, 0)},
+		expectedPosting{10, "credit", money.New(100, 0)})
+	expectTransaction(mock, 11, "transfer", "debit", money.New(100, 0), money.New(400, 0))
+	expectTransaction(mock, 10, "transfer", "credit", money.New(100, 0), money.New(1100, 0))
+	expectEvent(mock, "transfer.completed", 11, 10)
+	expectAudit(mock, "transfer", models.Actor{CustomerID: 2, RequestID: "req-1"}, 11, 10,
+		`[{"account_id": 11, "balance": 500}, {"account_id": 10, "balance": 1000}]`,
+		`[{"account_id": 11, "balance": 400}, {"account_id": 10, "balance": 1100}]`)
+	mock.ExpectCommit()
+
+	err = repo.TransferTx(11, 10, money.New(100, 0), 0, models.Actor{CustomerID: 2, RequestID: "req-1"})
+	assert.NoError(t, err)
+
+	// The opposite transfer takes the locks in the same order.
 	mock.ExpectBegin()
-	mock.ExpectQuery("SELECT balance FROM natural_person").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(500.0))
-	mock.ExpectQuery("SELECT balance FROM legal_person").WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(1000.0))
-	mock.ExpectExec("UPDATE natural_person").WithArgs(400.0, 1).WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectExec("UPDATE legal_person").WithArgs(1100.0, 2).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows(1100.0, "active"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows(400.0, "active"))
+	expectFee(mock, "transfer", money.Zero, 10, 11)
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(1050, 0), 10).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(450, 0), 11).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectJournalEntry(mock, "transfer",
+		expectedPosting{10, "debit", money.New(50, 0)},
+		expectedPosting{11, "credit", money.New(50, 0)})
+	expectTransaction(mock, 10, "transfer", "debit", money.New(50, 0), money.New(1050, 0))
+	expectTransaction(mock, 11, "transfer", "credit", money.New(50, 0), money.New(450, 0))
+	expectEvent(mock, "transfer.completed", 10, 11)
+	expectAudit(mock, "transfer", models.Actor{}, 10, 11,
+		`[{"account_id": 10, "balance": 1100}, {"account_id": 11, "balance": 400}]`,
+		`[{"account_id": 10, "balance": 1050}, {"account_id": 11, "balance": 450}]`)
 	mock.ExpectCommit()
 
-	err = repo.TransferTx(1, 2, 100.0, "natural", "legal")
+	err = repo.TransferTx(10, 11, money.New(50, 0), 0, models.Actor{})
 	assert.NoError(t, err)
 
 	// Test insufficient funds
 	mock.ExpectBegin()
-	mock.ExpectQuery("SELECT balance FROM natural_person").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(50.0))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows(1000.0, "active"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows(50.0, "active"))
+	expectFee(mock, "transfer", money.Zero, 11, 10)
+	mock.ExpectRollback()
+
+	err = repo.TransferTx(11, 10, money.New(100, 0), 0, models.Actor{})
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// Unknown accounts
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows(1000.0, "active"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(99).WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	err = repo.TransferTx(10, 99, money.New(100, 0), 0, models.Actor{})
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+
+	// A frozen account cannot send money, but can still receive it.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows(1000.0, "frozen"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows(500.0, "active"))
+	expectFee(mock, "transfer", money.Zero, 10, 11)
+	mock.ExpectRollback()
+
+	err = repo.TransferTx(10, 11, money.New(100, 0), 0, models.Actor{})
+	assert.ErrorIs(t, err, ErrAccountFrozen)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_TransferTx_RetriesDeadlocks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db, TxRetry: RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}}
+	retries := txRetryCount("transfer.retries")
+	deadlocks := txRetryCount("transfer.deadlocks")
+	serialization := txRetryCount("transfer.serialization_failures")
+
+	// First attempt deadlocks, second hits a serialization failure, third succeeds.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnError(&pq.Error{Code: "40P01"})
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows("500.00", "active"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows("0.00", "active"))
+	expectFee(mock, "transfer", money.Zero, 10, 11)
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(400, 0), 10).WillReturnError(&pq.Error{Code: "40001"})
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows("500.00", "active"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows("0.00", "active"))
+	expectFee(mock, "transfer", money.Zero, 10, 11)
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(400, 0), 10).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(100, 0), 11).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectJournalEntry(mock, "transfer",
+		expectedPosting{10, "debit", money.New(100, 0)},
+		expectedPosting{11, "credit", money.New(100, 0)})
+	expectTransaction(mock, 10, "transfer", "debit", money.New(100, 0), money.New(400, 0))
+	expectTransaction(mock, 11, "transfer", "credit", money.New(100, 0), money.New(100, 0))
+	expectEvent(mock, "transfer.completed", 10, 11)
+	expectAudit(mock, "transfer", models.Actor{}, 10, 11,
+		`[{"account_id": 10, "balance": 500}, {"account_id": 11, "balance": 0}]`,
+		`[{"account_id": 10, "balance": 400}, {"account_id": 11, "balance": 100}]`)
+	mock.ExpectCommit()
+
+	err = repo.TransferTx(10, 11, money.New(100, 0), 0, models.Actor{})
+	assert.NoError(t, err)
+	assert.Equal(t, retries+2, txRetryCount("transfer.retries"))
+	assert.Equal(t, deadlocks+1, txRetryCount("transfer.deadlocks"))
+	assert.Equal(t, serialization+1, txRetryCount("transfer.serialization_failures"))
+
+	// Attempts run out.
+	exhausted := txRetryCount("transfer.exhausted")
+	for i := 0; i < 3; i++ {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnError(&pq.Error{Code: "40P01"})
+		mock.ExpectRollback()
+	}
+
+	err = repo.TransferTx(10, 11, money.New(100, 0), 0, models.Actor{})
+	assert.Error(t, err)
+	assert.Equal(t, exhausted+1, txRetryCount("transfer.exhausted"))
+
+	// Other errors are not retried.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnError(&pq.Error{Code: "23505"})
 	mock.ExpectRollback()
 
-	err = repo.TransferTx(1, 2, 100.0, "natural", "legal")
+	err = repo.TransferTx(10, 11, money.New(100, 0), 0, models.Actor{})
 	assert.Error(t, err)
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 }
+
+func txRetryCount(key string) int64 {
+	if v, ok := TxRetryStats.Get(key).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func TestPsqlAccountRepository_DepositTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+
+	// Deposit credits the customer and debits cash.
+	mock.ExpectBegin()
+	expectJournalEntry(mock, "deposit",
+		expectedPosting{ledger.CashAccountID, "debit", money.New(100, 0)},
+		expectedPosting{10, "credit", money.New(100, 0)})
+	mock.ExpectQuery("UPDATE accounts SET balance = balance \\+ \\$1").WithArgs(money.New(100, 0), 10).WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("100.00"))
+	expectTransaction(mock, 10, "deposit", "credit", money.New(100, 0), money.New(100, 0))
+	mock.ExpectExec("INSERT INTO outbox").
+		WithArgs("deposit.completed", 10, 0, jsonArg(`{"journal_entry_id": 1, "account_id": 10, "amount": 100.00, "currency": "BRL", "fee": 0.00}`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectAudit(mock, "deposit", models.Actor{OperatorID: 3, RequestID: "req-2"}, 10, 0,
+		`[{"account_id": 10, "balance": 0}]`, `[{"account_id": 10, "balance": 100}]`)
+	mock.ExpectCommit()
+
+	err = repo.DepositTx(10, money.New(100, 0), models.Actor{OperatorID: 3, RequestID: "req-2"})
+	assert.NoError(t, err)
+
+	// Unknown account rolls the entry back.
+	mock.ExpectBegin()
+	expectJournalEntry(mock, "deposit",
+		expectedPosting{ledger.CashAccountID, "debit", money.New(1, 0)},
+		expectedPosting{99, "credit", money.New(1, 0)})
+	mock.ExpectQuery("UPDATE accounts").WithArgs(money.New(1, 0), 99).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT status FROM accounts").WithArgs(99).WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	err = repo.DepositTx(99, money.New(1, 0), models.Actor{})
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+
+	// Closed accounts take no deposits.
+	mock.ExpectBegin()
+	expectJournalEntry(mock, "deposit",
+		expectedPosting{ledger.CashAccountID, "debit", money.New(1, 0)},
+		expectedPosting{12, "credit", money.New(1, 0)})
+	mock.ExpectQuery("UPDATE accounts SET balance = balance \\+ \\$1 WHERE id = \\$2 AND customer_id IS NOT NULL AND status <> 'closed'").
+		WithArgs(money.New(1, 0), 12).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT status FROM accounts").WithArgs(12).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("closed"))
+	mock.ExpectRollback()
+
+	err = repo.DepositTx(12, money.New(1, 0), models.Actor{})
+	assert.ErrorIs(t, err, ErrAccountClosed)
+
+	// Unbalanced entries never reach the database.
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	_, err = postEntryTx(tx, &models.JournalEntry{Kind: "deposit", Postings: []models.Posting{
+		{AccountID: 10, Direction: "credit", Amount: money.New(2, 0)},
+		{AccountID: ledger.CashAccountID, Direction: "debit", Amount: money.New(1, 0)},
+	}})
+	assert.ErrorIs(t, err, ledger.ErrUnbalancedEntry)
+	assert.NoError(t, tx.Rollback())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_WithdrawTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+
+	// The funds check is part of the UPDATE itself.
+	mock.ExpectBegin()
+	expectJournalEntry(mock, "withdrawal",
+		expectedPosting{10, "debit", money.New(30, 0)},
+		expectedPosting{ledger.CashAccountID, "credit", money.New(30, 0)})
+	mock.ExpectQuery("UPDATE accounts SET balance = balance - \\$1 WHERE id = \\$2 AND customer_id IS NOT NULL AND status = 'active' AND balance - .* >= \\$1").
+		WithArgs(money.New(30, 0), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("70.00"))
+	expectTransaction(mock, 10, "withdrawal", "debit", money.New(30, 0), money.New(70, 0))
+	expectFee(mock, "withdrawal", money.FromCents(250), 10)
+	expectJournalEntry(mock, "withdrawal_fee",
+		expectedPosting{10, "debit", money.FromCents(250)},
+		expectedPosting{ledger.EquityAccountID, "credit", money.FromCents(250)})
+	mock.ExpectQuery("UPDATE accounts SET balance = balance - \\$1").
+		WithArgs(money.FromCents(250), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("67.50"))
+	expectTransaction(mock, 10, "withdrawal_fee", "debit", money.FromCents(250), money.FromCents(6750))
+	expectEvent(mock, "withdrawal.completed", 10, 0)
+	expectAudit(mock, "withdraw", models.Actor{CustomerID: 1}, 10, 0,
+		`[{"account_id": 10, "balance": 100}]`, `[{"account_id": 10, "balance": 67.50}]`)
+	mock.ExpectCommit()
+
+	err = repo.WithdrawTx(10, money.New(30, 0), models.Actor{CustomerID: 1})
+	assert.NoError(t, err)
+
+	// The account has the fee waived.
+	mock.ExpectBegin()
+	expectJournalEntry(mock, "withdrawal",
+		expectedPosting{10, "debit", money.New(30, 0)},
+		expectedPosting{ledger.CashAccountID, "credit", money.New(30, 0)})
+	mock.ExpectQuery("UPDATE accounts SET balance = balance - \\$1").
+		WithArgs(money.New(30, 0), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("37.50"))
+	expectTransaction(mock, 10, "withdrawal", "debit", money.New(30, 0), money.FromCents(3750))
+	expectFee(mock, "withdrawal", money.Zero, 10)
+	expectEvent(mock, "withdrawal.completed", 10, 0)
+	expectAudit(mock, "withdraw", models.Actor{CustomerID: 1}, 10, 0,
+		`[{"account_id": 10, "balance": 67.50}]`, `[{"account_id": 10, "balance": 37.50}]`)
+	mock.ExpectCommit()
+
+	err = repo.WithdrawTx(10, money.New(30, 0), models.Actor{CustomerID: 1})
+	assert.NoError(t, err)
+
+	// The balance covers the withdrawal but not its fee: neither happens.
+	mock.ExpectBegin()
+	expectJournalEntry(mock, "withdrawal",
+		expectedPosting{10, "debit", money.New(30, 0)},
+		expectedPosting{ledger.CashAccountID, "credit", money.New(30, 0)})
+	mock.ExpectQuery("UPDATE accounts SET balance = balance - \\$1").
+		WithArgs(money.New(30, 0), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("0.00"))
+	expectTransaction(mock, 10, "withdrawal", "debit", money.New(30, 0), money.Zero)
+	expectFee(mock, "withdrawal", money.FromCents(250), 10)
+	expectJournalEntry(mock, "withdrawal_fee",
+		expectedPosting{10, "debit", money.FromCents(250)},
+		expectedPosting{ledger.EquityAccountID, "credit", money.FromCents(250)})
+	mock.ExpectQuery("UPDATE accounts SET balance = balance - \\$1").WithArgs(money.FromCents(250), 10).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT status FROM accounts").WithArgs(10).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("active"))
+	mock.ExpectRollback()
+
+	err = repo.WithdrawTx(10, money.New(30, 0), models.Actor{})
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// No row matched but the account exists: insufficient funds.
+	mock.ExpectBegin()
+	expectJournalEntry(mock, "withdrawal",
+		expectedPosting{11, "debit", money.New(500, 0)},
+		expectedPosting{ledger.CashAccountID, "credit", money.New(500, 0)})
+	mock.ExpectQuery("UPDATE accounts SET balance = balance - \\$1").WithArgs(money.New(500, 0), 11).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT status FROM accounts").WithArgs(11).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("active"))
+	mock.ExpectRollback()
+
+	err = repo.WithdrawTx(11, money.New(500, 0), models.Actor{})
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// No row matched because the account is frozen.
+	mock.ExpectBegin()
+	expectJournalEntry(mock, "withdrawal",
+		expectedPosting{12, "debit", money.New(5, 0)},
+		expectedPosting{ledger.CashAccountID, "credit", money.New(5, 0)})
+	mock.ExpectQuery("UPDATE accounts SET balance = balance - \\$1").WithArgs(money.New(5, 0), 12).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT status FROM accounts").WithArgs(12).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("frozen"))
+	mock.ExpectRollback()
+
+	err = repo.WithdrawTx(12, money.New(5, 0), models.Actor{})
+	assert.ErrorIs(t, err, ErrAccountFrozen)
+
+	// Dormant accounts must be reactivated first.
+	mock.ExpectBegin()
+	expectJournalEntry(mock, "withdrawal",
+		expectedPosting{13, "debit", money.New(5, 0)},
+		expectedPosting{ledger.CashAccountID, "credit", money.New(5, 0)})
+	mock.ExpectQuery("UPDATE accounts SET balance = balance - \\$1").WithArgs(money.New(5, 0), 13).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT status FROM accounts").WithArgs(13).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("dormant"))
+	mock.ExpectRollback()
+
+	err = repo.WithdrawTx(13, money.New(5, 0), models.Actor{})
+	assert.ErrorIs(t, err, ErrAccountDormant)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+type expectedPosting struct {
+	accountID int
+	direction string
+	amount    money.Money
+}
+
+// expectJournalEntry expects an entry in BRL, the currency of the first
+// customer account among the postings.
+func expectJournalEntry(mock sqlmock.Sqlmock, kind string, postings ...expectedPosting) {
+	for _, p := range postings {
+		if !ledger.IsSystemAccount(p.accountID) {
+			mock.ExpectQuery("SELECT currency FROM accounts").WithArgs(p.accountID).
+				WillReturnRows(sqlmock.NewRows([]string{"currency"}).AddRow("BRL"))
+			break
+		}
+	}
+	mock.ExpectQuery("INSERT INTO journal_entries").
+		WithArgs(kind, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
+	for i, p := range postings {
+		mock.ExpectQuery("INSERT INTO postings").
+			WithArgs(1, p.accountID, p.direction, p.amount, "BRL").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(i + 1))
+	}
+}
+
+// expectFee expects the query for the fee of feeType with args. A zero
+// fee returns no rows, as when the fee is waived.
+func expectFee(mock sqlmock.Sqlmock, feeType string, fee money.Money, args ...driver.Value) {
+	rows := sqlmock.NewRows([]string{"amount"})
+	if !fee.IsZero() {
+		rows.AddRow(fee.String())
+	}
+	mock.ExpectQuery("FROM fees.*fee_type = '" + feeType + "'").WithArgs(args...).WillReturnRows(rows)
+}
+
+// expectEvent expects an event of eventType about the account, and the
+// counterparty when it is not zero, to be written to the outbox.
+func expectEvent(mock sqlmock.Sqlmock, eventType string, accountID, counterpartyID int) {
+	mock.ExpectExec("INSERT INTO outbox").
+		WithArgs(eventType, accountID, counterpartyID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+// expectAudit expects a record of action by actor to be appended to an
+// empty audit log, with the account states before and after as JSON. An
+// empty before is written as null.
+func expectAudit(mock sqlmock.Sqlmock, action string, actor models.Actor, accountID, counterpartyID int, before, after string) {
+	var beforeArg driver.Value
+	if before != "" {
+		beforeArg = jsonArg(before)
+	}
+	mock.ExpectExec("INSERT INTO audit_log_pending").
+		WithArgs(action, actor.String(), actor.RequestID, accountID, counterpartyID, beforeArg, jsonArg(after), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+// jsonArg matches an argument holding the same JSON document.
+type jsonArg string
+
+func (a jsonArg) Match(v driver.Value) bool {
+	b, ok := v.([]byte)
+	if !ok {
+		return false
+	}
+	var got, want interface{}
+	if json.Unmarshal(b, &got) != nil || json.Unmarshal([]byte(a), &want) != nil {
+		return false
+	}
+	return reflect.DeepEqual(got, want)
+}
+
+func expectTransaction(mock sqlmock.Sqlmock, accountID int, kind, direction string, amount, balanceAfter money.Money) {
+	mock.ExpectExec("INSERT INTO account_transactions").
+		WithArgs(1, accountID, kind, direction, amount, balanceAfter, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func TestPsqlAccountRepository_GetTransactions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+
+	createdAt := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	columns := []string{"id", "journal_entry_id", "kind", "direction", "amount", "balance_after", "counterparty_id", "created_at",
+		"quote_id", "from_currency", "to_currency", "source_amount", "converted_amount", "mid_rate", "spread_bps", "rate"}
+
+	// Without filters only the account and the limit are bound.
+	mock.ExpectQuery(`FROM account_transactions LEFT JOIN fx_conversions .* WHERE account_id = \$1 ORDER BY id DESC LIMIT \$2`).
+		WithArgs(10, 10).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(9, 6, "transfer", "debit", "20.00", "75.00", 12, createdAt, 4, "BRL", "USD", "20.00", "3.66", "0.1841", 50, "0.18317950").
+			AddRow(8, 5, "transfer", "debit", "25.00", "95.00", 11, createdAt, nil, nil, nil, nil, nil, nil, nil, nil).
+			AddRow(3, 1, "deposit", "credit", "120.00", "120.00", nil, createdAt, nil, nil, nil, nil, nil, nil, nil, nil))
+
+	transactions, err := repo.GetTransactions(10, models.TransactionFilter{Limit: 10})
+	assert.NoError(t, err)
+	fxCounterpartyID, counterpartyID, quoteID := 12, 11, int64(4)
+	assert.Equal(t, []models.Transaction{
+		{ID: 9, JournalEntryID: 6, Kind: "transfer", Direction: "debit", Amount: money.New(20, 0), BalanceAfter: money.New(75, 0),
+			CounterpartyID: &fxCounterpartyID, CreatedAt: createdAt, FX: &models.FXConversion{
+				JournalEntryID: 6, QuoteID: &quoteID, FromCurrency: "BRL", ToCurrency: "USD", SourceAmount: money.New(20, 0),
+				ConvertedAmount: money.FromCents(366), MidRate: fx.MustParseRate("0.1841"), SpreadBps: 50, Rate: fx.MustParseRate("0.1831795"),
+			}},
+		{ID: 8, JournalEntryID: 5, Kind: "transfer", Direction: "debit", Amount: money.New(25, 0), BalanceAfter: money.New(95, 0),
+			CounterpartyID: &counterpartyID, CreatedAt: createdAt},
+		{ID: 3, JournalEntryID: 1, Kind: "deposit", Direction: "credit", Amount: money.New(120, 0), BalanceAfter: money.New(120, 0),
+			CreatedAt: createdAt},
+	}, transactions)
+
+	// Every filter adds a bound condition.
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	minAmount, maxAmount := money.New(10, 0), money.New(50, 0)
+	mock.ExpectQuery(`AND id < \$2 AND created_at >= \$3 AND created_at < \$4 AND direction = \$5 AND amount >= \$6 AND amount <= \$7 ORDER BY id DESC LIMIT \$8`).
+		WithArgs(10, int64(8), from, to, "debit", minAmount, maxAmount, 5).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	transactions, err = repo.GetTransactions(10, models.TransactionFilter{
+		BeforeID:  8,
+		From:      &from,
+		To:        &to,
+		Direction: "debit",
+		MinAmount: &minAmount,
+		MaxAmount: &maxAmount,
+		Limit:     5,
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, transactions)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+var approvalColumnNames = []string{"id", "from_account_id", "to_account_id", "amount", "fee", "status", "requested_by_customer_id",
+	"requested_by_operator_id", "reviewed_by", "review_reason", "expires_at", "created_at", "reviewed_at"}
+
+func TestPsqlAccountRepository_CreateTransferApproval(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+	expiresAt := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	createdAt := expiresAt.Add(-24 * time.Hour)
+
+	// The transfer fee is held along with the amount.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(heldLockRows("20000.00", "active", "4990.00"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows("0.00", "active"))
+	expectFee(mock, "transfer", money.New(10, 0), 10, 11)
+	mock.ExpectQuery("INSERT INTO transfer_approvals").
+		WithArgs(10, 11, money.New(15000, 0), money.New(10, 0), 3, 0, expiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(1, "pending", createdAt))
+	mock.ExpectCommit()
+
+	approval := &models.TransferApproval{FromAccountID: 10, ToAccountID: 11, Amount: money.New(15000, 0), RequestedByCustomerID: 3, ExpiresAt: expiresAt}
+	assert.NoError(t, repo.CreateTransferApproval(approval))
+	assert.Equal(t, int64(1), approval.ID)
+	assert.Equal(t, money.New(10, 0), approval.Fee)
+	assert.Equal(t, models.ApprovalPending, approval.Status)
+	assert.Equal(t, createdAt, approval.CreatedAt)
+
+	// The amount alone is covered, but not with the fee.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(heldLockRows("20000.00", "active", "5000.00"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows("0.00", "active"))
+	expectFee(mock, "transfer", money.New(10, 0), 10, 11)
+	mock.ExpectRollback()
+
+	approval = &models.TransferApproval{FromAccountID: 10, ToAccountID: 11, Amount: money.New(15000, 0), RequestedByCustomerID: 3, ExpiresAt: expiresAt}
+	assert.ErrorIs(t, repo.CreateTransferApproval(approval), ErrInsufficientFunds)
+
+	// Funds already held by another approval cannot be held twice.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(heldLockRows("20000.00", "active", "15000.00"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows("0.00", "active"))
+	expectFee(mock, "transfer", money.Zero, 10, 11)
+	mock.ExpectRollback()
+
+	approval = &models.TransferApproval{FromAccountID: 10, ToAccountID: 11, Amount: money.New(15000, 0), RequestedByOperatorID: 7, ExpiresAt: expiresAt}
+	assert.ErrorIs(t, repo.CreateTransferApproval(approval), ErrInsufficientFunds)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_CreateTransferApproval_Key(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+	expiresAt := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	createdAt := expiresAt.Add(-24 * time.Hour)
+	newApproval := func() *models.TransferApproval {
+		return &models.TransferApproval{FromAccountID: 10, ToAccountID: 11, Amount: money.New(15000, 0), RequestedByCustomerID: 3,
+			ExpiresAt: expiresAt, Key: "scheduled-transfer:1:2026-03-01"}
+	}
+
+	// The key is taken with the approval, and points at it.
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO transfer_requests").WithArgs("scheduled-transfer:1:2026-03-01").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows("20000.00", "active"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows("0.00", "active"))
+	expectFee(mock, "transfer", money.Zero, 10, 11)
+	mock.ExpectQuery("INSERT INTO transfer_approvals").
+		WithArgs(10, 11, money.New(15000, 0), money.Zero, 3, 0, expiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(1, "pending", createdAt))
+	mock.ExpectExec("UPDATE transfer_requests SET approval_id").WithArgs(int64(1), "scheduled-transfer:1:2026-03-01").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	approval := newApproval()
+	assert.NoError(t, repo.CreateTransferApproval(approval))
+	assert.Equal(t, int64(1), approval.ID)
+
+	// Asked again, the approval already made is returned and nothing is
+	// held twice.
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO transfer_requests").WithArgs("scheduled-transfer:1:2026-03-01").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT approval_id FROM transfer_requests").WithArgs("scheduled-transfer:1:2026-03-01").
+		WillReturnRows(sqlmock.NewRows([]string{"approval_id"}).AddRow(1))
+	mock.ExpectQuery("SELECT .* FROM transfer_approvals WHERE id = \\$1").WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(approvalColumnNames).AddRow(1, 10, 11, "15000.00", "0.00", "pending", 3, nil, nil, nil, expiresAt, createdAt, nil))
+	mock.ExpectRollback()
+
+	approval = newApproval()
+	assert.NoError(t, repo.CreateTransferApproval(approval))
+	assert.Equal(t, int64(1), approval.ID)
+	assert.Equal(t, models.ApprovalPending, approval.Status)
+
+	// The key went to a transfer made without approval.
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO transfer_requests").WithArgs("scheduled-transfer:1:2026-03-01").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT approval_id FROM transfer_requests").WithArgs("scheduled-transfer:1:2026-03-01").
+		WillReturnRows(sqlmock.NewRows([]string{"approval_id"}).AddRow(nil))
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, repo.CreateTransferApproval(newApproval()), ErrTransferMade)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_TransferOnceTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+	actor := models.Actor{CustomerID: 2, RequestID: "scheduled-transfer:1:2026-03-01:1"}
+
+	// The key is taken in the transfer's transaction.
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO transfer_requests").WithArgs("scheduled-transfer:1:2026-03-01").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows(1000.0, "active"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows(500.0, "active"))
+	expectFee(mock, "transfer", money.Zero, 10, 11)
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(900, 0), 10).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(600, 0), 11).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectJournalEntry(mock, "transfer",
+		expectedPosting{10, "debit", money.New(100, 0)},
+		expectedPosting{11, "credit", money.New(100, 0)})
+	expectTransaction(mock, 10, "transfer", "debit", money.New(100, 0), money.New(900, 0))
+	expectTransaction(mock, 11, "transfer", "credit", money.New(100, 0), money.New(600, 0))
+	expectEvent(mock, "transfer.completed", 10, 11)
+	expectAudit(mock, "transfer", actor, 10, 11,
+		`[{"account_id": 10, "balance": 1000}, {"account_id": 11, "balance": 500}]`,
+		`[{"account_id": 10, "balance": 900}, {"account_id": 11, "balance": 600}]`)
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.TransferOnceTx("scheduled-transfer:1:2026-03-01", 10, 11, money.New(100, 0), actor))
+
+	// A repeat finds the key taken and moves nothing.
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO transfer_requests").WithArgs("scheduled-transfer:1:2026-03-01").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT approval_id FROM transfer_requests").WithArgs("scheduled-transfer:1:2026-03-01").
+		WillReturnRows(sqlmock.NewRows([]string{"approval_id"}).AddRow(nil))
+	mock.ExpectRollback()
+
+	assert.NoError(t, repo.TransferOnceTx("scheduled-transfer:1:2026-03-01", 10, 11, money.New(100, 0), actor))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_TransferTx_HeldFunds(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+
+	// 1000 on the account, 950 of it held by a pending approval.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(heldLockRows(1000.0, "active", 950.0))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows(0.0, "active"))
+	expectFee(mock, "transfer", money.Zero, 10, 11)
+	mock.ExpectRollback()
+
+	err = repo.TransferTx(10, 11, money.New(100, 0), 0, models.Actor{})
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_TransferTx_Overflow(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+
+	// The destination already holds the largest balance Money can represent.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows("100.00", "active"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows("92233720368547758.07", "active"))
+	expectFee(mock, "transfer", money.Zero, 10, 11)
+	mock.ExpectRollback()
+
+	err = repo.TransferTx(10, 11, money.New(1, 0), 0, models.Actor{})
+	assert.ErrorIs(t, err, money.ErrOverflow)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_ReviewTransfer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+	expiresAt := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	createdAt := expiresAt.Add(-24 * time.Hour)
+	reviewedAt := createdAt.Add(time.Hour)
+	approvalRow := func(status string) *sqlmock.Rows {
+		return sqlmock.NewRows(approvalColumnNames).
+			AddRow(1, 10, 11, "15000.00", "0.00", status, 3, nil, nil, nil, expiresAt, createdAt, nil)
+	}
+
+	// Approving executes the transfer in the same transaction. The lock
+	// query runs after the status update, so the approval's own hold no
+	// longer counts.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .* FROM transfer_approvals WHERE id = \\$1 FOR UPDATE").WithArgs(1).WillReturnRows(approvalRow("pending"))
+	mock.ExpectQuery("UPDATE transfer_approvals SET status = \\$1").
+		WithArgs("approved", 7, "", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"reviewed_at"}).AddRow(reviewedAt))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows("20000.00", "active"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows("0.00", "active"))
+	expectFee(mock, "transfer", money.Zero, 10, 11)
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(5000, 0), 10).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(15000, 0), 11).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectJournalEntry(mock, "transfer",
+		expectedPosting{10, "debit", money.New(15000, 0)},
+		expectedPosting{11, "credit", money.New(15000, 0)})
+	expectTransaction(mock, 10, "transfer", "debit", money.New(15000, 0), money.New(5000, 0))
+	expectTransaction(mock, 11, "transfer", "credit", money.New(15000, 0), money.New(15000, 0))
+	expectEvent(mock, "transfer.completed", 10, 11)
+	// The transfer is audited as the reviewer's.
+	expectAudit(mock, "transfer", models.Actor{OperatorID: 7, RequestID: "req-3"}, 10, 11,
+		`[{"account_id": 10, "balance": 20000}, {"account_id": 11, "balance": 0}]`,
+		`[{"account_id": 10, "balance": 5000}, {"account_id": 11, "balance": 15000}]`)
+	mock.ExpectCommit()
+
+	approval, err := repo.ApproveTransfer(1, models.Actor{OperatorID: 7, RequestID: "req-3"}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, models.ApprovalApproved, approval.Status)
+	assert.Equal(t, 7, approval.ReviewedBy)
+	assert.Equal(t, &reviewedAt, approval.ReviewedAt)
+
+	// Rejecting only records the review; the hold goes with the status.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .* FROM transfer_approvals WHERE id = \\$1 FOR UPDATE").WithArgs(1).WillReturnRows(approvalRow("pending"))
+	mock.ExpectQuery("UPDATE transfer_approvals SET status = \\$1").
+		WithArgs("rejected", 7, "unknown payee", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"reviewed_at"}).AddRow(reviewedAt))
+	mock.ExpectCommit()
+
+	approval, err = repo.RejectTransfer(1, models.Actor{OperatorID: 7}, "unknown payee")
+	assert.NoError(t, err)
+	assert.Equal(t, models.ApprovalRejected, approval.Status)
+	assert.Equal(t, "unknown payee", approval.ReviewReason)
+
+	for _, tt := range []struct {
+		status string
+		want   error
+	}{
+		{"approved", ErrApprovalReviewed},
+		{"rejected", ErrApprovalReviewed},
+		{"expired", ErrApprovalExpired},
+	} {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT .* FROM transfer_approvals WHERE id = \\$1 FOR UPDATE").WithArgs(1).WillReturnRows(approvalRow(tt.status))
+		mock.ExpectRollback()
+
+		_, err = repo.ApproveTransfer(1, models.Actor{OperatorID: 7}, "")
+		assert.ErrorIs(t, err, tt.want, tt.status)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .* FROM transfer_approvals WHERE id = \\$1 FOR UPDATE").WithArgs(99).WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	_, err = repo.RejectTransfer(99, models.Actor{OperatorID: 7}, "unknown payee")
+	assert.ErrorIs(t, err, ErrApprovalNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_ListTransferApprovals(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+	expiresAt := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	reviewedAt := expiresAt.Add(-time.Hour)
+
+	mock.ExpectQuery(`FROM transfer_approvals WHERE true AND \(CASE .* END\) = \$1 AND \$2 IN \(from_account_id, to_account_id\) AND id < \$3 ORDER BY id DESC LIMIT \$4`).
+		WithArgs("approved", 10, int64(9), 21).
+		WillReturnRows(sqlmock.NewRows(approvalColumnNames).
+			AddRow(4, 10, 11, "15000.00", "0.00", "approved", nil, 2, 7, "ok", expiresAt, expiresAt.Add(-24*time.Hour), reviewedAt))
+
+	approvals, err := repo.ListTransferApprovals(models.ApprovalFilter{Status: "approved", AccountID: 10, BeforeID: 9, Limit: 21})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.TransferApproval{{
+		ID:                    4,
+		FromAccountID:         10,
+		ToAccountID:           11,
+		Amount:                money.New(15000, 0),
+		Status:                "approved",
+		RequestedByOperatorID: 2,
+		ReviewedBy:            7,
+		ReviewReason:          "ok",
+		ExpiresAt:             expiresAt,
+		CreatedAt:             expiresAt.Add(-24 * time.Hour),
+		ReviewedAt:            &reviewedAt,
+	}}, approvals)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_GetBalances(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+
+	mock.ExpectQuery("SELECT balance, balance - .* FROM accounts WHERE id = \\$1").WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"balance", "available"}).AddRow("100.00", "60.00"))
+	balance, err := repo.GetBalances(10)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.Balance{Ledger: money.New(100, 0), Available: money.New(60, 0)}, balance)
+
+	mock.ExpectQuery("SELECT balance, balance - .* FROM accounts WHERE id = \\$1").WithArgs(99).WillReturnError(sql.ErrNoRows)
+	_, err = repo.GetBalances(99)
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+var holdColumnNames = []string{"id", "account_id", "amount", "captured_amount", "status", "description", "expires_at", "created_at", "released_at"}
+
+func TestPsqlAccountRepository_CreateHold(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+	expiresAt := time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(heldLockRows("100.00", "active", "40.00"))
+	mock.ExpectQuery("INSERT INTO holds").
+		WithArgs(10, money.New(60, 0), "Hotel", expiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow(5, "active", createdAt))
+	mock.ExpectCommit()
+
+	hold := &models.Hold{AccountID: 10, Amount: money.New(60, 0), Description: "Hotel", ExpiresAt: expiresAt}
+	assert.NoError(t, repo.CreateHold(hold))
+	assert.Equal(t, int64(5), hold.ID)
+	assert.Equal(t, models.HoldActive, hold.Status)
+
+	// Only the available balance counts.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(heldLockRows("100.00", "active", "40.00"))
+	mock.ExpectRollback()
+
+	hold = &models.Hold{AccountID: 10, Amount: money.FromCents(6001), ExpiresAt: expiresAt}
+	assert.ErrorIs(t, repo.CreateHold(hold), ErrInsufficientFunds)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows("100.00", "frozen"))
+	mock.ExpectRollback()
+
+	hold = &models.Hold{AccountID: 10, Amount: money.New(1, 0), ExpiresAt: expiresAt}
+	assert.ErrorIs(t, repo.CreateHold(hold), ErrAccountFrozen)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_CaptureHold(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+	expiresAt := time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	releasedAt := createdAt.Add(time.Hour)
+	holdRow := func(status string) *sqlmock.Rows {
+		return sqlmock.NewRows(holdColumnNames).AddRow(5, 10, "60.00", nil, status, "Hotel", expiresAt, createdAt, nil)
+	}
+
+	// A partial capture debits only the captured amount, plus the
+	// withdrawal fee; the lock runs after the hold is released, so it no
+	// longer counts.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .* FROM holds WHERE id = \\$1 AND account_id = \\$2 FOR UPDATE").WithArgs(5, 10).WillReturnRows(holdRow("active"))
+	mock.ExpectQuery("UPDATE holds SET status = 'captured'").WithArgs(money.New(45, 0), 5).
+		WillReturnRows(sqlmock.NewRows([]string{"released_at"}).AddRow(releasedAt))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows("100.00", "active"))
+	expectFee(mock, "withdrawal", money.FromCents(250), 10)
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.FromCents(5250), 10).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectJournalEntry(mock, "capture",
+		expectedPosting{10, "debit", money.New(45, 0)},
+		expectedPosting{ledger.CashAccountID, "credit", money.New(45, 0)})
+	expectTransaction(mock, 10, "capture", "debit", money.New(45, 0), money.New(55, 0))
+	expectJournalEntry(mock, "withdrawal_fee",
+		expectedPosting{10, "debit", money.FromCents(250)},
+		expectedPosting{ledger.EquityAccountID, "credit", money.FromCents(250)})
+	expectTransaction(mock, 10, "withdrawal_fee", "debit", money.FromCents(250), money.FromCents(5250))
+	expectEvent(mock, "hold.captured", 10, 0)
+	expectAudit(mock, "capture_hold", models.Actor{CustomerID: 1}, 10, 0,
+		`[{"account_id": 10, "balance": 100}]`, `[{"account_id": 10, "balance": 52.50}]`)
+	mock.ExpectCommit()
+
+	hold, err := repo.CaptureHold(10, 5, money.New(45, 0), models.Actor{CustomerID: 1})
+	assert.NoError(t, err)
+	captured := money.New(45, 0)
+	assert.Equal(t, &models.Hold{
+		ID: 5, AccountID: 10, Amount: money.New(60, 0), CapturedAmount: &captured, Status: models.HoldCaptured,
+		Description: "Hotel", ExpiresAt: expiresAt, CreatedAt: createdAt, ReleasedAt: &releasedAt,
+	}, hold)
+
+	// Zero captures the whole hold. The account has the fee waived.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .* FROM holds").WithArgs(5, 10).WillReturnRows(holdRow("active"))
+	mock.ExpectQuery("UPDATE holds SET status = 'captured'").WithArgs(money.New(60, 0), 5).
+		WillReturnRows(sqlmock.NewRows([]string{"released_at"}).AddRow(releasedAt))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows("100.00", "active"))
+	expectFee(mock, "withdrawal", money.Zero, 10)
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(40, 0), 10).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectJournalEntry(mock, "capture",
+		expectedPosting{10, "debit", money.New(60, 0)},
+		expectedPosting{ledger.CashAccountID, "credit", money.New(60, 0)})
+	expectTransaction(mock, 10, "capture", "debit", money.New(60, 0), money.New(40, 0))
+	expectEvent(mock, "hold.captured", 10, 0)
+	expectAudit(mock, "capture_hold", models.Actor{OperatorID: 2}, 10, 0,
+		`[{"account_id": 10, "balance": 100}]`, `[{"account_id": 10, "balance": 40}]`)
+	mock.ExpectCommit()
+
+	hold, err = repo.CaptureHold(10, 5, money.Zero, models.Actor{OperatorID: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(60, 0), *hold.CapturedAmount)
+
+	// The balance covers the capture but not its fee: neither happens.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .* FROM holds").WithArgs(5, 10).WillReturnRows(holdRow("active"))
+	mock.ExpectQuery("UPDATE holds SET status = 'captured'").WithArgs(money.New(60, 0), 5).
+		WillReturnRows(sqlmock.NewRows([]string{"released_at"}).AddRow(releasedAt))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows("60.00", "active"))
+	expectFee(mock, "withdrawal", money.FromCents(250), 10)
+	mock.ExpectRollback()
+
+	_, err = repo.CaptureHold(10, 5, money.Zero, models.Actor{CustomerID: 1})
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// More than the hold.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .* FROM holds").WithArgs(5, 10).WillReturnRows(holdRow("active"))
+	mock.ExpectRollback()
+
+	_, err = repo.CaptureHold(10, 5, money.FromCents(6001), models.Actor{})
+	assert.ErrorIs(t, err, ErrCaptureExceedsHold)
+
+	for _, tt := range []struct {
+		status string
+		want   error
+	}{
+		{"captured", ErrHoldReleased},
+		{"voided", ErrHoldReleased},
+		{"expired", ErrHoldExpired},
+	} {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT .* FROM holds").WithArgs(5, 10).WillReturnRows(holdRow(tt.status))
+		mock.ExpectRollback()
+
+		_, err = repo.CaptureHold(10, 5, money.Zero, models.Actor{})
+		assert.ErrorIs(t, err, tt.want, tt.status)
+	}
+
+	// Holds of other accounts are not found.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .* FROM holds").WithArgs(5, 11).WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	_, err = repo.CaptureHold(11, 5, money.Zero, models.Actor{})
+	assert.ErrorIs(t, err, ErrHoldNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_VoidHold(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+	expiresAt := time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	releasedAt := createdAt.Add(time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .* FROM holds").WithArgs(5, 10).
+		WillReturnRows(sqlmock.NewRows(holdColumnNames).AddRow(5, 10, "60.00", nil, "active", nil, expiresAt, createdAt, nil))
+	mock.ExpectQuery("UPDATE holds SET status = 'voided'").WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"released_at"}).AddRow(releasedAt))
+	mock.ExpectCommit()
+
+	hold, err := repo.VoidHold(10, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, models.HoldVoided, hold.Status)
+	assert.Equal(t, &releasedAt, hold.ReleasedAt)
+	assert.Nil(t, hold.CapturedAmount)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_ListHolds(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+	expiresAt := time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	releasedAt := createdAt.Add(time.Hour)
+
+	mock.ExpectQuery(`FROM holds WHERE account_id = \$1 AND \(CASE .* END\) = \$2 AND id < \$3 ORDER BY id DESC LIMIT \$4`).
+		WithArgs(10, "captured", int64(9), 21).
+		WillReturnRows(sqlmock.NewRows(holdColumnNames).AddRow(5, 10, "60.00", "45.00", "captured", "Hotel", expiresAt, createdAt, releasedAt))
+
+	holds, err := repo.ListHolds(10, models.HoldFilter{Status: "captured", BeforeID: 9, Limit: 21})
+	assert.NoError(t, err)
+	captured := money.New(45, 0)
+	assert.Equal(t, []models.Hold{{
+		ID: 5, AccountID: 10, Amount: money.New(60, 0), CapturedAmount: &captured, Status: "captured",
+		Description: "Hotel", ExpiresAt: expiresAt, CreatedAt: createdAt, ReleasedAt: &releasedAt,
+	}}, holds)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_TransferTx_Overdraft(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+
+	// 100 on the account and a 500 overdraft limit: 600 can be sent.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(overdraftLockRows("100.00", "active", "0", "500.00"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows("0", "active"))
+	expectFee(mock, "transfer", money.Zero, 10, 11)
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(-500, 0), 10).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(600, 0), 11).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectJournalEntry(mock, "transfer",
+		expectedPosting{10, "debit", money.New(600, 0)},
+		expectedPosting{11, "credit", money.New(600, 0)})
+	expectTransaction(mock, 10, "transfer", "debit", money.New(600, 0), money.New(-500, 0))
+	expectTransaction(mock, 11, "transfer", "credit", money.New(600, 0), money.New(600, 0))
+	expectEvent(mock, "transfer.completed", 10, 11)
+	expectAudit(mock, "transfer", models.Actor{}, 10, 11,
+		`[{"account_id": 10, "balance": 100}, {"account_id": 11, "balance": 0}]`,
+		`[{"account_id": 10, "balance": -500}, {"account_id": 11, "balance": 600}]`)
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.TransferTx(10, 11, money.New(600, 0), 0, models.Actor{}))
+
+	// One cent more is past the limit.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(overdraftLockRows("100.00", "active", "0", "500.00"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows("0", "active"))
+	expectFee(mock, "transfer", money.Zero, 10, 11)
+	mock.ExpectRollback()
+
+	err = repo.TransferTx(10, 11, money.FromCents(60001), 0, models.Actor{})
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_GetOverdraft(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+	columns := []string{"available", "limit", "custom", "monthly_rate_bps"}
+
+	tests := []struct {
+		name      string
+		available string
+		want      models.Overdraft
+	}{
+		{"positive balance", "50.00", models.Overdraft{Limit: money.New(500, 0), Remaining: money.New(500, 0), MonthlyRateBps: 800}},
+		{"partly used", "-120.00", models.Overdraft{Limit: money.New(500, 0), Used: money.New(120, 0), Remaining: money.New(380, 0), MonthlyRateBps: 800}},
+		{"past the limit", "-550.00", models.Overdraft{Limit: money.New(500, 0), Used: money.New(550, 0), MonthlyRateBps: 800}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectQuery("SELECT balance - .* FROM accounts WHERE id = \\$1").WithArgs(10).
+				WillReturnRows(sqlmock.NewRows(columns).AddRow(tt.available, "500.00", false, 800))
+			overdraft, err := repo.GetOverdraft(10)
+			assert.NoError(t, err)
+			assert.Equal(t, &tt.want, overdraft)
+		})
+	}
+
+	mock.ExpectQuery("SELECT balance - .* FROM accounts WHERE id = \\$1").WithArgs(99).WillReturnError(sql.ErrNoRows)
+	_, err = repo.GetOverdraft(99)
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_SetOverdraftLimit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+	limit := money.New(1000, 0)
+
+	mock.ExpectExec("UPDATE accounts SET overdraft_limit = \\$1").WithArgs(limit, 10).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.SetOverdraftLimit(10, &limit))
+
+	// A nil limit clears the column.
+	mock.ExpectExec("UPDATE accounts SET overdraft_limit = \\$1").WithArgs(nil, 10).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.SetOverdraftLimit(10, nil))
+
+	mock.ExpectExec("UPDATE accounts SET overdraft_limit = \\$1").WithArgs(nil, 99).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.SetOverdraftLimit(99, nil), ErrAccountNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_ChargeOverdraftInterest(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+	date := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// -300.00 at 8% a month: 300 * 0.08 / 30 = 0.80 for the day.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(overdraftLockRows("-300.00", "active", "0", "500.00"))
+	mock.ExpectQuery("SELECT EXISTS .* FROM overdraft_interest_charges").WithArgs(10, date).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery("SELECT COALESCE\\(\\(SELECT monthly_rate_bps").WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"monthly_rate_bps"}).AddRow(800))
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.FromCents(-30080), 10).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectJournalEntry(mock, "overdraft_interest",
+		expectedPosting{10, "debit", money.FromCents(80)},
+		expectedPosting{ledger.EquityAccountID, "credit", money.FromCents(80)})
+	expectTransaction(mock, 10, "overdraft_interest", "debit", money.FromCents(80), money.FromCents(-30080))
+	mock.ExpectExec("INSERT INTO overdraft_interest_charges").
+		WithArgs(10, date, money.New(-300, 0), money.FromCents(80), 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectAudit(mock, "overdraft_interest", models.Actor{System: "overdraft_interest"}, 10, 0,
+		`[{"account_id": 10, "balance": -300}]`, `[{"account_id": 10, "balance": -300.8}]`)
+	mock.ExpectCommit()
+
+	interest, err := repo.ChargeOverdraftInterest(10, date)
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromCents(80), interest)
+
+	// Already charged today.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(overdraftLockRows("-300.80", "active", "0", "500.00"))
+	mock.ExpectQuery("SELECT EXISTS .* FROM overdraft_interest_charges").WithArgs(10, date).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+
+	interest, err = repo.ChargeOverdraftInterest(10, date)
+	assert.NoError(t, err)
+	assert.True(t, interest.IsZero())
+
+	// Back above zero since the account was listed.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows("20.00", "active"))
+	mock.ExpectRollback()
+
+	interest, err = repo.ChargeOverdraftInterest(11, date)
+	assert.NoError(t, err)
+	assert.True(t, interest.IsZero())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_TransferTx_Fee(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+
+	// The sender pays the fee as a separate entry, after the transfer.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows("500.00", "active"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows("0", "active"))
+	expectFee(mock, "transfer", money.FromCents(150), 10, 11)
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.FromCents(39850), 10).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(100, 0), 11).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectJournalEntry(mock, "transfer",
+		expectedPosting{10, "debit", money.New(100, 0)},
+		expectedPosting{11, "credit", money.New(100, 0)})
+	expectTransaction(mock, 10, "transfer", "debit", money.New(100, 0), money.New(400, 0))
+	expectTransaction(mock, 11, "transfer", "credit", money.New(100, 0), money.New(100, 0))
+	expectJournalEntry(mock, "transfer_fee",
+		expectedPosting{10, "debit", money.FromCents(150)},
+		expectedPosting{ledger.EquityAccountID, "credit", money.FromCents(150)})
+	expectTransaction(mock, 10, "transfer_fee", "debit", money.FromCents(150), money.FromCents(39850))
+	expectEvent(mock, "transfer.completed", 10, 11)
+	expectAudit(mock, "transfer", models.Actor{}, 10, 11,
+		`[{"account_id": 10, "balance": 500}, {"account_id": 11, "balance": 0}]`,
+		`[{"account_id": 10, "balance": 398.50}, {"account_id": 11, "balance": 100}]`)
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.TransferTx(10, 11, money.New(100, 0), 0, models.Actor{}))
+
+	// The balance covers the amount but not the fee.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows("100.00", "active"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows("0", "active"))
+	expectFee(mock, "transfer", money.FromCents(150), 10, 11)
+	mock.ExpectRollback()
+
+	err = repo.TransferTx(10, 11, money.New(100, 0), 0, models.Actor{})
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_FeeWaivers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("INSERT INTO fee_waivers").WithArgs(10, "maintenance", "Employee account").
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+	waiver := &models.FeeWaiver{AccountID: 10, FeeType: "maintenance", Reason: "Employee account"}
+	assert.NoError(t, repo.WaiveFee(waiver))
+	assert.Equal(t, createdAt, waiver.CreatedAt)
+
+	// Customer accounts only: no row is inserted for an unknown account.
+	mock.ExpectQuery("INSERT INTO fee_waivers").WithArgs(99, "maintenance", "Employee account").
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}))
+	err = repo.WaiveFee(&models.FeeWaiver{AccountID: 99, FeeType: "maintenance", Reason: "Employee account"})
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+
+	mock.ExpectExec("DELETE FROM fee_waivers").WithArgs(10, "maintenance").WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.RemoveFeeWaiver(10, "maintenance"))
+
+	mock.ExpectExec("DELETE FROM fee_waivers").WithArgs(10, "maintenance").WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.RemoveFeeWaiver(10, "maintenance"), ErrFeeWaiverNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_ChargeMaintenanceFee(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+	period := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// Charged to a frozen account, into its overdraft.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(overdraftLockRows("5.00", "frozen", "0", "100.00"))
+	mock.ExpectQuery("SELECT EXISTS .* FROM maintenance_fee_charges").WithArgs(10, period).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	expectFee(mock, "maintenance", money.New(15, 0), 10)
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(-10, 0), 10).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectJournalEntry(mock, "maintenance_fee",
+		expectedPosting{10, "debit", money.New(15, 0)},
+		expectedPosting{ledger.EquityAccountID, "credit", money.New(15, 0)})
+	expectTransaction(mock, 10, "maintenance_fee", "debit", money.New(15, 0), money.New(-10, 0))
+	mock.ExpectExec("INSERT INTO maintenance_fee_charges").
+		WithArgs(10, period, money.New(15, 0), 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectAudit(mock, "maintenance_fee", models.Actor{System: "maintenance_fee"}, 10, 0,
+		`[{"account_id": 10, "balance": 5}]`, `[{"account_id": 10, "balance": -10}]`)
+	mock.ExpectCommit()
+
+	fee, err := repo.ChargeMaintenanceFee(10, period)
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(15, 0), fee)
+
+	// Only what the available balance covers, without an overdraft limit.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(12).WillReturnRows(heldLockRows("9.00", "active", "4.00"))
+	mock.ExpectQuery("SELECT EXISTS .* FROM maintenance_fee_charges").WithArgs(12, period).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	expectFee(mock, "maintenance", money.New(15, 0), 12)
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(4, 0), 12).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectJournalEntry(mock, "maintenance_fee",
+		expectedPosting{12, "debit", money.New(5, 0)},
+		expectedPosting{ledger.EquityAccountID, "credit", money.New(5, 0)})
+	expectTransaction(mock, 12, "maintenance_fee", "debit", money.New(5, 0), money.New(4, 0))
+	mock.ExpectExec("INSERT INTO maintenance_fee_charges").
+		WithArgs(12, period, money.New(5, 0), 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectAudit(mock, "maintenance_fee", models.Actor{System: "maintenance_fee"}, 12, 0,
+		`[{"account_id": 12, "balance": 9}]`, `[{"account_id": 12, "balance": 4}]`)
+	mock.ExpectCommit()
+
+	fee, err = repo.ChargeMaintenanceFee(12, period)
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(5, 0), fee)
+
+	// Nothing is charged past the limit...
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(13).WillReturnRows(overdraftLockRows("-100.00", "active", "0", "100.00"))
+	mock.ExpectQuery("SELECT EXISTS .* FROM maintenance_fee_charges").WithArgs(13, period).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	expectFee(mock, "maintenance", money.New(15, 0), 13)
+	mock.ExpectRollback()
+
+	fee, err = repo.ChargeMaintenanceFee(13, period)
+	assert.NoError(t, err)
+	assert.True(t, fee.IsZero())
+
+	// ...nor to an account closed since it was listed.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(14).WillReturnRows(lockRows("50.00", "closed"))
+	mock.ExpectRollback()
+
+	fee, err = repo.ChargeMaintenanceFee(14, period)
+	assert.NoError(t, err)
+	assert.True(t, fee.IsZero())
+
+	// Already charged this month.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(lockRows("-10.00", "active"))
+	mock.ExpectQuery("SELECT EXISTS .* FROM maintenance_fee_charges").WithArgs(10, period).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+
+	fee, err = repo.ChargeMaintenanceFee(10, period)
+	assert.NoError(t, err)
+	assert.True(t, fee.IsZero())
+
+	// Waived since the account was listed.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(lockRows("100.00", "active"))
+	mock.ExpectQuery("SELECT EXISTS .* FROM maintenance_fee_charges").WithArgs(11, period).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	expectFee(mock, "maintenance", money.Zero, 11)
+	mock.ExpectRollback()
+
+	fee, err = repo.ChargeMaintenanceFee(11, period)
+	assert.NoError(t, err)
+	assert.True(t, fee.IsZero())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+// expectFXJournalEntry expects a transfer of amount in from converted to
+// converted in to, through equity.
+func expectFXJournalEntry(mock sqlmock.Sqlmock, fromID, toID int, from, to string, amount, converted money.Money) {
+	mock.ExpectQuery("INSERT INTO journal_entries").
+		WithArgs("transfer", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
+	for i, args := range [][]driver.Value{
+		{1, fromID, "debit", amount, from},
+		{1, ledger.EquityAccountID, "credit", amount, from},
+		{1, ledger.EquityAccountID, "debit", converted, to},
+		{1, toID, "credit", converted, to},
+	} {
+		mock.ExpectQuery("INSERT INTO postings").WithArgs(args...).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(i + 1))
+	}
+}
+
+func TestPsqlAccountRepository_TransferTx_FX(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+	rateColumns := []string{"base", "quote", "rate", "spread_bps", "updated_at"}
+	quoteColumns := []string{"id", "account_id", "from_currency", "to_currency", "amount", "converted_amount", "mid_rate",
+		"spread_bps", "rate", "used_at", "expired"}
+
+	// Without a quote the current rate applies: 100 BRL at 0.1841 less
+	// 50 bps is 18.32 USD.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(currencyLockRows(1000.0, "active", "0", "0", "BRL"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(currencyLockRows(50.0, "active", "0", "0", "USD"))
+	mock.ExpectQuery("FROM fx_rates WHERE base").WithArgs("BRL", "USD").
+		WillReturnRows(sqlmock.NewRows(rateColumns).AddRow("BRL", "USD", "0.1841", 50, time.Now()))
+	expectFee(mock, "transfer", money.Zero, 10, 11)
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(900, 0), 10).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.MustParse("68.32"), 11).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectFXJournalEntry(mock, 10, 11, "BRL", "USD", money.New(100, 0), money.MustParse("18.32"))
+	expectTransaction(mock, 10, "transfer", "debit", money.New(100, 0), money.New(900, 0))
+	expectTransaction(mock, 11, "transfer", "credit", money.MustParse("18.32"), money.MustParse("68.32"))
+	mock.ExpectExec("INSERT INTO fx_conversions").
+		WithArgs(1, nil, "BRL", "USD", money.New(100, 0), money.MustParse("18.32"), fx.MustParseRate("0.1841"), 50, fx.MustParseRate("0.1831795")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO outbox").
+		WithArgs("transfer.completed", 10, 11, jsonArg(`{
+			"journal_entry_id": 1, "from_account_id": 10, "to_account_id": 11, "amount": 100.00, "currency": "BRL", "fee": 0.00,
+			"fx": {
+				"journal_entry_id": 1, "from_currency": "BRL", "to_currency": "USD", "source_amount": 100.00, "converted_amount": 18.32,
+				"mid_rate": 0.1841, "spread_bps": 50, "rate": 0.1831795
+			}
+		}`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	// Each account's balances are in its own currency.
+	expectAudit(mock, "transfer", models.Actor{}, 10, 11,
+		`[{"account_id": 10, "balance": 1000}, {"account_id": 11, "balance": 50}]`,
+		`[{"account_id": 10, "balance": 900}, {"account_id": 11, "balance": 68.32}]`)
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.TransferTx(10, 11, money.New(100, 0), 0, models.Actor{}))
+
+	// A quote locks its rate, whatever the current one, and is spent.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(currencyLockRows(900.0, "active", "0", "0", "BRL"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(currencyLockRows(68.32, "active", "0", "0", "USD"))
+	mock.ExpectQuery("FROM fx_quotes WHERE id = \\$1 FOR UPDATE").WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows(quoteColumns).AddRow(7, 10, "BRL", "USD", "100.00", "18.50", "0.186", 50, "0.18507", nil, false))
+	mock.ExpectExec("UPDATE fx_quotes SET used_at").WithArgs(int64(7)).WillReturnResult(sqlmock.NewResult(0, 1))
+	expectFee(mock, "transfer", money.Zero, 10, 11)
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.New(800, 0), 10).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE accounts").WithArgs(money.MustParse("86.82"), 11).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectFXJournalEntry(mock, 10, 11, "BRL", "USD", money.New(100, 0), money.MustParse("18.50"))
+	expectTransaction(mock, 10, "transfer", "debit", money.New(100, 0), money.New(800, 0))
+	expectTransaction(mock, 11, "transfer", "credit", money.MustParse("18.50"), money.MustParse("86.82"))
+	mock.ExpectExec("INSERT INTO fx_conversions").
+		WithArgs(1, int64(7), "BRL", "USD", money.New(100, 0), money.MustParse("18.50"), fx.MustParseRate("0.186"), 50, fx.MustParseRate("0.18507")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectEvent(mock, "transfer.completed", 10, 11)
+	expectAudit(mock, "transfer", models.Actor{}, 10, 11,
+		`[{"account_id": 10, "balance": 900}, {"account_id": 11, "balance": 68.32}]`,
+		`[{"account_id": 10, "balance": 800}, {"account_id": 11, "balance": 86.82}]`)
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.TransferTx(10, 11, money.New(100, 0), 7, models.Actor{}))
+
+	// Quotes that are used, expired or made for another transfer fail.
+	for _, tt := range []struct {
+		name  string
+		quote []driver.Value
+		err   error
+	}{
+		{"used", []driver.Value{7, 10, "BRL", "USD", "100.00", "18.50", "0.186", 50, "0.18507", time.Now(), false}, ErrQuoteUsed},
+		{"expired", []driver.Value{7, 10, "BRL", "USD", "100.00", "18.50", "0.186", 50, "0.18507", nil, true}, ErrQuoteExpired},
+		{"other amount", []driver.Value{7, 10, "BRL", "USD", "90.00", "16.65", "0.186", 50, "0.18507", nil, false}, ErrQuoteMismatch},
+		{"other account", []driver.Value{7, 12, "BRL", "USD", "100.00", "18.50", "0.186", 50, "0.18507", nil, false}, ErrQuoteMismatch},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(currencyLockRows(900.0, "active", "0", "0", "BRL"))
+			mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(11).WillReturnRows(currencyLockRows(68.32, "active", "0", "0", "USD"))
+			mock.ExpectQuery("FROM fx_quotes").WithArgs(int64(7)).WillReturnRows(sqlmock.NewRows(quoteColumns).AddRow(tt.quote...))
+			mock.ExpectRollback()
+
+			assert.ErrorIs(t, repo.TransferTx(10, 11, money.New(100, 0), 7, models.Actor{}), tt.err)
+		})
+	}
+
+	// A quote cannot be used between accounts in the same currency.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(currencyLockRows(900.0, "active", "0", "0", "BRL"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(12).WillReturnRows(currencyLockRows(0.0, "active", "0", "0", "BRL"))
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, repo.TransferTx(10, 12, money.New(100, 0), 7, models.Actor{}), ErrQuoteMismatch)
+
+	// Without a rate for the pair nothing moves.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(10).WillReturnRows(currencyLockRows(900.0, "active", "0", "0", "BRL"))
+	mock.ExpectQuery("SELECT balance, status, .* FROM accounts").WithArgs(13).WillReturnRows(currencyLockRows(0.0, "active", "0", "0", "EUR"))
+	mock.ExpectQuery("FROM fx_rates WHERE base").WithArgs("BRL", "EUR").WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, repo.TransferTx(10, 13, money.New(100, 0), 0, models.Actor{}), ErrFXRateNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_GetBalanceAsOf(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+	asOf := time.Date(2026, 1, 31, 23, 59, 59, 0, time.UTC)
+
+	// Snapshots of January 30th and before can be used.
+	mock.ExpectQuery(`SELECT COALESCE\(s.balance, 0\) \+ COALESCE\(\(SELECT SUM\(.*\) FROM account_transactions
+			  WHERE account_id = a.id AND created_at <= \$2 AND created_at >= COALESCE\(\(s.snapshot_date \+ 1\)::timestamp AT TIME ZONE 'UTC', '-infinity'\)\), 0\)
+			  FROM accounts a LEFT JOIN LATERAL \(SELECT snapshot_date, balance FROM balance_snapshots
+					  WHERE account_id = a.id AND snapshot_date < \$3 ORDER BY snapshot_date DESC LIMIT 1\) s ON true`).
+		WithArgs(10, asOf, time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)).
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("120.50"))
+
+	balance, err := repo.GetBalanceAsOf(10, asOf)
+	assert.NoError(t, err)
+	assert.Equal(t, money.MustParse("120.50"), balance)
+
+	// The day is the UTC one.
+	brt := time.FixedZone("BRT", -3*60*60)
+	mock.ExpectQuery("FROM accounts a").
+		WithArgs(10, time.Date(2026, 1, 31, 22, 0, 0, 0, brt), time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)).
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("0"))
+
+	_, err = repo.GetBalanceAsOf(10, time.Date(2026, 1, 31, 22, 0, 0, 0, brt))
+	assert.NoError(t, err)
+
+	mock.ExpectQuery("FROM accounts a").WithArgs(99, asOf, sqlmock.AnyArg()).WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetBalanceAsOf(99, asOf)
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAccountRepository_SnapshotBalances(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAccountRepository{DB: db}
+	date := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT MAX\(snapshot_date\) FROM balance_snapshots`).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(nil))
+
+	last, err := repo.LastBalanceSnapshotDate()
+	assert.NoError(t, err)
+	assert.True(t, last.IsZero())
+
+	// Accounts opened by the end of the day and not closed before it.
+	mock.ExpectExec(`INSERT INTO balance_snapshots .* WHERE account_id = a.id AND created_at < \$2 AND created_at >= COALESCE\(\(s.snapshot_date \+ 1\)::timestamp AT TIME ZONE 'UTC', '-infinity'\)\), 0\)
+			  .*
+			  WHERE a.customer_id IS NOT NULL AND a.created_at < \$2 AND \(a.closed_at IS NULL OR a.closed_at >= \$1::date::timestamp AT TIME ZONE 'UTC'\)
+			  ON CONFLICT \(account_id, snapshot_date\) DO NOTHING`).
+		WithArgs(date, date.AddDate(0, 0, 1)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	n, err := repo.SnapshotBalances(date)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	mock.ExpectQuery(`SELECT MAX\(snapshot_date\) FROM balance_snapshots`).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(date))
+
+	last, err = repo.LastBalanceSnapshotDate()
+	assert.NoError(t, err)
+	assert.Equal(t, date, last)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
+func TestPsqlAccountRepository_SnapshotBalances_NonUTCSession(t *testing.T) {
+	db := openTestDB(t)
+	// A single connection, so every query runs in the session set below.
+	db.SetMaxOpenConns(1)
+	_, err := db.Exec("SET TIME ZONE 'America/Sao_Paulo'")
+	require.NoError(t, err)
+	repo := &PsqlAccountRepository{DB: db}
+
+	account := &models.Account{Category: "savings"}
+	require.NoError(t, repo.CreateNaturalPerson(&models.NaturalPerson{FullName: "Non UTC Session"}, account, ""))
+	require.NoError(t, repo.DepositTx(account.ID, money.New(100, 0), models.Actor{}))
+
+	// Opened in 2025, with the deposit at 01:00 UTC on February 1st, which
+	// is still January 31st in São Paulo.
+	day := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	depositedAt := time.Date(2026, 2, 1, 1, 0, 0, 0, time.UTC)
+	_, err = db.Exec("UPDATE accounts SET created_at = $2 WHERE id = $1", account.ID, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	_, err = db.Exec("UPDATE account_transactions SET created_at = $2 WHERE account_id = $1", account.ID, depositedAt)
+	require.NoError(t, err)
+
+	// The snapshot of January 31st (UTC) comes before the deposit...
+	_, err = repo.SnapshotBalances(day)
+	require.NoError(t, err)
+	var snapshot money.Money
+	require.NoError(t, db.QueryRow("SELECT balance FROM balance_snapshots WHERE account_id = $1 AND snapshot_date = $2",
+		account.ID, day).Scan(&snapshot))
+	assert.Equal(t, money.Zero, snapshot)
+
+	// ...and the balance after it counts the deposit on top of the snapshot.
+	balance, err := repo.GetBalanceAsOf(account.ID, depositedAt.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, money.New(100, 0), balance)
+
+	balance, err = repo.GetBalanceAsOf(account.ID, depositedAt.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, money.Zero, balance)
+}
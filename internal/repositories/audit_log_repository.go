@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+// AuditLogRepository reads the hash-chained audit log. The account
+// repository writes each record as pending, in the transaction of the
+// change it records, and ChainPending adds it to the chain.
+type AuditLogRepository interface {
+	// ListRecords returns up to limit records with a sequence after
+	// afterSequence, in order.
+	ListRecords(afterSequence int64, limit int) ([]models.AuditRecord, error)
+	// ChainPending chains up to limit pending records, oldest first, and
+	// returns how many it chained.
+	ChainPending(limit int) (int, error)
+}
@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/auditlog"
+	"github.com/gregoryAlvim/gobank/internal/database"
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+// auditLogLock is the key of the transaction advisory lock held while
+// pending records are chained, so that two chainers cannot fork the log.
+const auditLogLock = 0x61756469746c6f67 // "auditlog"
+
+type PsqlAuditLogRepository struct {
+	DB *sql.DB
+}
+
+func NewPsqlAuditLogRepository() *PsqlAuditLogRepository {
+	return &PsqlAuditLogRepository{DB: database.DB}
+}
+
+func (r *PsqlAuditLogRepository) ListRecords(afterSequence int64, limit int) ([]models.AuditRecord, error) {
+	query := `SELECT sequence, action, actor, request_id, account_id, COALESCE(counterparty_id, 0), before, after, created_at,
+			  prev_hash, hash FROM audit_log WHERE sequence > $1 ORDER BY sequence LIMIT $2`
+	rows, err := r.DB.Query(query, afterSequence, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []models.AuditRecord{}
+	for rows.Next() {
+		var rec models.AuditRecord
+		var before []byte
+		if err := rows.Scan(&rec.Sequence, &rec.Action, &rec.Actor, &rec.RequestID, &rec.AccountID, &rec.CounterpartyID, &before,
+			&rec.After, &rec.CreatedAt, &rec.PrevHash, &rec.Hash); err != nil {
+			return nil, err
+		}
+		if before != nil {
+			rec.Before = before
+		}
+		records = append(records, rec)
+	}
+	return records, rows.Err()
+}
+
+// Actors of the bank's own jobs, as the audit log records them.
+var (
+	overdraftInterestActor = models.Actor{System: "overdraft_interest"}
+	maintenanceFeeActor    = models.Actor{System: "maintenance_fee"}
+	interestActor          = models.Actor{System: "interest"}
+	dormancyActor          = models.Actor{System: "dormancy"}
+)
+
+// appendAuditTx records the action inside tx, in audit_log_pending;
+// ChainPending later chains it into the audit log. It takes no lock of
+// its own, so it must run after the accounts involved are locked: that
+// keeps an account's records in commit order. before is nil when the
+// account did not exist.
+func appendAuditTx(tx *sql.Tx, action string, actor models.Actor, accountID, counterpartyID int, before, after []models.AccountState) error {
+	var beforeArg interface{}
+	if before != nil {
+		b, err := json.Marshal(before)
+		if err != nil {
+			return err
+		}
+		beforeArg = b
+	}
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO audit_log_pending (action, actor, request_id, account_id, counterparty_id, before, after, created_at)
+			  VALUES ($1, $2, $3, $4, NULLIF($5, 0), $6, $7, $8)`
+	// Postgres keeps microseconds.
+	_, err = tx.Exec(query, action, actor.String(), actor.RequestID, accountID, counterpartyID, beforeArg, afterJSON,
+		time.Now().UTC().Truncate(time.Microsecond))
+	return err
+}
+
+// ChainPending moves up to limit pending records, in id order, to the
+// audit log, hashing each one onto the last, and returns how many it
+// moved. It holds the log's lock until it commits; appendAuditTx never
+// takes it.
+func (r *PsqlAuditLogRepository) ChainPending(limit int) (int, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", auditLogLock); err != nil {
+		return 0, err
+	}
+	var sequence int64
+	prevHash := auditlog.Genesis
+	err = tx.QueryRow("SELECT sequence, hash FROM audit_log ORDER BY sequence DESC LIMIT 1").Scan(&sequence, &prevHash)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, err
+	}
+
+	ids, records, err := listPendingAuditTx(tx, limit)
+	if err != nil {
+		return 0, err
+	}
+	for i := range records {
+		record := &records[i]
+		sequence++
+		record.Sequence = sequence
+		record.PrevHash = prevHash
+		record.Hash = auditlog.Hash(record)
+		prevHash = record.Hash
+
+		var beforeArg interface{}
+		if record.Before != nil {
+			beforeArg = []byte(record.Before)
+		}
+		query := `INSERT INTO audit_log (sequence, action, actor, request_id, account_id, counterparty_id, before, after, created_at,
+				  prev_hash, hash) VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0), $7, $8, $9, $10, $11)`
+		if _, err := tx.Exec(query, record.Sequence, record.Action, record.Actor, record.RequestID, record.AccountID,
+			record.CounterpartyID, beforeArg, []byte(record.After), record.CreatedAt, record.PrevHash, record.Hash); err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec("DELETE FROM audit_log_pending WHERE id = $1", ids[i]); err != nil {
+			return 0, err
+		}
+	}
+	return len(records), tx.Commit()
+}
+
+// listPendingAuditTx returns up to limit pending records, oldest first,
+// with their ids in audit_log_pending.
+func listPendingAuditTx(tx *sql.Tx, limit int) ([]int64, []models.AuditRecord, error) {
+	query := `SELECT id, action, actor, request_id, account_id, COALESCE(counterparty_id, 0), before, after, created_at
+			  FROM audit_log_pending ORDER BY id LIMIT $1`
+	rows, err := tx.Query(query, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	var records []models.AuditRecord
+	for rows.Next() {
+		var id int64
+		var rec models.AuditRecord
+		var before []byte
+		if err := rows.Scan(&id, &rec.Action, &rec.Actor, &rec.RequestID, &rec.AccountID, &rec.CounterpartyID, &before,
+			&rec.After, &rec.CreatedAt); err != nil {
+			return nil, nil, err
+		}
+		if before != nil {
+			rec.Before = before
+		}
+		ids = append(ids, id)
+		records = append(records, rec)
+	}
+	return ids, records, rows.Err()
+}
@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/auditlog"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
+func TestPsqlAuditLogRepository_ListRecords(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAuditLogRepository{DB: db}
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"sequence", "action", "actor", "request_id", "account_id", "counterparty_id", "before", "after", "created_at",
+		"prev_hash", "hash"}
+
+	mock.ExpectQuery("FROM audit_log WHERE sequence > \\$1 ORDER BY sequence LIMIT \\$2").WithArgs(int64(4), 2).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(5, "create_account", "customer:1", "req-1", 10, 0, nil, []byte(`[{"account_id":10,"balance":0.00,"status":"active"}]`),
+				createdAt, "a", "b").
+			AddRow(6, "transfer", "operator:2", "", 10, 11, []byte(`[{"account_id":10,"balance":5.00}]`),
+				[]byte(`[{"account_id":10,"balance":0.00}]`), createdAt, "b", "c"))
+
+	records, err := repo.ListRecords(4, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.AuditRecord{
+		{Sequence: 5, Action: "create_account", Actor: "customer:1", RequestID: "req-1", AccountID: 10,
+			After: json.RawMessage(`[{"account_id":10,"balance":0.00,"status":"active"}]`), CreatedAt: createdAt, PrevHash: "a", Hash: "b"},
+		{Sequence: 6, Action: "transfer", Actor: "operator:2", AccountID: 10, CounterpartyID: 11,
+			Before: json.RawMessage(`[{"account_id":10,"balance":5.00}]`), After: json.RawMessage(`[{"account_id":10,"balance":0.00}]`),
+			CreatedAt: createdAt, PrevHash: "b", Hash: "c"},
+	}, records)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+// hashArg captures the hash ChainPending writes.
+type hashArg struct{ hash *string }
+
+func (a hashArg) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	*a.hash = s
+	return ok && len(s) == 64
+}
+
+func TestAppendAuditTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	// The record is only written as pending: no lock, nothing read.
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO audit_log_pending").
+		WithArgs("deposit", "customer:1", "req-1", 10, 0, []byte(`[{"account_id":10,"balance":5.00}]`),
+			[]byte(`[{"account_id":10,"balance":7.50}]`), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	err = appendAuditTx(tx, models.AuditDeposit, models.Actor{CustomerID: 1, RequestID: "req-1"}, 10, 0,
+		[]models.AccountState{{AccountID: 10, Balance: money.New(5, 0)}},
+		[]models.AccountState{{AccountID: 10, Balance: money.FromCents(750)}})
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+
+	// A new account has no before.
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO audit_log_pending").
+		WithArgs("create_account", "customer:1", "", 10, 0, nil, []byte(`[{"account_id":10,"balance":0.00}]`), sqlmock.AnyArg()).
+		WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	tx, err = db.Begin()
+	assert.NoError(t, err)
+	err = appendAuditTx(tx, models.AuditCreateAccount, models.Actor{CustomerID: 1}, 10, 0, nil, []models.AccountState{{AccountID: 10}})
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.NoError(t, tx.Rollback())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAuditLogRepository_ChainPending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAuditLogRepository{DB: db}
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	pendingColumns := []string{"id", "action", "actor", "request_id", "account_id", "counterparty_id", "before", "after", "created_at"}
+
+	// Pending records are chained onto the last record in id order, and
+	// each one's hash covers the one before.
+	var first, second string
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(\$1\)`).WithArgs(auditLogLock).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT sequence, hash FROM audit_log ORDER BY sequence DESC LIMIT 1").
+		WillReturnRows(sqlmock.NewRows([]string{"sequence", "hash"}).AddRow(41, "prev"))
+	mock.ExpectQuery("FROM audit_log_pending ORDER BY id LIMIT \\$1").WithArgs(100).
+		WillReturnRows(sqlmock.NewRows(pendingColumns).
+			AddRow(7, "deposit", "customer:1", "req-1", 10, 0, []byte(`[{"account_id":10,"balance":5.00}]`),
+				[]byte(`[{"account_id":10,"balance":7.50}]`), createdAt).
+			AddRow(9, "transfer", "operator:2", "", 10, 11, []byte(`[{"account_id":10,"balance":7.50}]`),
+				[]byte(`[{"account_id":10,"balance":2.50}]`), createdAt))
+	mock.ExpectExec("INSERT INTO audit_log ").
+		WithArgs(int64(42), "deposit", "customer:1", "req-1", 10, 0, []byte(`[{"account_id":10,"balance":5.00}]`),
+			[]byte(`[{"account_id":10,"balance":7.50}]`), createdAt, "prev", hashArg{&first}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("DELETE FROM audit_log_pending WHERE id = \\$1").WithArgs(int64(7)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO audit_log ").
+		WithArgs(int64(43), "transfer", "operator:2", "", 10, 11, []byte(`[{"account_id":10,"balance":7.50}]`),
+			[]byte(`[{"account_id":10,"balance":2.50}]`), createdAt, sqlmock.AnyArg(), hashArg{&second}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("DELETE FROM audit_log_pending WHERE id = \\$1").WithArgs(int64(9)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	n, err := repo.ChainPending(100)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, auditlog.Hash(&models.AuditRecord{
+		Sequence: 42, Action: "deposit", Actor: "customer:1", RequestID: "req-1", AccountID: 10,
+		Before: json.RawMessage(`[{"account_id":10,"balance":5.00}]`), After: json.RawMessage(`[{"account_id":10,"balance":7.50}]`),
+		CreatedAt: createdAt, PrevHash: "prev",
+	}), first)
+	assert.Equal(t, auditlog.Hash(&models.AuditRecord{
+		Sequence: 43, Action: "transfer", Actor: "operator:2", AccountID: 10, CounterpartyID: 11,
+		Before: json.RawMessage(`[{"account_id":10,"balance":7.50}]`), After: json.RawMessage(`[{"account_id":10,"balance":2.50}]`),
+		CreatedAt: createdAt, PrevHash: first,
+	}), second)
+
+	// An empty log starts from the genesis hash.
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(\$1\)`).WithArgs(auditLogLock).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT sequence, hash FROM audit_log").WillReturnRows(sqlmock.NewRows([]string{"sequence", "hash"}))
+	mock.ExpectQuery("FROM audit_log_pending").WithArgs(100).
+		WillReturnRows(sqlmock.NewRows(pendingColumns).
+			AddRow(1, "create_account", "customer:1", "", 10, 0, nil, []byte(`[{"account_id":10,"balance":0.00}]`), createdAt))
+	mock.ExpectExec("INSERT INTO audit_log ").
+		WithArgs(int64(1), "create_account", "customer:1", "", 10, 0, nil, []byte(`[{"account_id":10,"balance":0.00}]`),
+			createdAt, auditlog.Genesis, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("DELETE FROM audit_log_pending").WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	n, err = repo.ChainPending(100)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	// A failure rolls the whole batch back, so its records stay pending.
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(\$1\)`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT sequence, hash FROM audit_log").WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	n, err = repo.ChainPending(100)
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.Zero(t, n)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
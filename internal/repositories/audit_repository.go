@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+type AuditRepository interface {
+	RecordEvent(event *models.AuditEvent) error
+	// ListEvents returns audit events matching the filter, newest first. Up
+	// to filter.Limit rows are returned.
+	ListEvents(filter models.AuditFilter) ([]models.AuditEvent, error)
+}
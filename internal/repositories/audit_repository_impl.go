@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/gregoryAlvim/gobank/internal/database"
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+type PsqlAuditRepository struct {
+	DB *sql.DB
+}
+
+func NewPsqlAuditRepository() *PsqlAuditRepository {
+	return &PsqlAuditRepository{DB: database.DB}
+}
+
+func (r *PsqlAuditRepository) RecordEvent(event *models.AuditEvent) error {
+	query := `INSERT INTO audit_events (operator_id, operator_role, action, method, path, account_id, customer_id,
+			  reason, request, status_code)
+			  VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0), NULLIF($7, 0), NULLIF($8, ''), NULLIF($9, ''), $10)
+			  RETURNING id, created_at`
+	return r.DB.QueryRow(query, event.OperatorID, event.OperatorRole, event.Action, event.Method, event.Path,
+		event.AccountID, event.CustomerID, event.Reason, event.Request, event.StatusCode).Scan(&event.ID, &event.CreatedAt)
+}
+
+func (r *PsqlAuditRepository) ListEvents(filter models.AuditFilter) ([]models.AuditEvent, error) {
+	query := `SELECT id, operator_id, operator_role, action, method, path, COALESCE(account_id, 0),
+			  COALESCE(customer_id, 0), COALESCE(reason, ''), COALESCE(request, ''), status_code, created_at
+			  FROM audit_events WHERE true`
+	var args []interface{}
+
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		query += fmt.Sprintf(" AND "+condition, len(args))
+	}
+	if filter.BeforeID > 0 {
+		addCondition("id < $%d", filter.BeforeID)
+	}
+	if filter.AccountID > 0 {
+		addCondition("account_id = $%d", filter.AccountID)
+	}
+	if filter.OperatorID > 0 {
+		addCondition("operator_id = $%d", filter.OperatorID)
+	}
+
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.AuditEvent{}
+	for rows.Next() {
+		var e models.AuditEvent
+		if err := rows.Scan(&e.ID, &e.OperatorID, &e.OperatorRole, &e.Action, &e.Method, &e.Path, &e.AccountID,
+			&e.CustomerID, &e.Reason, &e.Request, &e.StatusCode, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
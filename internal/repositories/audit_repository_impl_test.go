@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+func TestPsqlAuditRepository_RecordEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAuditRepository{DB: db}
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`INSERT INTO audit_events`).
+		WithArgs(4, "teller", "account:freeze", "POST", "/account/10/freeze", 10, 0, "chargeback", `{"reason":"chargeback"}`, 200).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, createdAt))
+
+	event := &models.AuditEvent{
+		OperatorID: 4, OperatorRole: "teller", Action: "account:freeze", Method: "POST", Path: "/account/10/freeze",
+		AccountID: 10, Reason: "chargeback", Request: `{"reason":"chargeback"}`, StatusCode: 200,
+	}
+	assert.NoError(t, repo.RecordEvent(event))
+	assert.Equal(t, int64(1), event.ID)
+	assert.Equal(t, createdAt, event.CreatedAt)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAuditRepository_ListEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAuditRepository{DB: db}
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"id", "operator_id", "operator_role", "action", "method", "path", "account_id",
+		"customer_id", "reason", "request", "status_code", "created_at"}
+
+	mock.ExpectQuery(`FROM audit_events WHERE true AND id < \$1 AND account_id = \$2 ORDER BY id DESC LIMIT \$3`).
+		WithArgs(int64(20), 10, 51).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(12, 4, "teller", "account:freeze", "POST", "/account/10/freeze", 10, 0, "chargeback", "", 200, createdAt))
+
+	events, err := repo.ListEvents(models.AuditFilter{AccountID: 10, BeforeID: 20, Limit: 51})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.AuditEvent{{
+		ID: 12, OperatorID: 4, OperatorRole: "teller", Action: "account:freeze", Method: "POST",
+		Path: "/account/10/freeze", AccountID: 10, Reason: "chargeback", StatusCode: 200, CreatedAt: createdAt,
+	}}, events)
+
+	mock.ExpectQuery(`FROM audit_events WHERE true ORDER BY id DESC LIMIT \$1`).WithArgs(50).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	events, err = repo.ListEvents(models.AuditFilter{Limit: 50})
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
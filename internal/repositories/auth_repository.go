@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+type AuthRepository interface {
+	// GetCredentials finds the customer whose CPF or CNPJ is taxID, without
+	// mask, and returns its ID and password hash.
+	GetCredentials(taxID string) (customerID int, passwordHash string, err error)
+	CreateRefreshToken(token *models.RefreshToken) error
+	// RotateRefreshToken revokes the live token with oldHash and stores next
+	// for the same customer. Presenting a token that was already revoked
+	// revokes every token of its customer.
+	RotateRefreshToken(oldHash string, next *models.RefreshToken) error
+	RevokeRefreshToken(tokenHash string) error
+	GetOperatorCredentials(username string) (operatorID int, role string, passwordHash string, err error)
+	CreateOperator(operator *models.Operator) error
+}
@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/gregoryAlvim/gobank/internal/database"
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used")
+	ErrOperatorNotFound    = errors.New("operator not found")
+	ErrDuplicateUsername   = errors.New("an operator with this username already exists")
+)
+
+type PsqlAuthRepository struct {
+	DB *sql.DB
+}
+
+func NewPsqlAuthRepository() *PsqlAuthRepository {
+	return &PsqlAuthRepository{DB: database.DB}
+}
+
+// GetCredentials returns ErrCustomerNotFound for unknown tax IDs and for
+// customers that never set a password.
+func (r *PsqlAuthRepository) GetCredentials(taxID string) (int, string, error) {
+	var customerID int
+	var passwordHash string
+	query := "SELECT id, password_hash FROM customers WHERE (cpf = $1 OR cnpj = $1) AND password_hash IS NOT NULL"
+	if err := r.DB.QueryRow(query, taxID).Scan(&customerID, &passwordHash); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, "", ErrCustomerNotFound
+		}
+		return 0, "", err
+	}
+	return customerID, passwordHash, nil
+}
+
+func (r *PsqlAuthRepository) CreateRefreshToken(token *models.RefreshToken) error {
+	return insertRefreshToken(r.DB, token)
+}
+
+func insertRefreshToken(q interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}, token *models.RefreshToken) error {
+	query := "INSERT INTO refresh_tokens (customer_id, token_hash, expires_at) VALUES ($1, $2, $3) RETURNING id"
+	return q.QueryRow(query, token.CustomerID, token.TokenHash, token.ExpiresAt).Scan(&token.ID)
+}
+
+func (r *PsqlAuthRepository) RotateRefreshToken(oldHash string, next *models.RefreshToken) error {
+	tx, err := r.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var customerID int
+	var revoked, expired bool
+	query := `SELECT customer_id, revoked_at IS NOT NULL, expires_at <= now()
+			  FROM refresh_tokens WHERE token_hash = $1 FOR UPDATE`
+	if err := tx.QueryRow(query, oldHash).Scan(&customerID, &revoked, &expired); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrInvalidRefreshToken
+		}
+		return err
+	}
+
+	if revoked {
+		// A revoked token coming back means it leaked: end every session
+		// of the customer.
+		if _, err := tx.Exec("UPDATE refresh_tokens SET revoked_at = now() WHERE customer_id = $1 AND revoked_at IS NULL", customerID); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		return ErrRefreshTokenReused
+	}
+	if expired {
+		return ErrInvalidRefreshToken
+	}
+
+	if _, err := tx.Exec("UPDATE refresh_tokens SET revoked_at = now() WHERE token_hash = $1", oldHash); err != nil {
+		return err
+	}
+	next.CustomerID = customerID
+	if err := insertRefreshToken(tx, next); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *PsqlAuthRepository) RevokeRefreshToken(tokenHash string) error {
+	_, err := r.DB.Exec("UPDATE refresh_tokens SET revoked_at = now() WHERE token_hash = $1 AND revoked_at IS NULL", tokenHash)
+	return err
+}
+
+// GetOperatorCredentials returns ErrOperatorNotFound for unknown usernames.
+func (r *PsqlAuthRepository) GetOperatorCredentials(username string) (int, string, string, error) {
+	var operatorID int
+	var role, passwordHash string
+	query := "SELECT id, role, password_hash FROM operators WHERE username = $1"
+	if err := r.DB.QueryRow(query, username).Scan(&operatorID, &role, &passwordHash); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, "", "", ErrOperatorNotFound
+		}
+		return 0, "", "", err
+	}
+	return operatorID, role, passwordHash, nil
+}
+
+func (r *PsqlAuthRepository) CreateOperator(operator *models.Operator) error {
+	query := "INSERT INTO operators (username, role, password_hash) VALUES ($1, $2, $3) RETURNING id"
+	err := r.DB.QueryRow(query, operator.Username, operator.Role, operator.PasswordHash).Scan(&operator.ID)
+	if isUniqueViolation(err, "operators_username_key") {
+		return ErrDuplicateUsername
+	}
+	return err
+}
@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+func TestPsqlAuthRepository_GetCredentials(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAuthRepository{DB: db}
+
+	mock.ExpectQuery(`SELECT id, password_hash FROM customers WHERE \(cpf = \$1 OR cnpj = \$1\) AND password_hash IS NOT NULL`).
+		WithArgs("52998224725").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "password_hash"}).AddRow(3, "$2a$10$hash"))
+
+	customerID, hash, err := repo.GetCredentials("52998224725")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, customerID)
+	assert.Equal(t, "$2a$10$hash", hash)
+
+	mock.ExpectQuery(`SELECT id, password_hash FROM customers`).WithArgs("11222333000181").WillReturnError(sql.ErrNoRows)
+
+	_, _, err = repo.GetCredentials("11222333000181")
+	assert.ErrorIs(t, err, ErrCustomerNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAuthRepository_RotateRefreshToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAuthRepository{DB: db}
+	expiresAt := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	lookup := `SELECT customer_id, revoked_at IS NOT NULL, expires_at <= now\(\)\s+FROM refresh_tokens WHERE token_hash = \$1 FOR UPDATE`
+
+	// A live token is revoked and replaced.
+	mock.ExpectBegin()
+	mock.ExpectQuery(lookup).WithArgs("old").
+		WillReturnRows(sqlmock.NewRows([]string{"customer_id", "revoked", "expired"}).AddRow(3, false, false))
+	mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = now\(\) WHERE token_hash = \$1`).WithArgs("old").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO refresh_tokens`).WithArgs(3, "new", expiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+	mock.ExpectCommit()
+
+	next := &models.RefreshToken{TokenHash: "new", ExpiresAt: expiresAt}
+	err = repo.RotateRefreshToken("old", next)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8), next.ID)
+	assert.Equal(t, 3, next.CustomerID)
+
+	// Reusing a revoked token ends every session of the customer.
+	mock.ExpectBegin()
+	mock.ExpectQuery(lookup).WithArgs("old").
+		WillReturnRows(sqlmock.NewRows([]string{"customer_id", "revoked", "expired"}).AddRow(3, true, false))
+	mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = now\(\) WHERE customer_id = \$1 AND revoked_at IS NULL`).WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err = repo.RotateRefreshToken("old", &models.RefreshToken{TokenHash: "other", ExpiresAt: expiresAt})
+	assert.ErrorIs(t, err, ErrRefreshTokenReused)
+
+	// Expired and unknown tokens are refused.
+	mock.ExpectBegin()
+	mock.ExpectQuery(lookup).WithArgs("expired").
+		WillReturnRows(sqlmock.NewRows([]string{"customer_id", "revoked", "expired"}).AddRow(3, false, true))
+	mock.ExpectRollback()
+
+	err = repo.RotateRefreshToken("expired", &models.RefreshToken{TokenHash: "other", ExpiresAt: expiresAt})
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lookup).WithArgs("unknown").WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	err = repo.RotateRefreshToken("unknown", &models.RefreshToken{TokenHash: "other", ExpiresAt: expiresAt})
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAuthRepository_CreateAndRevokeRefreshToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAuthRepository{DB: db}
+	expiresAt := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`INSERT INTO refresh_tokens \(customer_id, token_hash, expires_at\)`).WithArgs(3, "hash", expiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = now\(\) WHERE token_hash = \$1 AND revoked_at IS NULL`).WithArgs("hash").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	token := &models.RefreshToken{CustomerID: 3, TokenHash: "hash", ExpiresAt: expiresAt}
+	assert.NoError(t, repo.CreateRefreshToken(token))
+	assert.Equal(t, int64(1), token.ID)
+	assert.NoError(t, repo.RevokeRefreshToken("hash"))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlAuthRepository_Operators(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlAuthRepository{DB: db}
+
+	mock.ExpectQuery(`INSERT INTO operators \(username, role, password_hash\) VALUES \(\$1, \$2, \$3\) RETURNING id`).
+		WithArgs("ana", "teller", "$2a$10$hash").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectQuery(`INSERT INTO operators`).WithArgs("ana", "auditor", "$2a$10$hash").
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "operators_username_key"})
+
+	operator := &models.Operator{Username: "ana", Role: "teller", PasswordHash: "$2a$10$hash"}
+	assert.NoError(t, repo.CreateOperator(operator))
+	assert.Equal(t, 4, operator.ID)
+	err = repo.CreateOperator(&models.Operator{Username: "ana", Role: "auditor", PasswordHash: "$2a$10$hash"})
+	assert.ErrorIs(t, err, ErrDuplicateUsername)
+
+	mock.ExpectQuery(`SELECT id, role, password_hash FROM operators WHERE username = \$1`).WithArgs("ana").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "password_hash"}).AddRow(4, "teller", "$2a$10$hash"))
+	mock.ExpectQuery(`SELECT id, role, password_hash FROM operators`).WithArgs("bob").WillReturnError(sql.ErrNoRows)
+
+	operatorID, role, hash, err := repo.GetOperatorCredentials("ana")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, operatorID)
+	assert.Equal(t, "teller", role)
+	assert.Equal(t, "$2a$10$hash", hash)
+
+	_, _, _, err = repo.GetOperatorCredentials("bob")
+	assert.ErrorIs(t, err, ErrOperatorNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
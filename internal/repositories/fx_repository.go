@@ -0,0 +1,13 @@
+package repositories
+
+import "github.com/gregoryAlvim/gobank/internal/models"
+
+type FXRepository interface {
+	ListRates() ([]models.FXRate, error)
+	GetRate(base, quote string) (*models.FXRate, error)
+	// SetRate creates or replaces the rate of its currency pair and fills
+	// in UpdatedAt.
+	SetRate(rate *models.FXRate) error
+	// CreateQuote fills in the quote's ID and CreatedAt.
+	CreateQuote(quote *models.FXQuote) error
+}
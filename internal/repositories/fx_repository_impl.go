@@ -0,0 +1,175 @@
+package repositories
+
+import (
+	"database/sql"
+
+	"github.com/gregoryAlvim/gobank/internal/database"
+	"github.com/gregoryAlvim/gobank/internal/fx"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
+type PsqlFXRepository struct {
+	DB *sql.DB
+}
+
+func NewPsqlFXRepository() *PsqlFXRepository {
+	return &PsqlFXRepository{DB: database.DB}
+}
+
+const fxRateColumns = "base, quote, rate, spread_bps, updated_at"
+
+func scanFXRate(row interface{ Scan(...interface{}) error }, rate *models.FXRate) error {
+	return row.Scan(&rate.Base, &rate.Quote, &rate.Rate, &rate.SpreadBps, &rate.UpdatedAt)
+}
+
+func (r *PsqlFXRepository) ListRates() ([]models.FXRate, error) {
+	rows, err := r.DB.Query("SELECT " + fxRateColumns + " FROM fx_rates ORDER BY base, quote")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := []models.FXRate{}
+	for rows.Next() {
+		var rate models.FXRate
+		if err := scanFXRate(rows, &rate); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+func (r *PsqlFXRepository) GetRate(base, quote string) (*models.FXRate, error) {
+	return getFXRate(r.DB, base, quote)
+}
+
+// getFXRate reads the rate of a currency pair with db, which may be a
+// transaction.
+func getFXRate(db interface {
+	QueryRow(string, ...interface{}) *sql.Row
+}, base, quote string) (*models.FXRate, error) {
+	var rate models.FXRate
+	query := "SELECT " + fxRateColumns + " FROM fx_rates WHERE base = $1 AND quote = $2"
+	if err := scanFXRate(db.QueryRow(query, base, quote), &rate); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrFXRateNotFound
+		}
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *PsqlFXRepository) SetRate(rate *models.FXRate) error {
+	query := `INSERT INTO fx_rates (base, quote, rate, spread_bps) VALUES ($1, $2, $3, $4)
+			  ON CONFLICT (base, quote) DO UPDATE SET rate = EXCLUDED.rate, spread_bps = EXCLUDED.spread_bps, updated_at = now()
+			  RETURNING updated_at`
+	return r.DB.QueryRow(query, rate.Base, rate.Quote, rate.Rate, rate.SpreadBps).Scan(&rate.UpdatedAt)
+}
+
+func (r *PsqlFXRepository) CreateQuote(quote *models.FXQuote) error {
+	query := `INSERT INTO fx_quotes (account_id, from_currency, to_currency, amount, converted_amount, mid_rate, spread_bps, rate, expires_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at`
+	return r.DB.QueryRow(query, quote.AccountID, quote.FromCurrency, quote.ToCurrency, quote.Amount, quote.ConvertedAmount,
+		quote.MidRate, quote.SpreadBps, quote.Rate, quote.ExpiresAt).Scan(&quote.ID, &quote.CreatedAt)
+}
+
+// convertTx converts amount out of the account fromID from one currency
+// to the other. With a quote, the quote must have been made for this
+// account, pair and amount, and is spent; otherwise the current rate is
+// used.
+func convertTx(tx *sql.Tx, fromID int, from, to string, amount money.Money, quoteID int64) (*models.FXConversion, error) {
+	if quoteID == 0 {
+		rate, err := getFXRate(tx, from, to)
+		if err != nil {
+			return nil, err
+		}
+		return rate.Convert(amount)
+	}
+
+	var quote models.FXQuote
+	var expired bool
+	query := `SELECT id, account_id, from_currency, to_currency, amount, converted_amount, mid_rate, spread_bps, rate, used_at,
+			  expires_at <= now() FROM fx_quotes WHERE id = $1 FOR UPDATE`
+	err := tx.QueryRow(query, quoteID).Scan(&quote.ID, &quote.AccountID, &quote.FromCurrency, &quote.ToCurrency, &quote.Amount,
+		&quote.ConvertedAmount, &quote.MidRate, &quote.SpreadBps, &quote.Rate, &quote.UsedAt, &expired)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrQuoteNotFound
+		}
+		return nil, err
+	}
+	switch {
+	case quote.AccountID != fromID || quote.FromCurrency != from || quote.ToCurrency != to || quote.Amount != amount:
+		return nil, ErrQuoteMismatch
+	case quote.UsedAt != nil:
+		return nil, ErrQuoteUsed
+	case expired:
+		return nil, ErrQuoteExpired
+	}
+
+	if _, err := tx.Exec("UPDATE fx_quotes SET used_at = now() WHERE id = $1", quoteID); err != nil {
+		return nil, err
+	}
+	return &models.FXConversion{
+		QuoteID:         &quote.ID,
+		FromCurrency:    quote.FromCurrency,
+		ToCurrency:      quote.ToCurrency,
+		SourceAmount:    quote.Amount,
+		ConvertedAmount: quote.ConvertedAmount,
+		MidRate:         quote.MidRate,
+		SpreadBps:       quote.SpreadBps,
+		Rate:            quote.Rate,
+	}, nil
+}
+
+// recordConversionTx journals a cross-currency transfer like recordEntryTx
+// and records the conversion it applied.
+func recordConversionTx(tx *sql.Tx, entry *models.JournalEntry, balances map[int]money.Money, conversion *models.FXConversion) error {
+	if err := recordEntryTx(tx, entry, balances); err != nil {
+		return err
+	}
+	conversion.JournalEntryID = entry.ID
+
+	query := `INSERT INTO fx_conversions (journal_entry_id, quote_id, from_currency, to_currency, source_amount, converted_amount,
+			  mid_rate, spread_bps, rate) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err := tx.Exec(query, entry.ID, conversion.QuoteID, conversion.FromCurrency, conversion.ToCurrency, conversion.SourceAmount,
+		conversion.ConvertedAmount, conversion.MidRate, conversion.SpreadBps, conversion.Rate)
+	return err
+}
+
+// nullFXConversion scans the fx_conversions columns of a LEFT JOIN, which
+// are all NULL when there was no conversion.
+type nullFXConversion struct {
+	QuoteID         sql.NullInt64
+	FromCurrency    sql.NullString
+	ToCurrency      sql.NullString
+	SourceAmount    *money.Money
+	ConvertedAmount *money.Money
+	MidRate         *fx.Rate
+	SpreadBps       sql.NullInt64
+	Rate            *fx.Rate
+}
+
+// get returns the conversion of the journal entry, or nil when there was
+// none.
+func (c *nullFXConversion) get(journalEntryID int64) *models.FXConversion {
+	if !c.FromCurrency.Valid {
+		return nil
+	}
+	conversion := &models.FXConversion{
+		JournalEntryID:  journalEntryID,
+		FromCurrency:    c.FromCurrency.String,
+		ToCurrency:      c.ToCurrency.String,
+		SourceAmount:    *c.SourceAmount,
+		ConvertedAmount: *c.ConvertedAmount,
+		MidRate:         *c.MidRate,
+		SpreadBps:       int(c.SpreadBps.Int64),
+		Rate:            *c.Rate,
+	}
+	if c.QuoteID.Valid {
+		conversion.QuoteID = &c.QuoteID.Int64
+	}
+	return conversion
+}
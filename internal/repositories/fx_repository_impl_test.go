@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/fx"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
+func TestPsqlFXRepository_Rates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlFXRepository{DB: db}
+	updatedAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	columns := []string{"base", "quote", "rate", "spread_bps", "updated_at"}
+
+	mock.ExpectQuery("FROM fx_rates ORDER BY base, quote").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("USD", "BRL", "5.4321", 50, updatedAt))
+
+	rates, err := repo.ListRates()
+	assert.NoError(t, err)
+	assert.Equal(t, []models.FXRate{{Base: "USD", Quote: "BRL", Rate: fx.MustParseRate("5.4321"), SpreadBps: 50, UpdatedAt: updatedAt}}, rates)
+
+	mock.ExpectQuery("FROM fx_rates WHERE base").WithArgs("USD", "EUR").WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetRate("USD", "EUR")
+	assert.ErrorIs(t, err, ErrFXRateNotFound)
+
+	rate := &models.FXRate{Base: "USD", Quote: "BRL", Rate: fx.MustParseRate("5.5"), SpreadBps: 25}
+	mock.ExpectQuery("INSERT INTO fx_rates .* ON CONFLICT \\(base, quote\\) DO UPDATE").
+		WithArgs("USD", "BRL", rate.Rate, 25).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(updatedAt))
+
+	assert.NoError(t, repo.SetRate(rate))
+	assert.Equal(t, updatedAt, rate.UpdatedAt)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlFXRepository_CreateQuote(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlFXRepository{DB: db}
+	createdAt := time.Now()
+	quote := &models.FXQuote{
+		AccountID: 10, FromCurrency: "USD", ToCurrency: "BRL", Amount: money.New(100, 0), ConvertedAmount: money.MustParse("540.49"),
+		MidRate: fx.MustParseRate("5.4321"), SpreadBps: 50, Rate: fx.MustParseRate("5.4049395"), ExpiresAt: createdAt.Add(time.Minute),
+	}
+	mock.ExpectQuery("INSERT INTO fx_quotes").
+		WithArgs(10, "USD", "BRL", quote.Amount, quote.ConvertedAmount, quote.MidRate, 50, quote.Rate, quote.ExpiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, createdAt))
+
+	assert.NoError(t, repo.CreateQuote(quote))
+	assert.Equal(t, int64(3), quote.ID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+type IdempotencyRepository interface {
+	// Reserve claims the key for a new request until lockedUntil and
+	// returns the token of the reservation. If the key is already in use
+	// and not expired, the existing record is returned with an empty
+	// token. A key still in progress past its lock is taken over by a
+	// request with the same hash.
+	Reserve(key, requestHash string, lockedUntil, expiresAt time.Time) (record *models.IdempotencyRecord, token string, err error)
+	// Renew, Complete and Release only act on the reservation with token,
+	// so they do nothing once the key was taken over.
+	Renew(key, token string, lockedUntil time.Time) error
+	Complete(key, token string, statusCode int, contentType string, body []byte) error
+	Release(key, token string) error
+	DeleteExpired() (int64, error)
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/database"
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+type PsqlIdempotencyRepository struct {
+	DB *sql.DB
+}
+
+func NewPsqlIdempotencyRepository() *PsqlIdempotencyRepository {
+	return &PsqlIdempotencyRepository{DB: database.DB}
+}
+
+func (r *PsqlIdempotencyRepository) Reserve(key, requestHash string, lockedUntil, expiresAt time.Time) (*models.IdempotencyRecord, string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", err
+	}
+	token := hex.EncodeToString(b)
+
+	// An expired key is taken over as if it had never been used. So is a
+	// key whose request stopped without completing or releasing it, but
+	// only by a retry of the same request.
+	query := `INSERT INTO idempotency_keys (key, request_hash, token, locked_until, expires_at) VALUES ($1, $2, $3, $4, $5)
+			  ON CONFLICT (key) DO UPDATE
+			  SET request_hash = EXCLUDED.request_hash, token = EXCLUDED.token, status_code = NULL, content_type = NULL,
+			      response_body = NULL, created_at = now(), locked_until = EXCLUDED.locked_until,
+			      expires_at = EXCLUDED.expires_at
+			  WHERE idempotency_keys.expires_at <= now()
+			     OR (idempotency_keys.status_code IS NULL AND idempotency_keys.locked_until <= now()
+			         AND idempotency_keys.request_hash = EXCLUDED.request_hash)
+			  RETURNING key`
+	var reservedKey string
+	err := r.DB.QueryRow(query, key, requestHash, token, lockedUntil, expiresAt).Scan(&reservedKey)
+	if err == nil {
+		return nil, token, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, "", err
+	}
+
+	record := &models.IdempotencyRecord{Key: key}
+	var statusCode sql.NullInt64
+	var contentType sql.NullString
+	query = `SELECT request_hash, status_code, content_type, response_body, expires_at FROM idempotency_keys WHERE key = $1`
+	err = r.DB.QueryRow(query, key).Scan(&record.RequestHash, &statusCode, &contentType, &record.ResponseBody, &record.ExpiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+	record.StatusCode = int(statusCode.Int64)
+	record.ContentType = contentType.String
+	return record, "", nil
+}
+
+func (r *PsqlIdempotencyRepository) Renew(key, token string, lockedUntil time.Time) error {
+	query := "UPDATE idempotency_keys SET locked_until = $3 WHERE key = $1 AND token = $2 AND status_code IS NULL"
+	_, err := r.DB.Exec(query, key, token, lockedUntil)
+	return err
+}
+
+func (r *PsqlIdempotencyRepository) Complete(key, token string, statusCode int, contentType string, body []byte) error {
+	query := "UPDATE idempotency_keys SET status_code = $1, content_type = $2, response_body = $3 WHERE key = $4 AND token = $5"
+	_, err := r.DB.Exec(query, statusCode, contentType, body, key, token)
+	return err
+}
+
+func (r *PsqlIdempotencyRepository) Release(key, token string) error {
+	_, err := r.DB.Exec("DELETE FROM idempotency_keys WHERE key = $1 AND token = $2", key, token)
+	return err
+}
+
+func (r *PsqlIdempotencyRepository) DeleteExpired() (int64, error) {
+	res, err := r.DB.Exec("DELETE FROM idempotency_keys WHERE expires_at <= now()")
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
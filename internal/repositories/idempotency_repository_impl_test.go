@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+func TestPsqlIdempotencyRepository_Reserve(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlIdempotencyRepository{DB: db}
+	lockedUntil := time.Date(2026, 3, 1, 12, 1, 0, 0, time.UTC)
+	expiresAt := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	// New key
+	mock.ExpectQuery("INSERT INTO idempotency_keys").WithArgs("key-1", "hash", sqlmock.AnyArg(), lockedUntil, expiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow("key-1"))
+
+	record, token, err := repo.Reserve("key-1", "hash", lockedUntil, expiresAt)
+	assert.NoError(t, err)
+	assert.Len(t, token, 32)
+	assert.Nil(t, record)
+
+	// Every reservation gets its own token.
+	mock.ExpectQuery("INSERT INTO idempotency_keys").WithArgs("key-3", "hash", sqlmock.AnyArg(), lockedUntil, expiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow("key-3"))
+
+	_, other, err := repo.Reserve("key-3", "hash", lockedUntil, expiresAt)
+	assert.NoError(t, err)
+	assert.NotEqual(t, token, other)
+
+	// Key already used: the stored response is returned
+	mock.ExpectQuery("INSERT INTO idempotency_keys").WithArgs("key-1", "hash", sqlmock.AnyArg(), lockedUntil, expiresAt).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT (.+) FROM idempotency_keys").WithArgs("key-1").
+		WillReturnRows(sqlmock.NewRows([]string{"request_hash", "status_code", "content_type", "response_body", "expires_at"}).
+			AddRow("hash", 200, "application/json", []byte(`{"ok":true}`), expiresAt))
+
+	record, token, err = repo.Reserve("key-1", "hash", lockedUntil, expiresAt)
+	assert.NoError(t, err)
+	assert.Empty(t, token)
+	assert.Equal(t, &models.IdempotencyRecord{
+		Key:          "key-1",
+		RequestHash:  "hash",
+		StatusCode:   200,
+		ContentType:  "application/json",
+		ResponseBody: []byte(`{"ok":true}`),
+		ExpiresAt:    expiresAt,
+	}, record)
+
+	// Key still in progress
+	mock.ExpectQuery("INSERT INTO idempotency_keys").WithArgs("key-2", "hash", sqlmock.AnyArg(), lockedUntil, expiresAt).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT (.+) FROM idempotency_keys").WithArgs("key-2").
+		WillReturnRows(sqlmock.NewRows([]string{"request_hash", "status_code", "content_type", "response_body", "expires_at"}).
+			AddRow("hash", nil, nil, nil, expiresAt))
+
+	record, token, err = repo.Reserve("key-2", "hash", lockedUntil, expiresAt)
+	assert.NoError(t, err)
+	assert.Empty(t, token)
+	assert.Equal(t, 0, record.StatusCode)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlIdempotencyRepository_RenewCompleteReleaseAndExpire(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlIdempotencyRepository{DB: db}
+
+	lockedUntil := time.Date(2026, 3, 1, 12, 1, 0, 0, time.UTC)
+
+	// Only the reservation holding the token is touched.
+	mock.ExpectExec("UPDATE idempotency_keys SET locked_until = \\$3 WHERE key = \\$1 AND token = \\$2 AND status_code IS NULL").
+		WithArgs("key-1", "tok-1", lockedUntil).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.Renew("key-1", "tok-1", lockedUntil))
+
+	mock.ExpectExec("UPDATE idempotency_keys SET status_code .* WHERE key = \\$4 AND token = \\$5").
+		WithArgs(201, "application/json", []byte(`{}`), "key-1", "tok-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.Complete("key-1", "tok-1", 201, "application/json", []byte(`{}`)))
+
+	mock.ExpectExec("DELETE FROM idempotency_keys WHERE key = \\$1 AND token = \\$2").WithArgs("key-2", "tok-2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.Release("key-2", "tok-2"))
+
+	mock.ExpectExec("DELETE FROM idempotency_keys WHERE expires_at").WillReturnResult(sqlmock.NewResult(0, 3))
+	n, err := repo.DeleteExpired()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
+type InterestRepository interface {
+	ListRates() ([]models.InterestRate, error)
+	// SetRate keeps the category's EffectiveFrom when it already has a
+	// rate.
+	SetRate(rate *models.InterestRate) error
+	SetCDIRate(rate *models.CDIRate) error
+	// GetCDIRate returns the last CDI rate published on or before date.
+	GetCDIRate(date time.Time) (*models.CDIRate, error)
+	ListAccrualAccounts() ([]models.AccrualAccount, error)
+	// GetEndOfDayBalance returns the account's balance at the end of date,
+	// in UTC.
+	GetEndOfDayBalance(accountID int, date time.Time) (money.Money, error)
+	// RecordAccrual returns false when the day was already accrued.
+	RecordAccrual(accrual *models.InterestAccrual) (bool, error)
+	// ListUnpaidInterest sums the unpaid accruals dated before before, per
+	// account.
+	ListUnpaidInterest(before time.Time) ([]models.UnpaidInterest, error)
+	// PayInterest credits the account's unpaid accruals dated before before
+	// and returns the amount credited. The payment is appended to the audit
+	// log as made by the interest job.
+	PayInterest(accountID int, before time.Time) (money.Money, error)
+}
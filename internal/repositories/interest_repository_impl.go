@@ -0,0 +1,218 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/database"
+	"github.com/gregoryAlvim/gobank/internal/ledger"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
+var ErrCDIRateNotFound = errors.New("no CDI rate has been published on or before the day")
+
+type PsqlInterestRepository struct {
+	DB *sql.DB
+}
+
+func NewPsqlInterestRepository() *PsqlInterestRepository {
+	return &PsqlInterestRepository{DB: database.DB}
+}
+
+func (r *PsqlInterestRepository) ListRates() ([]models.InterestRate, error) {
+	rows, err := r.DB.Query("SELECT category, rate_type, rate_bps, effective_from FROM interest_rates ORDER BY category")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := []models.InterestRate{}
+	for rows.Next() {
+		var rate models.InterestRate
+		if err := rows.Scan(&rate.Category, &rate.Type, &rate.RateBps, &rate.EffectiveFrom); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+func (r *PsqlInterestRepository) SetRate(rate *models.InterestRate) error {
+	query := `INSERT INTO interest_rates (category, rate_type, rate_bps) VALUES ($1, $2, $3)
+			  ON CONFLICT (category) DO UPDATE SET rate_type = EXCLUDED.rate_type, rate_bps = EXCLUDED.rate_bps
+			  RETURNING effective_from`
+	return r.DB.QueryRow(query, rate.Category, rate.Type, rate.RateBps).Scan(&rate.EffectiveFrom)
+}
+
+func (r *PsqlInterestRepository) SetCDIRate(rate *models.CDIRate) error {
+	query := `INSERT INTO cdi_rates (rate_date, annual_rate_bps) VALUES ($1, $2)
+			  ON CONFLICT (rate_date) DO UPDATE SET annual_rate_bps = EXCLUDED.annual_rate_bps`
+	_, err := r.DB.Exec(query, rate.Date, rate.AnnualRateBps)
+	return err
+}
+
+func (r *PsqlInterestRepository) GetCDIRate(date time.Time) (*models.CDIRate, error) {
+	var rate models.CDIRate
+	query := "SELECT rate_date, annual_rate_bps FROM cdi_rates WHERE rate_date <= $1 ORDER BY rate_date DESC LIMIT 1"
+	if err := r.DB.QueryRow(query, date).Scan(&rate.Date, &rate.AnnualRateBps); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrCDIRateNotFound
+		}
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// ListAccrualAccounts returns the open accounts whose category earns
+// interest, with the day each one starts earning and the last day already
+// accrued.
+func (r *PsqlInterestRepository) ListAccrualAccounts() ([]models.AccrualAccount, error) {
+	query := `SELECT a.id, ir.category, ir.rate_type, ir.rate_bps, ir.effective_from,
+			  GREATEST((a.created_at AT TIME ZONE 'UTC')::date, ir.effective_from),
+			  (SELECT MAX(accrual_date) FROM interest_accruals WHERE account_id = a.id)
+			  FROM accounts a JOIN interest_rates ir ON ir.category = a.category
+			  WHERE a.customer_id IS NOT NULL AND a.status <> 'closed' ORDER BY a.id`
+	rows, err := r.DB.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := []models.AccrualAccount{}
+	for rows.Next() {
+		var account models.AccrualAccount
+		var lastAccrued sql.NullTime
+		rate := &account.Rate
+		if err := rows.Scan(&account.AccountID, &rate.Category, &rate.Type, &rate.RateBps, &rate.EffectiveFrom,
+			&account.Start, &lastAccrued); err != nil {
+			return nil, err
+		}
+		if lastAccrued.Valid {
+			account.LastAccrued = &lastAccrued.Time
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, rows.Err()
+}
+
+// GetEndOfDayBalance reads the balance after the account's last movement
+// of the day from its history. An account with no movement by then has a
+// zero balance.
+func (r *PsqlInterestRepository) GetEndOfDayBalance(accountID int, date time.Time) (money.Money, error) {
+	var balance money.Money
+	query := "SELECT balance_after FROM account_transactions WHERE account_id = $1 AND created_at < $2 ORDER BY id DESC LIMIT 1"
+	err := r.DB.QueryRow(query, accountID, date.AddDate(0, 0, 1)).Scan(&balance)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return balance, err
+}
+
+func (r *PsqlInterestRepository) RecordAccrual(accrual *models.InterestAccrual) (bool, error) {
+	query := `INSERT INTO interest_accruals (account_id, accrual_date, balance, rate_type, rate_bps, cdi_rate_bps, amount)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (account_id, accrual_date) DO NOTHING`
+	res, err := r.DB.Exec(query, accrual.AccountID, accrual.Date, accrual.Balance, accrual.Rate.Type, accrual.Rate.RateBps,
+		accrual.CDIRateBps, accrual.Amount)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *PsqlInterestRepository) ListUnpaidInterest(before time.Time) ([]models.UnpaidInterest, error) {
+	query := `SELECT account_id, SUM(amount) FROM interest_accruals
+			  WHERE accrual_date < $1 AND paid_at IS NULL GROUP BY account_id ORDER BY account_id`
+	rows, err := r.DB.Query(query, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	unpaid := []models.UnpaidInterest{}
+	for rows.Next() {
+		var u models.UnpaidInterest
+		if err := rows.Scan(&u.AccountID, &u.Amount); err != nil {
+			return nil, err
+		}
+		unpaid = append(unpaid, u)
+	}
+	return unpaid, rows.Err()
+}
+
+// PayInterest credits the accrued interest exactly as a deposit is
+// credited, journaled as interest against equity, marks the accruals paid
+// and appends the payment to the audit log in the same transaction.
+// Accruals that add up to less than half a cent are marked paid without a
+// journal entry or audit record.
+func (r *PsqlInterestRepository) PayInterest(accountID int, before time.Time) (money.Money, error) {
+	tx, err := r.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	total, balance, err := payInterestTx(tx, accountID, before)
+	if err != nil {
+		return 0, err
+	}
+	if total.IsPositive() {
+		unpaid := []models.AccountState{{AccountID: accountID, Balance: balance.Sub(total)}}
+		paid := []models.AccountState{{AccountID: accountID, Balance: balance}}
+		if err := appendAuditTx(tx, models.AuditPayInterest, interestActor, accountID, 0, unpaid, paid); err != nil {
+			return 0, err
+		}
+	}
+	return total, tx.Commit()
+}
+
+// payInterestTx pays the accruals before the given time within tx and
+// returns the total paid and, when it is positive, the account's new
+// balance. The accruals are added up unrounded and only their total is
+// rounded to the cent. It is shared with CloseAccount, which pays what is
+// left before closing. The caller appends the payment to the audit log.
+func payInterestTx(tx *sql.Tx, accountID int, before time.Time) (total, balance money.Money, err error) {
+	query := "SELECT amount FROM interest_accruals WHERE account_id = $1 AND accrual_date < $2 AND paid_at IS NULL FOR UPDATE"
+	rows, err := tx.Query(query, accountID, before)
+	if err != nil {
+		return 0, 0, err
+	}
+	var accrued money.Precise
+	for rows.Next() {
+		var amount money.Precise
+		if err := rows.Scan(&amount); err != nil {
+			rows.Close()
+			return 0, 0, err
+		}
+		if accrued, err = accrued.CheckedAdd(amount); err != nil {
+			rows.Close()
+			return 0, 0, err
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, err
+	}
+	total = accrued.Round()
+
+	var entryID sql.NullInt64
+	if total.IsPositive() {
+		entry := ledger.NewInterest(accountID, total)
+		balances, err := postEntryTx(tx, entry)
+		if err != nil {
+			return 0, 0, err
+		}
+		balance = balances[accountID]
+		entryID = sql.NullInt64{Int64: entry.ID, Valid: true}
+	}
+
+	query = `UPDATE interest_accruals SET paid_at = now(), journal_entry_id = $3
+			 WHERE account_id = $1 AND accrual_date < $2 AND paid_at IS NULL`
+	if _, err := tx.Exec(query, accountID, before, entryID); err != nil {
+		return 0, 0, err
+	}
+	return total, balance, nil
+}
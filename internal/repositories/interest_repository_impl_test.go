@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/ledger"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
+func TestPsqlInterestRepository_ListAccrualAccounts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlInterestRepository{DB: db}
+	effectiveFrom := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)
+	lastAccrued := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("FROM accounts a JOIN interest_rates ir ON ir.category = a.category").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "category", "rate_type", "rate_bps", "effective_from", "start", "last_accrued"}).
+			AddRow(10, "savings", "fixed", 600, effectiveFrom, start, lastAccrued).
+			AddRow(11, "savings", "fixed", 600, effectiveFrom, effectiveFrom, nil))
+
+	accounts, err := repo.ListAccrualAccounts()
+	assert.NoError(t, err)
+	rate := models.InterestRate{Category: "savings", Type: "fixed", RateBps: 600, EffectiveFrom: effectiveFrom}
+	assert.Equal(t, []models.AccrualAccount{
+		{AccountID: 10, Rate: rate, Start: start, LastAccrued: &lastAccrued},
+		{AccountID: 11, Rate: rate, Start: effectiveFrom},
+	}, accounts)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlInterestRepository_GetEndOfDayBalance(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlInterestRepository{DB: db}
+	date := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// The day ends at midnight of the next one.
+	mock.ExpectQuery("SELECT balance_after FROM account_transactions WHERE account_id = \\$1 AND created_at < \\$2").
+		WithArgs(10, date.AddDate(0, 0, 1)).
+		WillReturnRows(sqlmock.NewRows([]string{"balance_after"}).AddRow("150.00"))
+	balance, err := repo.GetEndOfDayBalance(10, date)
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(150, 0), balance)
+
+	mock.ExpectQuery("SELECT balance_after FROM account_transactions").WithArgs(11, date.AddDate(0, 0, 1)).WillReturnError(sql.ErrNoRows)
+	balance, err = repo.GetEndOfDayBalance(11, date)
+	assert.NoError(t, err)
+	assert.True(t, balance.IsZero())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlInterestRepository_RecordAccrual(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlInterestRepository{DB: db}
+	date := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	cdi := 1460
+	accrual := &models.InterestAccrual{
+		AccountID: 10, Date: date, Balance: money.New(10000, 0),
+		Rate: models.InterestRate{Type: "cdi", RateBps: 11000}, CDIRateBps: &cdi, Amount: money.MustParsePrecise("4.4000044"),
+	}
+
+	mock.ExpectExec("INSERT INTO interest_accruals .* ON CONFLICT \\(account_id, accrual_date\\) DO NOTHING").
+		WithArgs(10, date, money.New(10000, 0), "cdi", 11000, &cdi, money.MustParsePrecise("4.4000044")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	recorded, err := repo.RecordAccrual(accrual)
+	assert.NoError(t, err)
+	assert.True(t, recorded)
+
+	mock.ExpectExec("INSERT INTO interest_accruals").WillReturnResult(sqlmock.NewResult(0, 0))
+	recorded, err = repo.RecordAccrual(accrual)
+	assert.NoError(t, err)
+	assert.False(t, recorded)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlInterestRepository_PayInterest(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlInterestRepository{DB: db}
+	before := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	// The accruals are credited like a deposit and marked paid. They are
+	// added up before rounding: 2.502, where the rounded days would make
+	// 2.49.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT amount FROM interest_accruals .* FOR UPDATE").WithArgs(10, before).
+		WillReturnRows(sqlmock.NewRows([]string{"amount"}).AddRow("1.20400000").AddRow("0.00000000").AddRow("1.20400000").AddRow("0.09400000"))
+	expectJournalEntry(mock, "interest",
+		expectedPosting{ledger.EquityAccountID, "debit", money.FromCents(250)},
+		expectedPosting{10, "credit", money.FromCents(250)})
+	mock.ExpectQuery("UPDATE accounts SET balance = balance \\+ \\$1").WithArgs(money.FromCents(250), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow("102.50"))
+	expectTransaction(mock, 10, "interest", "credit", money.FromCents(250), money.FromCents(10250))
+	mock.ExpectExec("UPDATE interest_accruals SET paid_at = now\\(\\), journal_entry_id = \\$3").
+		WithArgs(10, before, int64(1)).WillReturnResult(sqlmock.NewResult(0, 4))
+	expectAudit(mock, "pay_interest", models.Actor{System: "interest"}, 10, 0,
+		`[{"account_id": 10, "balance": 100}]`, `[{"account_id": 10, "balance": 102.5}]`)
+	mock.ExpectCommit()
+
+	credited, err := repo.PayInterest(10, before)
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromCents(250), credited)
+
+	// Accruals that add up to less than half a cent are only marked paid.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT amount FROM interest_accruals").WithArgs(11, before).
+		WillReturnRows(sqlmock.NewRows([]string{"amount"}).AddRow("0.00000000").AddRow("0.00400000"))
+	mock.ExpectExec("UPDATE interest_accruals SET paid_at").WithArgs(11, before, nil).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	credited, err = repo.PayInterest(11, before)
+	assert.NoError(t, err)
+	assert.True(t, credited.IsZero())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,12 @@
+package repositories
+
+import "github.com/gregoryAlvim/gobank/internal/models"
+
+type LedgerRepository interface {
+	// GetPostings returns up to filter.Limit of the account's postings,
+	// newest first.
+	GetPostings(accountID int, filter models.PostingFilter) ([]models.Posting, error)
+	// GetReconciliation compares the account's stored balance with the
+	// balance derived from its postings. Postings is left empty.
+	GetReconciliation(accountID int) (*models.Reconciliation, error)
+}
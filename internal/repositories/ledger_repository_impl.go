@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/gregoryAlvim/gobank/internal/database"
+	"github.com/gregoryAlvim/gobank/internal/ledger"
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+type PsqlLedgerRepository struct {
+	DB *sql.DB
+}
+
+func NewPsqlLedgerRepository() *PsqlLedgerRepository {
+	return &PsqlLedgerRepository{DB: database.DB}
+}
+
+func (r *PsqlLedgerRepository) GetPostings(accountID int, filter models.PostingFilter) ([]models.Posting, error) {
+	query := "SELECT id, journal_entry_id, account_id, direction, amount, currency FROM postings WHERE account_id = $1"
+	args := []interface{}{accountID}
+	if filter.BeforeID > 0 {
+		args = append(args, filter.BeforeID)
+		query += fmt.Sprintf(" AND id < $%d", len(args))
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	postings := []models.Posting{}
+	for rows.Next() {
+		var p models.Posting
+		if err := rows.Scan(&p.ID, &p.JournalEntryID, &p.AccountID, &p.Direction, &p.Amount, &p.Currency); err != nil {
+			return nil, err
+		}
+		postings = append(postings, p)
+	}
+	return postings, rows.Err()
+}
+
+// GetReconciliation reads the stored balance and sums the postings in a
+// single statement, so both come from the same snapshot even while money
+// is moving.
+func (r *PsqlLedgerRepository) GetReconciliation(accountID int) (*models.Reconciliation, error) {
+	reconciliation := models.Reconciliation{AccountID: accountID, Postings: []models.Posting{}}
+	query := `SELECT balance, (SELECT COALESCE(SUM(CASE WHEN direction = 'credit' THEN amount ELSE -amount END), 0)
+			  FROM postings WHERE account_id = accounts.id)
+			  FROM accounts WHERE id = $1 AND customer_id IS NOT NULL`
+	err := r.DB.QueryRow(query, accountID).Scan(&reconciliation.Balance, &reconciliation.LedgerBalance)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+	reconciliation.Balanced = reconciliation.Balance == reconciliation.LedgerBalance
+	return &reconciliation, nil
+}
+
+// insertJournalEntryTx validates the entry and writes it with its postings
+// inside the given transaction. The entry's ID and CreatedAt are filled in,
+// and so is the currency of postings that have none.
+func insertJournalEntryTx(tx *sql.Tx, entry *models.JournalEntry) error {
+	if err := ledger.Validate(entry); err != nil {
+		return err
+	}
+	if err := setCurrencyTx(tx, entry); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO journal_entries (kind, description) VALUES ($1, $2) RETURNING id, created_at`
+	if err := tx.QueryRow(query, entry.Kind, entry.Description).Scan(&entry.ID, &entry.CreatedAt); err != nil {
+		return err
+	}
+
+	for i := range entry.Postings {
+		p := &entry.Postings[i]
+		p.JournalEntryID = entry.ID
+		query := `INSERT INTO postings (journal_entry_id, account_id, direction, amount, currency)
+				  VALUES ($1, $2, $3, $4, $5) RETURNING id`
+		if err := tx.QueryRow(query, entry.ID, p.AccountID, p.Direction, p.Amount, p.Currency).Scan(&p.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setCurrencyTx sets postings without a currency to the currency of the
+// customer account the entry touches. Only cross-currency transfers touch
+// accounts in different currencies, and they set the currency of every
+// posting themselves.
+func setCurrencyTx(tx *sql.Tx, entry *models.JournalEntry) error {
+	accountID := 0
+	for _, p := range entry.Postings {
+		if p.Currency != "" {
+			continue
+		}
+		if !ledger.IsSystemAccount(p.AccountID) {
+			accountID = p.AccountID
+			break
+		}
+	}
+	if accountID == 0 {
+		return nil
+	}
+
+	var code string
+	if err := tx.QueryRow("SELECT currency FROM accounts WHERE id = $1", accountID).Scan(&code); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrAccountNotFound
+		}
+		return err
+	}
+	for i := range entry.Postings {
+		if entry.Postings[i].Currency == "" {
+			entry.Postings[i].Currency = code
+		}
+	}
+	return nil
+}
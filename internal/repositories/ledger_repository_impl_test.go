@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
+func TestPsqlLedgerRepository_GetPostings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlLedgerRepository{DB: db}
+
+	rows := sqlmock.NewRows([]string{"id", "journal_entry_id", "account_id", "direction", "amount", "currency"}).
+		AddRow(4, 2, 10, "debit", "30.50", "BRL").
+		AddRow(1, 1, 10, "credit", "100.00", "BRL")
+	mock.ExpectQuery("SELECT (.+) FROM postings WHERE account_id = \\$1 ORDER BY id DESC LIMIT \\$2").WithArgs(10, 51).WillReturnRows(rows)
+
+	postings, err := repo.GetPostings(10, models.PostingFilter{Limit: 51})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Posting{
+		{ID: 4, JournalEntryID: 2, AccountID: 10, Direction: "debit", Amount: money.New(30, 50), Currency: "BRL"},
+		{ID: 1, JournalEntryID: 1, AccountID: 10, Direction: "credit", Amount: money.New(100, 0), Currency: "BRL"},
+	}, postings)
+
+	// The next page starts before the cursor.
+	mock.ExpectQuery("SELECT (.+) FROM postings WHERE account_id = \\$1 AND id < \\$2 ORDER BY id DESC LIMIT \\$3").WithArgs(10, int64(4), 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "journal_entry_id", "account_id", "direction", "amount", "currency"}))
+
+	postings, err = repo.GetPostings(10, models.PostingFilter{BeforeID: 4, Limit: 3})
+	assert.NoError(t, err)
+	assert.Empty(t, postings)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlLedgerRepository_GetReconciliation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlLedgerRepository{DB: db}
+
+	// Both balances are read by the same statement.
+	mock.ExpectQuery("SELECT balance, \\(SELECT COALESCE\\(SUM\\(.+\\) FROM postings WHERE account_id = accounts.id\\) FROM accounts WHERE id = \\$1").
+		WithArgs(11).
+		WillReturnRows(sqlmock.NewRows([]string{"balance", "sum"}).AddRow("70.00", "69.50"))
+
+	reconciliation, err := repo.GetReconciliation(11)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.Reconciliation{
+		AccountID:     11,
+		Balance:       money.New(70, 0),
+		LedgerBalance: money.New(69, 50),
+		Balanced:      false,
+		Postings:      []models.Posting{},
+	}, reconciliation)
+
+	mock.ExpectQuery("SELECT balance").WithArgs(99).WillReturnError(sql.ErrNoRows)
+	_, err = repo.GetReconciliation(99)
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
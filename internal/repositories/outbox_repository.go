@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+type OutboxRepository interface {
+	// LockRelay reports false when another relay holds the lock. Otherwise
+	// the lock is held until unlock is called.
+	LockRelay() (unlock func(), ok bool, err error)
+	// ListPending returns up to limit unpublished events, oldest first.
+	ListPending(limit int) ([]models.Event, error)
+	MarkPublished(sequence int64) error
+	// MarkFailed counts a failed attempt to publish the event, which stays
+	// pending.
+	MarkFailed(sequence int64, reason string) error
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/gregoryAlvim/gobank/internal/database"
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+// outboxRelayLock is the key of the advisory lock held by the relay.
+const outboxRelayLock = 0x6f7574626f78 // "outbox"
+
+type PsqlOutboxRepository struct {
+	DB *sql.DB
+}
+
+func NewPsqlOutboxRepository() *PsqlOutboxRepository {
+	return &PsqlOutboxRepository{DB: database.DB}
+}
+
+// LockRelay takes a session advisory lock on a connection of its own, so
+// only one relay publishes at a time and events of an account cannot be
+// published out of order by two relays. The lock goes away with the
+// connection if the process dies.
+func (r *PsqlOutboxRepository) LockRelay() (func(), bool, error) {
+	ctx := context.Background()
+	conn, err := r.DB.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var ok bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", outboxRelayLock).Scan(&ok); err != nil || !ok {
+		conn.Close()
+		return nil, false, err
+	}
+	unlock := func() {
+		conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", outboxRelayLock)
+		conn.Close()
+	}
+	return unlock, true, nil
+}
+
+func (r *PsqlOutboxRepository) ListPending(limit int) ([]models.Event, error) {
+	query := `SELECT id, event_id, type, account_id, COALESCE(counterparty_id, 0), payload, created_at
+			  FROM outbox WHERE published_at IS NULL ORDER BY id LIMIT $1`
+	rows, err := r.DB.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.Event{}
+	for rows.Next() {
+		var e models.Event
+		if err := rows.Scan(&e.Sequence, &e.ID, &e.Type, &e.AccountID, &e.CounterpartyID, &e.Data, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func (r *PsqlOutboxRepository) MarkPublished(sequence int64) error {
+	_, err := r.DB.Exec("UPDATE outbox SET published_at = now(), attempts = attempts + 1 WHERE id = $1", sequence)
+	return err
+}
+
+func (r *PsqlOutboxRepository) MarkFailed(sequence int64, reason string) error {
+	_, err := r.DB.Exec("UPDATE outbox SET attempts = attempts + 1, last_error = $2 WHERE id = $1", sequence, reason)
+	return err
+}
+
+// insertEventTx writes an event with data to the outbox inside tx. It must
+// run after the accounts involved are locked, so that the events of an
+// account are numbered in commit order. counterpartyID is zero when the
+// event concerns a single account.
+func insertEventTx(tx *sql.Tx, eventType string, accountID, counterpartyID int, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO outbox (type, account_id, counterparty_id, payload) VALUES ($1, $2, NULLIF($3, 0), $4)`
+	_, err = tx.Exec(query, eventType, accountID, counterpartyID, payload)
+	return err
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+func TestPsqlOutboxRepository_LockRelay(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlOutboxRepository{DB: db}
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock\\(\\$1\\)").WithArgs(outboxRelayLock).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+	mock.ExpectExec("SELECT pg_advisory_unlock\\(\\$1\\)").WithArgs(outboxRelayLock).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	unlock, ok, err := repo.LockRelay()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	unlock()
+
+	// Another relay holds the lock.
+	mock.ExpectQuery("SELECT pg_try_advisory_lock\\(\\$1\\)").WithArgs(outboxRelayLock).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(false))
+
+	unlock, ok, err = repo.LockRelay()
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Nil(t, unlock)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlOutboxRepository_ListPending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlOutboxRepository{DB: db}
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT (.+) FROM outbox WHERE published_at IS NULL ORDER BY id LIMIT \\$1").WithArgs(100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_id", "type", "account_id", "counterparty_id", "payload", "created_at"}).
+			AddRow(4, "0b9c3b5e-3f7e-4e43-9d8a-5c1f2a7e6d10", "deposit.completed", 10, 0, []byte(`{"amount": 100.00}`), createdAt).
+			AddRow(5, "7a1d2c44-96b1-4c1e-8e0f-2b3c4d5e6f70", "transfer.completed", 10, 11, []byte(`{"amount": 50.00}`), createdAt))
+
+	events, err := repo.ListPending(100)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Event{
+		{ID: "0b9c3b5e-3f7e-4e43-9d8a-5c1f2a7e6d10", Sequence: 4, Type: "deposit.completed", AccountID: 10,
+			Data: json.RawMessage(`{"amount": 100.00}`), CreatedAt: createdAt},
+		{ID: "7a1d2c44-96b1-4c1e-8e0f-2b3c4d5e6f70", Sequence: 5, Type: "transfer.completed", AccountID: 10, CounterpartyID: 11,
+			Data: json.RawMessage(`{"amount": 50.00}`), CreatedAt: createdAt},
+	}, events)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlOutboxRepository_Mark(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlOutboxRepository{DB: db}
+
+	mock.ExpectExec("UPDATE outbox SET published_at = now\\(\\), attempts = attempts \\+ 1 WHERE id = \\$1").WithArgs(int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE outbox SET attempts = attempts \\+ 1, last_error = \\$2 WHERE id = \\$1").WithArgs(int64(5), "connection refused").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.MarkPublished(4))
+	assert.NoError(t, repo.MarkFailed(5, "connection refused"))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
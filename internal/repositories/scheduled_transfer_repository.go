@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+type ScheduledTransferRepository interface {
+	Create(schedule *models.ScheduledTransfer) error
+	// Get returns the scheduled transfer if it is from the account.
+	Get(accountID int, scheduleID int64) (*models.ScheduledTransfer, error)
+	ListByAccount(accountID int) ([]models.ScheduledTransfer, error)
+	// Update changes the amount, description, end and status of an active
+	// scheduled transfer.
+	Update(schedule *models.ScheduledTransfer) error
+	Cancel(accountID int, scheduleID int64) (*models.ScheduledTransfer, error)
+	ListRuns(scheduleID int64) ([]models.ScheduledTransferRun, error)
+	// ClaimDue returns up to limit active transfers due at now and pushes
+	// their next attempt back by lease, so that other schedulers skip them
+	// while they run.
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]models.ScheduledTransfer, error)
+	// RecordRun stores the run and the transfer's progress after it.
+	RecordRun(run *models.ScheduledTransferRun, schedule *models.ScheduledTransfer) error
+}
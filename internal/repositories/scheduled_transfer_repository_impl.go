@@ -0,0 +1,192 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/database"
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+var (
+	ErrScheduledTransferNotFound = errors.New("scheduled transfer not found")
+	ErrScheduledTransferInactive = errors.New("scheduled transfer has already completed or been cancelled")
+)
+
+type PsqlScheduledTransferRepository struct {
+	DB *sql.DB
+}
+
+func NewPsqlScheduledTransferRepository() *PsqlScheduledTransferRepository {
+	return &PsqlScheduledTransferRepository{DB: database.DB}
+}
+
+const scheduleColumns = "id, from_account_id, to_account_id, amount, description, frequency, start_date, " +
+	"COALESCE(day_of_month, 0), end_date, max_runs, runs_count, status, next_run_date, next_attempt_at, attempts, " +
+	"COALESCE(created_by_customer_id, 0), COALESCE(created_by_operator_id, 0), created_by_role, created_at"
+
+func scanSchedule(row interface{ Scan(...interface{}) error }, s *models.ScheduledTransfer) error {
+	var description sql.NullString
+	err := row.Scan(&s.ID, &s.FromAccountID, &s.ToAccountID, &s.Amount, &description, &s.Frequency, &s.StartDate,
+		&s.DayOfMonth, &s.EndDate, &s.MaxRuns, &s.RunsCount, &s.Status, &s.NextRunDate, &s.NextAttemptAt, &s.Attempts,
+		&s.CreatedByCustomerID, &s.CreatedByOperatorID, &s.CreatedByRole, &s.CreatedAt)
+	s.Description = description.String
+	return err
+}
+
+func scanSchedules(rows *sql.Rows) ([]models.ScheduledTransfer, error) {
+	defer rows.Close()
+
+	schedules := []models.ScheduledTransfer{}
+	for rows.Next() {
+		var s models.ScheduledTransfer
+		if err := scanSchedule(rows, &s); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, s)
+	}
+	return schedules, rows.Err()
+}
+
+// Create stores a scheduled transfer whose accounts must both exist.
+func (r *PsqlScheduledTransferRepository) Create(s *models.ScheduledTransfer) error {
+	query := `INSERT INTO scheduled_transfers (from_account_id, to_account_id, amount, description, frequency, start_date,
+			  day_of_month, end_date, max_runs, next_run_date, next_attempt_at, created_by_customer_id, created_by_operator_id, created_by_role)
+			  SELECT f.id, t.id, $3, NULLIF($4, ''), $5, $6, NULLIF($7, 0), $8, $9, $10, $11, NULLIF($12, 0), NULLIF($13, 0), $14
+			  FROM accounts f, accounts t WHERE f.id = $1 AND t.id = $2
+			  RETURNING id, runs_count, attempts, status, created_at`
+	err := r.DB.QueryRow(query, s.FromAccountID, s.ToAccountID, s.Amount, s.Description, s.Frequency, s.StartDate,
+		s.DayOfMonth, s.EndDate, s.MaxRuns, s.NextRunDate, s.NextAttemptAt, s.CreatedByCustomerID, s.CreatedByOperatorID,
+		s.CreatedByRole).Scan(&s.ID, &s.RunsCount, &s.Attempts, &s.Status, &s.CreatedAt)
+	if err == sql.ErrNoRows {
+		return ErrAccountNotFound
+	}
+	return err
+}
+
+func (r *PsqlScheduledTransferRepository) Get(accountID int, scheduleID int64) (*models.ScheduledTransfer, error) {
+	var s models.ScheduledTransfer
+	query := "SELECT " + scheduleColumns + " FROM scheduled_transfers WHERE id = $1 AND from_account_id = $2"
+	if err := scanSchedule(r.DB.QueryRow(query, scheduleID, accountID), &s); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrScheduledTransferNotFound
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListByAccount returns the transfers scheduled from the account, newest
+// first.
+func (r *PsqlScheduledTransferRepository) ListByAccount(accountID int) ([]models.ScheduledTransfer, error) {
+	rows, err := r.DB.Query("SELECT "+scheduleColumns+" FROM scheduled_transfers WHERE from_account_id = $1 ORDER BY id DESC", accountID)
+	if err != nil {
+		return nil, err
+	}
+	return scanSchedules(rows)
+}
+
+// Update leaves the next run alone unless the transfer completes, so a
+// run under way keeps its lease.
+func (r *PsqlScheduledTransferRepository) Update(s *models.ScheduledTransfer) error {
+	query := `UPDATE scheduled_transfers SET amount = $3, description = NULLIF($4, ''), end_date = $5, max_runs = $6, status = $7,
+			  next_run_date = CASE WHEN $7 = 'active' THEN next_run_date END,
+			  next_attempt_at = CASE WHEN $7 = 'active' THEN next_attempt_at END
+			  WHERE id = $1 AND from_account_id = $2 AND status = 'active'`
+	res, err := r.DB.Exec(query, s.ID, s.FromAccountID, s.Amount, s.Description, s.EndDate, s.MaxRuns, s.Status)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+	return ErrScheduledTransferInactive
+}
+
+// Cancel stops an active scheduled transfer. A run already under way
+// still completes.
+func (r *PsqlScheduledTransferRepository) Cancel(accountID int, scheduleID int64) (*models.ScheduledTransfer, error) {
+	var s models.ScheduledTransfer
+	query := `UPDATE scheduled_transfers SET status = 'cancelled', next_run_date = NULL, next_attempt_at = NULL
+			  WHERE id = $1 AND from_account_id = $2 AND status = 'active' RETURNING ` + scheduleColumns
+	err := scanSchedule(r.DB.QueryRow(query, scheduleID, accountID), &s)
+	if err == sql.ErrNoRows {
+		if _, err := r.Get(accountID, scheduleID); err != nil {
+			return nil, err
+		}
+		return nil, ErrScheduledTransferInactive
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListRuns returns the runs of the scheduled transfer, newest first.
+func (r *PsqlScheduledTransferRepository) ListRuns(scheduleID int64) ([]models.ScheduledTransferRun, error) {
+	query := `SELECT id, scheduled_transfer_id, run_date, attempt, status, COALESCE(error, ''), approval_id, ran_at
+			  FROM scheduled_transfer_runs WHERE scheduled_transfer_id = $1 ORDER BY id DESC`
+	rows, err := r.DB.Query(query, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []models.ScheduledTransferRun{}
+	for rows.Next() {
+		var run models.ScheduledTransferRun
+		if err := rows.Scan(&run.ID, &run.ScheduledTransferID, &run.RunDate, &run.Attempt, &run.Status, &run.Error,
+			&run.ApprovalID, &run.RanAt); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// ClaimDue locks the due rows with SKIP LOCKED, so concurrent schedulers
+// claim different transfers, and returns them as they were before the
+// lease was taken.
+func (r *PsqlScheduledTransferRepository) ClaimDue(now time.Time, lease time.Duration, limit int) ([]models.ScheduledTransfer, error) {
+	query := `WITH due AS (
+				SELECT ` + scheduleColumns + ` FROM scheduled_transfers
+				WHERE status = 'active' AND next_attempt_at <= $1
+				ORDER BY next_attempt_at LIMIT $3 FOR UPDATE SKIP LOCKED
+			  ), claimed AS (
+				UPDATE scheduled_transfers s SET next_attempt_at = $2 FROM due WHERE s.id = due.id
+			  )
+			  SELECT * FROM due ORDER BY next_attempt_at, id`
+	rows, err := r.DB.Query(query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanSchedules(rows)
+}
+
+// RecordRun stores the run and the transfer's runs count, attempts and
+// next run in one transaction. A transfer cancelled while it ran stays
+// cancelled.
+func (r *PsqlScheduledTransferRepository) RecordRun(run *models.ScheduledTransferRun, s *models.ScheduledTransfer) error {
+	tx, err := r.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO scheduled_transfer_runs (scheduled_transfer_id, run_date, attempt, status, error, approval_id)
+			  VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6) RETURNING id, ran_at`
+	err = tx.QueryRow(query, s.ID, run.RunDate, run.Attempt, run.Status, run.Error, run.ApprovalID).Scan(&run.ID, &run.RanAt)
+	if err != nil {
+		return err
+	}
+	run.ScheduledTransferID = s.ID
+
+	query = `UPDATE scheduled_transfers SET runs_count = $2, attempts = $3, status = $4, next_run_date = $5, next_attempt_at = $6
+			 WHERE id = $1 AND status = 'active'`
+	if _, err := tx.Exec(query, s.ID, s.RunsCount, s.Attempts, s.Status, s.NextRunDate, s.NextAttemptAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
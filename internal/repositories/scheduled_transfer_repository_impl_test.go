@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
+var scheduleRowColumns = []string{"id", "from_account_id", "to_account_id", "amount", "description", "frequency", "start_date",
+	"day_of_month", "end_date", "max_runs", "runs_count", "status", "next_run_date", "next_attempt_at", "attempts",
+	"created_by_customer_id", "created_by_operator_id", "created_by_role", "created_at"}
+
+func TestPsqlScheduledTransferRepository_ClaimDue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlScheduledTransferRepository{DB: db}
+	now := time.Date(2026, 4, 14, 10, 0, 0, 0, time.UTC)
+	runDate := time.Date(2026, 4, 14, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("FOR UPDATE SKIP LOCKED .* UPDATE scheduled_transfers s SET next_attempt_at = \\$2").
+		WithArgs(now, now.Add(5*time.Minute), 100).
+		WillReturnRows(sqlmock.NewRows(scheduleRowColumns).
+			AddRow(7, 20, 30, "100.00", nil, "weekly", runDate, 0, nil, 4, 1, "active", runDate, runDate, 0, 0, 9, "teller", createdAt))
+
+	due, err := repo.ClaimDue(now, 5*time.Minute, 100)
+	assert.NoError(t, err)
+	maxRuns := 4
+	start := models.Date{Time: runDate}
+	assert.Equal(t, []models.ScheduledTransfer{{
+		ID: 7, FromAccountID: 20, ToAccountID: 30, Amount: money.New(100, 0), Frequency: "weekly", StartDate: start,
+		MaxRuns: &maxRuns, RunsCount: 1, Status: "active", NextRunDate: &start, NextAttemptAt: &runDate,
+		CreatedByOperatorID: 9, CreatedByRole: "teller", CreatedAt: createdAt,
+	}}, due)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlScheduledTransferRepository_RecordRun(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlScheduledTransferRepository{DB: db}
+	runDate := models.Date{Time: time.Date(2026, 4, 14, 0, 0, 0, 0, time.UTC)}
+	next := runDate.AddDays(7)
+	ranAt := time.Date(2026, 4, 14, 10, 0, 1, 0, time.UTC)
+	run := &models.ScheduledTransferRun{RunDate: runDate, Attempt: 1, Status: models.RunSucceeded}
+	schedule := &models.ScheduledTransfer{ID: 7, RunsCount: 2, Status: models.ScheduleActive, NextRunDate: &next, NextAttemptAt: &next.Time}
+
+	// A cancelled transfer is not brought back by the run.
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO scheduled_transfer_runs").
+		WithArgs(int64(7), runDate, 1, "succeeded", "", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ran_at"}).AddRow(3, ranAt))
+	mock.ExpectExec("UPDATE scheduled_transfers SET runs_count = \\$2, .* WHERE id = \\$1 AND status = 'active'").
+		WithArgs(int64(7), 2, 0, "active", &next, &next.Time).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.RecordRun(run, schedule))
+	assert.Equal(t, int64(3), run.ID)
+	assert.Equal(t, int64(7), run.ScheduledTransferID)
+	assert.Equal(t, ranAt, run.RanAt)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPsqlScheduledTransferRepository_Cancel(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlScheduledTransferRepository{DB: db}
+	startDate := time.Date(2026, 4, 14, 0, 0, 0, 0, time.UTC)
+
+	// Already completed.
+	mock.ExpectQuery("UPDATE scheduled_transfers SET status = 'cancelled'").
+		WithArgs(int64(7), 20).
+		WillReturnRows(sqlmock.NewRows(scheduleRowColumns))
+	mock.ExpectQuery("SELECT .* FROM scheduled_transfers WHERE id = \\$1 AND from_account_id = \\$2").
+		WithArgs(int64(7), 20).
+		WillReturnRows(sqlmock.NewRows(scheduleRowColumns).
+			AddRow(7, 20, 30, "100.00", nil, "once", startDate, 0, nil, nil, 1, "completed", nil, nil, 0, 4, 0, "customer", startDate))
+	_, err = repo.Cancel(20, 7)
+	assert.ErrorIs(t, err, ErrScheduledTransferInactive)
+
+	// Not from this account.
+	mock.ExpectQuery("UPDATE scheduled_transfers SET status = 'cancelled'").
+		WithArgs(int64(8), 20).
+		WillReturnRows(sqlmock.NewRows(scheduleRowColumns))
+	mock.ExpectQuery("SELECT .* FROM scheduled_transfers WHERE id = \\$1 AND from_account_id = \\$2").
+		WithArgs(int64(8), 20).
+		WillReturnRows(sqlmock.NewRows(scheduleRowColumns))
+	_, err = repo.Cancel(20, 8)
+	assert.ErrorIs(t, err, ErrScheduledTransferNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"errors"
+	"expvar"
+	"math/rand"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Postgres SQLSTATE codes after which a transaction can safely be retried
+// from the start.
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+// RetryPolicy controls how transactions aborted by a deadlock or a
+// serialization failure are retried. Zero fields fall back to the defaults.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   10 * time.Millisecond,
+	MaxDelay:    500 * time.Millisecond,
+}
+
+// TxRetryStats counts transaction retries per operation, published at
+// /debug/vars as "tx_retries". Keys are "<op>.retries",
+// "<op>.deadlocks", "<op>.serialization_failures" and "<op>.exhausted".
+var TxRetryStats = expvar.NewMap("tx_retries")
+
+// retryTx runs fn, retrying it with exponential backoff and jitter while it
+// fails with a retryable Postgres error. fn must run a whole transaction.
+func retryTx(op string, policy RetryPolicy, fn func() error) error {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = DefaultRetryPolicy.MaxAttempts
+	}
+	if policy.BaseDelay <= 0 {
+		policy.BaseDelay = DefaultRetryPolicy.BaseDelay
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = DefaultRetryPolicy.MaxDelay
+	}
+
+	delay := policy.BaseDelay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		code := retryableSQLState(err)
+		if code == "" {
+			return err
+		}
+
+		if attempt >= policy.MaxAttempts {
+			TxRetryStats.Add(op+".exhausted", 1)
+			return err
+		}
+
+		TxRetryStats.Add(op+".retries", 1)
+		if code == sqlStateDeadlockDetected {
+			TxRetryStats.Add(op+".deadlocks", 1)
+		} else {
+			TxRetryStats.Add(op+".serialization_failures", 1)
+		}
+
+		// Sleep between half and the whole of the current delay so that
+		// competing transactions do not retry in lockstep.
+		time.Sleep(delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1)))
+		delay *= 2
+		if delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+	}
+}
+
+// retryableSQLState returns the SQLSTATE of err if it is a deadlock or a
+// serialization failure, and "" otherwise.
+func retryableSQLState(err error) string {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return ""
+	}
+	switch pqErr.Code {
+	case sqlStateSerializationFailure, sqlStateDeadlockDetected:
+		return string(pqErr.Code)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+type WebhookRepository interface {
+	CreateSubscription(subscription *models.WebhookSubscription) error
+	GetSubscription(subscriptionID int64) (*models.WebhookSubscription, error)
+	// ListSubscriptions lists the customer's subscriptions, or the
+	// partners' when customerID is zero. Secrets are left out.
+	ListSubscriptions(customerID int) ([]models.WebhookSubscription, error)
+	DeleteSubscription(subscriptionID int64) error
+	// EnqueueDeliveries queues the event for every subscription that wants
+	// it and returns how many were queued. An event queued before is not
+	// queued again.
+	EnqueueDeliveries(event models.Event, payload []byte) (int, error)
+	// ClaimDue returns up to limit pending deliveries due at now and pushes
+	// their next attempt back by lease, so that other workers skip them
+	// while they are sent.
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]models.DueWebhookDelivery, error)
+	// RecordAttempt stores the delivery's status, attempts, next attempt
+	// and last response.
+	RecordAttempt(delivery *models.WebhookDelivery) error
+	// ListDeliveries lists the subscription's deliveries, newest first,
+	// with the given status or with any when status is empty.
+	ListDeliveries(subscriptionID int64, status string, limit int) ([]models.WebhookDelivery, error)
+	// Resend makes the subscription's delivery pending again, due now and
+	// with all of its attempts.
+	Resend(subscriptionID, deliveryID int64) (*models.WebhookDelivery, error)
+}
@@ -0,0 +1,202 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/gregoryAlvim/gobank/internal/database"
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+var (
+	ErrWebhookNotFound  = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+type PsqlWebhookRepository struct {
+	DB *sql.DB
+}
+
+func NewPsqlWebhookRepository() *PsqlWebhookRepository {
+	return &PsqlWebhookRepository{DB: database.DB}
+}
+
+func (r *PsqlWebhookRepository) CreateSubscription(s *models.WebhookSubscription) error {
+	query := `INSERT INTO webhook_subscriptions (customer_id, partner, url, secret, event_types)
+			  VALUES (NULLIF($1, 0), NULLIF($2, ''), $3, $4, $5) RETURNING id, created_at`
+	err := r.DB.QueryRow(query, s.CustomerID, s.Partner, s.URL, s.Secret, pq.Array(s.EventTypes)).Scan(&s.ID, &s.CreatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return ErrCustomerNotFound
+		}
+	}
+	return err
+}
+
+const subscriptionColumns = "id, COALESCE(customer_id, 0), COALESCE(partner, ''), url, event_types, created_at"
+
+func scanSubscription(row interface{ Scan(...interface{}) error }, s *models.WebhookSubscription) error {
+	var eventTypes pq.StringArray
+	if err := row.Scan(&s.ID, &s.CustomerID, &s.Partner, &s.URL, &eventTypes, &s.CreatedAt); err != nil {
+		return err
+	}
+	s.EventTypes = []string(eventTypes)
+	return nil
+}
+
+func (r *PsqlWebhookRepository) GetSubscription(subscriptionID int64) (*models.WebhookSubscription, error) {
+	var s models.WebhookSubscription
+	query := "SELECT " + subscriptionColumns + " FROM webhook_subscriptions WHERE id = $1"
+	if err := scanSubscription(r.DB.QueryRow(query, subscriptionID), &s); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *PsqlWebhookRepository) ListSubscriptions(customerID int) ([]models.WebhookSubscription, error) {
+	query := "SELECT " + subscriptionColumns + " FROM webhook_subscriptions WHERE customer_id IS NOT DISTINCT FROM NULLIF($1, 0) ORDER BY id"
+	rows, err := r.DB.Query(query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := []models.WebhookSubscription{}
+	for rows.Next() {
+		var s models.WebhookSubscription
+		if err := scanSubscription(rows, &s); err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, s)
+	}
+	return subscriptions, rows.Err()
+}
+
+// DeleteSubscription deletes the subscription together with its
+// deliveries.
+func (r *PsqlWebhookRepository) DeleteSubscription(subscriptionID int64) error {
+	res, err := r.DB.Exec("DELETE FROM webhook_subscriptions WHERE id = $1", subscriptionID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// EnqueueDeliveries queues the event for the partners and for the
+// customers who own one of its accounts.
+func (r *PsqlWebhookRepository) EnqueueDeliveries(event models.Event, payload []byte) (int, error) {
+	accountIDs := make([]int64, 0, 2)
+	for _, id := range event.AccountIDs() {
+		accountIDs = append(accountIDs, int64(id))
+	}
+	query := `INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+			  SELECT s.id, $1, $2, $3 FROM webhook_subscriptions s
+			  WHERE (s.customer_id IS NULL OR s.customer_id IN (SELECT customer_id FROM accounts WHERE id = ANY($4)))
+			  AND (cardinality(s.event_types) = 0 OR $2 = ANY(s.event_types))
+			  ON CONFLICT (subscription_id, event_id) DO NOTHING`
+	res, err := r.DB.Exec(query, event.ID, event.Type, payload, pq.Array(accountIDs))
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// deliveryColumns are the columns of webhook_deliveries d.
+const deliveryColumns = "d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at, " +
+	"d.last_status_code, COALESCE(d.last_error, ''), d.created_at, d.delivered_at"
+
+func scanDelivery(row interface{ Scan(...interface{}) error }, d *models.WebhookDelivery, extra ...interface{}) error {
+	var lastStatusCode sql.NullInt64
+	dest := []interface{}{&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts, &d.NextAttemptAt,
+		&lastStatusCode, &d.LastError, &d.CreatedAt, &d.DeliveredAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+	if lastStatusCode.Valid {
+		code := int(lastStatusCode.Int64)
+		d.LastStatusCode = &code
+	}
+	return nil
+}
+
+// ClaimDue locks the due rows with SKIP LOCKED, so concurrent workers
+// claim different deliveries.
+func (r *PsqlWebhookRepository) ClaimDue(now time.Time, lease time.Duration, limit int) ([]models.DueWebhookDelivery, error) {
+	query := `WITH due AS (
+				SELECT id FROM webhook_deliveries
+				WHERE status = 'pending' AND next_attempt_at <= $1
+				ORDER BY next_attempt_at, id LIMIT $3 FOR UPDATE SKIP LOCKED
+			  ), claimed AS (
+				UPDATE webhook_deliveries w SET next_attempt_at = $2 FROM due WHERE w.id = due.id RETURNING w.*
+			  )
+			  SELECT ` + deliveryColumns + `, s.url, s.secret FROM claimed d
+			  JOIN webhook_subscriptions s ON s.id = d.subscription_id ORDER BY d.id`
+	rows, err := r.DB.Query(query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	due := []models.DueWebhookDelivery{}
+	for rows.Next() {
+		var d models.DueWebhookDelivery
+		if err := scanDelivery(rows, &d.WebhookDelivery, &d.URL, &d.Secret); err != nil {
+			return nil, err
+		}
+		due = append(due, d)
+	}
+	return due, rows.Err()
+}
+
+func (r *PsqlWebhookRepository) RecordAttempt(d *models.WebhookDelivery) error {
+	query := `UPDATE webhook_deliveries SET status = $2, attempts = $3, next_attempt_at = $4, last_status_code = $5,
+			  last_error = NULLIF($6, ''), delivered_at = $7 WHERE id = $1`
+	_, err := r.DB.Exec(query, d.ID, d.Status, d.Attempts, d.NextAttemptAt, d.LastStatusCode, d.LastError, d.DeliveredAt)
+	return err
+}
+
+func (r *PsqlWebhookRepository) ListDeliveries(subscriptionID int64, status string, limit int) ([]models.WebhookDelivery, error) {
+	query := "SELECT " + deliveryColumns + ` FROM webhook_deliveries d
+			  WHERE subscription_id = $1 AND ($2 = '' OR status = $2) ORDER BY id DESC LIMIT $3`
+	rows, err := r.DB.Query(query, subscriptionID, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var d models.WebhookDelivery
+		if err := scanDelivery(rows, &d); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *PsqlWebhookRepository) Resend(subscriptionID, deliveryID int64) (*models.WebhookDelivery, error) {
+	query := `UPDATE webhook_deliveries d SET status = 'pending', attempts = 0, next_attempt_at = now()
+			  WHERE id = $1 AND subscription_id = $2 RETURNING ` + deliveryColumns
+	var d models.WebhookDelivery
+	if err := scanDelivery(r.DB.QueryRow(query, deliveryID, subscriptionID), &d); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	return &d, nil
+}
@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+func TestPsqlWebhookRepository_Subscriptions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlWebhookRepository{DB: db}
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	subscription := &models.WebhookSubscription{CustomerID: 3, URL: "https://example.com/hooks", Secret: "whsec_test",
+		EventTypes: []string{"deposit.completed"}}
+	mock.ExpectQuery("INSERT INTO webhook_subscriptions").
+		WithArgs(3, "", "https://example.com/hooks", "whsec_test", pq.Array([]string{"deposit.completed"})).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, createdAt))
+
+	assert.NoError(t, repo.CreateSubscription(subscription))
+	assert.Equal(t, int64(5), subscription.ID)
+	assert.Equal(t, createdAt, subscription.CreatedAt)
+
+	// Unknown customer
+	mock.ExpectQuery("INSERT INTO webhook_subscriptions").WillReturnError(&pq.Error{Code: "23503"})
+
+	err = repo.CreateSubscription(&models.WebhookSubscription{CustomerID: 99, URL: "https://example.com/hooks"})
+	assert.ErrorIs(t, err, ErrCustomerNotFound)
+
+	columns := []string{"id", "customer_id", "partner", "url", "event_types", "created_at"}
+	mock.ExpectQuery("SELECT (.+) FROM webhook_subscriptions WHERE customer_id IS NOT DISTINCT FROM NULLIF\\(\\$1, 0\\)").WithArgs(0).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(6, 0, "acme", "https://acme.example/hooks", "{}", createdAt))
+
+	subscriptions, err := repo.ListSubscriptions(0)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.WebhookSubscription{
+		{ID: 6, Partner: "acme", URL: "https://acme.example/hooks", EventTypes: []string{}, CreatedAt: createdAt},
+	}, subscriptions)
+
+	mock.ExpectQuery("SELECT (.+) FROM webhook_subscriptions WHERE id = \\$1").WithArgs(int64(5)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(5, 3, "", "https://example.com/hooks", "{deposit.completed}", createdAt))
+
+	got, err := repo.GetSubscription(5)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.WebhookSubscription{ID: 5, CustomerID: 3, URL: "https://example.com/hooks",
+		EventTypes: []string{"deposit.completed"}, CreatedAt: createdAt}, got)
+
+	mock.ExpectExec("DELETE FROM webhook_subscriptions WHERE id = \\$1").WithArgs(int64(7)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, repo.DeleteSubscription(7), ErrWebhookNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+var deliveryColumnNames = []string{"id", "subscription_id", "event_id", "event_type", "payload", "status", "attempts", "next_attempt_at",
+	"last_status_code", "last_error", "created_at", "delivered_at"}
+
+func TestPsqlWebhookRepository_Deliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &PsqlWebhookRepository{DB: db}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	payload := []byte(`{"id": "e1"}`)
+
+	// A transfer goes to the customers of both accounts.
+	event := models.Event{ID: "e1", Type: "transfer.completed", AccountID: 10, CounterpartyID: 11}
+	mock.ExpectExec("INSERT INTO webhook_deliveries .* ON CONFLICT \\(subscription_id, event_id\\) DO NOTHING").
+		WithArgs("e1", "transfer.completed", payload, pq.Array([]int64{10, 11})).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	n, err := repo.EnqueueDeliveries(event, payload)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	mock.ExpectQuery("FOR UPDATE SKIP LOCKED .* UPDATE webhook_deliveries w SET next_attempt_at = \\$2").
+		WithArgs(now, now.Add(time.Minute), 50).
+		WillReturnRows(sqlmock.NewRows(append(deliveryColumnNames, "url", "secret")).
+			AddRow(8, 5, "e1", "transfer.completed", payload, "pending", 1, now, 503, "receiver responded 503", now, nil,
+				"https://example.com/hooks", "whsec_test"))
+
+	due, err := repo.ClaimDue(now, time.Minute, 50)
+	assert.NoError(t, err)
+	code := 503
+	assert.Equal(t, []models.DueWebhookDelivery{{
+		WebhookDelivery: models.WebhookDelivery{ID: 8, SubscriptionID: 5, EventID: "e1", EventType: "transfer.completed",
+			Payload: json.RawMessage(payload), Status: "pending", Attempts: 1, NextAttemptAt: &now, LastStatusCode: &code,
+			LastError: "receiver responded 503", CreatedAt: now},
+		URL:    "https://example.com/hooks",
+		Secret: "whsec_test",
+	}}, due)
+
+	delivered := due[0].WebhookDelivery
+	delivered.Status, delivered.Attempts, delivered.NextAttemptAt, delivered.LastError, delivered.DeliveredAt = "delivered", 2, nil, "", &now
+	mock.ExpectExec("UPDATE webhook_deliveries SET status = \\$2").
+		WithArgs(int64(8), "delivered", 2, nil, &code, "", &now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.RecordAttempt(&delivered))
+
+	mock.ExpectQuery("SELECT (.+) FROM webhook_deliveries d WHERE subscription_id = \\$1 AND \\(\\$2 = '' OR status = \\$2\\)").
+		WithArgs(int64(5), "dead", 20).
+		WillReturnRows(sqlmock.NewRows(deliveryColumnNames).AddRow(9, 5, "e2", "deposit.completed", payload, "dead", 8, nil, nil, "timeout", now, nil))
+
+	deliveries, err := repo.ListDeliveries(5, "dead", 20)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.WebhookDelivery{{ID: 9, SubscriptionID: 5, EventID: "e2", EventType: "deposit.completed",
+		Payload: json.RawMessage(payload), Status: "dead", Attempts: 8, LastError: "timeout", CreatedAt: now}}, deliveries)
+
+	mock.ExpectQuery("UPDATE webhook_deliveries d SET status = 'pending', attempts = 0, next_attempt_at = now\\(\\)").
+		WithArgs(int64(9), int64(5)).
+		WillReturnRows(sqlmock.NewRows(deliveryColumnNames).AddRow(9, 5, "e2", "deposit.completed", payload, "pending", 0, now, nil, "timeout", now, nil))
+
+	resent, err := repo.Resend(5, 9)
+	assert.NoError(t, err)
+	assert.Equal(t, "pending", resent.Status)
+	assert.Equal(t, &now, resent.NextAttemptAt)
+
+	// The delivery is not the subscription's.
+	mock.ExpectQuery("UPDATE webhook_deliveries d SET status = 'pending'").WithArgs(int64(9), int64(6)).
+		WillReturnRows(sqlmock.NewRows(deliveryColumnNames))
+
+	_, err = repo.Resend(6, 9)
+	assert.ErrorIs(t, err, ErrDeliveryNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -1,87 +1,798 @@
 package services
 
 import (
-	"encoding/json"
 	"errors"
+	"fmt"
+	"slices"
+	"time"
 
+	"github.com/gregoryAlvim/gobank/internal/accountnumber"
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/currency"
 	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
 	"github.com/gregoryAlvim/gobank/internal/repositories"
+	"github.com/gregoryAlvim/gobank/internal/taxid"
+	"github.com/gregoryAlvim/gobank/internal/validation"
 )
 
+var (
+	ErrInvalidAccountType = errors.New("invalid account type")
+	// ErrInvalidAmount is wrapped with the operation, e.g. "deposit amount
+	// must be positive".
+	ErrInvalidAmount = errors.New("amount must be positive")
+	ErrSameAccount   = errors.New("cannot transfer to the same account")
+	ErrSelfApproval  = errors.New("operators cannot review transfers they requested")
+	// ErrPayoutAccount is returned when the account a closed account's
+	// balance would be paid out to is not another account of the same
+	// customer.
+	ErrPayoutAccount = errors.New("payout account must belong to the same customer")
+)
+
+// DefaultApprovalTTL is how long a transfer waits for approval when
+// AccountService.ApprovalTTL is zero.
+const DefaultApprovalTTL = 24 * time.Hour
+
 type AccountService struct {
 	repo repositories.AccountRepository
+	// ApprovalThreshold is the largest amount, in currency.Default,
+	// transferred straight away. Larger transfers wait for an operator's
+	// approval. Zero disables approvals.
+	ApprovalThreshold money.Money
+	// Rates converts transfers out of accounts in other currencies to
+	// currency.Default for the comparison with ApprovalThreshold. It is
+	// only needed when there is a threshold.
+	Rates repositories.FXRepository
+	// ApprovalTTL is how long a transfer waits for approval before it
+	// expires. Zero means DefaultApprovalTTL.
+	ApprovalTTL time.Duration
 }
 
 func NewAccountService(repo repositories.AccountRepository) *AccountService {
 	return &AccountService{repo: repo}
 }
 
-func (s *AccountService) CreateAccount(accountType string, data []byte) error {
-	switch accountType {
-	case "natural":
-		var person models.NaturalPerson
-		if err := json.Unmarshal(data, &person); err != nil {
-			return err
+// CreateAccount registers a new customer of the given type and opens its
+// first account. data holds the customer fields, the password the
+// customer will log in with, and the account's category and opening
+// balance. Invalid payloads are rejected with validation.Errors listing
+// every bad field. The customer's CPF or CNPJ is stored without its mask
+// and the password only as a bcrypt hash. requestID is recorded in the
+// audit log, with the new customer as the actor.
+func (s *AccountService) CreateAccount(customerType string, data []byte, requestID string) (*models.Account, error) {
+	switch customerType {
+	case models.CustomerTypeNatural:
+		var req struct {
+			models.NaturalPerson
+			models.OpenAccountRequest
+			models.Credentials
+		}
+		if err := validation.DecodeJSON(data, &req); err != nil {
+			return nil, err
+		}
+		v := validation.New()
+		req.NaturalPerson.Validate(v)
+		req.OpenAccountRequest.Validate(v)
+		req.Credentials.Validate(v)
+		if err := v.Err(); err != nil {
+			return nil, err
+		}
+
+		person := req.NaturalPerson
+		hash, err := auth.HashPassword(req.Password)
+		if err != nil {
+			return nil, err
 		}
-		return s.repo.CreateNaturalPerson(&person)
-	case "legal":
-		var person models.LegalPerson
-		if err := json.Unmarshal(data, &person); err != nil {
-			return err
+		person.PasswordHash = hash
+		person.CPF, _ = taxid.NormalizeCPF(person.CPF)
+		account := req.OpenAccountRequest.Account(0)
+		if err := s.repo.CreateNaturalPerson(&person, &account, requestID); err != nil {
+			return nil, err
 		}
-		return s.repo.CreateLegalPerson(&person)
+		return &account, nil
+	case models.CustomerTypeLegal:
+		var req struct {
+			models.LegalPerson
+			models.OpenAccountRequest
+			models.Credentials
+		}
+		if err := validation.DecodeJSON(data, &req); err != nil {
+			return nil, err
+		}
+		v := validation.New()
+		req.LegalPerson.Validate(v)
+		req.OpenAccountRequest.Validate(v)
+		req.Credentials.Validate(v)
+		if err := v.Err(); err != nil {
+			return nil, err
+		}
+
+		person := req.LegalPerson
+		hash, err := auth.HashPassword(req.Password)
+		if err != nil {
+			return nil, err
+		}
+		person.PasswordHash = hash
+		person.CNPJ, _ = taxid.NormalizeCNPJ(person.CNPJ)
+		account := req.OpenAccountRequest.Account(0)
+		if err := s.repo.CreateLegalPerson(&person, &account, requestID); err != nil {
+			return nil, err
+		}
+		return &account, nil
 	default:
-		return errors.New("invalid account type")
+		return nil, ErrInvalidAccountType
+	}
+}
+
+// OpenAccount opens another account for an existing customer on behalf of
+// actor.
+func (s *AccountService) OpenAccount(actor *auth.Principal, customerID int, request models.OpenAccountRequest) (*models.Account, error) {
+	v := validation.New()
+	request.Validate(v)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	account := request.Account(customerID)
+	if err := s.repo.CreateAccount(&account, auditActor(actor)); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (s *AccountService) GetCustomerAccounts(customerID int) ([]models.Account, error) {
+	return s.repo.GetCustomerAccounts(customerID)
+}
+
+func (s *AccountService) GetAccount(accountID int) (*models.Account, error) {
+	return s.repo.GetAccount(accountID)
+}
+
+// GetAccountByNumber looks an account up by the number shown to customers.
+// The number's check digit must already have been verified.
+func (s *AccountService) GetAccountByNumber(number accountnumber.Number) (*models.Account, error) {
+	return s.repo.GetAccountByNumber(number)
+}
+
+// GetBalance returns the account's ledger balance and the part of it that
+// is not held.
+func (s *AccountService) GetBalance(accountID int) (*models.Balance, error) {
+	return s.repo.GetBalances(accountID)
+}
+
+// GetBalanceAsOf returns the account's ledger balance at asOf.
+func (s *AccountService) GetBalanceAsOf(accountID int, asOf time.Time) (*models.BalanceAsOf, error) {
+	balance, err := s.repo.GetBalanceAsOf(accountID, asOf)
+	if err != nil {
+		return nil, err
 	}
+	return &models.BalanceAsOf{Ledger: balance, AsOf: asOf}, nil
 }
 
-func (s *AccountService) GetBalance(accountID int, accountType string) (float64, error) {
-	return s.repo.GetAccountBalance(accountID, accountType)
+// balanceSnapshotDelay is how long after midnight (UTC) the day before is
+// snapshotted, so that transactions begun before midnight have committed.
+const balanceSnapshotDelay = time.Hour
+
+// SnapshotBalances takes the daily balance snapshots of every day after
+// the last one snapshotted, up to the last day that ended
+// balanceSnapshotDelay before now, and returns how many it took. The
+// first run only snapshots that day.
+func (s *AccountService) SnapshotBalances(now time.Time) (int, error) {
+	through := now.Add(-balanceSnapshotDelay).UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	last, err := s.repo.LastBalanceSnapshotDate()
+	if err != nil {
+		return 0, err
+	}
+	day := through
+	if !last.IsZero() {
+		day = models.DateOf(last).AddDays(1).Time
+	}
+
+	taken := 0
+	for ; !day.After(through); day = day.AddDate(0, 0, 1) {
+		n, err := s.repo.SnapshotBalances(day)
+		if err != nil {
+			return taken, err
+		}
+		taken += n
+	}
+	return taken, nil
 }
 
-func (s *AccountService) Deposit(accountID int, amount float64, accountType string) error {
-	if amount <= 0 {
-		return errors.New("deposit amount must be positive")
+const (
+	DefaultTransactionPageSize = 50
+	MaxTransactionPageSize     = 200
+)
+
+// GetTransactions returns one page of an account's history, newest first.
+// NextCursor is set when older transactions remain.
+func (s *AccountService) GetTransactions(accountID int, filter models.TransactionFilter) (*models.TransactionPage, error) {
+	if _, err := s.repo.GetAccountBalance(accountID); err != nil {
+		return nil, err
 	}
 
-	currentBalance, err := s.repo.GetAccountBalance(accountID, accountType)
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultTransactionPageSize
+	}
+	if filter.Limit > MaxTransactionPageSize {
+		filter.Limit = MaxTransactionPageSize
+	}
+	pageSize := filter.Limit
+
+	// Fetch one extra row to find out whether there is a next page.
+	filter.Limit++
+	transactions, err := s.repo.GetTransactions(accountID, filter)
 	if err != nil {
+		return nil, err
+	}
+
+	page := &models.TransactionPage{Transactions: transactions}
+	if len(transactions) > pageSize {
+		page.Transactions = transactions[:pageSize]
+		page.NextCursor = EncodeCursor(page.Transactions[pageSize-1].ID)
+	}
+	return page, nil
+}
+
+// Deposit credits amount to the account on behalf of actor. currency is
+// the currency of the amount; it must be the account's, and empty means
+// the account's.
+func (s *AccountService) Deposit(actor *auth.Principal, accountID int, amount money.Money, currency string) error {
+	if !amount.IsPositive() {
+		return fmt.Errorf("deposit %w", ErrInvalidAmount)
+	}
+	if err := s.checkCurrency(accountID, currency); err != nil {
 		return err
 	}
 
-	newBalance := currentBalance + amount
-	return s.repo.UpdateAccountBalance(accountID, newBalance, accountType)
+	return s.repo.DepositTx(accountID, amount, auditActor(actor))
 }
 
-func (s *AccountService) Withdraw(accountID int, amount float64, accountType string) error {
-	if amount <= 0 {
-		return errors.New("withdrawal amount must be positive")
+// Withdraw debits amount from the account on behalf of actor. Like
+// Deposit, currency must be the account's or empty.
+func (s *AccountService) Withdraw(actor *auth.Principal, accountID int, amount money.Money, currency string) error {
+	if !amount.IsPositive() {
+		return fmt.Errorf("withdrawal %w", ErrInvalidAmount)
 	}
+	if err := s.checkCurrency(accountID, currency); err != nil {
+		return err
+	}
+
+	// The funds check happens inside the repository's transaction so that
+	// concurrent withdrawals cannot both pass it.
+	return s.repo.WithdrawTx(accountID, amount, auditActor(actor))
+}
 
-	currentBalance, err := s.repo.GetAccountBalance(accountID, accountType)
+// checkCurrency reports ErrCurrencyMismatch when currency is set and is
+// not the account's. Accounts never change currency, so the check does not
+// need the lock taken by the deposit or withdrawal.
+func (s *AccountService) checkCurrency(accountID int, currency string) error {
+	if currency == "" {
+		return nil
+	}
+	account, err := s.repo.GetAccount(accountID)
 	if err != nil {
 		return err
 	}
-
-	if currentBalance < amount {
-		return errors.New("insufficient funds")
+	if account.Currency != currency {
+		return fmt.Errorf("%w: the account holds %s", repositories.ErrCurrencyMismatch, account.Currency)
 	}
+	return nil
+}
+
+// Transfer performs the money transfer between two accounts within a
+// transaction. amount is in the source account's currency; between
+// accounts in different currencies it is converted at the rate locked by
+// quoteID, or at the current rate when quoteID is zero. Transfers above
+// ApprovalThreshold, compared at the current mid rate when amount is in
+// another currency, are not executed: the amount and the transfer fee are
+// held on the source account and the pending approval is returned
+// instead. Approved transfers are converted at the rate of the moment
+// they are approved, so quoteID is not used for them. requester is
+// whoever asked for the transfer; an operator can never approve their
+// own.
+func (s *AccountService) Transfer(requester *auth.Principal, fromID, toID int, amount money.Money, quoteID int64) (*models.TransferApproval, error) {
+	return s.transfer(requester, "", fromID, toID, amount, quoteID)
+}
 
-	newBalance := currentBalance - amount
-	return s.repo.UpdateAccountBalance(accountID, newBalance, accountType)
+// TransferOnce is Transfer for a transfer that must happen at most once,
+// such as one run of a scheduled transfer. When a transfer or approval
+// was already made with key it is not made again: the approval made is
+// returned, or nil for a transfer, as if it had just been made.
+func (s *AccountService) TransferOnce(requester *auth.Principal, key string, fromID, toID int, amount money.Money) (*models.TransferApproval, error) {
+	approval, err := s.transfer(requester, key, fromID, toID, amount, 0)
+	if errors.Is(err, repositories.ErrTransferMade) {
+		return nil, nil
+	}
+	return approval, err
 }
 
-// Transfer performs the money transfer between two accounts within a transaction.
-func (s *AccountService) Transfer(fromID, toID int, amount float64, fromType, toType string) error {
-	if amount <= 0 {
-		return errors.New("transfer amount must be positive")
+func (s *AccountService) transfer(requester *auth.Principal, key string, fromID, toID int, amount money.Money, quoteID int64) (*models.TransferApproval, error) {
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("transfer %w", ErrInvalidAmount)
+	}
+	if fromID == toID {
+		return nil, ErrSameAccount
+	}
+
+	needsApproval, err := s.needsApproval(fromID, amount)
+	if err != nil {
+		return nil, err
+	}
+	if needsApproval {
+		ttl := s.ApprovalTTL
+		if ttl <= 0 {
+			ttl = DefaultApprovalTTL
+		}
+		approval := &models.TransferApproval{
+			FromAccountID: fromID,
+			ToAccountID:   toID,
+			Amount:        amount,
+			ExpiresAt:     time.Now().Add(ttl),
+			Key:           key,
+		}
+		if requester.IsOperator() {
+			approval.RequestedByOperatorID = requester.OperatorID
+		} else {
+			approval.RequestedByCustomerID = requester.CustomerID
+		}
+		if err := s.repo.CreateTransferApproval(approval); err != nil {
+			return nil, err
+		}
+		return approval, nil
 	}
 
 	// The actual withdrawal and deposit will be handled by the repository
 	// within a single database transaction to ensure atomicity.
-	return s.repo.TransferTx(fromID, toID, amount, fromType, toType)
+	if key != "" {
+		return nil, s.repo.TransferOnceTx(key, fromID, toID, amount, auditActor(requester))
+	}
+	return nil, s.repo.TransferTx(fromID, toID, amount, quoteID, auditActor(requester))
+}
+
+// needsApproval reports whether a transfer of amount out of the account
+// fromID is above ApprovalThreshold. An amount in another currency is
+// converted to the threshold's at the mid rate; one that cannot be
+// converted for lack of a rate needs approval.
+func (s *AccountService) needsApproval(fromID int, amount money.Money) (bool, error) {
+	if !s.ApprovalThreshold.IsPositive() {
+		return false, nil
+	}
+	account, err := s.repo.GetAccount(fromID)
+	if err != nil {
+		return false, err
+	}
+	if account.Currency != currency.Default {
+		rate, err := s.Rates.GetRate(account.Currency, currency.Default)
+		if errors.Is(err, repositories.ErrFXRateNotFound) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if amount, err = rate.Rate.Convert(amount); err != nil {
+			return false, err
+		}
+	}
+	return amount.Cmp(s.ApprovalThreshold) > 0, nil
+}
+
+const (
+	DefaultApprovalPageSize = 50
+	MaxApprovalPageSize     = 200
+)
+
+// ListTransferApprovals returns one page of transfer approvals, newest
+// first. NextCursor is set when older approvals remain.
+func (s *AccountService) ListTransferApprovals(filter models.ApprovalFilter) (*models.ApprovalPage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultApprovalPageSize
+	}
+	if filter.Limit > MaxApprovalPageSize {
+		filter.Limit = MaxApprovalPageSize
+	}
+	pageSize := filter.Limit
+
+	// Fetch one extra row to find out whether there is a next page.
+	filter.Limit++
+	approvals, err := s.repo.ListTransferApprovals(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.ApprovalPage{Approvals: approvals}
+	if len(approvals) > pageSize {
+		page.Approvals = approvals[:pageSize]
+		page.NextCursor = EncodeCursor(page.Approvals[pageSize-1].ID)
+	}
+	return page, nil
 }
 
-func (s *AccountService) CloseAccount(accountID int, accountType string) error {
-	return s.repo.DeleteAccount(accountID, accountType)
+// ApproveTransfer executes a pending transfer. The reason is optional.
+func (s *AccountService) ApproveTransfer(approvalID int64, reviewer *auth.Principal, request models.ReviewRequest) (*models.TransferApproval, error) {
+	if err := s.checkReview(approvalID, reviewer, request, false); err != nil {
+		return nil, err
+	}
+	return s.repo.ApproveTransfer(approvalID, auditActor(reviewer), request.Reason)
+}
+
+// RejectTransfer cancels a pending transfer and releases the held funds.
+// The reason is required.
+func (s *AccountService) RejectTransfer(approvalID int64, reviewer *auth.Principal, request models.ReviewRequest) (*models.TransferApproval, error) {
+	if err := s.checkReview(approvalID, reviewer, request, true); err != nil {
+		return nil, err
+	}
+	return s.repo.RejectTransfer(approvalID, auditActor(reviewer), request.Reason)
+}
+
+// checkReview validates the request and makes sure reviewer is an
+// operator other than the one who requested the transfer.
+func (s *AccountService) checkReview(approvalID int64, reviewer *auth.Principal, request models.ReviewRequest, reasonRequired bool) error {
+	if !reviewer.IsOperator() {
+		return auth.ErrForbidden
+	}
+	v := validation.New()
+	request.Validate(v, reasonRequired)
+	if err := v.Err(); err != nil {
+		return err
+	}
+
+	approval, err := s.repo.GetTransferApproval(approvalID)
+	if err != nil {
+		return err
+	}
+	if approval.RequestedByOperatorID == reviewer.OperatorID {
+		return ErrSelfApproval
+	}
+	return nil
+}
+
+const (
+	// DefaultHoldTTL is how long a hold lasts when the request does not
+	// say.
+	DefaultHoldTTL = 7 * 24 * time.Hour
+	// MaxHoldTTL is the longest a hold may last.
+	MaxHoldTTL = 30 * 24 * time.Hour
+
+	DefaultHoldPageSize = 50
+	MaxHoldPageSize     = 200
+)
+
+// PlaceHold reserves an amount on the account, lowering its available
+// balance until the hold is captured, voided or expires.
+func (s *AccountService) PlaceHold(accountID int, request models.HoldRequest) (*models.Hold, error) {
+	now := time.Now()
+	v := validation.New()
+	request.Validate(v)
+	if request.ExpiresAt != nil {
+		v.Check(request.ExpiresAt.After(now) && !request.ExpiresAt.After(now.Add(MaxHoldTTL)),
+			"expires_at", validation.CodeOutOfRange, fmt.Sprintf("must be in the next %d days", MaxHoldTTL/(24*time.Hour)))
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	hold := &models.Hold{AccountID: accountID, Amount: request.Amount, Description: request.Description, ExpiresAt: now.Add(DefaultHoldTTL)}
+	if request.ExpiresAt != nil {
+		hold.ExpiresAt = *request.ExpiresAt
+	}
+	if err := s.repo.CreateHold(hold); err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// ListHolds returns one page of the account's holds, newest first.
+// NextCursor is set when older holds remain.
+func (s *AccountService) ListHolds(accountID int, filter models.HoldFilter) (*models.HoldPage, error) {
+	if _, err := s.repo.GetAccountBalance(accountID); err != nil {
+		return nil, err
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultHoldPageSize
+	}
+	if filter.Limit > MaxHoldPageSize {
+		filter.Limit = MaxHoldPageSize
+	}
+	pageSize := filter.Limit
+
+	// Fetch one extra row to find out whether there is a next page.
+	filter.Limit++
+	holds, err := s.repo.ListHolds(accountID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.HoldPage{Holds: holds}
+	if len(holds) > pageSize {
+		page.Holds = holds[:pageSize]
+		page.NextCursor = EncodeCursor(page.Holds[pageSize-1].ID)
+	}
+	return page, nil
+}
+
+// CaptureHold debits the captured amount, the whole hold by default, on
+// behalf of actor and releases the rest. The withdrawal fee is charged
+// with it.
+func (s *AccountService) CaptureHold(actor *auth.Principal, accountID int, holdID int64, request models.CaptureRequest) (*models.Hold, error) {
+	v := validation.New()
+	request.Validate(v)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	var amount money.Money
+	if request.Amount != nil {
+		amount = *request.Amount
+	}
+	return s.repo.CaptureHold(accountID, holdID, amount, auditActor(actor))
+}
+
+// VoidHold releases a hold without debiting anything.
+func (s *AccountService) VoidHold(accountID int, holdID int64) (*models.Hold, error) {
+	return s.repo.VoidHold(accountID, holdID)
+}
+
+// GetOverdraft returns the account's overdraft limit and what is left of
+// it.
+func (s *AccountService) GetOverdraft(accountID int) (*models.Overdraft, error) {
+	return s.repo.GetOverdraft(accountID)
+}
+
+// SetOverdraftLimit gives the account its own overdraft limit, replacing
+// its category's, or removes it when the request's limit is null.
+func (s *AccountService) SetOverdraftLimit(accountID int, request models.OverdraftLimitRequest) error {
+	v := validation.New()
+	request.Validate(v)
+	if err := v.Err(); err != nil {
+		return err
+	}
+	return s.repo.SetOverdraftLimit(accountID, request.Limit)
+}
+
+func (s *AccountService) ListOverdraftPolicies() ([]models.OverdraftPolicy, error) {
+	return s.repo.ListOverdraftPolicies()
+}
+
+// SetOverdraftPolicy sets the overdraft limit and interest rate of every
+// account in the category that has no limit of its own.
+func (s *AccountService) SetOverdraftPolicy(category string, policy models.OverdraftPolicy) (*models.OverdraftPolicy, error) {
+	policy.Category = category
+	v := validation.New()
+	v.OneOf("category", policy.Category, models.AccountCategories...)
+	policy.Validate(v)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetOverdraftPolicy(&policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// ChargeOverdraftInterest charges one day of interest to every account
+// with a negative balance and returns how many were charged. Accounts
+// already charged for date are skipped, so the job can run again for the
+// same day. A failure on one account does not stop the others; the
+// failures are returned together.
+func (s *AccountService) ChargeOverdraftInterest(date time.Time) (int, error) {
+	accountIDs, err := s.repo.ListOverdrawnAccounts()
+	if err != nil {
+		return 0, err
+	}
+
+	charged := 0
+	var errs []error
+	for _, accountID := range accountIDs {
+		interest, err := s.repo.ChargeOverdraftInterest(accountID, date)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("account %d: %w", accountID, err))
+			continue
+		}
+		if interest.IsPositive() {
+			charged++
+		}
+	}
+	return charged, errors.Join(errs...)
+}
+
+func (s *AccountService) ListFees() ([]models.Fee, error) {
+	return s.repo.ListFees()
+}
+
+// SetFee sets the amount of a withdrawal, transfer or maintenance fee.
+// New amounts apply to operations from then on.
+func (s *AccountService) SetFee(fee models.Fee) (*models.Fee, error) {
+	v := validation.New()
+	fee.Validate(v)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetFee(&fee); err != nil {
+		return nil, err
+	}
+	return &fee, nil
+}
+
+func (s *AccountService) ListFeeWaivers(accountID int) ([]models.FeeWaiver, error) {
+	return s.repo.ListFeeWaivers(accountID)
+}
+
+// WaiveFee stops charging the account one type of fee until the waiver is
+// removed.
+func (s *AccountService) WaiveFee(accountID int, feeType string, request models.FeeWaiverRequest) (*models.FeeWaiver, error) {
+	v := validation.New()
+	v.OneOf("fee_type", feeType, models.FeeTypes...)
+	request.Validate(v)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	waiver := &models.FeeWaiver{AccountID: accountID, FeeType: feeType, Reason: request.Reason}
+	if err := s.repo.WaiveFee(waiver); err != nil {
+		return nil, err
+	}
+	return waiver, nil
+}
+
+func (s *AccountService) RemoveFeeWaiver(accountID int, feeType string) error {
+	return s.repo.RemoveFeeWaiver(accountID, feeType)
+}
+
+// ChargeMaintenanceFees charges the maintenance fee of date's month to
+// every account opened before the month began and returns how many were
+// charged. Accounts already charged for the month are skipped, so the job
+// can run again in the same month. A failure on one account does not stop
+// the others; the failures are returned together.
+func (s *AccountService) ChargeMaintenanceFees(date time.Time) (int, error) {
+	period := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	accountIDs, err := s.repo.ListMaintenanceFeeAccounts(period)
+	if err != nil {
+		return 0, err
+	}
+
+	charged := 0
+	var errs []error
+	for _, accountID := range accountIDs {
+		fee, err := s.repo.ChargeMaintenanceFee(accountID, period)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("account %d: %w", accountID, err))
+			continue
+		}
+		if fee.IsPositive() {
+			charged++
+		}
+	}
+	return charged, errors.Join(errs...)
+}
+
+// FreezeAccount stops the account from being debited. Credits still go
+// through.
+func (s *AccountService) FreezeAccount(actor *auth.Principal, accountID int, request models.StatusChangeRequest) (*models.AccountStatusChange, error) {
+	return s.changeStatus(actor, accountID, request, models.AccountFrozen)
+}
+
+// UnfreezeAccount makes a frozen account active again.
+func (s *AccountService) UnfreezeAccount(actor *auth.Principal, accountID int, request models.StatusChangeRequest) (*models.AccountStatusChange, error) {
+	return s.changeStatus(actor, accountID, request, models.AccountActive, models.AccountFrozen)
+}
+
+// ActivateAccount makes a pending or dormant account active. Frozen
+// accounts are unfrozen instead, which takes a different permission.
+func (s *AccountService) ActivateAccount(actor *auth.Principal, accountID int, request models.StatusChangeRequest) (*models.AccountStatusChange, error) {
+	return s.changeStatus(actor, accountID, request, models.AccountActive, models.AccountPending, models.AccountDormant)
+}
+
+// changeStatus moves the account to status. When from is not empty the
+// account must currently be in one of those statuses.
+func (s *AccountService) changeStatus(actor *auth.Principal, accountID int, request models.StatusChangeRequest, status string, from ...string) (*models.AccountStatusChange, error) {
+	v := validation.New()
+	request.Validate(v)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(from) > 0 {
+		account, err := s.repo.GetAccount(accountID)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(from, account.Status) {
+			return nil, fmt.Errorf("%w from %s to %s", repositories.ErrStatusTransition, account.Status, status)
+		}
+	}
+
+	change := newStatusChange(actor, accountID, request.Reason)
+	change.ToStatus = status
+	if err := s.repo.ChangeAccountStatus(change, auditActor(actor)); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// auditActor is the principal as the audit log records it.
+func auditActor(p *auth.Principal) models.Actor {
+	actor := models.Actor{RequestID: p.RequestID}
+	if p.IsOperator() {
+		actor.OperatorID = p.OperatorID
+	} else {
+		actor.CustomerID = p.CustomerID
+	}
+	return actor
+}
+
+func newStatusChange(actor *auth.Principal, accountID int, reason string) *models.AccountStatusChange {
+	change := &models.AccountStatusChange{AccountID: accountID, Reason: reason}
+	if actor.IsOperator() {
+		change.ChangedByOperatorID = actor.OperatorID
+	} else {
+		change.ChangedByCustomerID = actor.CustomerID
+	}
+	return change
+}
+
+// ListStatusChanges returns the account's status changes, newest first.
+func (s *AccountService) ListStatusChanges(accountID int) ([]models.AccountStatusChange, error) {
+	if _, err := s.repo.GetAccount(accountID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListStatusChanges(accountID)
+}
+
+// DormantReason is the reason recorded for accounts marked dormant by
+// MarkDormantAccounts.
+const DormantReason = "no customer activity"
+
+// MarkDormantAccounts marks dormant the active accounts with no deposit,
+// withdrawal, transfer or capture in the period before now, and returns
+// how many it marked.
+func (s *AccountService) MarkDormantAccounts(now time.Time, period time.Duration) (int, error) {
+	return s.repo.MarkDormantAccounts(now.Add(-period), DormantReason)
+}
+
+// CorrectBalance sets the account's balance, journaling the difference as
+// an adjustment. It works on frozen accounts too.
+func (s *AccountService) CorrectBalance(actor *auth.Principal, accountID int, request models.BalanceCorrectionRequest) error {
+	v := validation.New()
+	request.Validate(v)
+	if err := v.Err(); err != nil {
+		return err
+	}
+	return s.repo.UpdateAccountBalance(accountID, request.Balance, auditActor(actor))
+}
+
+// CloseAccount closes the account for good. The account is kept with its
+// history; what is left of the balance, interest included, is moved to
+// the request's payout account, which must be another open account of the
+// same customer.
+func (s *AccountService) CloseAccount(actor *auth.Principal, accountID int, request models.CloseAccountRequest) (*models.AccountStatusChange, error) {
+	v := validation.New()
+	request.Validate(v)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	if request.PayoutAccountID != 0 {
+		if request.PayoutAccountID == accountID {
+			return nil, ErrSameAccount
+		}
+		account, err := s.repo.GetAccount(accountID)
+		if err != nil {
+			return nil, err
+		}
+		payout, err := s.repo.GetAccount(request.PayoutAccountID)
+		if errors.Is(err, repositories.ErrAccountNotFound) || (err == nil && payout.CustomerID != account.CustomerID) {
+			return nil, ErrPayoutAccount
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	change := newStatusChange(actor, accountID, request.Reason)
+	if err := s.repo.CloseAccount(change, request.PayoutAccountID, auditActor(actor)); err != nil {
+		return nil, err
+	}
+	return change, nil
 }
@@ -1,10 +1,47 @@
 package services
 
+import (
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/accountnumber"
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
 type AccountServiceInterface interface {
-	CreateAccount(accountType string, data []byte) error
-	GetBalance(accountID int, accountType string) (float64, error)
-	Deposit(accountID int, amount float64, accountType string) error
-	Withdraw(accountID int, amount float64, accountType string) error
-	Transfer(fromID, toID int, amount float64, fromType, toType string) error
-	CloseAccount(accountID int, accountType string) error
+	CreateAccount(customerType string, data []byte, requestID string) (*models.Account, error)
+	OpenAccount(actor *auth.Principal, customerID int, request models.OpenAccountRequest) (*models.Account, error)
+	GetCustomerAccounts(customerID int) ([]models.Account, error)
+	GetAccount(accountID int) (*models.Account, error)
+	GetAccountByNumber(number accountnumber.Number) (*models.Account, error)
+	GetBalance(accountID int) (*models.Balance, error)
+	GetBalanceAsOf(accountID int, asOf time.Time) (*models.BalanceAsOf, error)
+	GetTransactions(accountID int, filter models.TransactionFilter) (*models.TransactionPage, error)
+	Deposit(actor *auth.Principal, accountID int, amount money.Money, currency string) error
+	Withdraw(actor *auth.Principal, accountID int, amount money.Money, currency string) error
+	Transfer(requester *auth.Principal, fromID, toID int, amount money.Money, quoteID int64) (*models.TransferApproval, error)
+	TransferOnce(requester *auth.Principal, key string, fromID, toID int, amount money.Money) (*models.TransferApproval, error)
+	ListTransferApprovals(filter models.ApprovalFilter) (*models.ApprovalPage, error)
+	ApproveTransfer(approvalID int64, reviewer *auth.Principal, request models.ReviewRequest) (*models.TransferApproval, error)
+	RejectTransfer(approvalID int64, reviewer *auth.Principal, request models.ReviewRequest) (*models.TransferApproval, error)
+	PlaceHold(accountID int, request models.HoldRequest) (*models.Hold, error)
+	ListHolds(accountID int, filter models.HoldFilter) (*models.HoldPage, error)
+	CaptureHold(actor *auth.Principal, accountID int, holdID int64, request models.CaptureRequest) (*models.Hold, error)
+	VoidHold(accountID int, holdID int64) (*models.Hold, error)
+	GetOverdraft(accountID int) (*models.Overdraft, error)
+	SetOverdraftLimit(accountID int, request models.OverdraftLimitRequest) error
+	ListOverdraftPolicies() ([]models.OverdraftPolicy, error)
+	SetOverdraftPolicy(category string, policy models.OverdraftPolicy) (*models.OverdraftPolicy, error)
+	ListFees() ([]models.Fee, error)
+	SetFee(fee models.Fee) (*models.Fee, error)
+	ListFeeWaivers(accountID int) ([]models.FeeWaiver, error)
+	WaiveFee(accountID int, feeType string, request models.FeeWaiverRequest) (*models.FeeWaiver, error)
+	RemoveFeeWaiver(accountID int, feeType string) error
+	FreezeAccount(actor *auth.Principal, accountID int, request models.StatusChangeRequest) (*models.AccountStatusChange, error)
+	UnfreezeAccount(actor *auth.Principal, accountID int, request models.StatusChangeRequest) (*models.AccountStatusChange, error)
+	ActivateAccount(actor *auth.Principal, accountID int, request models.StatusChangeRequest) (*models.AccountStatusChange, error)
+	ListStatusChanges(accountID int) ([]models.AccountStatusChange, error)
+	CorrectBalance(actor *auth.Principal, accountID int, request models.BalanceCorrectionRequest) error
+	CloseAccount(actor *auth.Principal, accountID int, request models.CloseAccountRequest) (*models.AccountStatusChange, error)
 }
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/fx"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	repomocks "github.com/gregoryAlvim/gobank/internal/repositories/mocks"
+)
+
+func TestAccountService_SnapshotBalances(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, time.March, d, 0, 0, 0, 0, time.UTC) }
+
+	for _, tt := range []struct {
+		name string
+		now  time.Time
+		last time.Time
+		want []time.Time
+	}{
+		{"first run", day(10).Add(2 * time.Hour), time.Time{}, []time.Time{day(9)}},
+		{"catches up", day(10).Add(2 * time.Hour), day(6), []time.Time{day(7), day(8), day(9)}},
+		{"up to date", day(10).Add(2 * time.Hour), day(9), nil},
+		// Transactions begun before midnight may not have committed yet.
+		{"right after midnight", day(10).Add(30 * time.Minute), day(8), nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repomocks.NewAccountRepository(t)
+			service := NewAccountService(repo)
+
+			repo.On("LastBalanceSnapshotDate").Return(tt.last, nil)
+			for _, d := range tt.want {
+				repo.On("SnapshotBalances", d).Return(2, nil).Once()
+			}
+
+			n, err := service.SnapshotBalances(tt.now)
+			assert.NoError(t, err)
+			assert.Equal(t, 2*len(tt.want), n)
+		})
+	}
+}
+
+func TestAccountService_GetBalanceAsOf(t *testing.T) {
+	repo := repomocks.NewAccountRepository(t)
+	service := NewAccountService(repo)
+	asOf := time.Date(2026, 1, 31, 23, 59, 59, 0, time.UTC)
+
+	repo.On("GetBalanceAsOf", 10, asOf).Return(money.MustParse("120.50"), nil)
+
+	balance, err := service.GetBalanceAsOf(10, asOf)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.BalanceAsOf{Ledger: money.MustParse("120.50"), AsOf: asOf}, balance)
+}
+
+func TestAccountService_TransferOnce(t *testing.T) {
+	customer := &auth.Principal{CustomerID: 4, Role: auth.RoleCustomer, RequestID: "scheduled-transfer:1:2026-03-01:1"}
+	actor := models.Actor{CustomerID: 4, RequestID: "scheduled-transfer:1:2026-03-01:1"}
+
+	repo := repomocks.NewAccountRepository(t)
+	service := NewAccountService(repo)
+	repo.On("TransferOnceTx", "scheduled-transfer:1:2026-03-01", 20, 30, money.New(100, 0), actor).Return(nil)
+
+	approval, err := service.TransferOnce(customer, "scheduled-transfer:1:2026-03-01", 20, 30, money.New(100, 0))
+	assert.NoError(t, err)
+	assert.Nil(t, approval)
+
+	// Above the threshold the key goes with the approval. One made as a
+	// transfer before the threshold was set counts as done.
+	repo = repomocks.NewAccountRepository(t)
+	service = NewAccountService(repo)
+	service.ApprovalThreshold = money.New(50, 0)
+	repo.On("GetAccount", 20).Return(&models.Account{ID: 20, Currency: "BRL"}, nil)
+	repo.On("CreateTransferApproval", mock.MatchedBy(func(a *models.TransferApproval) bool {
+		return a.Key == "scheduled-transfer:1:2026-03-01" && a.RequestedByCustomerID == 4
+	})).Return(repositories.ErrTransferMade)
+
+	approval, err = service.TransferOnce(customer, "scheduled-transfer:1:2026-03-01", 20, 30, money.New(100, 0))
+	assert.NoError(t, err)
+	assert.Nil(t, approval)
+}
+
+func TestAccountService_Transfer_ApprovalThresholdInOtherCurrency(t *testing.T) {
+	customer := &auth.Principal{CustomerID: 4, Role: auth.RoleCustomer}
+	actor := models.Actor{CustomerID: 4}
+
+	for _, tt := range []struct {
+		name     string
+		amount   money.Money
+		rate     *models.FXRate
+		rateErr  error
+		approval bool
+	}{
+		// USD 150 is BRL 810, below the threshold of BRL 1000.
+		{"below", money.New(150, 0), &models.FXRate{Base: "USD", Quote: "BRL", Rate: fx.MustParseRate("5.4")}, nil, false},
+		// USD 200 is BRL 1080, above it, though 200 alone is not.
+		{"above", money.New(200, 0), &models.FXRate{Base: "USD", Quote: "BRL", Rate: fx.MustParseRate("5.4")}, nil, true},
+		{"no rate", money.New(1, 0), nil, repositories.ErrFXRateNotFound, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repomocks.NewAccountRepository(t)
+			rates := repomocks.NewFXRepository(t)
+			service := NewAccountService(repo)
+			service.ApprovalThreshold = money.New(1000, 0)
+			service.Rates = rates
+
+			repo.On("GetAccount", 20).Return(&models.Account{ID: 20, Currency: "USD"}, nil)
+			rates.On("GetRate", "USD", "BRL").Return(tt.rate, tt.rateErr)
+			if tt.approval {
+				repo.On("CreateTransferApproval", mock.MatchedBy(func(a *models.TransferApproval) bool {
+					return a.Amount == tt.amount
+				})).Return(nil)
+			} else {
+				repo.On("TransferTx", 20, 30, tt.amount, int64(0), actor).Return(nil)
+			}
+
+			approval, err := service.Transfer(customer, 20, 30, tt.amount, 0)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.approval, approval != nil)
+		})
+	}
+}
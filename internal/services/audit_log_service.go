@@ -0,0 +1,75 @@
+package services
+
+import (
+	"github.com/gregoryAlvim/gobank/internal/auditlog"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+)
+
+// auditLogBatch is how many records Verify reads, and Chain chains, at a
+// time.
+const auditLogBatch = 1000
+
+// AuditLogService chains and checks the hash-chained audit log of account
+// changes.
+type AuditLogService struct {
+	repo repositories.AuditLogRepository
+}
+
+func NewAuditLogService(repo repositories.AuditLogRepository) *AuditLogService {
+	return &AuditLogService{repo: repo}
+}
+
+// Chain adds the pending records to the audit log, oldest first, and
+// returns how many it added. Records pending when Chain fails stay
+// pending for the next run.
+func (s *AuditLogService) Chain() (int, error) {
+	chained := 0
+	for {
+		n, err := s.repo.ChainPending(auditLogBatch)
+		chained += n
+		if err != nil || n < auditLogBatch {
+			return chained, err
+		}
+	}
+}
+
+// Verify walks the audit log from the first record and returns its head.
+// The first record that does not follow from the ones before it is
+// reported as an *auditlog.BrokenLinkError, returned with the head of the
+// part of the log that checked out. When anchor is not nil, the record at
+// its sequence must still exist and have its hash. Records not chained yet
+// are not checked.
+func (s *AuditLogService) Verify(anchor *models.AuditLogHead) (*models.AuditLogHead, error) {
+	verifier := auditlog.NewVerifier()
+	head := func() *models.AuditLogHead {
+		sequence, hash := verifier.Head()
+		return &models.AuditLogHead{Sequence: sequence, Hash: hash}
+	}
+
+	var after int64
+	for {
+		records, err := s.repo.ListRecords(after, auditLogBatch)
+		if err != nil {
+			return head(), err
+		}
+		for i := range records {
+			r := &records[i]
+			if anchor != nil && r.Sequence == anchor.Sequence && r.Hash != anchor.Hash {
+				return head(), &auditlog.BrokenLinkError{Sequence: r.Sequence, Reason: "does not match the anchored hash"}
+			}
+			if err := verifier.Check(r); err != nil {
+				return head(), err
+			}
+			after = r.Sequence
+		}
+		if len(records) < auditLogBatch {
+			break
+		}
+	}
+
+	if anchor != nil && after < anchor.Sequence {
+		return head(), &auditlog.BrokenLinkError{Sequence: after + 1, Reason: "is missing"}
+	}
+	return head(), nil
+}
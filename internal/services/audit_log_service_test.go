@@ -0,0 +1,96 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/auditlog"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	repomocks "github.com/gregoryAlvim/gobank/internal/repositories/mocks"
+)
+
+// auditChain returns n chained deposit records.
+func auditChain(n int) []models.AuditRecord {
+	records := make([]models.AuditRecord, n)
+	prevHash := auditlog.Genesis
+	for i := range records {
+		r := &records[i]
+		*r = models.AuditRecord{Sequence: int64(i + 1), Action: models.AuditDeposit, Actor: "customer:1", AccountID: 10,
+			After: json.RawMessage(`[{"account_id":10,"balance":1.00}]`), CreatedAt: time.Date(2026, 3, 1, 12, i, 0, 0, time.UTC),
+			PrevHash: prevHash}
+		r.Hash = auditlog.Hash(r)
+		prevHash = r.Hash
+	}
+	return records
+}
+
+func TestAuditLogService_Verify(t *testing.T) {
+	chain := auditChain(3)
+
+	for _, tt := range []struct {
+		name     string
+		records  func() []models.AuditRecord
+		anchor   *models.AuditLogHead
+		wantHead int64
+		wantErr  *auditlog.BrokenLinkError
+	}{
+		{name: "intact", records: func() []models.AuditRecord { return chain }, wantHead: 3},
+		{name: "empty", records: func() []models.AuditRecord { return nil }, wantHead: 0},
+		{name: "anchored", records: func() []models.AuditRecord { return chain },
+			anchor: &models.AuditLogHead{Sequence: 2, Hash: chain[1].Hash}, wantHead: 3},
+		{name: "edited", records: func() []models.AuditRecord {
+			records := append([]models.AuditRecord(nil), chain...)
+			records[1].Actor = "customer:2"
+			return records
+		}, wantHead: 1, wantErr: &auditlog.BrokenLinkError{Sequence: 2, Reason: "does not match its hash"}},
+		{name: "deleted", records: func() []models.AuditRecord {
+			return []models.AuditRecord{chain[0], chain[2]}
+		}, wantHead: 1, wantErr: &auditlog.BrokenLinkError{Sequence: 2, Reason: "is missing"}},
+		{name: "rewritten after the anchor", records: func() []models.AuditRecord { return chain },
+			anchor:   &models.AuditLogHead{Sequence: 2, Hash: chain[2].Hash},
+			wantHead: 1, wantErr: &auditlog.BrokenLinkError{Sequence: 2, Reason: "does not match the anchored hash"}},
+		{name: "cut off before the anchor", records: func() []models.AuditRecord { return chain[:2] },
+			anchor:   &models.AuditLogHead{Sequence: 3, Hash: chain[2].Hash},
+			wantHead: 2, wantErr: &auditlog.BrokenLinkError{Sequence: 3, Reason: "is missing"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repomocks.NewAuditLogRepository(t)
+			service := NewAuditLogService(repo)
+			repo.On("ListRecords", int64(0), auditLogBatch).Return(tt.records(), nil)
+
+			head, err := service.Verify(tt.anchor)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.wantErr, err)
+			}
+			assert.Equal(t, tt.wantHead, head.Sequence)
+			if tt.wantHead > 0 {
+				assert.Equal(t, chain[tt.wantHead-1].Hash, head.Hash)
+			} else {
+				assert.Equal(t, auditlog.Genesis, head.Hash)
+			}
+		})
+	}
+}
+
+func TestAuditLogService_Chain(t *testing.T) {
+	repo := repomocks.NewAuditLogRepository(t)
+	service := NewAuditLogService(repo)
+
+	// Full batches are followed by another one, until one comes back short.
+	repo.On("ChainPending", auditLogBatch).Return(auditLogBatch, nil).Twice()
+	repo.On("ChainPending", auditLogBatch).Return(3, nil).Once()
+	chained, err := service.Chain()
+	assert.NoError(t, err)
+	assert.Equal(t, 2*auditLogBatch+3, chained)
+
+	repo.On("ChainPending", auditLogBatch).Return(0, errors.New("pq: connection refused")).Once()
+	chained, err = service.Chain()
+	assert.Error(t, err)
+	assert.Zero(t, chained)
+}
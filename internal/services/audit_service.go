@@ -0,0 +1,50 @@
+package services
+
+import (
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+)
+
+const (
+	DefaultAuditPageSize = 50
+	MaxAuditPageSize     = 200
+)
+
+// AuditService keeps the trail of what back-office operators did.
+type AuditService struct {
+	repo repositories.AuditRepository
+}
+
+func NewAuditService(repo repositories.AuditRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+func (s *AuditService) Record(event *models.AuditEvent) error {
+	return s.repo.RecordEvent(event)
+}
+
+// ListEvents returns one page of the audit trail, newest first. NextCursor
+// is set when older events remain.
+func (s *AuditService) ListEvents(filter models.AuditFilter) (*models.AuditPage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultAuditPageSize
+	}
+	if filter.Limit > MaxAuditPageSize {
+		filter.Limit = MaxAuditPageSize
+	}
+	pageSize := filter.Limit
+
+	// Fetch one extra row to find out whether there is a next page.
+	filter.Limit++
+	events, err := s.repo.ListEvents(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.AuditPage{Events: events}
+	if len(events) > pageSize {
+		page.Events = events[:pageSize]
+		page.NextCursor = EncodeCursor(page.Events[pageSize-1].ID)
+	}
+	return page, nil
+}
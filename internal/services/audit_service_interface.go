@@ -0,0 +1,8 @@
+package services
+
+import "github.com/gregoryAlvim/gobank/internal/models"
+
+type AuditServiceInterface interface {
+	Record(event *models.AuditEvent) error
+	ListEvents(filter models.AuditFilter) (*models.AuditPage, error)
+}
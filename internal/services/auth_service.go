@@ -0,0 +1,153 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	"github.com/gregoryAlvim/gobank/internal/taxid"
+	"github.com/gregoryAlvim/gobank/internal/validation"
+)
+
+// maxUsernameLength matches the operators.username column.
+const maxUsernameLength = 64
+
+type AuthService struct {
+	repo       repositories.AuthRepository
+	tokens     *auth.TokenIssuer
+	refreshTTL time.Duration
+	now        func() time.Time
+}
+
+func NewAuthService(repo repositories.AuthRepository, tokens *auth.TokenIssuer, refreshTTL time.Duration) *AuthService {
+	return &AuthService{repo: repo, tokens: tokens, refreshTTL: refreshTTL, now: time.Now}
+}
+
+// Login checks the customer's password and starts a session. Unknown tax
+// IDs and wrong passwords both fail with auth.ErrInvalidCredentials.
+func (s *AuthService) Login(taxID, password string) (*models.TokenPair, error) {
+	customerID, hash, err := s.repo.GetCredentials(normalizeTaxID(taxID))
+	if err != nil && !errors.Is(err, repositories.ErrCustomerNotFound) {
+		return nil, err
+	}
+	// Compare even when the customer is unknown, so both cases take as long.
+	if !auth.CheckPassword(hash, password) {
+		return nil, auth.ErrInvalidCredentials
+	}
+
+	refreshToken, stored, err := s.newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	stored.CustomerID = customerID
+	if err := s.repo.CreateRefreshToken(stored); err != nil {
+		return nil, err
+	}
+	return s.tokenPair(customerID, refreshToken)
+}
+
+// Refresh trades a refresh token for a new access token and a new refresh
+// token. The old refresh token stops working.
+func (s *AuthService) Refresh(refreshToken string) (*models.TokenPair, error) {
+	next, stored, err := s.newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RotateRefreshToken(auth.HashRefreshToken(refreshToken), stored); err != nil {
+		return nil, err
+	}
+	return s.tokenPair(stored.CustomerID, next)
+}
+
+// Logout revokes the refresh token. Access tokens already issued stay
+// valid until they expire.
+func (s *AuthService) Logout(refreshToken string) error {
+	return s.repo.RevokeRefreshToken(auth.HashRefreshToken(refreshToken))
+}
+
+// OperatorLogin checks an operator's password and returns an access token
+// carrying its role. Operators get no refresh token.
+func (s *AuthService) OperatorLogin(username, password string) (*models.TokenPair, error) {
+	operatorID, role, hash, err := s.repo.GetOperatorCredentials(username)
+	if err != nil && !errors.Is(err, repositories.ErrOperatorNotFound) {
+		return nil, err
+	}
+	if !auth.CheckPassword(hash, password) {
+		return nil, auth.ErrInvalidCredentials
+	}
+
+	accessToken, _, err := s.tokens.IssueOperatorToken(operatorID, auth.Role(role))
+	if err != nil {
+		return nil, err
+	}
+	return &models.TokenPair{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(s.tokens.AccessTTL().Seconds()),
+	}, nil
+}
+
+// CreateOperator registers a back-office operator with one of
+// auth.OperatorRoles.
+func (s *AuthService) CreateOperator(username, role, password string) (*models.Operator, error) {
+	v := validation.New()
+	if v.Required("username", username) {
+		v.MaxLength("username", username, maxUsernameLength)
+	}
+	if v.Required("role", role) {
+		v.Check(auth.Role(role).IsOperator(), "role", validation.CodeNotAllowed, "must be teller, supervisor or auditor")
+	}
+	credentials := models.Credentials{Password: password}
+	credentials.Validate(v)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	operator := &models.Operator{Username: username, Role: role, PasswordHash: hash}
+	if err := s.repo.CreateOperator(operator); err != nil {
+		return nil, err
+	}
+	return operator, nil
+}
+
+func (s *AuthService) newRefreshToken() (string, *models.RefreshToken, error) {
+	token, err := auth.NewRefreshToken()
+	if err != nil {
+		return "", nil, err
+	}
+	return token, &models.RefreshToken{
+		TokenHash: auth.HashRefreshToken(token),
+		ExpiresAt: s.now().Add(s.refreshTTL),
+	}, nil
+}
+
+func (s *AuthService) tokenPair(customerID int, refreshToken string) (*models.TokenPair, error) {
+	accessToken, _, err := s.tokens.IssueAccessToken(customerID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TokenPair{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.tokens.AccessTTL().Seconds()),
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// normalizeTaxID strips the mask of a CPF or CNPJ. Anything else is
+// returned unchanged and simply matches no customer.
+func normalizeTaxID(taxID string) string {
+	if cpf, err := taxid.NormalizeCPF(taxID); err == nil {
+		return cpf
+	}
+	if cnpj, err := taxid.NormalizeCNPJ(taxID); err == nil {
+		return cnpj
+	}
+	return taxID
+}
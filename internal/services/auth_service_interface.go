@@ -0,0 +1,11 @@
+package services
+
+import "github.com/gregoryAlvim/gobank/internal/models"
+
+type AuthServiceInterface interface {
+	Login(taxID, password string) (*models.TokenPair, error)
+	Refresh(refreshToken string) (*models.TokenPair, error)
+	Logout(refreshToken string) error
+	OperatorLogin(username, password string) (*models.TokenPair, error)
+	CreateOperator(username, role, password string) (*models.Operator, error)
+}
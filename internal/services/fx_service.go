@@ -0,0 +1,106 @@
+package services
+
+import (
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/fx"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	"github.com/gregoryAlvim/gobank/internal/validation"
+)
+
+// DefaultQuoteTTL is how long a quote locks its rate when
+// FXService.QuoteTTL is zero.
+const DefaultQuoteTTL = time.Minute
+
+type FXService struct {
+	repo     repositories.FXRepository
+	accounts repositories.AccountRepository
+	// QuoteTTL is how long a quote can be used after it is made. Zero
+	// means DefaultQuoteTTL.
+	QuoteTTL time.Duration
+}
+
+func NewFXService(repo repositories.FXRepository, accounts repositories.AccountRepository) *FXService {
+	return &FXService{repo: repo, accounts: accounts}
+}
+
+func (s *FXService) ListRates() ([]models.FXRate, error) {
+	return s.repo.ListRates()
+}
+
+// SetRate sets the rate and spread of a currency pair. Transfers already
+// made and quotes already given keep their rate.
+func (s *FXService) SetRate(base, quote string, rate models.FXRate) (*models.FXRate, error) {
+	rate.Base, rate.Quote = base, quote
+	v := validation.New()
+	rate.Validate(v)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetRate(&rate); err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// LoadRates sets the rate of every entry, e.g. those read from a rates
+// file with fx.Load.
+func (s *FXService) LoadRates(entries []fx.Entry) error {
+	for _, e := range entries {
+		rate := models.FXRate{Rate: e.Rate, SpreadBps: e.SpreadBps}
+		if _, err := s.SetRate(e.Base, e.Quote, rate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Quote converts an amount of the account's currency at the current rate
+// and locks the result for QuoteTTL. Passing the quote's ID to a transfer
+// of the same amount out of the account applies it.
+func (s *FXService) Quote(accountID int, request models.FXQuoteRequest) (*models.FXQuote, error) {
+	v := validation.New()
+	request.Validate(v)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	account, err := s.accounts.GetAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.Currency == request.ToCurrency {
+		v.Add("to_currency", validation.CodeInvalid, "must differ from the account's currency")
+		return nil, v.Err()
+	}
+
+	rate, err := s.repo.GetRate(account.Currency, request.ToCurrency)
+	if err != nil {
+		return nil, err
+	}
+	conversion, err := rate.Convert(request.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := s.QuoteTTL
+	if ttl <= 0 {
+		ttl = DefaultQuoteTTL
+	}
+	quote := &models.FXQuote{
+		AccountID:       accountID,
+		FromCurrency:    conversion.FromCurrency,
+		ToCurrency:      conversion.ToCurrency,
+		Amount:          conversion.SourceAmount,
+		ConvertedAmount: conversion.ConvertedAmount,
+		MidRate:         conversion.MidRate,
+		SpreadBps:       conversion.SpreadBps,
+		Rate:            conversion.Rate,
+		ExpiresAt:       time.Now().Add(ttl),
+	}
+	if err := s.repo.CreateQuote(quote); err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
@@ -0,0 +1,11 @@
+package services
+
+import (
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+type FXServiceInterface interface {
+	ListRates() ([]models.FXRate, error)
+	SetRate(base, quote string, rate models.FXRate) (*models.FXRate, error)
+	Quote(accountID int, request models.FXQuoteRequest) (*models.FXQuote, error)
+}
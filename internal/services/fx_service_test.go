@@ -0,0 +1,67 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/gregoryAlvim/gobank/internal/fx"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	repomocks "github.com/gregoryAlvim/gobank/internal/repositories/mocks"
+	"github.com/gregoryAlvim/gobank/internal/validation"
+)
+
+func TestFXService_Quote(t *testing.T) {
+	repo := repomocks.NewFXRepository(t)
+	accounts := repomocks.NewAccountRepository(t)
+	service := NewFXService(repo, accounts)
+	service.QuoteTTL = 30 * time.Second
+
+	accounts.On("GetAccount", 10).Return(&models.Account{ID: 10, Currency: "USD"}, nil)
+	repo.On("GetRate", "USD", "BRL").Return(&models.FXRate{Base: "USD", Quote: "BRL", Rate: fx.MustParseRate("5.4321"), SpreadBps: 50}, nil)
+	repo.On("CreateQuote", mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.FXQuote).ID = 3
+	}).Return(nil)
+
+	before := time.Now()
+	quote, err := service.Quote(10, models.FXQuoteRequest{ToCurrency: "BRL", Amount: money.New(100, 0)})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), quote.ID)
+	assert.Equal(t, "USD", quote.FromCurrency)
+	assert.Equal(t, money.MustParse("540.49"), quote.ConvertedAmount)
+	assert.Equal(t, fx.MustParseRate("5.4321"), quote.MidRate)
+	assert.Equal(t, fx.MustParseRate("5.4049395"), quote.Rate)
+	assert.WithinDuration(t, before.Add(30*time.Second), quote.ExpiresAt, time.Second)
+
+	// A quote converts to another currency.
+	_, err = service.Quote(10, models.FXQuoteRequest{ToCurrency: "USD", Amount: money.New(100, 0)})
+	var fieldErrs validation.Errors
+	assert.ErrorAs(t, err, &fieldErrs)
+
+	accounts.On("GetAccount", 11).Return(&models.Account{ID: 11, Currency: "BRL"}, nil)
+	repo.On("GetRate", "BRL", "EUR").Return(nil, repositories.ErrFXRateNotFound)
+
+	_, err = service.Quote(11, models.FXQuoteRequest{ToCurrency: "EUR", Amount: money.New(100, 0)})
+	assert.ErrorIs(t, err, repositories.ErrFXRateNotFound)
+}
+
+func TestFXService_LoadRates(t *testing.T) {
+	repo := repomocks.NewFXRepository(t)
+	service := NewFXService(repo, nil)
+
+	repo.On("SetRate", &models.FXRate{Base: "USD", Quote: "BRL", Rate: fx.MustParseRate("5.4321"), SpreadBps: 50}).Return(nil)
+	repo.On("SetRate", &models.FXRate{Base: "BRL", Quote: "USD", Rate: fx.MustParseRate("0.1841")}).Return(nil)
+
+	assert.NoError(t, service.LoadRates([]fx.Entry{
+		{Base: "USD", Quote: "BRL", Rate: fx.MustParseRate("5.4321"), SpreadBps: 50},
+		{Base: "BRL", Quote: "USD", Rate: fx.MustParseRate("0.1841")},
+	}))
+
+	_, err := service.SetRate("USD", "XYZ", models.FXRate{Rate: fx.MustParseRate("1")})
+	var fieldErrs validation.Errors
+	assert.ErrorAs(t, err, &fieldErrs)
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	"github.com/gregoryAlvim/gobank/internal/validation"
+)
+
+type InterestService struct {
+	repo repositories.InterestRepository
+}
+
+func NewInterestService(repo repositories.InterestRepository) *InterestService {
+	return &InterestService{repo: repo}
+}
+
+func (s *InterestService) ListRates() ([]models.InterestRate, error) {
+	return s.repo.ListRates()
+}
+
+// SetRate sets the interest earned by every account in the category. A
+// category that earned nothing before starts earning today.
+func (s *InterestService) SetRate(category string, rate models.InterestRate) (*models.InterestRate, error) {
+	rate.Category = category
+	v := validation.New()
+	v.OneOf("category", rate.Category, models.AccountCategories...)
+	rate.Validate(v)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetRate(&rate); err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// SetCDIRate publishes the CDI for a day, replacing any rate already
+// published for it. Days already accrued are not recomputed.
+func (s *InterestService) SetCDIRate(rate models.CDIRate) (*models.CDIRate, error) {
+	v := validation.New()
+	rate.Validate(v)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetCDIRate(&rate); err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// Run accrues interest on every earning account for each day up to the
+// day before today, backfilling the days missed since the last run, and
+// credits what was accrued in the months before today's. Days already
+// accrued and accruals already credited are skipped, so Run can be called
+// any number of times. With dryRun nothing is written and the report says
+// what would be accrued and credited.
+//
+// A failure on one account is reported against it and the others are
+// still processed. A day with no CDI rate of its own uses the last one
+// published before it. An account on a CDI rate with none published on
+// or before a day is accrued up to the day before and picks up from there
+// on the next run.
+func (s *InterestService) Run(today time.Time, dryRun bool) (*models.InterestReport, error) {
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	through := today.AddDate(0, 0, -1)
+	payBefore := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	report := &models.InterestReport{DryRun: dryRun, AccruedThrough: through, Accounts: []models.InterestAccountReport{}}
+	index := make(map[int]int)
+	accountReport := func(accountID int) *models.InterestAccountReport {
+		i, ok := index[accountID]
+		if !ok {
+			i = len(report.Accounts)
+			index[accountID] = i
+			report.Accounts = append(report.Accounts, models.InterestAccountReport{AccountID: accountID})
+		}
+		return &report.Accounts[i]
+	}
+	fail := func(accountID int, err error) {
+		r := accountReport(accountID)
+		if r.Error == "" {
+			r.Error = err.Error()
+		}
+	}
+
+	accounts, err := s.repo.ListAccrualAccounts()
+	if err != nil {
+		return nil, err
+	}
+	// In a dry run the new accruals are not stored, so those owed in the
+	// payout are added to the unpaid ones.
+	owed := make(map[int]money.Precise)
+	for _, account := range accounts {
+		accruals, err := s.accruals(account, through)
+		if err != nil {
+			fail(account.AccountID, err)
+		}
+		for i := range accruals {
+			accrual := &accruals[i]
+			if !dryRun {
+				recorded, err := s.repo.RecordAccrual(accrual)
+				if err != nil {
+					fail(account.AccountID, err)
+					break
+				}
+				if !recorded {
+					continue
+				}
+			} else if accrual.Date.Before(payBefore) {
+				owed[account.AccountID] += accrual.Amount
+			}
+			r := accountReport(account.AccountID)
+			r.DaysAccrued++
+			r.Accrued += accrual.Amount
+		}
+	}
+
+	unpaid, err := s.repo.ListUnpaidInterest(payBefore)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		for _, u := range unpaid {
+			owed[u.AccountID] += u.Amount
+		}
+		for accountID, amount := range owed {
+			accountReport(accountID).Credited = amount.Round()
+		}
+		return report, nil
+	}
+	for _, u := range unpaid {
+		credited, err := s.repo.PayInterest(u.AccountID, payBefore)
+		if err != nil {
+			fail(u.AccountID, err)
+			continue
+		}
+		accountReport(u.AccountID).Credited = credited
+	}
+	return report, nil
+}
+
+// accruals computes the interest the account earned on each day after
+// the last one accrued, up to through. When a day cannot be computed the
+// accruals of the days before it are returned with the error.
+func (s *InterestService) accruals(account models.AccrualAccount, through time.Time) ([]models.InterestAccrual, error) {
+	day := account.Start
+	if account.LastAccrued != nil && !account.LastAccrued.Before(day) {
+		day = account.LastAccrued.AddDate(0, 0, 1)
+	}
+
+	var accruals []models.InterestAccrual
+	for ; !day.After(through); day = day.AddDate(0, 0, 1) {
+		balance, err := s.repo.GetEndOfDayBalance(account.AccountID, day)
+		if err != nil {
+			return accruals, err
+		}
+		accrual := models.InterestAccrual{AccountID: account.AccountID, Date: day, Balance: balance, Rate: account.Rate}
+
+		var cdi *models.CDIRate
+		if account.Rate.Type == models.RateTypeCDI {
+			if cdi, err = s.repo.GetCDIRate(day); err != nil {
+				return accruals, fmt.Errorf("%s: %w", day.Format(time.DateOnly), err)
+			}
+			accrual.CDIRateBps = &cdi.AnnualRateBps
+		}
+		if accrual.Amount, err = account.Rate.DailyInterest(balance, cdi); err != nil {
+			return accruals, err
+		}
+		accruals = append(accruals, accrual)
+	}
+	return accruals, nil
+}
@@ -0,0 +1,14 @@
+package services
+
+import (
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+type InterestServiceInterface interface {
+	ListRates() ([]models.InterestRate, error)
+	SetRate(category string, rate models.InterestRate) (*models.InterestRate, error)
+	SetCDIRate(rate models.CDIRate) (*models.CDIRate, error)
+	Run(today time.Time, dryRun bool) (*models.InterestReport, error)
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	repomocks "github.com/gregoryAlvim/gobank/internal/repositories/mocks"
+)
+
+func day(month time.Month, d int) time.Time {
+	return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+// setupInterestRun runs on April 2nd. Account 10 earns a fixed 36.5% a
+// year, 0.1% a day, and was last accrued on March 30th, so March 31st and
+// April 1st are backfilled. 29.996 of its interest is still unpaid.
+// Account 11 earns 100% of the CDI, which was
+// never published.
+func setupInterestRun(repo *repomocks.InterestRepository) {
+	lastAccrued := day(time.March, 30)
+	repo.On("ListAccrualAccounts").Return([]models.AccrualAccount{
+		{AccountID: 10, Rate: models.InterestRate{Type: models.RateTypeFixed, RateBps: 3650}, Start: day(time.March, 1), LastAccrued: &lastAccrued},
+		{AccountID: 11, Rate: models.InterestRate{Type: models.RateTypeCDI, RateBps: 10000}, Start: day(time.April, 1)},
+	}, nil)
+	repo.On("GetEndOfDayBalance", 10, day(time.March, 31)).Return(money.New(1000, 0), nil)
+	repo.On("GetEndOfDayBalance", 10, day(time.April, 1)).Return(money.New(2000, 0), nil)
+	repo.On("GetEndOfDayBalance", 11, day(time.April, 1)).Return(money.New(500, 0), nil)
+	repo.On("GetCDIRate", day(time.April, 1)).Return(nil, repositories.ErrCDIRateNotFound)
+	repo.On("ListUnpaidInterest", day(time.April, 1)).Return([]models.UnpaidInterest{{AccountID: 10, Amount: money.MustParsePrecise("29.996")}}, nil)
+}
+
+func TestInterestService_Run(t *testing.T) {
+	repo := repomocks.NewInterestRepository(t)
+	service := NewInterestService(repo)
+	setupInterestRun(repo)
+
+	repo.On("RecordAccrual", mock.MatchedBy(func(a *models.InterestAccrual) bool {
+		return a.AccountID == 10 && a.Date.Equal(day(time.March, 31)) && a.Amount == money.MustParsePrecise("1")
+	})).Return(true, nil)
+	// Already accrued by another run.
+	repo.On("RecordAccrual", mock.MatchedBy(func(a *models.InterestAccrual) bool {
+		return a.AccountID == 10 && a.Date.Equal(day(time.April, 1)) && a.Amount == money.MustParsePrecise("2")
+	})).Return(false, nil)
+	repo.On("PayInterest", 10, day(time.April, 1)).Return(money.New(30, 0), nil)
+
+	report, err := service.Run(time.Date(2026, time.April, 2, 15, 30, 0, 0, time.UTC), false)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.InterestReport{
+		AccruedThrough: day(time.April, 1),
+		Accounts: []models.InterestAccountReport{
+			{AccountID: 10, DaysAccrued: 1, Accrued: money.MustParsePrecise("1"), Credited: money.New(30, 0)},
+			{AccountID: 11, Error: "2026-04-01: " + repositories.ErrCDIRateNotFound.Error()},
+		},
+	}, report)
+}
+
+func TestInterestService_Run_DryRun(t *testing.T) {
+	repo := repomocks.NewInterestRepository(t)
+	service := NewInterestService(repo)
+	setupInterestRun(repo)
+
+	// Nothing is written. March 31st is owed in April's payout on top of
+	// what is already unpaid, and only the total is rounded; April 1st is
+	// not owed yet.
+	report, err := service.Run(time.Date(2026, time.April, 2, 15, 30, 0, 0, time.UTC), true)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.InterestReport{
+		DryRun:         true,
+		AccruedThrough: day(time.April, 1),
+		Accounts: []models.InterestAccountReport{
+			{AccountID: 10, DaysAccrued: 2, Accrued: money.MustParsePrecise("3"), Credited: money.New(31, 0)},
+			{AccountID: 11, Error: "2026-04-01: " + repositories.ErrCDIRateNotFound.Error()},
+		},
+	}, report)
+	repo.AssertNotCalled(t, "RecordAccrual", mock.Anything)
+	repo.AssertNotCalled(t, "PayInterest", mock.Anything, mock.Anything)
+}
+
+func TestInterestService_Run_CDI(t *testing.T) {
+	repo := repomocks.NewInterestRepository(t)
+	service := NewInterestService(repo)
+
+	// 110% of a 14.60% CDI on 10000.01 is 4.4000044 a day, accrued
+	// without rounding.
+	repo.On("ListAccrualAccounts").Return([]models.AccrualAccount{
+		{AccountID: 12, Rate: models.InterestRate{Type: models.RateTypeCDI, RateBps: 11000}, Start: day(time.May, 10)},
+	}, nil)
+	repo.On("GetEndOfDayBalance", 12, day(time.May, 10)).Return(money.MustParse("10000.01"), nil)
+	repo.On("GetCDIRate", day(time.May, 10)).Return(&models.CDIRate{Date: day(time.May, 8), AnnualRateBps: 1460}, nil)
+	repo.On("RecordAccrual", mock.MatchedBy(func(a *models.InterestAccrual) bool {
+		return a.Amount == money.MustParsePrecise("4.4000044") && a.CDIRateBps != nil && *a.CDIRateBps == 1460
+	})).Return(true, nil)
+	repo.On("ListUnpaidInterest", day(time.May, 1)).Return([]models.UnpaidInterest{}, nil)
+
+	report, err := service.Run(day(time.May, 11), false)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.InterestAccountReport{{AccountID: 12, DaysAccrued: 1, Accrued: money.MustParsePrecise("4.4000044")}}, report.Accounts)
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+)
+
+const (
+	DefaultPostingPageSize = 50
+	MaxPostingPageSize     = 200
+)
+
+type LedgerService struct {
+	repo repositories.LedgerRepository
+}
+
+func NewLedgerService(repo repositories.LedgerRepository) *LedgerService {
+	return &LedgerService{repo: repo}
+}
+
+// Reconcile checks the stored balance of an account against the balance
+// derived from its journal postings, and returns one page of the postings
+// with it.
+func (s *LedgerService) Reconcile(accountID int, filter models.PostingFilter) (*models.Reconciliation, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultPostingPageSize
+	}
+	if filter.Limit > MaxPostingPageSize {
+		filter.Limit = MaxPostingPageSize
+	}
+	pageSize := filter.Limit
+
+	reconciliation, err := s.repo.GetReconciliation(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra row to find out whether there is a next page.
+	filter.Limit++
+	postings, err := s.repo.GetPostings(accountID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	reconciliation.Postings = postings
+	if len(postings) > pageSize {
+		reconciliation.Postings = postings[:pageSize]
+		reconciliation.NextCursor = EncodeCursor(postings[pageSize-1].ID)
+	}
+	return reconciliation, nil
+}
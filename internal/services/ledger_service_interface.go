@@ -0,0 +1,7 @@
+package services
+
+import "github.com/gregoryAlvim/gobank/internal/models"
+
+type LedgerServiceInterface interface {
+	Reconcile(accountID int, filter models.PostingFilter) (*models.Reconciliation, error)
+}
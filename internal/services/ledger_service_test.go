@@ -0,0 +1,39 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	repomocks "github.com/gregoryAlvim/gobank/internal/repositories/mocks"
+)
+
+func TestLedgerService_Reconcile(t *testing.T) {
+	repo := repomocks.NewLedgerRepository(t)
+	service := NewLedgerService(repo)
+
+	repo.On("GetReconciliation", 10).Return(func(int) (*models.Reconciliation, error) {
+		return &models.Reconciliation{
+			AccountID: 10, Balance: money.New(70, 0), LedgerBalance: money.New(70, 0), Balanced: true, Postings: []models.Posting{},
+		}, nil
+	})
+	// One extra posting is asked for to find out whether there is a next
+	// page.
+	repo.On("GetPostings", 10, models.PostingFilter{BeforeID: 9, Limit: 3}).Return([]models.Posting{
+		{ID: 8, AccountID: 10}, {ID: 6, AccountID: 10}, {ID: 3, AccountID: 10},
+	}, nil)
+
+	reconciliation, err := service.Reconcile(10, models.PostingFilter{BeforeID: 9, Limit: 2})
+	assert.NoError(t, err)
+	assert.True(t, reconciliation.Balanced)
+	assert.Equal(t, []models.Posting{{ID: 8, AccountID: 10}, {ID: 6, AccountID: 10}}, reconciliation.Postings)
+	assert.Equal(t, EncodeCursor(6), reconciliation.NextCursor)
+
+	// The page size is capped.
+	repo.On("GetPostings", 10, models.PostingFilter{Limit: MaxPostingPageSize + 1}).Return([]models.Posting{}, nil)
+	reconciliation, err = service.Reconcile(10, models.PostingFilter{Limit: 1000})
+	assert.NoError(t, err)
+	assert.Empty(t, reconciliation.NextCursor)
+}
@@ -2,24 +2,152 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	accountnumber "github.com/gregoryAlvim/gobank/internal/accountnumber"
+	auth "github.com/gregoryAlvim/gobank/internal/auth"
+	models "github.com/gregoryAlvim/gobank/internal/models"
+	money "github.com/gregoryAlvim/gobank/internal/money"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
 
 // AccountServiceInterface is an autogenerated mock type for the AccountServiceInterface type
 type AccountServiceInterface struct {
 	mock.Mock
 }
 
-// CloseAccount provides a mock function with given fields: accountID, accountType
-func (_m *AccountServiceInterface) CloseAccount(accountID int, accountType string) error {
-	ret := _m.Called(accountID, accountType)
+// ActivateAccount provides a mock function with given fields: actor, accountID, request
+func (_m *AccountServiceInterface) ActivateAccount(actor *auth.Principal, accountID int, request models.StatusChangeRequest) (*models.AccountStatusChange, error) {
+	ret := _m.Called(actor, accountID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ActivateAccount")
+	}
+
+	var r0 *models.AccountStatusChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*auth.Principal, int, models.StatusChangeRequest) (*models.AccountStatusChange, error)); ok {
+		return rf(actor, accountID, request)
+	}
+	if rf, ok := ret.Get(0).(func(*auth.Principal, int, models.StatusChangeRequest) *models.AccountStatusChange); ok {
+		r0 = rf(actor, accountID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AccountStatusChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*auth.Principal, int, models.StatusChangeRequest) error); ok {
+		r1 = rf(actor, accountID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ApproveTransfer provides a mock function with given fields: approvalID, reviewer, request
+func (_m *AccountServiceInterface) ApproveTransfer(approvalID int64, reviewer *auth.Principal, request models.ReviewRequest) (*models.TransferApproval, error) {
+	ret := _m.Called(approvalID, reviewer, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApproveTransfer")
+	}
+
+	var r0 *models.TransferApproval
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, *auth.Principal, models.ReviewRequest) (*models.TransferApproval, error)); ok {
+		return rf(approvalID, reviewer, request)
+	}
+	if rf, ok := ret.Get(0).(func(int64, *auth.Principal, models.ReviewRequest) *models.TransferApproval); ok {
+		r0 = rf(approvalID, reviewer, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TransferApproval)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, *auth.Principal, models.ReviewRequest) error); ok {
+		r1 = rf(approvalID, reviewer, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CaptureHold provides a mock function with given fields: actor, accountID, holdID, request
+func (_m *AccountServiceInterface) CaptureHold(actor *auth.Principal, accountID int, holdID int64, request models.CaptureRequest) (*models.Hold, error) {
+	ret := _m.Called(actor, accountID, holdID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CaptureHold")
+	}
+
+	var r0 *models.Hold
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*auth.Principal, int, int64, models.CaptureRequest) (*models.Hold, error)); ok {
+		return rf(actor, accountID, holdID, request)
+	}
+	if rf, ok := ret.Get(0).(func(*auth.Principal, int, int64, models.CaptureRequest) *models.Hold); ok {
+		r0 = rf(actor, accountID, holdID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Hold)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*auth.Principal, int, int64, models.CaptureRequest) error); ok {
+		r1 = rf(actor, accountID, holdID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CloseAccount provides a mock function with given fields: actor, accountID, request
+func (_m *AccountServiceInterface) CloseAccount(actor *auth.Principal, accountID int, request models.CloseAccountRequest) (*models.AccountStatusChange, error) {
+	ret := _m.Called(actor, accountID, request)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CloseAccount")
 	}
 
+	var r0 *models.AccountStatusChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*auth.Principal, int, models.CloseAccountRequest) (*models.AccountStatusChange, error)); ok {
+		return rf(actor, accountID, request)
+	}
+	if rf, ok := ret.Get(0).(func(*auth.Principal, int, models.CloseAccountRequest) *models.AccountStatusChange); ok {
+		r0 = rf(actor, accountID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AccountStatusChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*auth.Principal, int, models.CloseAccountRequest) error); ok {
+		r1 = rf(actor, accountID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CorrectBalance provides a mock function with given fields: actor, accountID, request
+func (_m *AccountServiceInterface) CorrectBalance(actor *auth.Principal, accountID int, request models.BalanceCorrectionRequest) error {
+	ret := _m.Called(actor, accountID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CorrectBalance")
+	}
+
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int, string) error); ok {
-		r0 = rf(accountID, accountType)
+	if rf, ok := ret.Get(0).(func(*auth.Principal, int, models.BalanceCorrectionRequest) error); ok {
+		r0 = rf(actor, accountID, request)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -27,17 +155,47 @@ func (_m *AccountServiceInterface) CloseAccount(accountID int, accountType strin
 	return r0
 }
 
-// CreateAccount provides a mock function with given fields: accountType, data
-func (_m *AccountServiceInterface) CreateAccount(accountType string, data []byte) error {
-	ret := _m.Called(accountType, data)
+// CreateAccount provides a mock function with given fields: customerType, data, requestID
+func (_m *AccountServiceInterface) CreateAccount(customerType string, data []byte, requestID string) (*models.Account, error) {
+	ret := _m.Called(customerType, data, requestID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateAccount")
 	}
 
+	var r0 *models.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, []byte, string) (*models.Account, error)); ok {
+		return rf(customerType, data, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(string, []byte, string) *models.Account); ok {
+		r0 = rf(customerType, data, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, []byte, string) error); ok {
+		r1 = rf(customerType, data, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Deposit provides a mock function with given fields: actor, accountID, amount, currency
+func (_m *AccountServiceInterface) Deposit(actor *auth.Principal, accountID int, amount money.Money, currency string) error {
+	ret := _m.Called(actor, accountID, amount, currency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Deposit")
+	}
+
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, []byte) error); ok {
-		r0 = rf(accountType, data)
+	if rf, ok := ret.Get(0).(func(*auth.Principal, int, money.Money, string) error); ok {
+		r0 = rf(actor, accountID, amount, currency)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -45,17 +203,527 @@ func (_m *AccountServiceInterface) CreateAccount(accountType string, data []byte
 	return r0
 }
 
-// Deposit provides a mock function with given fields: accountID, amount, accountType
-func (_m *AccountServiceInterface) Deposit(accountID int, amount float64, accountType string) error {
-	ret := _m.Called(accountID, amount, accountType)
+// FreezeAccount provides a mock function with given fields: actor, accountID, request
+func (_m *AccountServiceInterface) FreezeAccount(actor *auth.Principal, accountID int, request models.StatusChangeRequest) (*models.AccountStatusChange, error) {
+	ret := _m.Called(actor, accountID, request)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Deposit")
+		panic("no return value specified for FreezeAccount")
+	}
+
+	var r0 *models.AccountStatusChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*auth.Principal, int, models.StatusChangeRequest) (*models.AccountStatusChange, error)); ok {
+		return rf(actor, accountID, request)
+	}
+	if rf, ok := ret.Get(0).(func(*auth.Principal, int, models.StatusChangeRequest) *models.AccountStatusChange); ok {
+		r0 = rf(actor, accountID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AccountStatusChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*auth.Principal, int, models.StatusChangeRequest) error); ok {
+		r1 = rf(actor, accountID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccount provides a mock function with given fields: accountID
+func (_m *AccountServiceInterface) GetAccount(accountID int) (*models.Account, error) {
+	ret := _m.Called(accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccount")
+	}
+
+	var r0 *models.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (*models.Account, error)); ok {
+		return rf(accountID)
+	}
+	if rf, ok := ret.Get(0).(func(int) *models.Account); ok {
+		r0 = rf(accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccountByNumber provides a mock function with given fields: number
+func (_m *AccountServiceInterface) GetAccountByNumber(number accountnumber.Number) (*models.Account, error) {
+	ret := _m.Called(number)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccountByNumber")
+	}
+
+	var r0 *models.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(accountnumber.Number) (*models.Account, error)); ok {
+		return rf(number)
+	}
+	if rf, ok := ret.Get(0).(func(accountnumber.Number) *models.Account); ok {
+		r0 = rf(number)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(accountnumber.Number) error); ok {
+		r1 = rf(number)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBalance provides a mock function with given fields: accountID
+func (_m *AccountServiceInterface) GetBalance(accountID int) (*models.Balance, error) {
+	ret := _m.Called(accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalance")
+	}
+
+	var r0 *models.Balance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (*models.Balance, error)); ok {
+		return rf(accountID)
+	}
+	if rf, ok := ret.Get(0).(func(int) *models.Balance); ok {
+		r0 = rf(accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Balance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBalanceAsOf provides a mock function with given fields: accountID, asOf
+func (_m *AccountServiceInterface) GetBalanceAsOf(accountID int, asOf time.Time) (*models.BalanceAsOf, error) {
+	ret := _m.Called(accountID, asOf)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalanceAsOf")
+	}
+
+	var r0 *models.BalanceAsOf
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, time.Time) (*models.BalanceAsOf, error)); ok {
+		return rf(accountID, asOf)
+	}
+	if rf, ok := ret.Get(0).(func(int, time.Time) *models.BalanceAsOf); ok {
+		r0 = rf(accountID, asOf)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.BalanceAsOf)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, time.Time) error); ok {
+		r1 = rf(accountID, asOf)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCustomerAccounts provides a mock function with given fields: customerID
+func (_m *AccountServiceInterface) GetCustomerAccounts(customerID int) ([]models.Account, error) {
+	ret := _m.Called(customerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCustomerAccounts")
+	}
+
+	var r0 []models.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]models.Account, error)); ok {
+		return rf(customerID)
+	}
+	if rf, ok := ret.Get(0).(func(int) []models.Account); ok {
+		r0 = rf(customerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(customerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOverdraft provides a mock function with given fields: accountID
+func (_m *AccountServiceInterface) GetOverdraft(accountID int) (*models.Overdraft, error) {
+	ret := _m.Called(accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOverdraft")
+	}
+
+	var r0 *models.Overdraft
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (*models.Overdraft, error)); ok {
+		return rf(accountID)
+	}
+	if rf, ok := ret.Get(0).(func(int) *models.Overdraft); ok {
+		r0 = rf(accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Overdraft)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransactions provides a mock function with given fields: accountID, filter
+func (_m *AccountServiceInterface) GetTransactions(accountID int, filter models.TransactionFilter) (*models.TransactionPage, error) {
+	ret := _m.Called(accountID, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactions")
+	}
+
+	var r0 *models.TransactionPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, models.TransactionFilter) (*models.TransactionPage, error)); ok {
+		return rf(accountID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(int, models.TransactionFilter) *models.TransactionPage); ok {
+		r0 = rf(accountID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TransactionPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, models.TransactionFilter) error); ok {
+		r1 = rf(accountID, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListFeeWaivers provides a mock function with given fields: accountID
+func (_m *AccountServiceInterface) ListFeeWaivers(accountID int) ([]models.FeeWaiver, error) {
+	ret := _m.Called(accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFeeWaivers")
+	}
+
+	var r0 []models.FeeWaiver
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]models.FeeWaiver, error)); ok {
+		return rf(accountID)
+	}
+	if rf, ok := ret.Get(0).(func(int) []models.FeeWaiver); ok {
+		r0 = rf(accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.FeeWaiver)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListFees provides a mock function with given fields:
+func (_m *AccountServiceInterface) ListFees() ([]models.Fee, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFees")
+	}
+
+	var r0 []models.Fee
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]models.Fee, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []models.Fee); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Fee)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListHolds provides a mock function with given fields: accountID, filter
+func (_m *AccountServiceInterface) ListHolds(accountID int, filter models.HoldFilter) (*models.HoldPage, error) {
+	ret := _m.Called(accountID, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListHolds")
+	}
+
+	var r0 *models.HoldPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, models.HoldFilter) (*models.HoldPage, error)); ok {
+		return rf(accountID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(int, models.HoldFilter) *models.HoldPage); ok {
+		r0 = rf(accountID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.HoldPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, models.HoldFilter) error); ok {
+		r1 = rf(accountID, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListOverdraftPolicies provides a mock function with given fields:
+func (_m *AccountServiceInterface) ListOverdraftPolicies() ([]models.OverdraftPolicy, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOverdraftPolicies")
+	}
+
+	var r0 []models.OverdraftPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]models.OverdraftPolicy, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []models.OverdraftPolicy); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.OverdraftPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListStatusChanges provides a mock function with given fields: accountID
+func (_m *AccountServiceInterface) ListStatusChanges(accountID int) ([]models.AccountStatusChange, error) {
+	ret := _m.Called(accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListStatusChanges")
+	}
+
+	var r0 []models.AccountStatusChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]models.AccountStatusChange, error)); ok {
+		return rf(accountID)
+	}
+	if rf, ok := ret.Get(0).(func(int) []models.AccountStatusChange); ok {
+		r0 = rf(accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AccountStatusChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTransferApprovals provides a mock function with given fields: filter
+func (_m *AccountServiceInterface) ListTransferApprovals(filter models.ApprovalFilter) (*models.ApprovalPage, error) {
+	ret := _m.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTransferApprovals")
+	}
+
+	var r0 *models.ApprovalPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(models.ApprovalFilter) (*models.ApprovalPage, error)); ok {
+		return rf(filter)
+	}
+	if rf, ok := ret.Get(0).(func(models.ApprovalFilter) *models.ApprovalPage); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ApprovalPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(models.ApprovalFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OpenAccount provides a mock function with given fields: actor, customerID, request
+func (_m *AccountServiceInterface) OpenAccount(actor *auth.Principal, customerID int, request models.OpenAccountRequest) (*models.Account, error) {
+	ret := _m.Called(actor, customerID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenAccount")
+	}
+
+	var r0 *models.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*auth.Principal, int, models.OpenAccountRequest) (*models.Account, error)); ok {
+		return rf(actor, customerID, request)
+	}
+	if rf, ok := ret.Get(0).(func(*auth.Principal, int, models.OpenAccountRequest) *models.Account); ok {
+		r0 = rf(actor, customerID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*auth.Principal, int, models.OpenAccountRequest) error); ok {
+		r1 = rf(actor, customerID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PlaceHold provides a mock function with given fields: accountID, request
+func (_m *AccountServiceInterface) PlaceHold(accountID int, request models.HoldRequest) (*models.Hold, error) {
+	ret := _m.Called(accountID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PlaceHold")
+	}
+
+	var r0 *models.Hold
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, models.HoldRequest) (*models.Hold, error)); ok {
+		return rf(accountID, request)
+	}
+	if rf, ok := ret.Get(0).(func(int, models.HoldRequest) *models.Hold); ok {
+		r0 = rf(accountID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Hold)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, models.HoldRequest) error); ok {
+		r1 = rf(accountID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RejectTransfer provides a mock function with given fields: approvalID, reviewer, request
+func (_m *AccountServiceInterface) RejectTransfer(approvalID int64, reviewer *auth.Principal, request models.ReviewRequest) (*models.TransferApproval, error) {
+	ret := _m.Called(approvalID, reviewer, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RejectTransfer")
+	}
+
+	var r0 *models.TransferApproval
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, *auth.Principal, models.ReviewRequest) (*models.TransferApproval, error)); ok {
+		return rf(approvalID, reviewer, request)
+	}
+	if rf, ok := ret.Get(0).(func(int64, *auth.Principal, models.ReviewRequest) *models.TransferApproval); ok {
+		r0 = rf(approvalID, reviewer, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TransferApproval)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, *auth.Principal, models.ReviewRequest) error); ok {
+		r1 = rf(approvalID, reviewer, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveFeeWaiver provides a mock function with given fields: accountID, feeType
+func (_m *AccountServiceInterface) RemoveFeeWaiver(accountID int, feeType string) error {
+	ret := _m.Called(accountID, feeType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveFeeWaiver")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int, float64, string) error); ok {
-		r0 = rf(accountID, amount, accountType)
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(accountID, feeType)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -63,27 +731,29 @@ func (_m *AccountServiceInterface) Deposit(accountID int, amount float64, accoun
 	return r0
 }
 
-// GetBalance provides a mock function with given fields: accountID, accountType
-func (_m *AccountServiceInterface) GetBalance(accountID int, accountType string) (float64, error) {
-	ret := _m.Called(accountID, accountType)
+// SetFee provides a mock function with given fields: fee
+func (_m *AccountServiceInterface) SetFee(fee models.Fee) (*models.Fee, error) {
+	ret := _m.Called(fee)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetBalance")
+		panic("no return value specified for SetFee")
 	}
 
-	var r0 float64
+	var r0 *models.Fee
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, string) (float64, error)); ok {
-		return rf(accountID, accountType)
+	if rf, ok := ret.Get(0).(func(models.Fee) (*models.Fee, error)); ok {
+		return rf(fee)
 	}
-	if rf, ok := ret.Get(0).(func(int, string) float64); ok {
-		r0 = rf(accountID, accountType)
+	if rf, ok := ret.Get(0).(func(models.Fee) *models.Fee); ok {
+		r0 = rf(fee)
 	} else {
-		r0 = ret.Get(0).(float64)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Fee)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int, string) error); ok {
-		r1 = rf(accountID, accountType)
+	if rf, ok := ret.Get(1).(func(models.Fee) error); ok {
+		r1 = rf(fee)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -91,17 +761,17 @@ func (_m *AccountServiceInterface) GetBalance(accountID int, accountType string)
 	return r0, r1
 }
 
-// Transfer provides a mock function with given fields: fromID, toID, amount, fromType, toType
-func (_m *AccountServiceInterface) Transfer(fromID int, toID int, amount float64, fromType string, toType string) error {
-	ret := _m.Called(fromID, toID, amount, fromType, toType)
+// SetOverdraftLimit provides a mock function with given fields: accountID, request
+func (_m *AccountServiceInterface) SetOverdraftLimit(accountID int, request models.OverdraftLimitRequest) error {
+	ret := _m.Called(accountID, request)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Transfer")
+		panic("no return value specified for SetOverdraftLimit")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int, int, float64, string, string) error); ok {
-		r0 = rf(fromID, toID, amount, fromType, toType)
+	if rf, ok := ret.Get(0).(func(int, models.OverdraftLimitRequest) error); ok {
+		r0 = rf(accountID, request)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -109,17 +779,197 @@ func (_m *AccountServiceInterface) Transfer(fromID int, toID int, amount float64
 	return r0
 }
 
-// Withdraw provides a mock function with given fields: accountID, amount, accountType
-func (_m *AccountServiceInterface) Withdraw(accountID int, amount float64, accountType string) error {
-	ret := _m.Called(accountID, amount, accountType)
+// SetOverdraftPolicy provides a mock function with given fields: category, policy
+func (_m *AccountServiceInterface) SetOverdraftPolicy(category string, policy models.OverdraftPolicy) (*models.OverdraftPolicy, error) {
+	ret := _m.Called(category, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetOverdraftPolicy")
+	}
+
+	var r0 *models.OverdraftPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, models.OverdraftPolicy) (*models.OverdraftPolicy, error)); ok {
+		return rf(category, policy)
+	}
+	if rf, ok := ret.Get(0).(func(string, models.OverdraftPolicy) *models.OverdraftPolicy); ok {
+		r0 = rf(category, policy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.OverdraftPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, models.OverdraftPolicy) error); ok {
+		r1 = rf(category, policy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Transfer provides a mock function with given fields: requester, fromID, toID, amount, quoteID
+func (_m *AccountServiceInterface) Transfer(requester *auth.Principal, fromID int, toID int, amount money.Money, quoteID int64) (*models.TransferApproval, error) {
+	ret := _m.Called(requester, fromID, toID, amount, quoteID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Transfer")
+	}
+
+	var r0 *models.TransferApproval
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*auth.Principal, int, int, money.Money, int64) (*models.TransferApproval, error)); ok {
+		return rf(requester, fromID, toID, amount, quoteID)
+	}
+	if rf, ok := ret.Get(0).(func(*auth.Principal, int, int, money.Money, int64) *models.TransferApproval); ok {
+		r0 = rf(requester, fromID, toID, amount, quoteID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TransferApproval)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*auth.Principal, int, int, money.Money, int64) error); ok {
+		r1 = rf(requester, fromID, toID, amount, quoteID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransferOnce provides a mock function with given fields: requester, key, fromID, toID, amount
+func (_m *AccountServiceInterface) TransferOnce(requester *auth.Principal, key string, fromID int, toID int, amount money.Money) (*models.TransferApproval, error) {
+	ret := _m.Called(requester, key, fromID, toID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransferOnce")
+	}
+
+	var r0 *models.TransferApproval
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*auth.Principal, string, int, int, money.Money) (*models.TransferApproval, error)); ok {
+		return rf(requester, key, fromID, toID, amount)
+	}
+	if rf, ok := ret.Get(0).(func(*auth.Principal, string, int, int, money.Money) *models.TransferApproval); ok {
+		r0 = rf(requester, key, fromID, toID, amount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TransferApproval)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*auth.Principal, string, int, int, money.Money) error); ok {
+		r1 = rf(requester, key, fromID, toID, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnfreezeAccount provides a mock function with given fields: actor, accountID, request
+func (_m *AccountServiceInterface) UnfreezeAccount(actor *auth.Principal, accountID int, request models.StatusChangeRequest) (*models.AccountStatusChange, error) {
+	ret := _m.Called(actor, accountID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnfreezeAccount")
+	}
+
+	var r0 *models.AccountStatusChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*auth.Principal, int, models.StatusChangeRequest) (*models.AccountStatusChange, error)); ok {
+		return rf(actor, accountID, request)
+	}
+	if rf, ok := ret.Get(0).(func(*auth.Principal, int, models.StatusChangeRequest) *models.AccountStatusChange); ok {
+		r0 = rf(actor, accountID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AccountStatusChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*auth.Principal, int, models.StatusChangeRequest) error); ok {
+		r1 = rf(actor, accountID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VoidHold provides a mock function with given fields: accountID, holdID
+func (_m *AccountServiceInterface) VoidHold(accountID int, holdID int64) (*models.Hold, error) {
+	ret := _m.Called(accountID, holdID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VoidHold")
+	}
+
+	var r0 *models.Hold
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int64) (*models.Hold, error)); ok {
+		return rf(accountID, holdID)
+	}
+	if rf, ok := ret.Get(0).(func(int, int64) *models.Hold); ok {
+		r0 = rf(accountID, holdID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Hold)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int64) error); ok {
+		r1 = rf(accountID, holdID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WaiveFee provides a mock function with given fields: accountID, feeType, request
+func (_m *AccountServiceInterface) WaiveFee(accountID int, feeType string, request models.FeeWaiverRequest) (*models.FeeWaiver, error) {
+	ret := _m.Called(accountID, feeType, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WaiveFee")
+	}
+
+	var r0 *models.FeeWaiver
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, string, models.FeeWaiverRequest) (*models.FeeWaiver, error)); ok {
+		return rf(accountID, feeType, request)
+	}
+	if rf, ok := ret.Get(0).(func(int, string, models.FeeWaiverRequest) *models.FeeWaiver); ok {
+		r0 = rf(accountID, feeType, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.FeeWaiver)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, string, models.FeeWaiverRequest) error); ok {
+		r1 = rf(accountID, feeType, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Withdraw provides a mock function with given fields: actor, accountID, amount, currency
+func (_m *AccountServiceInterface) Withdraw(actor *auth.Principal, accountID int, amount money.Money, currency string) error {
+	ret := _m.Called(actor, accountID, amount, currency)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Withdraw")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int, float64, string) error); ok {
-		r0 = rf(accountID, amount, accountType)
+	if rf, ok := ret.Get(0).(func(*auth.Principal, int, money.Money, string) error); ok {
+		r0 = rf(actor, accountID, amount, currency)
 	} else {
 		r0 = ret.Error(0)
 	}
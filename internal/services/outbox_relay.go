@@ -0,0 +1,94 @@
+package services
+
+import (
+	"github.com/gregoryAlvim/gobank/internal/events"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+)
+
+// DefaultRelayBatchSize is how many events the relay reads at a time when
+// OutboxRelay.BatchSize is zero.
+const DefaultRelayBatchSize = 100
+
+// OutboxRelay publishes the events written to the outbox.
+type OutboxRelay struct {
+	repo      repositories.OutboxRepository
+	publisher events.Publisher
+	BatchSize int
+}
+
+func NewOutboxRelay(repo repositories.OutboxRepository, publisher events.Publisher) *OutboxRelay {
+	return &OutboxRelay{repo: repo, publisher: publisher}
+}
+
+// RelayReport is the outcome of a relay run.
+type RelayReport struct {
+	Published int
+	Failed    int
+}
+
+// Relay publishes the pending events, oldest first, and marks them
+// published. An event that fails stays pending, and so do the later events
+// of its accounts, which would otherwise be published out of order; the
+// events of other accounts go on. An event is marked published only after
+// the publisher took it, so a crash in between publishes it again. Relay
+// does nothing while another relay is running.
+func (s *OutboxRelay) Relay() (RelayReport, error) {
+	var report RelayReport
+	unlock, ok, err := s.repo.LockRelay()
+	if err != nil || !ok {
+		return report, err
+	}
+	defer unlock()
+
+	limit := s.BatchSize
+	if limit <= 0 {
+		limit = DefaultRelayBatchSize
+	}
+	for {
+		pending, err := s.repo.ListPending(limit)
+		if err != nil {
+			return report, err
+		}
+		failed := report.Failed
+		if err := s.publish(pending, &report); err != nil {
+			return report, err
+		}
+		// After a failure the next batch would start with the events
+		// held back, so they wait for the next run.
+		if len(pending) < limit || report.Failed > failed {
+			return report, nil
+		}
+	}
+}
+
+func (s *OutboxRelay) publish(pending []models.Event, report *RelayReport) error {
+	held := make(map[int]bool)
+	for _, event := range pending {
+		accounts := event.AccountIDs()
+		blocked := false
+		for _, id := range accounts {
+			blocked = blocked || held[id]
+		}
+
+		if !blocked {
+			err := s.publisher.Publish(event)
+			if err == nil {
+				if err := s.repo.MarkPublished(event.Sequence); err != nil {
+					return err
+				}
+				report.Published++
+				continue
+			}
+			report.Failed++
+			if err := s.repo.MarkFailed(event.Sequence, err.Error()); err != nil {
+				return err
+			}
+		}
+		// A transfer held back holds back both of its accounts.
+		for _, id := range accounts {
+			held[id] = true
+		}
+	}
+	return nil
+}
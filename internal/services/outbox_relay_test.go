@@ -0,0 +1,73 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/events"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	repomocks "github.com/gregoryAlvim/gobank/internal/repositories/mocks"
+)
+
+func TestOutboxRelay_Relay(t *testing.T) {
+	repo := repomocks.NewOutboxRepository(t)
+	publisher := events.NewInProcess()
+	relay := NewOutboxRelay(repo, publisher)
+
+	var published []int64
+	publisher.Subscribe(func(e models.Event) error {
+		if e.Sequence == 2 {
+			return errors.New("consumer down")
+		}
+		published = append(published, e.Sequence)
+		return nil
+	})
+
+	unlocked := false
+	repo.On("LockRelay").Return(func() { unlocked = true }, true, nil)
+	repo.On("ListPending", DefaultRelayBatchSize).Return([]models.Event{
+		{Sequence: 1, AccountID: 10},
+		{Sequence: 2, AccountID: 11},
+		{Sequence: 3, AccountID: 11},
+		{Sequence: 4, AccountID: 12, CounterpartyID: 11},
+		{Sequence: 5, AccountID: 12},
+		{Sequence: 6, AccountID: 13},
+	}, nil).Once()
+	repo.On("MarkPublished", int64(1)).Return(nil)
+	repo.On("MarkFailed", int64(2), "consumer down").Return(nil)
+	repo.On("MarkPublished", int64(6)).Return(nil)
+
+	// The events after the failure on account 11 wait, and so do those of
+	// account 12, which a transfer to 11 ties to it.
+	report, err := relay.Relay()
+	assert.NoError(t, err)
+	assert.Equal(t, RelayReport{Published: 2, Failed: 1}, report)
+	assert.Equal(t, []int64{1, 6}, published)
+	assert.True(t, unlocked)
+}
+
+func TestOutboxRelay_Relay_Batches(t *testing.T) {
+	repo := repomocks.NewOutboxRepository(t)
+	relay := NewOutboxRelay(repo, events.NewInProcess())
+	relay.BatchSize = 2
+
+	repo.On("LockRelay").Return(func() {}, true, nil).Once()
+	repo.On("ListPending", 2).Return([]models.Event{{Sequence: 1, AccountID: 10}, {Sequence: 2, AccountID: 10}}, nil).Once()
+	repo.On("ListPending", 2).Return([]models.Event{{Sequence: 3, AccountID: 10}}, nil).Once()
+	for seq := int64(1); seq <= 3; seq++ {
+		repo.On("MarkPublished", seq).Return(nil)
+	}
+
+	report, err := relay.Relay()
+	assert.NoError(t, err)
+	assert.Equal(t, RelayReport{Published: 3}, report)
+
+	// Another relay is running.
+	repo.On("LockRelay").Return(nil, false, nil).Once()
+
+	report, err = relay.Relay()
+	assert.NoError(t, err)
+	assert.Equal(t, RelayReport{}, report)
+}
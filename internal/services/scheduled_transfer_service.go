@@ -0,0 +1,257 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/calendar"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	"github.com/gregoryAlvim/gobank/internal/validation"
+)
+
+// ScheduleRetryPolicy says how often a scheduled transfer that failed is
+// tried again on the same date. Attempt n is retried n times Delay after
+// it failed. Once MaxAttempts attempts have failed the run is given up
+// on and the transfer moves on to its next date.
+type ScheduleRetryPolicy struct {
+	MaxAttempts int
+	Delay       time.Duration
+}
+
+// DefaultScheduleRetryPolicy is used when
+// ScheduledTransferService.Retry is the zero value.
+var DefaultScheduleRetryPolicy = ScheduleRetryPolicy{MaxAttempts: 3, Delay: time.Hour}
+
+const (
+	// scheduleLease is how long a claimed transfer is kept from other
+	// schedulers while it runs.
+	scheduleLease = 5 * time.Minute
+	scheduleBatch = 100
+)
+
+type ScheduledTransferService struct {
+	repo     repositories.ScheduledTransferRepository
+	accounts AccountServiceInterface
+	calendar *calendar.Calendar
+	Retry    ScheduleRetryPolicy
+}
+
+// NewScheduledTransferService runs transfers through accounts on the
+// business days of cal. A nil cal has no holidays.
+func NewScheduledTransferService(repo repositories.ScheduledTransferRepository, accounts AccountServiceInterface, cal *calendar.Calendar) *ScheduledTransferService {
+	if cal == nil {
+		cal = calendar.New()
+	}
+	return &ScheduledTransferService{repo: repo, accounts: accounts, calendar: cal}
+}
+
+// Create schedules a transfer from the account on behalf of requester.
+// A transfer due on a weekend or holiday runs on the next business day.
+func (s *ScheduledTransferService) Create(requester *auth.Principal, fromID int, request models.ScheduledTransferRequest) (*models.ScheduledTransfer, error) {
+	v := validation.New()
+	request.Validate(v)
+	if !request.StartDate.IsZero() {
+		today := models.DateOf(time.Now().UTC())
+		v.Check(!request.StartDate.Before(today.Time), "start_date", validation.CodeOutOfRange, "must not be in the past")
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	if request.ToAccountID == fromID {
+		return nil, ErrSameAccount
+	}
+
+	schedule := &models.ScheduledTransfer{
+		FromAccountID: fromID,
+		ToAccountID:   request.ToAccountID,
+		Amount:        request.Amount,
+		Description:   request.Description,
+		Frequency:     request.Frequency,
+		StartDate:     request.StartDate,
+		DayOfMonth:    request.DayOfMonth,
+		EndDate:       request.EndDate,
+		MaxRuns:       request.MaxRuns,
+		CreatedByRole: string(requester.Role),
+	}
+	if requester.IsOperator() {
+		schedule.CreatedByOperatorID = requester.OperatorID
+	} else {
+		schedule.CreatedByCustomerID = requester.CustomerID
+	}
+	if schedule.Frequency == models.FrequencyMonthly && schedule.DayOfMonth == 0 {
+		schedule.DayOfMonth = schedule.StartDate.Day()
+	}
+
+	first, ok := schedule.NextOccurrence(schedule.StartDate.AddDays(-1))
+	if !ok {
+		v.Add("end_date", validation.CodeOutOfRange, "leaves no date to run the transfer on")
+		return nil, v.Err()
+	}
+	s.setNextRun(schedule, first)
+
+	if err := s.repo.Create(schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *ScheduledTransferService) List(accountID int) ([]models.ScheduledTransfer, error) {
+	return s.repo.ListByAccount(accountID)
+}
+
+func (s *ScheduledTransferService) Get(accountID int, scheduleID int64) (*models.ScheduledTransfer, error) {
+	return s.repo.Get(accountID, scheduleID)
+}
+
+// Update changes an active scheduled transfer from its next run on. The
+// transfer completes straight away if the new end leaves no run.
+func (s *ScheduledTransferService) Update(accountID int, scheduleID int64, request models.UpdateScheduledTransferRequest) (*models.ScheduledTransfer, error) {
+	schedule, err := s.repo.Get(accountID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Status != models.ScheduleActive {
+		return nil, repositories.ErrScheduledTransferInactive
+	}
+
+	v := validation.New()
+	request.Validate(v, schedule)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	schedule.Amount = request.Amount
+	schedule.Description = request.Description
+	if schedule.Frequency != models.FrequencyOnce {
+		schedule.EndDate, schedule.MaxRuns = request.EndDate, request.MaxRuns
+		ended := schedule.MaxRuns != nil && schedule.RunsCount >= *schedule.MaxRuns
+		if schedule.EndDate != nil && schedule.NextRunDate != nil && schedule.NextRunDate.After(schedule.EndDate.Time) {
+			ended = true
+		}
+		if ended {
+			schedule.Status, schedule.NextRunDate, schedule.NextAttemptAt = models.ScheduleCompleted, nil, nil
+		}
+	}
+
+	if err := s.repo.Update(schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// Cancel stops a scheduled transfer. Past runs are kept.
+func (s *ScheduledTransferService) Cancel(accountID int, scheduleID int64) (*models.ScheduledTransfer, error) {
+	return s.repo.Cancel(accountID, scheduleID)
+}
+
+// ListRuns returns every attempt at the scheduled transfer, newest first.
+func (s *ScheduledTransferService) ListRuns(accountID int, scheduleID int64) ([]models.ScheduledTransferRun, error) {
+	if _, err := s.repo.Get(accountID, scheduleID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListRuns(scheduleID)
+}
+
+// RunDue runs every scheduled transfer due at now through
+// AccountService.Transfer, on behalf of whoever scheduled it, and returns
+// how many it ran. Transfers above the approval threshold wait for
+// approval like any other. Dates missed while the scheduler was stopped
+// are run one after the other.
+//
+// Failed transfers are retried according to Retry, except when retrying
+// cannot help, e.g. when an account no longer exists. A failure to
+// record a run does not stop the others; the failures are returned
+// together. Such a transfer is retried once its lease runs out. Each date
+// is transferred with a key of its own, so one that went through before
+// is recorded then without moving the money again.
+func (s *ScheduledTransferService) RunDue(now time.Time) (int, error) {
+	ran := 0
+	var errs []error
+	for {
+		due, err := s.repo.ClaimDue(now, scheduleLease, scheduleBatch)
+		if err != nil {
+			return ran, errors.Join(append(errs, err)...)
+		}
+		for i := range due {
+			if err := s.run(&due[i], now); err != nil {
+				errs = append(errs, fmt.Errorf("scheduled transfer %d: %w", due[i].ID, err))
+				continue
+			}
+			ran++
+		}
+		if len(due) < scheduleBatch {
+			return ran, errors.Join(errs...)
+		}
+	}
+}
+
+func (s *ScheduledTransferService) run(schedule *models.ScheduledTransfer, now time.Time) error {
+	policy := s.Retry
+	if policy.MaxAttempts <= 0 {
+		policy = DefaultScheduleRetryPolicy
+	}
+
+	runDate := *schedule.NextRunDate
+	run := &models.ScheduledTransferRun{RunDate: runDate, Attempt: schedule.Attempts + 1}
+	requester := &auth.Principal{
+		CustomerID: schedule.CreatedByCustomerID,
+		OperatorID: schedule.CreatedByOperatorID,
+		Role:       auth.Role(schedule.CreatedByRole),
+		RequestID:  fmt.Sprintf("scheduled-transfer:%d:%s:%d", schedule.ID, runDate, run.Attempt),
+	}
+
+	key := fmt.Sprintf("scheduled-transfer:%d:%s", schedule.ID, runDate)
+	approval, err := s.accounts.TransferOnce(requester, key, schedule.FromAccountID, schedule.ToAccountID, schedule.Amount)
+	switch {
+	case err != nil && retryable(err) && run.Attempt < policy.MaxAttempts:
+		run.Status, run.Error = models.RunRetrying, err.Error()
+		retryAt := s.retryTime(now.Add(time.Duration(run.Attempt) * policy.Delay))
+		schedule.Attempts, schedule.NextAttemptAt = run.Attempt, &retryAt
+		return s.repo.RecordRun(run, schedule)
+	case err != nil:
+		run.Status, run.Error = models.RunFailed, err.Error()
+	case approval != nil:
+		run.Status, run.ApprovalID = models.RunPendingApproval, &approval.ID
+	default:
+		run.Status = models.RunSucceeded
+	}
+
+	schedule.RunsCount++
+	schedule.Attempts = 0
+	if next, ok := schedule.NextOccurrence(runDate); ok {
+		s.setNextRun(schedule, next)
+	} else {
+		schedule.Status, schedule.NextRunDate, schedule.NextAttemptAt = models.ScheduleCompleted, nil, nil
+	}
+	return s.repo.RecordRun(run, schedule)
+}
+
+// setNextRun sets the transfer's next run to date, tried from the start of
+// the first business day on or after it.
+func (s *ScheduledTransferService) setNextRun(schedule *models.ScheduledTransfer, date models.Date) {
+	at := s.calendar.NextBusinessDay(date.Time)
+	schedule.NextRunDate, schedule.NextAttemptAt = &date, &at
+}
+
+// retryTime moves a retry that would fall on a weekend or holiday to the
+// start of the next business day.
+func (s *ScheduledTransferService) retryTime(at time.Time) time.Time {
+	if s.calendar.IsBusinessDay(at) {
+		return at
+	}
+	return s.calendar.NextBusinessDay(models.DateOf(at.UTC()).Time)
+}
+
+// retryable reports whether a failed transfer may go through if tried
+// again, e.g. once the account has the funds.
+func retryable(err error) bool {
+	for _, permanent := range []error{repositories.ErrAccountNotFound, repositories.ErrAccountClosed, ErrSameAccount, ErrInvalidAmount, auth.ErrForbidden} {
+		if errors.Is(err, permanent) {
+			return false
+		}
+	}
+	return true
+}
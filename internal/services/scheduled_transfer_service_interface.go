@@ -0,0 +1,15 @@
+package services
+
+import (
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+type ScheduledTransferServiceInterface interface {
+	Create(requester *auth.Principal, fromID int, request models.ScheduledTransferRequest) (*models.ScheduledTransfer, error)
+	List(accountID int) ([]models.ScheduledTransfer, error)
+	Get(accountID int, scheduleID int64) (*models.ScheduledTransfer, error)
+	Update(accountID int, scheduleID int64, request models.UpdateScheduledTransferRequest) (*models.ScheduledTransfer, error)
+	Cancel(accountID int, scheduleID int64) (*models.ScheduledTransfer, error)
+	ListRuns(accountID int, scheduleID int64) ([]models.ScheduledTransferRun, error)
+}
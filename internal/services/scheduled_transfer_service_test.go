@@ -0,0 +1,254 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/gregoryAlvim/gobank/internal/auth"
+	"github.com/gregoryAlvim/gobank/internal/calendar"
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/money"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	repomocks "github.com/gregoryAlvim/gobank/internal/repositories/mocks"
+	"github.com/gregoryAlvim/gobank/internal/services/mocks"
+	"github.com/gregoryAlvim/gobank/internal/validation"
+)
+
+func date(s string) models.Date {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return models.Date{Time: t}
+}
+
+func datePtr(s string) *models.Date {
+	d := date(s)
+	return &d
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+// tiradentes is a Tuesday holiday.
+var tiradentes = date("2026-04-21").Time
+
+func TestScheduledTransferService_Create(t *testing.T) {
+	repo := repomocks.NewScheduledTransferRepository(t)
+	service := NewScheduledTransferService(repo, new(mocks.AccountServiceInterface), calendar.New(tiradentes))
+	customer := &auth.Principal{CustomerID: 4, Role: auth.RoleCustomer}
+	start := models.DateOf(time.Now().UTC()).AddDays(1)
+
+	// The first date of a transfer on the 31st is the start date's month's
+	// last day, moved to the business day after it.
+	repo.On("Create", mock.Anything).Return(nil)
+	schedule, err := service.Create(customer, 20, models.ScheduledTransferRequest{
+		ToAccountID: 30,
+		Amount:      money.New(100, 0),
+		Frequency:   models.FrequencyMonthly,
+		StartDate:   start,
+		DayOfMonth:  31,
+	})
+	assert.NoError(t, err)
+	lastDay := models.Date{Time: time.Date(start.Year(), start.Month()+1, 0, 0, 0, 0, 0, time.UTC)}
+	assert.Equal(t, &lastDay, schedule.NextRunDate)
+	assert.Equal(t, calendar.New(tiradentes).NextBusinessDay(lastDay.Time), *schedule.NextAttemptAt)
+	assert.Equal(t, 4, schedule.CreatedByCustomerID)
+	assert.Equal(t, "customer", schedule.CreatedByRole)
+}
+
+func TestScheduledTransferService_Create_Invalid(t *testing.T) {
+	repo := repomocks.NewScheduledTransferRepository(t)
+	service := NewScheduledTransferService(repo, new(mocks.AccountServiceInterface), nil)
+	customer := &auth.Principal{CustomerID: 4, Role: auth.RoleCustomer}
+	tomorrow := models.DateOf(time.Now().UTC()).AddDays(1)
+	maxRuns := 2
+
+	tests := []struct {
+		name    string
+		request models.ScheduledTransferRequest
+		fields  []string
+	}{
+		{
+			name:    "start in the past",
+			request: models.ScheduledTransferRequest{ToAccountID: 30, Amount: money.New(10, 0), Frequency: "once", StartDate: tomorrow.AddDays(-2)},
+			fields:  []string{"start_date"},
+		},
+		{
+			name: "end on a once transfer",
+			request: models.ScheduledTransferRequest{ToAccountID: 30, Amount: money.New(10, 0), Frequency: "once", StartDate: tomorrow,
+				EndDate: &tomorrow, MaxRuns: &maxRuns},
+			fields: []string{"end_date", "max_runs"},
+		},
+		{
+			name:    "day of month on a weekly transfer",
+			request: models.ScheduledTransferRequest{ToAccountID: 30, Amount: money.New(10, 0), Frequency: "weekly", StartDate: tomorrow, DayOfMonth: 5},
+			fields:  []string{"day_of_month"},
+		},
+		{
+			name: "no date before the end",
+			request: models.ScheduledTransferRequest{ToAccountID: 30, Amount: money.New(10, 0), Frequency: "monthly", StartDate: tomorrow,
+				DayOfMonth: tomorrow.AddDays(-1).Day(), EndDate: &tomorrow},
+			fields: []string{"end_date"},
+		},
+		{
+			name:   "missing fields",
+			fields: []string{"to_id", "amount", "start_date", "frequency"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Create(customer, 20, tt.request)
+			var errs validation.Errors
+			assert.ErrorAs(t, err, &errs)
+			var fields []string
+			for _, e := range errs {
+				fields = append(fields, e.Field)
+			}
+			assert.Equal(t, tt.fields, fields)
+		})
+	}
+
+	_, err := service.Create(customer, 20, models.ScheduledTransferRequest{ToAccountID: 20, Amount: money.New(10, 0), Frequency: "once", StartDate: tomorrow})
+	assert.ErrorIs(t, err, ErrSameAccount)
+	repo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestScheduledTransferService_Update(t *testing.T) {
+	repo := repomocks.NewScheduledTransferRepository(t)
+	service := NewScheduledTransferService(repo, new(mocks.AccountServiceInterface), nil)
+	maxRuns := 3
+
+	// The transfer already ran three times, so the new count ends it.
+	repo.On("Get", 20, int64(7)).Return(&models.ScheduledTransfer{
+		ID: 7, FromAccountID: 20, Amount: money.New(100, 0), Frequency: models.FrequencyWeekly, StartDate: date("2026-03-03"),
+		RunsCount: 3, Status: models.ScheduleActive, NextRunDate: datePtr("2026-03-24"), NextAttemptAt: timePtr(date("2026-03-24").Time),
+	}, nil)
+	repo.On("Update", mock.Anything).Return(nil)
+
+	schedule, err := service.Update(20, 7, models.UpdateScheduledTransferRequest{Amount: money.New(50, 0), MaxRuns: &maxRuns})
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(50, 0), schedule.Amount)
+	assert.Equal(t, models.ScheduleCompleted, schedule.Status)
+	assert.Nil(t, schedule.NextRunDate)
+	assert.Nil(t, schedule.NextAttemptAt)
+}
+
+func TestScheduledTransferService_RunDue(t *testing.T) {
+	repo := repomocks.NewScheduledTransferRepository(t)
+	accounts := new(mocks.AccountServiceInterface)
+	service := NewScheduledTransferService(repo, accounts, calendar.New(tiradentes))
+	service.Retry = ScheduleRetryPolicy{MaxAttempts: 3, Delay: time.Hour}
+	now := time.Date(2026, time.April, 14, 10, 0, 0, 0, time.UTC)
+	twice := 2
+
+	due := []models.ScheduledTransfer{
+		// Due on Saturday, January 31st; February 28th is a Saturday too.
+		{ID: 1, FromAccountID: 20, ToAccountID: 30, Amount: money.New(100, 0), Frequency: models.FrequencyMonthly,
+			StartDate: date("2026-01-31"), DayOfMonth: 31, Status: models.ScheduleActive, NextRunDate: datePtr("2026-01-31"),
+			CreatedByCustomerID: 4, CreatedByRole: "customer"},
+		// Short of funds on its first attempt and again now.
+		{ID: 2, FromAccountID: 21, ToAccountID: 30, Amount: money.New(10, 0), Frequency: models.FrequencyWeekly,
+			StartDate: date("2026-04-14"), Status: models.ScheduleActive, NextRunDate: datePtr("2026-04-14"), Attempts: 1,
+			CreatedByCustomerID: 5, CreatedByRole: "customer"},
+		// Out of attempts; next Tuesday is a holiday.
+		{ID: 3, FromAccountID: 22, ToAccountID: 30, Amount: money.New(10, 0), Frequency: models.FrequencyWeekly,
+			StartDate: date("2026-04-07"), RunsCount: 1, Status: models.ScheduleActive, NextRunDate: datePtr("2026-04-14"), Attempts: 2,
+			CreatedByOperatorID: 9, CreatedByRole: "teller"},
+		// The destination was closed; retrying cannot help.
+		{ID: 4, FromAccountID: 23, ToAccountID: 31, Amount: money.New(10, 0), Frequency: models.FrequencyOnce,
+			StartDate: date("2026-04-14"), Status: models.ScheduleActive, NextRunDate: datePtr("2026-04-14"),
+			CreatedByCustomerID: 6, CreatedByRole: "customer"},
+		// The last run, above the approval threshold.
+		{ID: 5, FromAccountID: 24, ToAccountID: 30, Amount: money.New(5000, 0), Frequency: models.FrequencyWeekly,
+			StartDate: date("2026-04-07"), MaxRuns: &twice, RunsCount: 1, Status: models.ScheduleActive, NextRunDate: datePtr("2026-04-14"),
+			CreatedByCustomerID: 7, CreatedByRole: "customer"},
+	}
+	repo.On("ClaimDue", now, scheduleLease, scheduleBatch).Return(due, nil)
+
+	// Each run is a request of its own, named after the schedule, the date
+	// and the attempt. The transfer is keyed by the schedule and the date
+	// only, so no attempt pays out a date that was already paid.
+	customer := func(id int, requestID string) *auth.Principal {
+		return &auth.Principal{CustomerID: id, Role: auth.RoleCustomer, RequestID: requestID}
+	}
+	accounts.On("TransferOnce", customer(4, "scheduled-transfer:1:2026-01-31:1"), "scheduled-transfer:1:2026-01-31", 20, 30, money.New(100, 0)).Return(nil, nil)
+	accounts.On("TransferOnce", customer(5, "scheduled-transfer:2:2026-04-14:2"), "scheduled-transfer:2:2026-04-14", 21, 30, money.New(10, 0)).
+		Return(nil, repositories.ErrInsufficientFunds)
+	accounts.On("TransferOnce", &auth.Principal{OperatorID: 9, Role: auth.RoleTeller, RequestID: "scheduled-transfer:3:2026-04-14:3"},
+		"scheduled-transfer:3:2026-04-14", 22, 30, money.New(10, 0)).Return(nil, repositories.ErrInsufficientFunds)
+	accounts.On("TransferOnce", customer(6, "scheduled-transfer:4:2026-04-14:1"), "scheduled-transfer:4:2026-04-14", 23, 31, money.New(10, 0)).
+		Return(nil, repositories.ErrAccountNotFound)
+	accounts.On("TransferOnce", customer(7, "scheduled-transfer:5:2026-04-14:1"), "scheduled-transfer:5:2026-04-14", 24, 30, money.New(5000, 0)).
+		Return(&models.TransferApproval{ID: 77}, nil)
+
+	type recorded struct {
+		run      models.ScheduledTransferRun
+		schedule models.ScheduledTransfer
+	}
+	var records []recorded
+	repo.On("RecordRun", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		records = append(records, recorded{*args.Get(0).(*models.ScheduledTransferRun), *args.Get(1).(*models.ScheduledTransfer)})
+	})
+
+	ran, err := service.RunDue(now)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, ran)
+	approvalID := int64(77)
+	insufficient := repositories.ErrInsufficientFunds.Error()
+	wantRuns := []models.ScheduledTransferRun{
+		{RunDate: date("2026-01-31"), Attempt: 1, Status: models.RunSucceeded},
+		{RunDate: date("2026-04-14"), Attempt: 2, Status: models.RunRetrying, Error: insufficient},
+		{RunDate: date("2026-04-14"), Attempt: 3, Status: models.RunFailed, Error: insufficient},
+		{RunDate: date("2026-04-14"), Attempt: 1, Status: models.RunFailed, Error: repositories.ErrAccountNotFound.Error()},
+		{RunDate: date("2026-04-14"), Attempt: 1, Status: models.RunPendingApproval, ApprovalID: &approvalID},
+	}
+	type progress struct {
+		runsCount, attempts int
+		status              string
+		nextRun             *models.Date
+		nextAttempt         *time.Time
+	}
+	wantProgress := []progress{
+		{1, 0, models.ScheduleActive, datePtr("2026-02-28"), timePtr(date("2026-03-02").Time)},
+		{0, 2, models.ScheduleActive, datePtr("2026-04-14"), timePtr(now.Add(2 * time.Hour))},
+		{2, 0, models.ScheduleActive, datePtr("2026-04-21"), timePtr(date("2026-04-22").Time)},
+		{1, 0, models.ScheduleCompleted, nil, nil},
+		{2, 0, models.ScheduleCompleted, nil, nil},
+	}
+	if assert.Len(t, records, len(due)) {
+		for i, r := range records {
+			assert.Equal(t, wantRuns[i], r.run, "schedule %d", due[i].ID)
+			s := r.schedule
+			assert.Equal(t, wantProgress[i], progress{s.RunsCount, s.Attempts, s.Status, s.NextRunDate, s.NextAttemptAt}, "schedule %d", due[i].ID)
+		}
+	}
+}
+
+// A retry that would fall on a weekend waits for Monday.
+func TestScheduledTransferService_RunDue_RetryOnWeekend(t *testing.T) {
+	repo := repomocks.NewScheduledTransferRepository(t)
+	accounts := new(mocks.AccountServiceInterface)
+	service := NewScheduledTransferService(repo, accounts, nil)
+	friday := time.Date(2026, time.March, 6, 23, 30, 0, 0, time.UTC)
+
+	repo.On("ClaimDue", friday, scheduleLease, scheduleBatch).Return([]models.ScheduledTransfer{
+		{ID: 1, FromAccountID: 20, ToAccountID: 30, Amount: money.New(10, 0), Frequency: models.FrequencyOnce,
+			StartDate: date("2026-03-06"), Status: models.ScheduleActive, NextRunDate: datePtr("2026-03-06"),
+			CreatedByCustomerID: 4, CreatedByRole: "customer"},
+	}, nil)
+	accounts.On("TransferOnce", mock.Anything, mock.Anything, 20, 30, money.New(10, 0)).Return(nil, repositories.ErrAccountFrozen)
+	repo.On("RecordRun", mock.MatchedBy(func(run *models.ScheduledTransferRun) bool {
+		return run.Status == models.RunRetrying && run.Attempt == 1
+	}), mock.MatchedBy(func(s *models.ScheduledTransfer) bool {
+		return s.Attempts == 1 && s.NextAttemptAt.Equal(date("2026-03-09").Time)
+	})).Return(nil)
+
+	ran, err := service.RunDue(friday)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, ran)
+}
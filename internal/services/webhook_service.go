@@ -0,0 +1,224 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	"github.com/gregoryAlvim/gobank/internal/validation"
+	"github.com/gregoryAlvim/gobank/internal/webhook"
+)
+
+// WebhookRetryPolicy says when a delivery that failed is sent again. After
+// attempt n fails the next one waits BaseDelay·2^(n-1), but never more
+// than MaxDelay. Once MaxAttempts attempts have failed the delivery is
+// dead.
+type WebhookRetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultWebhookRetryPolicy is used when WebhookService.Retry is the zero
+// value. Its attempts span about four hours.
+var DefaultWebhookRetryPolicy = WebhookRetryPolicy{MaxAttempts: 10, BaseDelay: 30 * time.Second, MaxDelay: 6 * time.Hour}
+
+// Delay returns how long to wait after the given failed attempt.
+func (p WebhookRetryPolicy) Delay(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+const (
+	// webhookLease is how long a claimed batch is kept from other workers
+	// when the client has no timeout; otherwise the lease lasts for the
+	// whole batch to time out.
+	webhookLease = 5 * time.Minute
+	webhookBatch = 20
+
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 200
+)
+
+type WebhookService struct {
+	repo   repositories.WebhookRepository
+	client *webhook.Client
+	Retry  WebhookRetryPolicy
+}
+
+func NewWebhookService(repo repositories.WebhookRepository, client *webhook.Client) *WebhookService {
+	return &WebhookService{repo: repo, client: client}
+}
+
+// CreateSubscription subscribes the customer to the events of their
+// accounts, or a partner to the events of every account when customerID
+// is zero. The returned subscription carries the secret the deliveries
+// are signed with; it is not shown again.
+func (s *WebhookService) CreateSubscription(customerID int, request models.WebhookSubscriptionRequest) (*models.WebhookSubscription, error) {
+	v := validation.New()
+	request.Validate(v, customerID == 0)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+	subscription := &models.WebhookSubscription{
+		CustomerID: customerID,
+		Partner:    request.Partner,
+		URL:        request.URL,
+		EventTypes: request.EventTypes,
+		Secret:     secret,
+	}
+	if subscription.EventTypes == nil {
+		subscription.EventTypes = []string{}
+	}
+	if err := s.repo.CreateSubscription(subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// ListSubscriptions lists the customer's subscriptions, or the partners'
+// when customerID is zero.
+func (s *WebhookService) ListSubscriptions(customerID int) ([]models.WebhookSubscription, error) {
+	return s.repo.ListSubscriptions(customerID)
+}
+
+// DeleteSubscription removes the subscription and its deliveries.
+func (s *WebhookService) DeleteSubscription(customerID int, subscriptionID int64) error {
+	if _, err := s.subscription(customerID, subscriptionID); err != nil {
+		return err
+	}
+	return s.repo.DeleteSubscription(subscriptionID)
+}
+
+// ListDeliveries lists the subscription's deliveries, newest first, with
+// the given status or with any when it is empty.
+func (s *WebhookService) ListDeliveries(customerID int, subscriptionID int64, status string, limit int) ([]models.WebhookDelivery, error) {
+	if _, err := s.subscription(customerID, subscriptionID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultDeliveryLimit
+	}
+	return s.repo.ListDeliveries(subscriptionID, status, min(limit, maxDeliveryLimit))
+}
+
+// Resend sends a delivery again, e.g. a dead one once the receiver is
+// fixed, with all of its attempts.
+func (s *WebhookService) Resend(customerID int, subscriptionID, deliveryID int64) (*models.WebhookDelivery, error) {
+	if _, err := s.subscription(customerID, subscriptionID); err != nil {
+		return nil, err
+	}
+	return s.repo.Resend(subscriptionID, deliveryID)
+}
+
+// subscription returns the subscription if it is the customer's, or a
+// partner's when customerID is zero. Anybody else's is not found.
+func (s *WebhookService) subscription(customerID int, subscriptionID int64) (*models.WebhookSubscription, error) {
+	subscription, err := s.repo.GetSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription.CustomerID != customerID {
+		return nil, repositories.ErrWebhookNotFound
+	}
+	return subscription, nil
+}
+
+// Enqueue queues the event for every subscription that wants it. It is
+// meant to be subscribed to the events the outbox relay publishes; an
+// event published twice is queued once.
+func (s *WebhookService) Enqueue(event models.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = s.repo.EnqueueDeliveries(event, payload)
+	return err
+}
+
+// DeliverDue sends every delivery due at now and returns how many were
+// delivered. Failed deliveries are retried according to Retry. A failure
+// to record an attempt does not stop the others; the failures are
+// returned together. Such a delivery is sent again once its lease runs
+// out, so receivers must expect the same event more than once and tell
+// repeats apart by the X-Webhook-Id header.
+func (s *WebhookService) DeliverDue(now time.Time) (int, error) {
+	lease := webhookLease
+	if timeout := s.client.HTTP.Timeout; timeout > 0 {
+		lease = webhookBatch*timeout + time.Minute
+	}
+
+	delivered := 0
+	var errs []error
+	for {
+		due, err := s.repo.ClaimDue(now, lease, webhookBatch)
+		if err != nil {
+			return delivered, errors.Join(append(errs, err)...)
+		}
+		for i := range due {
+			ok, err := s.deliver(&due[i], now)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("webhook delivery %d: %w", due[i].ID, err))
+				continue
+			}
+			if ok {
+				delivered++
+			}
+		}
+		if len(due) < webhookBatch {
+			return delivered, errors.Join(errs...)
+		}
+	}
+}
+
+// deliver sends the delivery and records the attempt, reporting whether
+// the receiver took it.
+func (s *WebhookService) deliver(due *models.DueWebhookDelivery, now time.Time) (bool, error) {
+	policy := s.Retry
+	if policy.MaxAttempts <= 0 {
+		policy = DefaultWebhookRetryPolicy
+	}
+
+	code, sendErr := s.client.Send(webhook.Delivery{
+		URL:       due.URL,
+		Secret:    due.Secret,
+		EventID:   due.EventID,
+		EventType: due.EventType,
+		Body:      due.Payload,
+	}, now)
+
+	delivery := &due.WebhookDelivery
+	delivery.Attempts++
+	delivery.LastStatusCode = nil
+	if code != 0 {
+		delivery.LastStatusCode = &code
+	}
+	switch {
+	case sendErr == nil:
+		delivery.Status, delivery.LastError = models.DeliveryDelivered, ""
+		delivery.NextAttemptAt, delivery.DeliveredAt = nil, &now
+	case delivery.Attempts >= policy.MaxAttempts:
+		delivery.Status, delivery.LastError, delivery.NextAttemptAt = models.DeliveryDead, sendErr.Error(), nil
+	default:
+		next := now.Add(policy.Delay(delivery.Attempts))
+		delivery.Status, delivery.LastError, delivery.NextAttemptAt = models.DeliveryPending, sendErr.Error(), &next
+	}
+	if err := s.repo.RecordAttempt(delivery); err != nil {
+		return false, err
+	}
+	return sendErr == nil, nil
+}
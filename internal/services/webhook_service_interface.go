@@ -0,0 +1,13 @@
+package services
+
+import (
+	"github.com/gregoryAlvim/gobank/internal/models"
+)
+
+type WebhookServiceInterface interface {
+	CreateSubscription(customerID int, request models.WebhookSubscriptionRequest) (*models.WebhookSubscription, error)
+	ListSubscriptions(customerID int) ([]models.WebhookSubscription, error)
+	DeleteSubscription(customerID int, subscriptionID int64) error
+	ListDeliveries(customerID int, subscriptionID int64, status string, limit int) ([]models.WebhookDelivery, error)
+	Resend(customerID int, subscriptionID, deliveryID int64) (*models.WebhookDelivery, error)
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/gregoryAlvim/gobank/internal/models"
+	"github.com/gregoryAlvim/gobank/internal/repositories"
+	repomocks "github.com/gregoryAlvim/gobank/internal/repositories/mocks"
+	"github.com/gregoryAlvim/gobank/internal/validation"
+	"github.com/gregoryAlvim/gobank/internal/webhook"
+)
+
+func TestWebhookService_CreateSubscription(t *testing.T) {
+	repo := repomocks.NewWebhookRepository(t)
+	service := NewWebhookService(repo, webhook.NewClient(time.Second))
+
+	repo.On("CreateSubscription", mock.MatchedBy(func(s *models.WebhookSubscription) bool {
+		return s.CustomerID == 3 && s.Partner == "" && len(s.Secret) > len("whsec_")
+	})).Return(nil).Once()
+	subscription, err := service.CreateSubscription(3, models.WebhookSubscriptionRequest{URL: "https://example.com/hooks"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, subscription.EventTypes)
+	assert.NotEmpty(t, subscription.Secret)
+
+	// Customers do not name a partner; partners must.
+	_, err = service.CreateSubscription(3, models.WebhookSubscriptionRequest{Partner: "acme", URL: "ftp://example.com",
+		EventTypes: []string{"deposit.completed", "account.deleted"}})
+	var errs validation.Errors
+	assert.ErrorAs(t, err, &errs)
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	assert.Equal(t, []string{"partner", "url", "event_types"}, fields)
+
+	_, err = service.CreateSubscription(0, models.WebhookSubscriptionRequest{URL: "https://acme.example/hooks"})
+	assert.ErrorAs(t, err, &errs)
+	assert.Equal(t, "partner", errs[0].Field)
+
+	// Deliveries are only sent over https.
+	_, err = service.CreateSubscription(3, models.WebhookSubscriptionRequest{URL: "http://example.com/hooks"})
+	assert.ErrorAs(t, err, &errs)
+	assert.Equal(t, "url", errs[0].Field)
+}
+
+func TestWebhookService_Ownership(t *testing.T) {
+	repo := repomocks.NewWebhookRepository(t)
+	service := NewWebhookService(repo, webhook.NewClient(time.Second))
+
+	repo.On("GetSubscription", int64(5)).Return(&models.WebhookSubscription{ID: 5, CustomerID: 3}, nil)
+	repo.On("GetSubscription", int64(6)).Return(&models.WebhookSubscription{ID: 6, Partner: "acme"}, nil)
+
+	// Another customer's subscription, or a partner's, is not found.
+	assert.ErrorIs(t, service.DeleteSubscription(4, 5), repositories.ErrWebhookNotFound)
+	_, err := service.Resend(3, 6, 9)
+	assert.ErrorIs(t, err, repositories.ErrWebhookNotFound)
+	_, err = service.ListDeliveries(0, 5, "", 0)
+	assert.ErrorIs(t, err, repositories.ErrWebhookNotFound)
+
+	repo.On("ListDeliveries", int64(5), "dead", maxDeliveryLimit).Return([]models.WebhookDelivery{}, nil)
+	_, err = service.ListDeliveries(3, 5, "dead", 1000)
+	assert.NoError(t, err)
+
+	repo.On("Resend", int64(6), int64(9)).Return(&models.WebhookDelivery{ID: 9, Status: models.DeliveryPending}, nil)
+	_, err = service.Resend(0, 6, 9)
+	assert.NoError(t, err)
+}
+
+func TestWebhookService_Enqueue(t *testing.T) {
+	repo := repomocks.NewWebhookRepository(t)
+	service := NewWebhookService(repo, webhook.NewClient(time.Second))
+	event := models.Event{ID: "e1", Sequence: 4, Type: models.EventDeposit, AccountID: 10, Data: []byte(`{"amount":"10"}`)}
+
+	repo.On("EnqueueDeliveries", event, mock.MatchedBy(func(payload []byte) bool {
+		return assert.JSONEq(t, `{"id":"e1","sequence":4,"type":"deposit.completed","account_id":10,"data":{"amount":"10"},
+			"created_at":"0001-01-01T00:00:00Z"}`, string(payload))
+	})).Return(1, nil)
+	assert.NoError(t, service.Enqueue(event))
+}
+
+func TestWebhookService_DeliverDue(t *testing.T) {
+	var received []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Clone())
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	repo := repomocks.NewWebhookRepository(t)
+	// The test server listens on loopback, which webhook.NewClient refuses.
+	client := server.Client()
+	client.Timeout = time.Second
+	service := NewWebhookService(repo, &webhook.Client{HTTP: client})
+	service.Retry = WebhookRetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Hour}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	due := func(id int64, path string, attempts int) models.DueWebhookDelivery {
+		return models.DueWebhookDelivery{
+			WebhookDelivery: models.WebhookDelivery{ID: id, EventID: "e1", EventType: models.EventDeposit, Payload: []byte(`{}`),
+				Status: models.DeliveryPending, Attempts: attempts},
+			URL:    server.URL + path,
+			Secret: "whsec_test",
+		}
+	}
+
+	repo.On("ClaimDue", now, webhookBatch*time.Second+time.Minute, webhookBatch).
+		Return([]models.DueWebhookDelivery{due(1, "/up", 0), due(2, "/down", 1), due(3, "/down", 2)}, nil)
+
+	// Delivered.
+	repo.On("RecordAttempt", mock.MatchedBy(func(d *models.WebhookDelivery) bool {
+		return d.ID == 1 && d.Status == models.DeliveryDelivered && d.Attempts == 1 && *d.LastStatusCode == 200 &&
+			d.NextAttemptAt == nil && d.DeliveredAt.Equal(now)
+	})).Return(nil)
+	// Failed on the second attempt, so retried after two minutes.
+	repo.On("RecordAttempt", mock.MatchedBy(func(d *models.WebhookDelivery) bool {
+		return d.ID == 2 && d.Status == models.DeliveryPending && d.Attempts == 2 && *d.LastStatusCode == 503 &&
+			d.NextAttemptAt.Equal(now.Add(2*time.Minute)) && d.LastError != ""
+	})).Return(nil)
+	// Out of attempts.
+	repo.On("RecordAttempt", mock.MatchedBy(func(d *models.WebhookDelivery) bool {
+		return d.ID == 3 && d.Status == models.DeliveryDead && d.Attempts == 3 && d.NextAttemptAt == nil
+	})).Return(nil)
+
+	delivered, err := service.DeliverDue(now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+
+	assert.Len(t, received, 3)
+	assert.Equal(t, "e1", received[0].Get(webhook.IDHeader))
+	assert.Equal(t, webhook.Sign("whsec_test", now.Unix(), []byte(`{}`)), received[0].Get(webhook.SignatureHeader))
+}
+
+func TestWebhookRetryPolicy_Delay(t *testing.T) {
+	policy := WebhookRetryPolicy{MaxAttempts: 10, BaseDelay: 30 * time.Second, MaxDelay: 10 * time.Minute}
+	assert.Equal(t, 30*time.Second, policy.Delay(1))
+	assert.Equal(t, time.Minute, policy.Delay(2))
+	assert.Equal(t, 8*time.Minute, policy.Delay(5))
+	assert.Equal(t, 10*time.Minute, policy.Delay(6))
+	assert.Equal(t, 10*time.Minute, policy.Delay(60))
+}
@@ -0,0 +1,138 @@
+// Package taxid validates and normalizes Brazilian tax IDs: the CPF of
+// individuals and the CNPJ of companies.
+//
+// Both accept masked (123.456.789-09, 12.345.678/0001-95) and unmasked
+// input. Normalize strips the mask so IDs are stored and compared in one
+// form.
+//
+// CNPJs may be alphanumeric (12.ABC.345/01DE-35): the first 12 characters
+// are digits or uppercase letters and the last two are check digits. Each
+// character counts as its ASCII code minus 48, so numeric CNPJs validate
+// exactly as before.
+package taxid
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	CPFLength  = 11
+	CNPJLength = 14
+)
+
+var (
+	ErrInvalidCPF  = errors.New("invalid CPF")
+	ErrInvalidCNPJ = errors.New("invalid CNPJ")
+)
+
+// NormalizeCPF strips the mask from a CPF and checks its check digits.
+func NormalizeCPF(s string) (string, error) {
+	cpf := strip(s)
+	if len(cpf) != CPFLength || !allDigits(cpf) || allSame(cpf) {
+		return "", ErrInvalidCPF
+	}
+	if checkDigit(cpf[:9], cpfWeights(10)) != cpf[9] || checkDigit(cpf[:10], cpfWeights(11)) != cpf[10] {
+		return "", ErrInvalidCPF
+	}
+	return cpf, nil
+}
+
+// NormalizeCNPJ strips the mask from a CNPJ, uppercases its letters and
+// checks its check digits.
+func NormalizeCNPJ(s string) (string, error) {
+	cnpj := strings.ToUpper(strip(s))
+	if len(cnpj) != CNPJLength || !allAlphanumeric(cnpj[:12]) || !allDigits(cnpj[12:]) || allSame(cnpj) {
+		return "", ErrInvalidCNPJ
+	}
+	if checkDigit(cnpj[:12], cnpjWeights[1:]) != cnpj[12] || checkDigit(cnpj[:13], cnpjWeights) != cnpj[13] {
+		return "", ErrInvalidCNPJ
+	}
+	return cnpj, nil
+}
+
+// ValidCPF reports whether s is a valid CPF, masked or not.
+func ValidCPF(s string) bool {
+	_, err := NormalizeCPF(s)
+	return err == nil
+}
+
+// ValidCNPJ reports whether s is a valid CNPJ, masked or not.
+func ValidCNPJ(s string) bool {
+	_, err := NormalizeCNPJ(s)
+	return err == nil
+}
+
+// FormatCPF masks a normalized CPF as 123.456.789-09.
+func FormatCPF(cpf string) string {
+	if len(cpf) != CPFLength {
+		return cpf
+	}
+	return cpf[:3] + "." + cpf[3:6] + "." + cpf[6:9] + "-" + cpf[9:]
+}
+
+// FormatCNPJ masks a normalized CNPJ as 12.345.678/0001-95.
+func FormatCNPJ(cnpj string) string {
+	if len(cnpj) != CNPJLength {
+		return cnpj
+	}
+	return cnpj[:2] + "." + cnpj[2:5] + "." + cnpj[5:8] + "/" + cnpj[8:12] + "-" + cnpj[12:]
+}
+
+var cnpjWeights = []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+
+// cpfWeights returns the weights from first down to 2.
+func cpfWeights(first int) []int {
+	weights := make([]int, 0, first-1)
+	for w := first; w >= 2; w-- {
+		weights = append(weights, w)
+	}
+	return weights
+}
+
+// checkDigit computes a modulo 11 check digit: the weighted sum of the
+// characters, with remainders 0 and 1 giving 0 and any other remainder r
+// giving 11 - r.
+func checkDigit(s string, weights []int) byte {
+	sum := 0
+	for i := 0; i < len(s); i++ {
+		sum += int(s[i]-'0') * weights[i]
+	}
+	r := sum % 11
+	if r < 2 {
+		return '0'
+	}
+	return byte('0' + 11 - r)
+}
+
+func strip(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '-', '/', ' ':
+			return -1
+		}
+		return r
+	}, s)
+}
+
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func allAlphanumeric(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if (s[i] < '0' || s[i] > '9') && (s[i] < 'A' || s[i] > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func allSame(s string) bool {
+	return strings.Count(s, s[:1]) == len(s)
+}
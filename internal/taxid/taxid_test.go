@@ -0,0 +1,81 @@
+package taxid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeCPF(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		valid bool
+	}{
+		{"529.982.247-25", "52998224725", true},
+		{"52998224725", "52998224725", true},
+		{" 123.456.789-09 ", "12345678909", true},
+		{"529.982.247-24", "", false}, // wrong second digit
+		{"529.982.247-15", "", false}, // wrong first digit
+		{"111.111.111-11", "", false}, // repeated digits pass the checksum but are invalid
+		{"5299822472", "", false},
+		{"529982247250", "", false},
+		{"529.982.247-2X", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := NormalizeCPF(tt.input)
+			if !tt.valid {
+				assert.ErrorIs(t, err, ErrInvalidCPF)
+				assert.False(t, ValidCPF(tt.input))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.True(t, ValidCPF(tt.input))
+		})
+	}
+}
+
+func TestNormalizeCNPJ(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		valid bool
+	}{
+		{"11.222.333/0001-81", "11222333000181", true},
+		{"11222333000181", "11222333000181", true},
+		{"12.345.678/0001-95", "12345678000195", true},
+		{"12.ABC.345/01DE-35", "12ABC34501DE35", true},
+		{"12.abc.345/01de-35", "12ABC34501DE35", true},
+		{"11.222.333/0001-80", "", false},
+		{"12.ABC.345/01DE-36", "", false},
+		{"12.ABC.345/01DE-3A", "", false}, // check digits are always numeric
+		{"00.000.000/0000-00", "", false},
+		{"1122233300018", "", false},
+		{"11.222.333/0001-8", "", false},
+		{"11.222.333/0001-81#", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := NormalizeCNPJ(tt.input)
+			if !tt.valid {
+				assert.ErrorIs(t, err, ErrInvalidCNPJ)
+				assert.False(t, ValidCNPJ(tt.input))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.True(t, ValidCNPJ(tt.input))
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "529.982.247-25", FormatCPF("52998224725"))
+	assert.Equal(t, "11.222.333/0001-81", FormatCNPJ("11222333000181"))
+	assert.Equal(t, "12.ABC.345/01DE-35", FormatCNPJ("12ABC34501DE35"))
+}
@@ -0,0 +1,235 @@
+// Package validation collects field-level errors for request payloads.
+//
+// Models declare their rules in a Validate method that runs checks on a
+// Validator. Every failed check is kept, so a client sees all problems with
+// a payload at once rather than one per request.
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
+// Error codes reported in FieldError.Code.
+const (
+	CodeRequired     = "required"
+	CodeInvalid      = "invalid"
+	CodeTooShort     = "too_short"
+	CodeTooLong      = "too_long"
+	CodeOutOfRange   = "out_of_range"
+	CodeNotAllowed   = "not_allowed"
+	CodeUnknownField = "unknown_field"
+	CodeInvalidType  = "invalid_type"
+)
+
+// ErrMalformedJSON is returned by DecodeJSON when the body is not JSON at
+// all, as opposed to JSON with bad fields.
+var ErrMalformedJSON = errors.New("malformed JSON")
+
+// FieldError describes one invalid field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Errors is the list of field errors of a payload.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Field + ": " + fe.Message
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Validator accumulates field errors.
+type Validator struct {
+	errs Errors
+}
+
+func New() *Validator {
+	return &Validator{}
+}
+
+// Add records an error for field.
+func (v *Validator) Add(field, code, message string) {
+	v.errs = append(v.errs, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Check records an error for field unless ok.
+func (v *Validator) Check(ok bool, field, code, message string) {
+	if !ok {
+		v.Add(field, code, message)
+	}
+}
+
+// Err returns the accumulated errors as Errors, or nil if there are none.
+func (v *Validator) Err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// Required checks that value is not blank.
+func (v *Validator) Required(field, value string) bool {
+	ok := strings.TrimSpace(value) != ""
+	v.Check(ok, field, CodeRequired, "is required")
+	return ok
+}
+
+// MinLength checks that value has at least min characters.
+func (v *Validator) MinLength(field, value string, min int) {
+	v.Check(utf8.RuneCountInString(value) >= min, field, CodeTooShort, fmt.Sprintf("must be at least %d characters", min))
+}
+
+// MaxLength checks that value has at most max characters.
+func (v *Validator) MaxLength(field, value string, max int) {
+	v.Check(utf8.RuneCountInString(value) <= max, field, CodeTooLong, fmt.Sprintf("must be at most %d characters", max))
+}
+
+// Email checks that value is a bare email address such as
+// name@example.com.
+func (v *Validator) Email(field, value string) {
+	addr, err := mail.ParseAddress(value)
+	v.Check(err == nil && addr.Address == value && strings.Contains(value[strings.LastIndex(value, "@"):], "."),
+		field, CodeInvalid, "must be a valid email address")
+}
+
+// Between checks that min <= value <= max.
+func (v *Validator) Between(field string, value, min, max int) {
+	v.Check(value >= min && value <= max, field, CodeOutOfRange, fmt.Sprintf("must be between %d and %d", min, max))
+}
+
+// NonNegative checks that amount is zero or more.
+func (v *Validator) NonNegative(field string, amount money.Money) {
+	v.Check(!amount.IsNegative(), field, CodeOutOfRange, "must not be negative")
+}
+
+// Positive checks that amount is more than zero.
+func (v *Validator) Positive(field string, amount money.Money) {
+	v.Check(amount.IsPositive(), field, CodeOutOfRange, "must be positive")
+}
+
+// OneOf checks that value is one of allowed.
+func (v *Validator) OneOf(field, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.Add(field, CodeNotAllowed, "must be one of: "+strings.Join(allowed, ", "))
+}
+
+// DecodeJSON decodes a JSON object into the struct dst field by field, so
+// each problem is reported against the field that caused it. Keys that
+// match no field of dst, including fields of embedded structs, are
+// reported as unknown. Anything that is not a JSON object wraps
+// ErrMalformedJSON.
+func DecodeJSON(data []byte, dst interface{}) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return fmt.Errorf("%w: expected a JSON object", ErrMalformedJSON)
+		}
+		return fmt.Errorf("%w: %v", ErrMalformedJSON, err)
+	}
+	if raw == nil {
+		return fmt.Errorf("%w: expected a JSON object", ErrMalformedJSON)
+	}
+
+	fields := jsonFields(reflect.ValueOf(dst).Elem())
+
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	v := New()
+	for _, key := range keys {
+		field, ok := fields[key]
+		if !ok {
+			v.Add(key, CodeUnknownField, "is not a known field")
+			continue
+		}
+		if err := json.Unmarshal(raw[key], field.Addr().Interface()); err != nil {
+			if errors.Is(err, money.ErrSubCent) {
+				v.errs = append(v.errs, SubCentError(key))
+			} else {
+				v.Add(key, CodeInvalidType, typeMessage(field.Type()))
+			}
+		}
+	}
+	return v.Err()
+}
+
+// jsonFields maps the JSON names of the fields of struct v, including the
+// fields promoted from embedded structs, to the fields themselves.
+func jsonFields(v reflect.Value) map[string]reflect.Value {
+	fields := make(map[string]reflect.Value)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			// Like encoding/json, promote the fields of embedded structs
+			// even when the embedded type itself is unexported.
+			for k, fv := range jsonFields(v.Field(i)) {
+				fields[k] = fv
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = v.Field(i)
+	}
+	return fields
+}
+
+// SubCentError is the error for an amount in field with fractions of a
+// cent, which are refused rather than rounded.
+func SubCentError(field string) FieldError {
+	return FieldError{Field: field, Code: CodeInvalid, Message: "must not have fractions of a cent"}
+}
+
+var moneyType = reflect.TypeOf(money.Money(0))
+
+func typeMessage(t reflect.Type) string {
+	if t == moneyType {
+		return "must be a decimal amount, e.g. 10.50"
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "must be an integer"
+	case reflect.Float32, reflect.Float64:
+		return "must be a number"
+	case reflect.Bool:
+		return "must be a boolean"
+	case reflect.String:
+		return "must be a string"
+	case reflect.Slice, reflect.Array:
+		return "must be a list"
+	}
+	return "must be an object"
+}
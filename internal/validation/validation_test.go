@@ -0,0 +1,114 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryAlvim/gobank/internal/money"
+)
+
+func TestValidator(t *testing.T) {
+	v := New()
+	assert.NoError(t, v.Err())
+
+	assert.True(t, v.Required("name", "Ana"))
+	assert.False(t, v.Required("nickname", " "))
+	v.MaxLength("name", "Ana", 3)
+	v.MaxLength("city", "São Paulo", 8)
+	v.Between("age", 30, 0, 150)
+	v.Between("score", 11, 0, 10)
+	v.NonNegative("income", money.Zero)
+	v.NonNegative("balance", money.FromCents(-1))
+	v.Positive("amount", money.FromCents(1))
+	v.Positive("fee", money.Zero)
+	v.OneOf("category", "standard", "standard", "premium")
+	v.OneOf("kind", "gold", "standard", "premium")
+
+	err := v.Err()
+	assert.EqualError(t, err, "validation failed: nickname: is required; city: must be at most 8 characters; "+
+		"score: must be between 0 and 10; balance: must not be negative; fee: must be positive; kind: must be one of: standard, premium")
+	assert.Equal(t, Errors{
+		{Field: "nickname", Code: CodeRequired, Message: "is required"},
+		{Field: "city", Code: CodeTooLong, Message: "must be at most 8 characters"},
+		{Field: "score", Code: CodeOutOfRange, Message: "must be between 0 and 10"},
+		{Field: "balance", Code: CodeOutOfRange, Message: "must not be negative"},
+		{Field: "fee", Code: CodeOutOfRange, Message: "must be positive"},
+		{Field: "kind", Code: CodeNotAllowed, Message: "must be one of: standard, premium"},
+	}, err)
+}
+
+func TestValidator_Email(t *testing.T) {
+	tests := []struct {
+		input string
+		valid bool
+	}{
+		{"john.doe@example.com", true},
+		{"contact@acme.com.br", true},
+		{"john.doe", false},
+		{"john@localhost", false},
+		{"John <john@example.com>", false},
+		{"john@", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			v := New()
+			v.Email("email", tt.input)
+			if tt.valid {
+				assert.NoError(t, v.Err())
+			} else {
+				assert.Equal(t, Errors{{Field: "email", Code: CodeInvalid, Message: "must be a valid email address"}}, v.Err())
+			}
+		})
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	type base struct {
+		Name string `json:"name"`
+	}
+	type request struct {
+		base
+		Age     int         `json:"age"`
+		Balance money.Money `json:"balance"`
+		Ignored string      `json:"-"`
+	}
+
+	var req request
+	err := DecodeJSON([]byte(`{"name": "Ana", "age": 30, "balance": 10.5}`), &req)
+	assert.NoError(t, err)
+	assert.Equal(t, "Ana", req.Name)
+	assert.Equal(t, 30, req.Age)
+	assert.Equal(t, money.FromCents(1050), req.Balance)
+
+	req = request{}
+	err = DecodeJSON([]byte(`{"name": 1, "age": "30", "balance": "x", "Ignored": "y", "extra": true}`), &req)
+	assert.Equal(t, Errors{
+		{Field: "Ignored", Code: CodeUnknownField, Message: "is not a known field"},
+		{Field: "age", Code: CodeInvalidType, Message: "must be an integer"},
+		{Field: "balance", Code: CodeInvalidType, Message: "must be a decimal amount, e.g. 10.50"},
+		{Field: "extra", Code: CodeUnknownField, Message: "is not a known field"},
+		{Field: "name", Code: CodeInvalidType, Message: "must be a string"},
+	}, err)
+}
+
+func TestDecodeJSON_SubCentAmount(t *testing.T) {
+	var req struct {
+		Balance money.Money `json:"balance"`
+	}
+	err := DecodeJSON([]byte(`{"balance": 10.005}`), &req)
+	assert.Equal(t, Errors{SubCentError("balance")}, err)
+}
+
+func TestDecodeJSON_Malformed(t *testing.T) {
+	for _, body := range []string{``, `{`, `[]`, `"text"`, `null`} {
+		t.Run(body, func(t *testing.T) {
+			var req struct {
+				Name string `json:"name"`
+			}
+			assert.ErrorIs(t, DecodeJSON([]byte(body), &req), ErrMalformedJSON)
+		})
+	}
+}
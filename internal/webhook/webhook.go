@@ -0,0 +1,148 @@
+// Package webhook signs and sends webhook deliveries.
+//
+// Each delivery is a POST of the event as JSON. The X-Webhook-Timestamp
+// header holds the Unix time it was sent at, and X-Webhook-Signature is
+// "v1=" followed by the hex HMAC-SHA256, keyed with the subscription's
+// secret, of the timestamp, a dot and the body. Receivers should recompute
+// it, compare in constant time and reject old timestamps to stop replays.
+// X-Webhook-Id is the event ID, the same on every attempt.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	IDHeader        = "X-Webhook-Id"
+	EventHeader     = "X-Webhook-Event"
+	TimestampHeader = "X-Webhook-Timestamp"
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrTimestampExpired = errors.New("webhook timestamp is too old")
+	ErrForbiddenAddress = errors.New("webhook address is not public")
+)
+
+// NewSecret returns a random secret for a new subscription.
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Sign returns the signature of body sent at timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature headers of a delivery received at now. The
+// timestamp may be at most tolerance away from now.
+func Verify(secret string, header http.Header, body []byte, now time.Time, tolerance time.Duration) error {
+	timestamp, err := strconv.ParseInt(header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(header.Get(SignatureHeader)), []byte(Sign(secret, timestamp, body))) {
+		return ErrInvalidSignature
+	}
+	if d := now.Sub(time.Unix(timestamp, 0)); d > tolerance || d < -tolerance {
+		return ErrTimestampExpired
+	}
+	return nil
+}
+
+// Client sends deliveries.
+type Client struct {
+	HTTP *http.Client
+}
+
+// NewClient returns a client whose requests time out after timeout. It
+// only connects to public addresses: the address is checked when the
+// connection is made, after the name is resolved, so a name that resolves
+// to an internal address cannot get past it. Proxies from the environment
+// are not used, since they would connect on the client's behalf.
+func NewClient(timeout time.Duration) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: checkAddress}).DialContext
+	return &Client{HTTP: &http.Client{Timeout: timeout, Transport: transport}}
+}
+
+// checkAddress refuses connections to loopback, private, link-local and
+// unspecified addresses.
+func checkAddress(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !isPublic(addrPort.Addr()) {
+		return ErrForbiddenAddress
+	}
+	return nil
+}
+
+// isPublic reports whether deliveries may be sent to addr.
+func isPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return !(addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsUnspecified())
+}
+
+// Delivery is what is sent to a subscription.
+type Delivery struct {
+	URL       string
+	Secret    string
+	EventID   string
+	EventType string
+	Body      []byte
+}
+
+// Send posts the delivery signed at now and returns the response status.
+// A response outside 2xx is an error, returned with its status code. The
+// error names only the status: the receiver's body is not kept.
+func (c *Client) Send(d Delivery, now time.Time) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, d.URL, bytes.NewReader(d.Body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gobank-webhooks")
+	req.Header.Set(IDHeader, d.EventID)
+	req.Header.Set(EventHeader, d.EventType)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(d.Secret, timestamp, d.Body))
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver responded %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,118 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	// echo -n '1772366400.{"id":"e1"}' | openssl dgst -sha256 -hmac whsec_test
+	assert.Equal(t, "v1=f5dd64ff37e14f064074392f3fdba17f26fbc3decde59750d5a04ecbdef83a42",
+		Sign("whsec_test", 1772366400, []byte(`{"id":"e1"}`)))
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":"e1"}`)
+	sentAt := time.Unix(1772366400, 0)
+	header := http.Header{}
+	header.Set(TimestampHeader, "1772366400")
+	header.Set(SignatureHeader, Sign("whsec_test", sentAt.Unix(), body))
+
+	assert.NoError(t, Verify("whsec_test", header, body, sentAt.Add(time.Minute), 5*time.Minute))
+	assert.ErrorIs(t, Verify("whsec_other", header, body, sentAt, 5*time.Minute), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("whsec_test", header, []byte(`{"id":"e2"}`), sentAt, 5*time.Minute), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("whsec_test", header, body, sentAt.Add(10*time.Minute), 5*time.Minute), ErrTimestampExpired)
+
+	// The signature covers the timestamp.
+	header.Set(TimestampHeader, "1772366460")
+	assert.ErrorIs(t, Verify("whsec_test", header, body, sentAt, 5*time.Minute), ErrInvalidSignature)
+}
+
+func TestNewSecret(t *testing.T) {
+	a, err := NewSecret()
+	require.NoError(t, err)
+	b, err := NewSecret()
+	require.NoError(t, err)
+	assert.Len(t, a, len("whsec_")+64)
+	assert.NotEqual(t, a, b)
+}
+
+func TestClient_Send(t *testing.T) {
+	now := time.Unix(1772366400, 0)
+	var received http.Header
+	var receivedBody []byte
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+		if status >= 300 {
+			w.Write([]byte("internal details"))
+		}
+	}))
+	defer server.Close()
+
+	// The test server listens on loopback, which NewClient refuses.
+	client := &Client{HTTP: server.Client()}
+	delivery := Delivery{URL: server.URL, Secret: "whsec_test", EventID: "e1", EventType: "deposit.completed", Body: []byte(`{"id":"e1"}`)}
+
+	code, err := client.Send(delivery, now)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Equal(t, `{"id":"e1"}`, string(receivedBody))
+	assert.Equal(t, "e1", received.Get(IDHeader))
+	assert.Equal(t, "deposit.completed", received.Get(EventHeader))
+	assert.NoError(t, Verify("whsec_test", received, receivedBody, now, time.Minute))
+
+	status = http.StatusServiceUnavailable
+	code, err = client.Send(delivery, now)
+	// The body is not kept.
+	assert.EqualError(t, err, "receiver responded 503")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	server.Close()
+	code, err = client.Send(delivery, now)
+	assert.Error(t, err)
+	assert.Zero(t, code)
+}
+
+func TestNewClient_RefusesInternalAddresses(t *testing.T) {
+	hit := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer server.Close()
+
+	code, err := NewClient(time.Second).Send(Delivery{URL: server.URL, Body: []byte(`{}`)}, time.Now())
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+	assert.Zero(t, code)
+	assert.False(t, hit)
+}
+
+func TestIsPublic(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"93.184.215.14":        true,
+		"2606:2800:21f::1":     true,
+		"127.0.0.1":            false,
+		"::1":                  false,
+		"10.1.2.3":             false,
+		"172.16.0.1":           false,
+		"192.168.0.10":         false,
+		"fd00::1":              false,
+		"169.254.169.254":      false,
+		"fe80::1":              false,
+		"0.0.0.0":              false,
+		"::":                   false,
+		"::ffff:127.0.0.1":     false,
+		"::ffff:93.184.215.14": true,
+	} {
+		assert.Equal(t, want, isPublic(netip.MustParseAddr(addr)), addr)
+	}
+}
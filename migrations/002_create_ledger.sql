@@ -0,0 +1,63 @@
+-- Migration for journal_entries table
+CREATE TABLE journal_entries (
+    id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(50) NOT NULL,
+    description VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Migration for postings table
+CREATE TABLE postings (
+    id BIGSERIAL PRIMARY KEY,
+    journal_entry_id BIGINT NOT NULL REFERENCES journal_entries (id),
+    account_type VARCHAR(20) NOT NULL,
+    account_id INT NOT NULL,
+    direction VARCHAR(6) NOT NULL CHECK (direction IN ('debit', 'credit')),
+    amount DECIMAL NOT NULL CHECK (amount > 0)
+);
+
+CREATE INDEX postings_account_idx ON postings (account_type, account_id);
+CREATE INDEX postings_journal_entry_idx ON postings (journal_entry_id);
+
+-- The journal is append-only: entries and postings can never be changed.
+CREATE FUNCTION reject_journal_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'journal is append-only: % on % is not allowed', TG_OP, TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER journal_entries_immutable
+    BEFORE UPDATE OR DELETE ON journal_entries
+    FOR EACH ROW EXECUTE FUNCTION reject_journal_change();
+
+CREATE TRIGGER postings_immutable
+    BEFORE UPDATE OR DELETE ON postings
+    FOR EACH ROW EXECUTE FUNCTION reject_journal_change();
+
+-- Opening entries for balances that existed before the ledger. System
+-- account 2 is equity (see internal/ledger).
+DO $$
+DECLARE
+    acc RECORD;
+    entry_id BIGINT;
+BEGIN
+    FOR acc IN
+        SELECT 'natural' AS account_type, id, balance FROM natural_person WHERE balance IS NOT NULL AND balance <> 0
+        UNION ALL
+        SELECT 'legal', id, balance FROM legal_person WHERE balance IS NOT NULL AND balance <> 0
+    LOOP
+        INSERT INTO journal_entries (kind, description) VALUES ('opening', 'Opening balance')
+        RETURNING id INTO entry_id;
+
+        INSERT INTO postings (journal_entry_id, account_type, account_id, direction, amount) VALUES
+            (entry_id, acc.account_type, acc.id, CASE WHEN acc.balance > 0 THEN 'credit' ELSE 'debit' END, abs(acc.balance)),
+            (entry_id, 'system', 2, CASE WHEN acc.balance > 0 THEN 'debit' ELSE 'credit' END, abs(acc.balance));
+    END LOOP;
+END;
+$$;
+
+---- create above / drop below ----
+
+DROP TABLE postings;
+DROP TABLE journal_entries;
+DROP FUNCTION reject_journal_change();
@@ -0,0 +1,36 @@
+-- Migration for account_transactions table
+CREATE TABLE account_transactions (
+    id BIGSERIAL PRIMARY KEY,
+    journal_entry_id BIGINT NOT NULL REFERENCES journal_entries (id),
+    account_type VARCHAR(20) NOT NULL,
+    account_id INT NOT NULL,
+    kind VARCHAR(50) NOT NULL,
+    direction VARCHAR(6) NOT NULL CHECK (direction IN ('debit', 'credit')),
+    amount DECIMAL NOT NULL,
+    balance_after DECIMAL NOT NULL,
+    counterparty_type VARCHAR(20),
+    counterparty_id INT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX account_transactions_account_idx ON account_transactions (account_type, account_id, id DESC);
+
+-- Backfill history from the journal, computing the running balance of
+-- each customer account in posting order.
+INSERT INTO account_transactions (journal_entry_id, account_type, account_id, kind, direction, amount, balance_after,
+                                  counterparty_type, counterparty_id, created_at)
+SELECT p.journal_entry_id, p.account_type, p.account_id, je.kind, p.direction, p.amount,
+       SUM(CASE WHEN p.direction = 'credit' THEN p.amount ELSE -p.amount END)
+           OVER (PARTITION BY p.account_type, p.account_id ORDER BY p.id),
+       NULLIF(other.account_type, 'system'),
+       CASE WHEN other.account_type <> 'system' THEN other.account_id END,
+       je.created_at
+FROM postings p
+JOIN journal_entries je ON je.id = p.journal_entry_id
+LEFT JOIN postings other ON other.journal_entry_id = p.journal_entry_id AND other.id <> p.id
+WHERE p.account_type <> 'system'
+ORDER BY p.id;
+
+---- create above / drop below ----
+
+DROP TABLE account_transactions;
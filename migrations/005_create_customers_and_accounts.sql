@@ -0,0 +1,99 @@
+-- Migration for customers table
+CREATE TABLE customers (
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('natural', 'legal')),
+    age INT,
+    phone_number VARCHAR(20),
+    -- natural person
+    full_name VARCHAR(255),
+    email VARCHAR(255),
+    monthly_income DECIMAL,
+    -- legal person
+    trade_name VARCHAR(255),
+    corporate_email VARCHAR(255),
+    annual_revenue DECIMAL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Migration for accounts table. Customer accounts reference their holder;
+-- the bank's own ledger accounts have a system_code instead.
+CREATE TABLE accounts (
+    id BIGSERIAL PRIMARY KEY,
+    customer_id BIGINT REFERENCES customers (id),
+    system_code VARCHAR(50) UNIQUE,
+    category VARCHAR(50),
+    balance DECIMAL NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((customer_id IS NULL) <> (system_code IS NULL))
+);
+
+CREATE INDEX accounts_customer_idx ON accounts (customer_id);
+
+-- System accounts keep the IDs the ledger already uses (see internal/ledger).
+INSERT INTO accounts (id, system_code) VALUES (1, 'cash'), (2, 'equity');
+SELECT setval('accounts_id_seq', 2);
+
+-- Move every natural_person and legal_person row to a customer with one
+-- account, remembering where each account came from.
+ALTER TABLE customers ADD COLUMN legacy_id INT;
+ALTER TABLE accounts ADD COLUMN legacy_type VARCHAR(20), ADD COLUMN legacy_id INT;
+
+INSERT INTO customers (type, age, phone_number, full_name, email, monthly_income, legacy_id)
+SELECT 'natural', age, phone_number, full_name, email, monthly_income, id FROM natural_person ORDER BY id;
+
+INSERT INTO accounts (customer_id, category, balance, legacy_type, legacy_id)
+SELECT c.id, np.category, COALESCE(np.balance, 0), 'natural', np.id
+FROM natural_person np JOIN customers c ON c.type = 'natural' AND c.legacy_id = np.id
+ORDER BY np.id;
+
+INSERT INTO customers (type, age, phone_number, trade_name, corporate_email, annual_revenue, legacy_id)
+SELECT 'legal', age, phone_number, trade_name, corporate_email, annual_revenue, id FROM legal_person ORDER BY id;
+
+INSERT INTO accounts (customer_id, category, balance, legacy_type, legacy_id)
+SELECT c.id, lp.category, COALESCE(lp.balance, 0), 'legal', lp.id
+FROM legal_person lp JOIN customers c ON c.type = 'legal' AND c.legacy_id = lp.id
+ORDER BY lp.id;
+
+-- Point the journal at the new account IDs. System accounts keep theirs.
+ALTER TABLE postings DISABLE TRIGGER postings_immutable;
+
+UPDATE postings p SET account_id = a.id
+FROM accounts a
+WHERE a.legacy_type = p.account_type AND a.legacy_id = p.account_id;
+
+ALTER TABLE postings ENABLE TRIGGER postings_immutable;
+
+DROP INDEX postings_account_idx;
+ALTER TABLE postings DROP COLUMN account_type;
+ALTER TABLE postings ALTER COLUMN account_id TYPE BIGINT;
+CREATE INDEX postings_account_idx ON postings (account_id);
+
+-- Same for the account history and its counterparties.
+UPDATE account_transactions t SET account_id = a.id
+FROM accounts a
+WHERE a.legacy_type = t.account_type AND a.legacy_id = t.account_id;
+
+UPDATE account_transactions t SET counterparty_id = a.id
+FROM accounts a
+WHERE a.legacy_type = t.counterparty_type AND a.legacy_id = t.counterparty_id;
+
+DROP INDEX account_transactions_account_idx;
+ALTER TABLE account_transactions DROP COLUMN account_type, DROP COLUMN counterparty_type;
+ALTER TABLE account_transactions ALTER COLUMN account_id TYPE BIGINT, ALTER COLUMN counterparty_id TYPE BIGINT;
+CREATE INDEX account_transactions_account_idx ON account_transactions (account_id, id DESC);
+
+ALTER TABLE customers DROP COLUMN legacy_id;
+ALTER TABLE accounts DROP COLUMN legacy_type, DROP COLUMN legacy_id;
+
+DROP TABLE natural_person;
+DROP TABLE legal_person;
+
+---- create above / drop below ----
+
+-- Splitting accounts back into per-type tables would lose customers with
+-- more than one account, so this migration is not reversible.
+DO $$
+BEGIN
+    RAISE EXCEPTION 'migration 005_create_customers_and_accounts cannot be reverted';
+END;
+$$;
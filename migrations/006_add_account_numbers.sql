@@ -0,0 +1,52 @@
+-- Migration for account numbers: branch (agência), number and check digit.
+-- See internal/accountnumber for the check digit algorithm.
+CREATE SEQUENCE account_number_seq;
+
+ALTER TABLE accounts
+    ADD COLUMN branch CHAR(4),
+    ADD COLUMN number CHAR(8),
+    ADD COLUMN check_digit CHAR(1);
+
+-- Backfill existing customer accounts in branch 0001, numbered by ID.
+CREATE FUNCTION account_check_digit(digits TEXT) RETURNS CHAR(1) AS $$
+DECLARE
+    total INT := 0;
+    weight INT := 2;
+    digit INT;
+BEGIN
+    FOR i IN REVERSE length(digits)..1 LOOP
+        total := total + substr(digits, i, 1)::INT * weight;
+        weight := CASE WHEN weight = 9 THEN 2 ELSE weight + 1 END;
+    END LOOP;
+    digit := 11 - total % 11;
+    IF digit >= 10 THEN
+        digit := 0;
+    END IF;
+    RETURN digit::TEXT;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+UPDATE accounts
+SET branch = '0001',
+    number = lpad(id::TEXT, 8, '0'),
+    check_digit = account_check_digit('0001' || lpad(id::TEXT, 8, '0'))
+WHERE customer_id IS NOT NULL;
+
+DROP FUNCTION account_check_digit(TEXT);
+
+SELECT setval('account_number_seq', GREATEST((SELECT max(id) FROM accounts), 1));
+
+ALTER TABLE accounts
+    ADD CONSTRAINT accounts_number_key UNIQUE (branch, number),
+    ADD CONSTRAINT accounts_number_check CHECK ((customer_id IS NULL) = (number IS NULL));
+
+---- create above / drop below ----
+
+ALTER TABLE accounts
+    DROP CONSTRAINT accounts_number_check,
+    DROP CONSTRAINT accounts_number_key,
+    DROP COLUMN check_digit,
+    DROP COLUMN number,
+    DROP COLUMN branch;
+
+DROP SEQUENCE account_number_seq;
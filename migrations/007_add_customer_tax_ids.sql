@@ -0,0 +1,20 @@
+-- Migration for customer tax IDs. CPFs and CNPJs are stored without the
+-- mask (see internal/taxid). Customers registered before this migration
+-- have neither and are left NULL.
+ALTER TABLE customers
+    ADD COLUMN cpf CHAR(11),
+    ADD COLUMN cnpj CHAR(14),
+    ADD CONSTRAINT customers_cpf_key UNIQUE (cpf),
+    ADD CONSTRAINT customers_cnpj_key UNIQUE (cnpj),
+    ADD CONSTRAINT customers_tax_id_check CHECK (
+        (type = 'natural' AND cnpj IS NULL) OR (type = 'legal' AND cpf IS NULL)
+    );
+
+---- create above / drop below ----
+
+ALTER TABLE customers
+    DROP CONSTRAINT customers_tax_id_check,
+    DROP CONSTRAINT customers_cnpj_key,
+    DROP CONSTRAINT customers_cpf_key,
+    DROP COLUMN cnpj,
+    DROP COLUMN cpf;
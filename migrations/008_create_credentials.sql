@@ -0,0 +1,21 @@
+-- Migration for customer credentials. password_hash is a bcrypt hash;
+-- customers registered before this migration have none and cannot log in
+-- until one is set. Refresh tokens are stored as the SHA-256 of the token.
+ALTER TABLE customers ADD COLUMN password_hash VARCHAR(72);
+
+CREATE TABLE refresh_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    customer_id INT NOT NULL REFERENCES customers (id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX refresh_tokens_customer_id_idx ON refresh_tokens (customer_id);
+
+---- create above / drop below ----
+
+DROP TABLE refresh_tokens;
+
+ALTER TABLE customers DROP COLUMN password_hash;
@@ -0,0 +1,40 @@
+-- Migration for back-office operators. Operators log in with a username
+-- and a bcrypt password hash; role is checked against the permission
+-- matrix in internal/auth.
+CREATE TABLE operators (
+    id SERIAL PRIMARY KEY,
+    username VARCHAR(64) NOT NULL UNIQUE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('teller', 'supervisor', 'auditor')),
+    password_hash VARCHAR(72) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Frozen accounts reject debits.
+ALTER TABLE accounts ADD COLUMN frozen BOOLEAN NOT NULL DEFAULT false;
+
+-- Every request made by an operator, allowed or not.
+CREATE TABLE audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    operator_id INT NOT NULL REFERENCES operators (id),
+    operator_role VARCHAR(20) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    path VARCHAR(255) NOT NULL,
+    account_id INT,
+    customer_id INT,
+    reason VARCHAR(500),
+    request TEXT,
+    status_code INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX audit_events_account_idx ON audit_events (account_id, id DESC);
+CREATE INDEX audit_events_operator_idx ON audit_events (operator_id, id DESC);
+
+---- create above / drop below ----
+
+DROP TABLE audit_events;
+
+ALTER TABLE accounts DROP COLUMN frozen;
+
+DROP TABLE operators;
@@ -0,0 +1,25 @@
+-- Migration for transfers waiting for an operator's approval. While an
+-- approval is pending and not past expires_at, its amount is held on the
+-- source account and cannot be spent. Expired approvals keep the
+-- 'pending' status; readers treat them as expired.
+CREATE TABLE transfer_approvals (
+    id BIGSERIAL PRIMARY KEY,
+    from_account_id BIGINT NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    to_account_id BIGINT NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    amount DECIMAL NOT NULL CHECK (amount > 0),
+    status VARCHAR(10) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    requested_by_customer_id BIGINT REFERENCES customers (id),
+    requested_by_operator_id INT REFERENCES operators (id),
+    reviewed_by INT REFERENCES operators (id),
+    review_reason VARCHAR(500),
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    reviewed_at TIMESTAMPTZ,
+    CHECK ((requested_by_customer_id IS NULL) <> (requested_by_operator_id IS NULL))
+);
+
+CREATE INDEX transfer_approvals_pending_idx ON transfer_approvals (from_account_id) WHERE status = 'pending';
+
+---- create above / drop below ----
+
+DROP TABLE transfer_approvals;
@@ -0,0 +1,22 @@
+-- Migration for holds: money reserved on an account before it is settled,
+-- as in a card authorization. An active hold that has not expired lowers
+-- the available balance but not the balance itself. Expired holds keep
+-- the 'active' status; readers treat them as expired.
+CREATE TABLE holds (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    amount DECIMAL NOT NULL CHECK (amount > 0),
+    captured_amount DECIMAL CHECK (captured_amount > 0 AND captured_amount <= amount),
+    status VARCHAR(10) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'captured', 'voided')),
+    description VARCHAR(255),
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    released_at TIMESTAMPTZ
+);
+
+CREATE INDEX holds_account_idx ON holds (account_id, id DESC);
+CREATE INDEX holds_active_idx ON holds (account_id) WHERE status = 'active';
+
+---- create above / drop below ----
+
+DROP TABLE holds;
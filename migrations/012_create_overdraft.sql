@@ -0,0 +1,35 @@
+-- Migration for overdraft (cheque especial). Each account category has a
+-- limit the balance may go below zero by and a monthly interest rate, in
+-- basis points, charged daily on negative balances. An account's own
+-- overdraft_limit, when set, replaces its category's limit.
+CREATE TABLE overdraft_policies (
+    category VARCHAR(20) PRIMARY KEY,
+    limit_amount DECIMAL NOT NULL CHECK (limit_amount >= 0),
+    monthly_rate_bps INT NOT NULL CHECK (monthly_rate_bps BETWEEN 0 AND 10000)
+);
+
+INSERT INTO overdraft_policies (category, limit_amount, monthly_rate_bps) VALUES
+    ('standard', 500, 800),
+    ('premium', 5000, 600),
+    ('business', 20000, 500),
+    ('savings', 0, 0);
+
+ALTER TABLE accounts ADD COLUMN overdraft_limit DECIMAL CHECK (overdraft_limit >= 0);
+
+-- One interest charge per account and day, so the daily job can run more
+-- than once without charging twice.
+CREATE TABLE overdraft_interest_charges (
+    account_id BIGINT NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    charge_date DATE NOT NULL,
+    balance DECIMAL NOT NULL,
+    amount DECIMAL NOT NULL CHECK (amount > 0),
+    journal_entry_id BIGINT NOT NULL REFERENCES journal_entries (id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (account_id, charge_date)
+);
+
+---- create above / drop below ----
+
+DROP TABLE overdraft_interest_charges;
+ALTER TABLE accounts DROP COLUMN overdraft_limit;
+DROP TABLE overdraft_policies;
@@ -0,0 +1,44 @@
+-- Migration for interest on savings. Accounts whose category has a row in
+-- interest_rates earn interest every day from effective_from on. A fixed
+-- rate is an annual rate in basis points; a 'cdi' rate is a percentage of
+-- the CDI in basis points (10000 = 100% of the CDI).
+CREATE TABLE interest_rates (
+    category VARCHAR(20) PRIMARY KEY,
+    rate_type VARCHAR(5) NOT NULL CHECK (rate_type IN ('fixed', 'cdi')),
+    rate_bps INT NOT NULL CHECK (rate_bps >= 0),
+    effective_from DATE NOT NULL DEFAULT CURRENT_DATE
+);
+
+INSERT INTO interest_rates (category, rate_type, rate_bps) VALUES ('savings', 'fixed', 600);
+
+-- The annual CDI rate, in basis points, published for each business day.
+-- A day without a rate uses the last one published before it.
+CREATE TABLE cdi_rates (
+    rate_date DATE PRIMARY KEY,
+    annual_rate_bps INT NOT NULL CHECK (annual_rate_bps >= 0)
+);
+
+-- One accrual per account and day, including days that earned nothing,
+-- so a day is never accrued twice and missed days can be found. Accruals
+-- are credited once a month; paid_at and journal_entry_id are set then.
+CREATE TABLE interest_accruals (
+    account_id BIGINT NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    accrual_date DATE NOT NULL,
+    balance DECIMAL NOT NULL,
+    rate_type VARCHAR(5) NOT NULL,
+    rate_bps INT NOT NULL,
+    cdi_rate_bps INT,
+    amount DECIMAL NOT NULL CHECK (amount >= 0),
+    paid_at TIMESTAMPTZ,
+    journal_entry_id BIGINT REFERENCES journal_entries (id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (account_id, accrual_date)
+);
+
+CREATE INDEX interest_accruals_unpaid_idx ON interest_accruals (account_id) WHERE paid_at IS NULL;
+
+---- create above / drop below ----
+
+DROP TABLE interest_accruals;
+DROP TABLE cdi_rates;
+DROP TABLE interest_rates;
@@ -0,0 +1,56 @@
+-- Migration for fees. A withdrawal fee applies to every withdrawal; a
+-- transfer fee depends on the types of the customers on both sides; a
+-- maintenance fee is charged monthly by account category. Columns that do
+-- not apply to a fee type are empty strings so they can be part of the
+-- primary key.
+CREATE TABLE fees (
+    fee_type VARCHAR(20) NOT NULL CHECK (fee_type IN ('withdrawal', 'transfer', 'maintenance')),
+    from_type VARCHAR(10) NOT NULL DEFAULT '',
+    to_type VARCHAR(10) NOT NULL DEFAULT '',
+    category VARCHAR(20) NOT NULL DEFAULT '',
+    amount DECIMAL NOT NULL CHECK (amount >= 0),
+    PRIMARY KEY (fee_type, from_type, to_type, category),
+    CHECK (
+        (fee_type = 'withdrawal' AND from_type = '' AND to_type = '' AND category = '') OR
+        (fee_type = 'transfer' AND from_type IN ('natural', 'legal') AND to_type IN ('natural', 'legal') AND category = '') OR
+        (fee_type = 'maintenance' AND from_type = '' AND to_type = '' AND category <> '')
+    )
+);
+
+INSERT INTO fees (fee_type, from_type, to_type, category, amount) VALUES
+    ('withdrawal', '', '', '', 2.50),
+    ('transfer', 'natural', 'natural', '', 0),
+    ('transfer', 'natural', 'legal', '', 0),
+    ('transfer', 'legal', 'natural', '', 1.50),
+    ('transfer', 'legal', 'legal', '', 1.50),
+    ('maintenance', '', '', 'standard', 15.00),
+    ('maintenance', '', '', 'premium', 30.00),
+    ('maintenance', '', '', 'business', 50.00),
+    ('maintenance', '', '', 'savings', 0);
+
+-- Accounts that are not charged a type of fee.
+CREATE TABLE fee_waivers (
+    account_id BIGINT NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    fee_type VARCHAR(20) NOT NULL CHECK (fee_type IN ('withdrawal', 'transfer', 'maintenance')),
+    reason TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (account_id, fee_type)
+);
+
+-- One maintenance fee per account and month, so the monthly job can run
+-- more than once without charging twice. period is the first day of the
+-- month.
+CREATE TABLE maintenance_fee_charges (
+    account_id BIGINT NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    period DATE NOT NULL,
+    amount DECIMAL NOT NULL CHECK (amount > 0),
+    journal_entry_id BIGINT NOT NULL REFERENCES journal_entries (id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (account_id, period)
+);
+
+---- create above / drop below ----
+
+DROP TABLE maintenance_fee_charges;
+DROP TABLE fee_waivers;
+DROP TABLE fees;
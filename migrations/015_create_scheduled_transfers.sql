@@ -0,0 +1,53 @@
+-- Migration for scheduled transfers: transfers set up to run on a future
+-- date, once or weekly or monthly until end_date or max_runs. The
+-- scheduler claims active transfers whose next_attempt_at has passed;
+-- next_run_date is the date the next transfer is due and next_attempt_at
+-- the time it will be tried, on a business day and later after failures.
+-- Both are NULL once the transfer is completed or cancelled.
+CREATE TABLE scheduled_transfers (
+    id BIGSERIAL PRIMARY KEY,
+    from_account_id BIGINT NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    to_account_id BIGINT NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    amount DECIMAL NOT NULL CHECK (amount > 0),
+    description VARCHAR(255),
+    frequency VARCHAR(10) NOT NULL CHECK (frequency IN ('once', 'weekly', 'monthly')),
+    start_date DATE NOT NULL,
+    day_of_month SMALLINT CHECK (day_of_month BETWEEN 1 AND 31),
+    end_date DATE CHECK (end_date >= start_date),
+    max_runs INT CHECK (max_runs >= 1),
+    runs_count INT NOT NULL DEFAULT 0,
+    attempts INT NOT NULL DEFAULT 0,
+    next_run_date DATE,
+    next_attempt_at TIMESTAMPTZ,
+    status VARCHAR(10) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'completed', 'cancelled')),
+    created_by_customer_id BIGINT REFERENCES customers (id),
+    created_by_operator_id INT REFERENCES operators (id),
+    created_by_role VARCHAR(20) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((created_by_customer_id IS NULL) <> (created_by_operator_id IS NULL)),
+    CHECK ((day_of_month IS NOT NULL) = (frequency = 'monthly'))
+);
+
+CREATE INDEX scheduled_transfers_account_idx ON scheduled_transfers (from_account_id, id DESC);
+CREATE INDEX scheduled_transfers_due_idx ON scheduled_transfers (next_attempt_at) WHERE status = 'active';
+
+-- Every attempt at a scheduled transfer, failed or not. approval_id is set
+-- when the transfer was above the approval threshold and is waiting for
+-- an operator.
+CREATE TABLE scheduled_transfer_runs (
+    id BIGSERIAL PRIMARY KEY,
+    scheduled_transfer_id BIGINT NOT NULL REFERENCES scheduled_transfers (id) ON DELETE CASCADE,
+    run_date DATE NOT NULL,
+    attempt INT NOT NULL CHECK (attempt >= 1),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('succeeded', 'pending_approval', 'retrying', 'failed')),
+    error TEXT,
+    approval_id BIGINT REFERENCES transfer_approvals (id),
+    ran_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX scheduled_transfer_runs_schedule_idx ON scheduled_transfer_runs (scheduled_transfer_id, id DESC);
+
+---- create above / drop below ----
+
+DROP TABLE scheduled_transfer_runs;
+DROP TABLE scheduled_transfers;
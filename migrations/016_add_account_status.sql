@@ -0,0 +1,41 @@
+-- Migration for the account lifecycle. An account's status replaces the
+-- frozen flag and accounts are closed instead of deleted:
+--   pending: opened but not yet activated; takes credits only.
+--   active:  the normal state.
+--   frozen:  blocked by an operator; takes credits only.
+--   dormant: no movement for a long time; takes credits only until an
+--            operator reactivates it.
+--   closed:  final; takes nothing. Its balance was zero or paid out.
+ALTER TABLE accounts ADD COLUMN status VARCHAR(10) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('pending', 'active', 'frozen', 'dormant', 'closed'));
+ALTER TABLE accounts ADD COLUMN closed_at TIMESTAMPTZ;
+UPDATE accounts SET status = 'frozen' WHERE frozen;
+ALTER TABLE accounts DROP COLUMN frozen;
+
+-- Every status change, with who made it and why. A change with neither
+-- a customer nor an operator was made by the bank itself, e.g. marking
+-- an account dormant.
+CREATE TABLE account_status_changes (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    from_status VARCHAR(10) NOT NULL,
+    to_status VARCHAR(10) NOT NULL,
+    reason VARCHAR(500) NOT NULL,
+    changed_by_customer_id BIGINT REFERENCES customers (id),
+    changed_by_operator_id INT REFERENCES operators (id),
+    payout_account_id BIGINT REFERENCES accounts (id),
+    payout_amount DECIMAL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (changed_by_customer_id IS NULL OR changed_by_operator_id IS NULL)
+);
+
+CREATE INDEX account_status_changes_account_idx ON account_status_changes (account_id, id DESC);
+
+---- create above / drop below ----
+
+DROP TABLE account_status_changes;
+
+ALTER TABLE accounts ADD COLUMN frozen BOOLEAN NOT NULL DEFAULT false;
+UPDATE accounts SET frozen = true WHERE status = 'frozen';
+ALTER TABLE accounts DROP COLUMN closed_at;
+ALTER TABLE accounts DROP COLUMN status;
@@ -0,0 +1,58 @@
+-- Migration for multi-currency accounts. Every account holds a single ISO
+-- 4217 currency; accounts that existed before hold BRL. Postings carry the
+-- currency of their amount, so the debits and credits of an entry balance
+-- per currency. The bank's system accounts hold every currency.
+ALTER TABLE accounts ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'BRL';
+ALTER TABLE postings ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'BRL';
+
+-- How many units of quote one unit of base buys, and the bank's spread in
+-- basis points. The spread is taken off the rate a customer gets.
+CREATE TABLE fx_rates (
+    base CHAR(3) NOT NULL,
+    quote CHAR(3) NOT NULL,
+    rate DECIMAL NOT NULL CHECK (rate > 0),
+    spread_bps INT NOT NULL DEFAULT 0 CHECK (spread_bps >= 0 AND spread_bps < 10000),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (base, quote),
+    CHECK (base <> quote)
+);
+
+-- A quote locks a rate for a transfer of amount out of account_id until
+-- expires_at. It can be used once.
+CREATE TABLE fx_quotes (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    from_currency CHAR(3) NOT NULL,
+    to_currency CHAR(3) NOT NULL,
+    amount DECIMAL NOT NULL,
+    converted_amount DECIMAL NOT NULL,
+    mid_rate DECIMAL NOT NULL,
+    spread_bps INT NOT NULL,
+    rate DECIMAL NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- The conversion applied by each cross-currency transfer. The columns are
+-- named apart from account_transactions' so the two can be joined.
+CREATE TABLE fx_conversions (
+    journal_entry_id BIGINT PRIMARY KEY REFERENCES journal_entries (id),
+    quote_id BIGINT REFERENCES fx_quotes (id),
+    from_currency CHAR(3) NOT NULL,
+    to_currency CHAR(3) NOT NULL,
+    source_amount DECIMAL NOT NULL,
+    converted_amount DECIMAL NOT NULL,
+    mid_rate DECIMAL NOT NULL,
+    spread_bps INT NOT NULL,
+    rate DECIMAL NOT NULL
+);
+
+---- create above / drop below ----
+
+DROP TABLE fx_conversions;
+DROP TABLE fx_quotes;
+DROP TABLE fx_rates;
+
+ALTER TABLE postings DROP COLUMN currency;
+ALTER TABLE accounts DROP COLUMN currency;
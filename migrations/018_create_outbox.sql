@@ -0,0 +1,23 @@
+-- Migration for the transactional outbox. Domain events are inserted in
+-- the same transaction as the change they describe, after the accounts
+-- involved are locked, so the ids of an account's events follow the order
+-- in which they were committed. A relay publishes them in id order and
+-- sets published_at; a failed attempt is counted and retried later.
+CREATE TABLE outbox (
+    id BIGSERIAL PRIMARY KEY,
+    event_id UUID NOT NULL DEFAULT gen_random_uuid() UNIQUE,
+    type VARCHAR(50) NOT NULL,
+    account_id BIGINT NOT NULL,
+    counterparty_id BIGINT,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX outbox_pending_idx ON outbox (id) WHERE published_at IS NULL;
+
+---- create above / drop below ----
+
+DROP TABLE outbox;
@@ -0,0 +1,41 @@
+-- Migration for webhooks. A subscription belongs to a customer, and gets
+-- the events of the customer's accounts, or to a partner, and gets the
+-- events of every account. An empty event_types means every type.
+CREATE TABLE webhook_subscriptions (
+    id BIGSERIAL PRIMARY KEY,
+    customer_id BIGINT REFERENCES customers (id) ON DELETE CASCADE,
+    partner VARCHAR(100),
+    url VARCHAR(2048) NOT NULL,
+    secret TEXT NOT NULL,
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((customer_id IS NULL) <> (partner IS NULL))
+);
+
+CREATE INDEX webhook_subscriptions_customer_idx ON webhook_subscriptions (customer_id);
+
+-- One delivery per subscription and event, so an event relayed again is
+-- not sent twice. A pending delivery is sent at next_attempt_at; one that
+-- failed too many times is dead until it is resent by hand.
+CREATE TABLE webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_id UUID NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'dead')),
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ DEFAULT now(),
+    last_status_code INT,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    delivered_at TIMESTAMPTZ,
+    UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+
+---- create above / drop below ----
+
+DROP TABLE webhook_deliveries;
+DROP TABLE webhook_subscriptions;
@@ -0,0 +1,38 @@
+-- Migration for the tamper-evident audit log of account changes. Records
+-- are numbered without gaps and each one's hash covers the hash of the one
+-- before, so an edited, deleted or reordered record breaks the chain (see
+-- internal/auditlog). before and after are JSON, not JSONB, so they are
+-- kept exactly as they were hashed.
+CREATE TABLE audit_log (
+    sequence BIGINT PRIMARY KEY,
+    action VARCHAR(30) NOT NULL,
+    actor VARCHAR(64) NOT NULL,
+    request_id VARCHAR(128) NOT NULL DEFAULT '',
+    account_id BIGINT NOT NULL,
+    counterparty_id BIGINT,
+    before JSON,
+    after JSON NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    prev_hash CHAR(64) NOT NULL,
+    hash CHAR(64) NOT NULL UNIQUE
+);
+
+CREATE INDEX audit_log_account_idx ON audit_log (account_id, sequence);
+
+-- The application only ever appends. The chain is what proves nothing
+-- was changed; the trigger keeps honest mistakes out.
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+CREATE TRIGGER audit_log_no_truncate BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
+
+---- create above / drop below ----
+
+DROP TABLE audit_log;
+DROP FUNCTION audit_log_append_only();
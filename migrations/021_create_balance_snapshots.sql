@@ -0,0 +1,20 @@
+-- Migration for point-in-time balances. A snapshot is an account's
+-- balance at the end of a day (UTC): the sum of its movements dated
+-- before the next midnight. The balance at any other time is the last
+-- snapshot before it plus the movements since, so old balances do not
+-- need the whole history summed.
+CREATE TABLE balance_snapshots (
+    account_id BIGINT NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    snapshot_date DATE NOT NULL,
+    balance DECIMAL NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (account_id, snapshot_date)
+);
+
+-- The movements of an account between a snapshot and a point in time.
+CREATE INDEX account_transactions_account_created_idx ON account_transactions (account_id, created_at);
+
+---- create above / drop below ----
+
+DROP INDEX account_transactions_account_created_idx;
+DROP TABLE balance_snapshots;
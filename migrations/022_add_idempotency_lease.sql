@@ -0,0 +1,9 @@
+-- Migration for idempotency key leases. A key is held by the request that
+-- reserved it until locked_until; after that, a retry of the same request
+-- may take it over, so a crashed request does not block the key until it
+-- expires.
+ALTER TABLE idempotency_keys ADD COLUMN locked_until TIMESTAMPTZ NOT NULL DEFAULT now();
+
+---- create above / drop below ----
+
+ALTER TABLE idempotency_keys DROP COLUMN locked_until;
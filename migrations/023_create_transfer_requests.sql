@@ -0,0 +1,13 @@
+-- Migration for transfers made at most once. The key of a transfer that
+-- must not be repeated, such as one run of a scheduled transfer, is
+-- inserted in the same transaction as the transfer or its approval, so a
+-- repeat finds the key taken instead of moving the money again.
+CREATE TABLE transfer_requests (
+    key VARCHAR(255) PRIMARY KEY,
+    approval_id BIGINT REFERENCES transfer_approvals (id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+---- create above / drop below ----
+
+DROP TABLE transfer_requests;
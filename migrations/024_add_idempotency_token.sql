@@ -0,0 +1,9 @@
+-- Migration for idempotency reservation tokens. Each reservation of a key
+-- gets a random token; the request holding it is the only one that can
+-- complete, release or renew the key, so a request whose key was taken
+-- over cannot overwrite or delete the reservation of the one that took it.
+ALTER TABLE idempotency_keys ADD COLUMN token VARCHAR(64) NOT NULL DEFAULT '';
+
+---- create above / drop below ----
+
+ALTER TABLE idempotency_keys DROP COLUMN token;
@@ -0,0 +1,8 @@
+-- Migration for the transfer fee of pending approvals. The fee the
+-- transfer will be charged is held on the source account along with its
+-- amount, so an approved transfer is not refused for the fee alone.
+ALTER TABLE transfer_approvals ADD COLUMN fee DECIMAL NOT NULL DEFAULT 0 CHECK (fee >= 0);
+
+---- create above / drop below ----
+
+ALTER TABLE transfer_approvals DROP COLUMN fee;
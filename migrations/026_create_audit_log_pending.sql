@@ -0,0 +1,22 @@
+-- Migration for the audit log records waiting to be chained. A change
+-- inserts its record here, in its own transaction, and a background job
+-- moves the records to audit_log in id order, hashing each one onto the
+-- last. Only that job takes the log's lock, so money transactions no
+-- longer wait on each other to append. Records are inserted after the
+-- accounts involved are locked, so an account's records are numbered, and
+-- chained, in commit order.
+CREATE TABLE audit_log_pending (
+    id BIGSERIAL PRIMARY KEY,
+    action VARCHAR(30) NOT NULL,
+    actor VARCHAR(64) NOT NULL,
+    request_id VARCHAR(128) NOT NULL DEFAULT '',
+    account_id BIGINT NOT NULL,
+    counterparty_id BIGINT,
+    before JSON,
+    after JSON NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+---- create above / drop below ----
+
+DROP TABLE audit_log_pending;